@@ -3,29 +3,67 @@ package main
 import (
 	"bytes"
 	"encoding/json"
+	"flag"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
+	"os"
 )
 
-// Substitua pela sua API KEY do Firebase
-const firebaseAPIKey = "YOUR_API_KEY_HERE"
+// Gera um Firebase ID Token para testar o /auth/login.
+//
+// Contra o Firebase real, informe a API KEY do projeto em FIREBASE_API_KEY.
+// Com FIREBASE_AUTH_EMULATOR_HOST (ex: localhost:9099) as chamadas vão para o Firebase Auth Emulator,
+// que aceita qualquer API KEY; use -signup para criar o usuário no emulador antes do login.
+//
+//	go run . -email test@test.com -password 123456 -signup
 
-// Substitua pelo seu email e senha
-const userEmail = "test@test.com"
-const userPassword = "123456"
+const defaultIdentityToolkitURL = "https://identitytoolkit.googleapis.com"
 
 func main() {
-	idToken, err := getFirebaseIDToken(userEmail, userPassword)
+	email := flag.String("email", "test@test.com", "e-mail do usuário")
+	password := flag.String("password", "123456", "senha do usuário")
+	signUp := flag.Bool("signup", false, "cria o usuário antes do login (útil no emulador)")
+	flag.Parse()
+
+	baseURL, apiKey, err := identityToolkitConfig()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *signUp {
+		if _, err := callIdentityToolkit(baseURL, "accounts:signUp", apiKey, *email, *password); err != nil {
+			log.Fatal("Erro ao criar usuário:", err)
+		}
+	}
+
+	idToken, err := callIdentityToolkit(baseURL, "accounts:signInWithPassword", apiKey, *email, *password)
 	if err != nil {
 		log.Fatal("Erro ao obter Firebase ID Token:", err)
 	}
 	fmt.Println("Firebase ID Token gerado:\n", idToken)
 }
 
-func getFirebaseIDToken(email, password string) (string, error) {
-	url := fmt.Sprintf("https://identitytoolkit.googleapis.com/v1/accounts:signInWithPassword?key=%s", firebaseAPIKey)
+// identityToolkitConfig resolve a URL base e a API KEY a partir do ambiente
+func identityToolkitConfig() (string, string, error) {
+	apiKey := os.Getenv("FIREBASE_API_KEY")
+
+	if host := os.Getenv("FIREBASE_AUTH_EMULATOR_HOST"); host != "" {
+		if apiKey == "" {
+			apiKey = "fake-api-key"
+		}
+		return "http://" + host + "/identitytoolkit.googleapis.com", apiKey, nil
+	}
+
+	if apiKey == "" {
+		return "", "", fmt.Errorf("FIREBASE_API_KEY não definida (ou defina FIREBASE_AUTH_EMULATOR_HOST para usar o emulador)")
+	}
+	return defaultIdentityToolkitURL, apiKey, nil
+}
+
+func callIdentityToolkit(baseURL, method, apiKey, email, password string) (string, error) {
+	url := fmt.Sprintf("%s/v1/%s?key=%s", baseURL, method, apiKey)
 
 	body := map[string]interface{}{
 		"email":             email,
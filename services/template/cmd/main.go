@@ -1,22 +1,32 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"strings"
+	"time"
 
 	handlers "github.com/nuhorizon/go-project-template/services/template/internal/delivery/handlers"
 	routes "github.com/nuhorizon/go-project-template/services/template/internal/delivery/routes"
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
 	firebase "github.com/nuhorizon/go-project-template/services/template/internal/infra/firebase"
 	pg "github.com/nuhorizon/go-project-template/services/template/internal/infra/postgres"
 	"github.com/nuhorizon/go-project-template/services/template/internal/ports/infrastructure"
+	repositoriesPorts "github.com/nuhorizon/go-project-template/services/template/internal/ports/repositories"
 	servicesPorts "github.com/nuhorizon/go-project-template/services/template/internal/ports/services"
+	memoryRepositories "github.com/nuhorizon/go-project-template/services/template/internal/repository/memory"
 	pgRepositories "github.com/nuhorizon/go-project-template/services/template/internal/repository/postgres"
 	"github.com/nuhorizon/go-project-template/services/template/internal/services"
 	"github.com/nuhorizon/go-project-template/services/template/internal/usecases"
 	middlewares "github.com/nuhorizon/go-project-template/services/template/pkg/middlewares"
+	"github.com/nuhorizon/go-project-template/services/template/pkg/utils"
 	httpSwagger "github.com/swaggo/http-swagger/v2"
 
+	"firebase.google.com/go/v4/auth"
 	"github.com/go-chi/chi/v5"
 	chiMiddleware "github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/cors"
@@ -32,11 +42,19 @@ import (
 // @securityDefinitions.basic BasicAuth
 // @in header
 // @name Authorization
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
 // @host localhost:8080
 // @BasePath /
 
 func main() {
-	err := Run()
+	var err error
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		err = runMigrate(os.Args[2:], os.Stdout)
+	} else {
+		err = Run()
+	}
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -57,29 +75,61 @@ func Run() error {
 	}
 	defer db.CloseDB()
 
-	// Initialize Firebase
-	firebaseClient, err := firebase.NewFirebaseClient()
+	// Initialize Mailer
+	mailer, err := newMailer()
 	if err != nil {
+		log.Println("Failed to initialize mailer:", err)
+		return err
+	}
+
+	// Initialize Firebase (optional: without credentials only the direct e-mail/password flow is available)
+	var firebaseService servicesPorts.FirebaseAuthService
+	firebaseClient, err := firebase.NewFirebaseClient()
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		log.Println("FIREBASE_CREDENTIALS_JSON not set, Firebase login disabled")
+	case err != nil:
 		log.Println("Failed to initialize Firebase:", err)
 		return err
+	default:
+		firebaseService = services.NewFirebaseAuthService(firebaseClient, mailer, newActionCodeSettings())
 	}
 
-	firebaseService := services.NewFirebaseAuthService(firebaseClient)
+	// Initialize OpenID Connect identity providers (optional)
+	identityProviders, err := newIdentityProviders()
+	if err != nil {
+		log.Println("Failed to initialize identity providers:", err)
+		return err
+	}
 
 	// Initialize JWT Service
-	jwtSecret := os.Getenv("JWT_SECRET")
-	if jwtSecret == "" {
-		log.Println("JWT_SECRET is missing from environment variables")
+	jwtService, err := newJWTService()
+	if err != nil {
+		log.Println("Failed to initialize JWT service:", err)
 		return err
 	}
-	jwtService := services.NewJWTService(jwtSecret)
+
+	passwordHasher := services.NewBcryptPasswordHasher(utils.GetEnvAsInt("BCRYPT_COST", 0))
 
 	// Router
 	mux := chi.NewRouter()
 	initializeMux(mux)
 
 	// Dependency Injection for Handlers
-	setupHandlers(mux, db, firebaseService, jwtService)
+	subscriptions, revocations := setupHandlers(mux, db, firebaseService, identityProviders, jwtService, passwordHasher, mailer)
+
+	// Paid plans that weren't renewed go back to free after SUBSCRIPTION_GRACE_HOURS (right away when cancelled)
+	go expireSubscriptions(subscriptions, intervalMinutes("SUBSCRIPTION_SWEEP_INTERVAL", 5))
+
+	// Deleted accounts are kept for ACCOUNT_RETENTION_DAYS, then purged with everything they own
+	accountRetention := usecases.NewAccountRetentionUseCase(pgRepositories.NewUserPostgres(db.GetDB()), accountRetentionPeriod())
+	go purgeDeletedAccounts(accountRetention, intervalMinutes("ACCOUNT_PURGE_INTERVAL", 60))
+
+	// Revoked tokens and sessions are kept until the tokens they block expire
+	go pruneRevocations(revocations, intervalMinutes("REVOCATION_PRUNE_INTERVAL", 60))
+
+	// Magic links are kept until they expire, then only hold the e-mail they were sent to
+	go pruneMagicLinks(pgRepositories.NewMagicLinkPostgres(db.GetDB()), intervalMinutes("MAGIC_LINK_PRUNE_INTERVAL", 60))
 
 	log.Printf("🚀 CatWise API running on port %s", os.Getenv("PORT"))
 
@@ -91,17 +141,138 @@ func Run() error {
 	return nil
 }
 
+// newJWTService signs with the asymmetric keys found in JWT_KEYS_DIR when set,
+// falling back to the shared HS256 JWT_SECRET otherwise
+func newJWTService() (servicesPorts.JWTService, error) {
+	issuer, audience := jwtIssuer(), jwtAudience()
+
+	if keysDir := os.Getenv("JWT_KEYS_DIR"); keysDir != "" {
+		keys, err := services.LoadSigningKeys(keysDir)
+		if err != nil {
+			return nil, err
+		}
+		gracePeriod := time.Duration(utils.GetEnvAsInt("JWT_KEY_GRACE_PERIOD", 24)) * time.Hour
+		return services.NewKeySetJWTService(keys, os.Getenv("JWT_SIGNING_KID"), gracePeriod, issuer, audience)
+	}
+
+	jwtSecret := os.Getenv("JWT_SECRET")
+	if jwtSecret == "" {
+		return nil, errors.New("JWT_SECRET or JWT_KEYS_DIR is missing from environment variables")
+	}
+	return services.NewJWTService(jwtSecret, issuer, audience), nil
+}
+
+// jwtIssuer is the iss claim of the tokens this API issues (JWT_ISSUER)
+func jwtIssuer() string {
+	if issuer := os.Getenv("JWT_ISSUER"); issuer != "" {
+		return issuer
+	}
+	return "catwise-api"
+}
+
+// jwtAudience is the aud claim of the tokens this API issues and accepts (JWT_AUDIENCE)
+func jwtAudience() string {
+	if audience := os.Getenv("JWT_AUDIENCE"); audience != "" {
+		return audience
+	}
+	return "catwise-api"
+}
+
+// newIdentityProviders builds one OpenID Connect provider per name listed in OIDC_PROVIDERS
+// (e.g. "keycloak,google"), configured by OIDC_<NAME>_ISSUER, OIDC_<NAME>_AUDIENCE (comma
+// separated client IDs) and OIDC_<NAME>_JWKS_CACHE_TTL (minutes)
+func newIdentityProviders() ([]servicesPorts.IdentityProvider, error) {
+	var providers []servicesPorts.IdentityProvider
+	for _, name := range splitList(os.Getenv("OIDC_PROVIDERS")) {
+		name = strings.ToLower(name)
+		if name == domain.ProviderFirebase {
+			return nil, fmt.Errorf("identity provider name %q is reserved", name)
+		}
+
+		prefix := "OIDC_" + strings.ToUpper(name) + "_"
+		provider, err := services.NewOIDCIdentityProvider(services.OIDCConfig{
+			Name:         name,
+			IssuerURL:    os.Getenv(prefix + "ISSUER"),
+			Audiences:    splitList(os.Getenv(prefix + "AUDIENCE")),
+			JWKSCacheTTL: time.Duration(utils.GetEnvAsInt(prefix+"JWKS_CACHE_TTL", 60)) * time.Minute,
+		})
+		if err != nil {
+			return nil, err
+		}
+		providers = append(providers, provider)
+	}
+	return providers, nil
+}
+
+// splitList splits a comma separated env value, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// newMailer delivers e-mails through SMTP when MAILER=smtp, writes them to MAILER_DIR when
+// MAILER=file and only logs them otherwise (local development)
+func newMailer() (servicesPorts.Mailer, error) {
+	from := os.Getenv("MAIL_FROM")
+
+	switch mailer := os.Getenv("MAILER"); mailer {
+	case "smtp":
+		return services.NewSMTPMailer(services.SMTPConfig{
+			Host:     os.Getenv("SMTP_HOST"),
+			Port:     utils.GetEnvAsInt("SMTP_PORT", 587),
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     from,
+		}), nil
+	case "file":
+		dir := os.Getenv("MAILER_DIR")
+		if dir == "" {
+			dir = "tmp/mail"
+		}
+		return services.NewFileMailer(dir, from)
+	case "", "log":
+		return services.NewLogMailer(nil), nil
+	default:
+		return nil, fmt.Errorf("unknown MAILER %q", mailer)
+	}
+}
+
+// newActionCodeSettings configures the links Firebase generates (password reset) from AUTH_ACTION_*,
+// returning nil to keep the Firebase defaults when AUTH_ACTION_CONTINUE_URL is not set
+func newActionCodeSettings() *auth.ActionCodeSettings {
+	continueURL := os.Getenv("AUTH_ACTION_CONTINUE_URL")
+	if continueURL == "" {
+		return nil
+	}
+
+	return &auth.ActionCodeSettings{
+		URL:                   continueURL,
+		HandleCodeInApp:       utils.GetEnvAsBool("AUTH_ACTION_HANDLE_CODE_IN_APP", false),
+		IOSBundleID:           os.Getenv("AUTH_ACTION_IOS_BUNDLE_ID"),
+		AndroidPackageName:    os.Getenv("AUTH_ACTION_ANDROID_PACKAGE_NAME"),
+		AndroidMinimumVersion: os.Getenv("AUTH_ACTION_ANDROID_MINIMUM_VERSION"),
+		AndroidInstallApp:     utils.GetEnvAsBool("AUTH_ACTION_ANDROID_INSTALL_APP", false),
+		DynamicLinkDomain:     os.Getenv("AUTH_ACTION_DYNAMIC_LINK_DOMAIN"),
+	}
+}
+
 func initializeMux(mux *chi.Mux) {
 	mux.Use(chiMiddleware.RequestID)
 	mux.Use(chiMiddleware.RealIP)
+	mux.Use(middlewares.ClientInfoMiddleware)
 	mux.Use(chiMiddleware.Logger)
 	mux.Use(chiMiddleware.Recoverer)
 	mux.Use(chiMiddleware.Heartbeat("/ping"))
 	mux.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   []string{"https://*", "http://*"},
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
 		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
-		ExposedHeaders:   []string{"Link"},
+		ExposedHeaders:   []string{"Link", "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "RateLimit-Policy", "Retry-After"},
 		AllowCredentials: true,
 		MaxAge:           300,
 	}))
@@ -115,22 +286,302 @@ func initializeMux(mux *chi.Mux) {
 	})
 }
 
+// setupHandlers wires the repositories, use cases and handlers and registers every route.
+// It returns the subscription use case and the token revocation store for the background
+// sweepers started by Run (expireSubscriptions and pruneRevocations).
 func setupHandlers(
 	mux *chi.Mux,
 	db infrastructure.SQLConnector,
 	firebaseService servicesPorts.FirebaseAuthService,
+	identityProviders []servicesPorts.IdentityProvider,
 	jwtService servicesPorts.JWTService,
-) {
+	passwordHasher servicesPorts.PasswordHasher,
+	mailer servicesPorts.Mailer,
+) (usecases.SubscriptionUseCase, repositoriesPorts.TokenRevocationStore) {
 	// Repositórios
 	userRepo := pgRepositories.NewUserPostgres(db.GetDB())
+	roleRepo := pgRepositories.NewRolePostgres(db.GetDB())
+	identityRepo := pgRepositories.NewIdentityPostgres(db.GetDB())
+	mfaRepo := pgRepositories.NewMFAPostgres(db.GetDB())
+	refreshTokenRepo := pgRepositories.NewRefreshTokenPostgres(db.GetDB())
+	sessionRepo := pgRepositories.NewSessionPostgres(db.GetDB())
+	apiKeyRepo := pgRepositories.NewAPIKeyPostgres(db.GetDB())
+	magicLinkRepo := pgRepositories.NewMagicLinkPostgres(db.GetDB())
+	userDataRepo := pgRepositories.NewUserDataPostgres(db.GetDB())
+	catRepo := pgRepositories.NewCatPostgres(db.GetDB())
+	planRepo := pgRepositories.NewPlanPostgres(db.GetDB())
+	paymentEventRepo := pgRepositories.NewPaymentEventPostgres(db.GetDB())
+	revocationStore := newTokenRevocationStore(db)
+	loginAttemptStore := newLoginAttemptStore(db)
+	rateLimitStore := newRateLimitStore(db)
 
 	// Use Cases
-	authUseCase := usecases.NewAuthUseCase(userRepo, firebaseService, jwtService)
+	mfaUseCase := usecases.NewMFAUseCase(userRepo, mfaRepo, services.NewTOTPService(mfaIssuer()))
+	magicLinkUseCase := usecases.NewMagicLinkUseCase(magicLinkRepo, jwtService, mailer, magicLinkURL())
+	emailVerificationUseCase := usecases.NewEmailVerificationUseCase(userRepo, jwtService, mailer, emailVerificationURL())
+	authUseCase := usecases.NewAuthUseCase(userRepo, roleRepo, identityRepo, refreshTokenRepo, sessionRepo, revocationStore, firebaseService, identityProviders, mfaUseCase, magicLinkUseCase, emailVerificationUseCase, jwtService, passwordHasher)
+
+	eventPublisher := services.NewLogEventPublisher(nil)
+	subscriptionUseCase := usecases.NewSubscriptionUseCase(userRepo, planRepo, revocationStore, eventPublisher, subscriptionGracePeriod())
+	adminUseCase := usecases.NewAdminUseCase(userRepo, roleRepo, authUseCase, subscriptionUseCase)
+	apiKeyUseCase := usecases.NewAPIKeyUseCase(apiKeyRepo, userRepo, roleRepo)
+	userUseCase := usecases.NewUserUseCase(userRepo, userDataRepo, authUseCase, firebaseService)
+	catUseCase := usecases.NewCatUseCase(catRepo)
+	loginGuard := usecases.NewLoginGuard(loginAttemptStore, lockoutPolicy("LOGIN_IP_MAX_ATTEMPTS", usecases.DefaultIPLockout), lockoutPolicy("LOGIN_ACCOUNT_MAX_ATTEMPTS", usecases.DefaultAccountLockout))
+
+	// Middlewares
+	// Public auth routes are limited per client IP; authenticated routes per user, with the quota of their plan
+	publicRateLimit := middlewares.RateLimitMiddleware(rateLimitStore, rateLimitPolicy("auth", defaultPublicRateLimit, nil))
+	apiRateLimit := middlewares.RateLimitMiddleware(rateLimitStore, rateLimitPolicy("api", defaultPlanRateLimits[domain.PlanFree], defaultPlanRateLimits))
+	jwtAuth := middlewares.AuthMiddleware(jwtService, revocationStore)
+	apiKeyAuth := middlewares.APIKeyMiddleware(apiKeyUseCase, jwtAuth)
+	// Account management (sessions, identities, MFA, API keys) only accepts access tokens,
+	// so a leaked API key can't take over the account or mint new keys
+	sessionMiddleware := func(next http.Handler) http.Handler {
+		return jwtAuth(apiRateLimit(next))
+	}
+	// The rest of the API also accepts API keys, within their scopes
+	authMiddleware := func(next http.Handler) http.Handler {
+		return apiKeyAuth(apiRateLimit(next))
+	}
 
 	// Handlers
-	authHandler := handlers.NewAuthHandler(authUseCase)
+	authHandler := handlers.NewAuthHandler(authUseCase, loginGuard)
+	mfaHandler := handlers.NewMFAHandler(mfaUseCase)
+	adminHandler := handlers.NewAdminHandler(adminUseCase)
+	apiKeyHandler := handlers.NewAPIKeyHandler(apiKeyUseCase)
+	userHandler := handlers.NewUserHandler(userUseCase)
+	catHandler := handlers.NewCatHandler(catUseCase)
+	wellKnownHandler := handlers.NewWellKnownHandler(jwtService)
 
 	// Registro de rotas
-	routes.RegisterAuthRoutes(mux, authHandler)
+	routes.RegisterAuthRoutes(mux.With(publicRateLimit), authHandler, sessionMiddleware)
+	routes.RegisterMFARoutes(mux, mfaHandler, sessionMiddleware)
+	// API keys outlive sessions, only accounts with a verified e-mail may create them
+	routes.RegisterAPIKeyRoutes(mux, apiKeyHandler, func(next http.Handler) http.Handler {
+		return sessionMiddleware(middlewares.RequireVerifiedEmail(next))
+	})
+	routes.RegisterUserRoutes(mux, userHandler, authMiddleware, sessionMiddleware)
+	routes.RegisterCatRoutes(mux, catHandler, authMiddleware)
+	routes.RegisterAdminRoutes(mux, adminHandler, authMiddleware)
+	routes.RegisterWellKnownRoutes(mux, wellKnownHandler)
+
+	// Payment webhooks are signed by the provider, they are only served once its secret is configured
+	if paymentProvider := newPaymentProvider(); paymentProvider != nil {
+		paymentUseCase := usecases.NewPaymentUseCase(paymentProvider, paymentEventRepo, planRepo, revocationStore, eventPublisher)
+		routes.RegisterPaymentRoutes(mux, handlers.NewPaymentWebhookHandler(paymentUseCase, paymentProvider.SignatureHeader()))
+	}
+
+	// The sweeper shares the revocation store of the handlers, which matters with TOKEN_REVOCATION_STORE=memory
+	return subscriptionUseCase, revocationStore
+}
+
+// mfaIssuer is the account label authenticator apps show for TOTP codes (MFA_ISSUER)
+func mfaIssuer() string {
+	if issuer := os.Getenv("MFA_ISSUER"); issuer != "" {
+		return issuer
+	}
+	return "CatWise"
+}
+
+// magicLinkURL is where the links of the passwordless login point to (MAGIC_LINK_URL), usually a
+// page of the app forwarding the token to GET /auth/magic-link/verify. Defaults to that endpoint.
+func magicLinkURL() string {
+	if link := os.Getenv("MAGIC_LINK_URL"); link != "" {
+		return link
+	}
+	return "http://localhost:" + os.Getenv("PORT") + "/auth/magic-link/verify"
+}
+
+// emailVerificationURL is where the verify-email links point to (EMAIL_VERIFICATION_URL), usually a
+// page of the app forwarding the token to GET /auth/verify-email/confirm. Defaults to that endpoint.
+func emailVerificationURL() string {
+	if link := os.Getenv("EMAIL_VERIFICATION_URL"); link != "" {
+		return link
+	}
+	return "http://localhost:" + os.Getenv("PORT") + "/auth/verify-email/confirm"
+}
+
+// accountRetentionPeriod is how long deleted accounts are kept (ACCOUNT_RETENTION_DAYS, 30 by default)
+func accountRetentionPeriod() time.Duration {
+	days := utils.GetEnvAsInt("ACCOUNT_RETENTION_DAYS", 0)
+	if days <= 0 {
+		return usecases.DefaultAccountRetention
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// subscriptionGracePeriod is how long a paid plan is kept after its period ended without a
+// renewal (SUBSCRIPTION_GRACE_HOURS, 72 by default)
+func subscriptionGracePeriod() time.Duration {
+	hours := utils.GetEnvAsInt("SUBSCRIPTION_GRACE_HOURS", -1)
+	if hours < 0 {
+		return usecases.DefaultSubscriptionGracePeriod
+	}
+	return time.Duration(hours) * time.Hour
+}
+
+// intervalMinutes reads the interval of a background job from the env var key, in minutes. Unset,
+// invalid or non-positive values fall back to defaultMinutes, time.NewTicker panics on them.
+func intervalMinutes(key string, defaultMinutes int) time.Duration {
+	minutes := utils.GetEnvAsInt(key, 0)
+	if minutes <= 0 {
+		minutes = defaultMinutes
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// newPaymentProvider verifies the Stripe webhooks with STRIPE_WEBHOOK_SECRET, accepting signatures
+// up to STRIPE_WEBHOOK_TOLERANCE seconds old (300 by default). Returns nil when the secret is not set.
+func newPaymentProvider() servicesPorts.PaymentProvider {
+	secret := os.Getenv("STRIPE_WEBHOOK_SECRET")
+	if secret == "" {
+		log.Println("STRIPE_WEBHOOK_SECRET not set, payment webhooks disabled")
+		return nil
+	}
+
+	tolerance := services.DefaultWebhookTolerance
+	if seconds := utils.GetEnvAsInt("STRIPE_WEBHOOK_TOLERANCE", 0); seconds > 0 {
+		tolerance = time.Duration(seconds) * time.Second
+	}
+	return services.NewStripePaymentProvider(secret, tolerance)
+}
+
+// expireSubscriptions moves the users whose paid plan lapsed to the free plan right away and then
+// every interval, failures are logged and retried on the next run
+func expireSubscriptions(subscriptions usecases.SubscriptionUseCase, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		expired, err := subscriptions.ExpireLapsed(context.Background(), time.Now())
+		if err != nil {
+			log.Println("failed to expire subscriptions:", err)
+		}
+		if expired > 0 {
+			log.Printf("moved %d lapsed subscriptions to the free plan", expired)
+		}
+		<-ticker.C
+	}
+}
+
+// purgeDeletedAccounts runs the retention purge right away and then every interval, failures are
+// logged and retried on the next run
+func purgeDeletedAccounts(retention usecases.AccountRetentionUseCase, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := retention.PurgeExpired(context.Background(), time.Now())
+		if err != nil {
+			log.Println("failed to purge deleted accounts:", err)
+		} else if purged > 0 {
+			log.Printf("purged %d deleted accounts", purged)
+		}
+		<-ticker.C
+	}
+}
 
+// pruneRevocations forgets the expired revocations right away and then every interval, failures
+// are logged and retried on the next run
+func pruneRevocations(revocations repositoriesPorts.TokenRevocationStore, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		pruned, err := revocations.PruneExpired(context.Background(), time.Now())
+		if err != nil {
+			log.Println("failed to prune token revocations:", err)
+		} else if pruned > 0 {
+			log.Printf("pruned %d expired token revocations", pruned)
+		}
+		<-ticker.C
+	}
+}
+
+// pruneMagicLinks removes the expired magic links right away and then every interval, failures
+// are logged and retried on the next run
+func pruneMagicLinks(magicLinks repositoriesPorts.MagicLinkRepository, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		pruned, err := magicLinks.PruneExpired(context.Background(), time.Now())
+		if err != nil {
+			log.Println("failed to prune magic links:", err)
+		} else if pruned > 0 {
+			log.Printf("pruned %d expired magic links", pruned)
+		}
+		<-ticker.C
+	}
+}
+
+// newTokenRevocationStore keeps revocations in Postgres unless TOKEN_REVOCATION_STORE=memory
+// (only suitable for a single instance, revocations are lost on restart)
+func newTokenRevocationStore(db infrastructure.SQLConnector) repositoriesPorts.TokenRevocationStore {
+	if os.Getenv("TOKEN_REVOCATION_STORE") == "memory" {
+		return memoryRepositories.NewTokenRevocationMemory()
+	}
+	return pgRepositories.NewTokenRevocationPostgres(db.GetDB())
+}
+
+// newLoginAttemptStore keeps failed login attempts in Postgres unless LOGIN_ATTEMPT_STORE=memory
+// (counters are then per instance and lost on restart)
+func newLoginAttemptStore(db infrastructure.SQLConnector) repositoriesPorts.LoginAttemptStore {
+	if os.Getenv("LOGIN_ATTEMPT_STORE") == "memory" {
+		return memoryRepositories.NewLoginAttemptMemory()
+	}
+	return pgRepositories.NewLoginAttemptPostgres(db.GetDB())
+}
+
+// lockoutPolicy overrides the failures allowed before the first lockout, 0 disables the lockout
+func lockoutPolicy(maxAttemptsEnv string, policy usecases.LockoutPolicy) usecases.LockoutPolicy {
+	policy.FreeAttempts = utils.GetEnvAsInt(maxAttemptsEnv, policy.FreeAttempts)
+	return policy
+}
+
+// newRateLimitStore keeps rate limit counters in Postgres unless RATE_LIMIT_STORE=memory
+// (each instance then enforces the whole quota on its own)
+func newRateLimitStore(db infrastructure.SQLConnector) repositoriesPorts.RateLimitStore {
+	if os.Getenv("RATE_LIMIT_STORE") == "memory" {
+		return memoryRepositories.NewRateLimitMemory()
+	}
+	return pgRepositories.NewRateLimitPostgres(db.GetDB())
+}
+
+var (
+	defaultPublicRateLimit = domain.RateLimit{Requests: 30, Window: time.Minute}
+	defaultPlanRateLimits  = map[string]domain.RateLimit{
+		domain.PlanFree:    {Requests: 120, Window: time.Minute},
+		domain.PlanPremium: {Requests: 1200, Window: time.Minute},
+	}
+)
+
+// rateLimitPolicy reads the quotas of a route group as "<requests>/<window>": RATE_LIMIT_<GROUP>
+// for anonymous callers and plans without their own quota, RATE_LIMIT_<GROUP>_<PLAN> per plan.
+// Invalid values are logged and the defaults kept.
+func rateLimitPolicy(group string, defaultLimit domain.RateLimit, planLimits map[string]domain.RateLimit) middlewares.RateLimitPolicy {
+	prefix := "RATE_LIMIT_" + strings.ToUpper(group)
+	policy := middlewares.RateLimitPolicy{
+		Group:   group,
+		Default: envRateLimit(prefix, defaultLimit),
+		Plans:   make(map[string]domain.RateLimit),
+	}
+	for plan, limit := range planLimits {
+		policy.Plans[plan] = envRateLimit(prefix+"_"+strings.ToUpper(plan), limit)
+	}
+	return policy
+}
+
+func envRateLimit(name string, defaultLimit domain.RateLimit) domain.RateLimit {
+	value := os.Getenv(name)
+	if value == "" {
+		return defaultLimit
+	}
+	limit, err := domain.ParseRateLimit(value)
+	if err != nil {
+		log.Printf("%s: %v, using %s", name, err, defaultLimit)
+		return defaultLimit
+	}
+	return limit
 }
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/go-chi/chi/v5"
@@ -14,6 +15,7 @@ import (
 
 	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
 	"github.com/nuhorizon/go-project-template/services/template/internal/ports/services"
+	"github.com/nuhorizon/go-project-template/services/template/internal/usecases"
 	middlewares "github.com/nuhorizon/go-project-template/services/template/pkg/middlewares"
 )
 
@@ -43,18 +45,61 @@ func (m *MockFirebaseAuthService) SendPasswordReset(ctx context.Context, email s
 	return args.Error(0)
 }
 
+func (m *MockFirebaseAuthService) DeleteUser(ctx context.Context, uid string) error {
+	args := m.Called(ctx, uid)
+	return args.Error(0)
+}
+
 type MockJWTService struct {
 	mock.Mock
 }
 
-func (m *MockJWTService) GenerateToken(user *domain.User) (string, error) {
-	args := m.Called(user)
+func (m *MockJWTService) GenerateToken(user *domain.User, sessionID string) (string, error) {
+	args := m.Called(user, sessionID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockJWTService) ValidateToken(token string) (*domain.TokenClaims, error) {
+	args := m.Called(token)
+	claims, _ := args.Get(0).(*domain.TokenClaims)
+	return claims, args.Error(1)
+}
+
+func (m *MockJWTService) GenerateMFAToken(userID string) (string, error) {
+	args := m.Called(userID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockJWTService) ValidateMFAToken(token string) (*domain.TokenClaims, error) {
+	args := m.Called(token)
+	claims, _ := args.Get(0).(*domain.TokenClaims)
+	return claims, args.Error(1)
+}
+
+func (m *MockJWTService) GenerateMagicLinkToken(linkID, email string, expiresAt time.Time) (string, error) {
+	args := m.Called(linkID, email, expiresAt)
 	return args.String(0), args.Error(1)
 }
 
-func (m *MockJWTService) ValidateToken(token string) (*domain.User, error) {
+func (m *MockJWTService) ValidateMagicLinkToken(token string) (string, string, error) {
 	args := m.Called(token)
-	return args.Get(0).(*domain.User), args.Error(1)
+	return args.String(0), args.String(1), args.Error(2)
+}
+
+func (m *MockJWTService) GenerateEmailVerificationToken(userID, email string) (string, error) {
+	args := m.Called(userID, email)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockJWTService) ValidateEmailVerificationToken(token string) (string, string, error) {
+	args := m.Called(token)
+	return args.String(0), args.String(1), args.Error(2)
+}
+
+func (m *MockJWTService) PublicKeys() []services.PublicKey {
+	args := m.Called()
+	keys, _ := args.Get(0).([]services.PublicKey)
+	return keys
 }
 
 func TestSetupHandlersAndRoutes(t *testing.T) {
@@ -65,11 +110,24 @@ func TestSetupHandlersAndRoutes(t *testing.T) {
 		{method: http.MethodPost, route: "/auth/login"},
 		{method: http.MethodPost, route: "/auth/register"},
 		{method: http.MethodPost, route: "/auth/reset-password"},
+		{method: http.MethodPost, route: "/auth/magic-link"},
+		{method: http.MethodPost, route: "/auth/verify-email"},
+		{method: http.MethodGet, route: "/.well-known/jwks.json"},
+		{method: http.MethodPost, route: "/admin/users/user-id/revoke-sessions"},
+		{method: http.MethodPost, route: "/auth/mfa/verify"},
+		{method: http.MethodPost, route: "/mfa/totp"},
+		{method: http.MethodGet, route: "/api-keys"},
+		{method: http.MethodGet, route: "/users/me"},
+		{method: http.MethodGet, route: "/users/me/export"},
+		{method: http.MethodGet, route: "/auth/sessions"},
 		{method: http.MethodGet, route: "/cats"},
+		{method: http.MethodPost, route: "/webhooks/payments"},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.method+" "+tt.route, func(t *testing.T) {
+			t.Setenv("RATE_LIMIT_STORE", "memory")
+			t.Setenv("STRIPE_WEBHOOK_SECRET", "whsec_test")
 			db, _, err := sqlmock.New()
 			assert.NoError(t, err)
 			defer db.Close()
@@ -79,9 +137,10 @@ func TestSetupHandlersAndRoutes(t *testing.T) {
 
 			firebaseMock := new(MockFirebaseAuthService)
 			jwtMock := new(MockJWTService)
+			jwtMock.On("PublicKeys").Return(nil)
 
 			mux := chi.NewMux()
-			setupHandlers(mux, dbMock, firebaseMock, jwtMock)
+			setupHandlers(mux, dbMock, firebaseMock, nil, jwtMock, nil, nil)
 
 			req := httptest.NewRequest(tt.method, tt.route, nil)
 			// 👇 Add userID if route needs auth context (like /cats)
@@ -104,3 +163,114 @@ func TestInitializeMux(t *testing.T) {
 	initializeMux(mux)
 	assert.NotNil(t, mux)
 }
+
+func TestNewMailer(t *testing.T) {
+	tests := []struct {
+		mailer    string
+		expectErr bool
+	}{
+		{mailer: ""},
+		{mailer: "log"},
+		{mailer: "smtp"},
+		{mailer: "file"},
+		{mailer: "carrier-pigeon", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run("MAILER="+tt.mailer, func(t *testing.T) {
+			t.Setenv("MAILER", tt.mailer)
+			t.Setenv("MAILER_DIR", t.TempDir())
+
+			mailer, err := newMailer()
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, mailer)
+			}
+		})
+	}
+}
+
+func TestNewActionCodeSettings(t *testing.T) {
+	t.Setenv("AUTH_ACTION_CONTINUE_URL", "")
+	assert.Nil(t, newActionCodeSettings())
+
+	t.Setenv("AUTH_ACTION_CONTINUE_URL", "https://app.example.com/login")
+	t.Setenv("AUTH_ACTION_HANDLE_CODE_IN_APP", "true")
+	settings := newActionCodeSettings()
+	assert.Equal(t, "https://app.example.com/login", settings.URL)
+	assert.True(t, settings.HandleCodeInApp)
+}
+
+func TestNewIdentityProviders(t *testing.T) {
+	t.Setenv("OIDC_PROVIDERS", "")
+	providers, err := newIdentityProviders()
+	assert.NoError(t, err)
+	assert.Empty(t, providers)
+
+	t.Setenv("OIDC_PROVIDERS", "Keycloak, google")
+	t.Setenv("OIDC_KEYCLOAK_ISSUER", "https://sso.example.com/realms/app")
+	t.Setenv("OIDC_KEYCLOAK_AUDIENCE", "mobile-app,web-app")
+	t.Setenv("OIDC_GOOGLE_ISSUER", "https://accounts.google.com")
+	t.Setenv("OIDC_GOOGLE_AUDIENCE", "client-id.apps.googleusercontent.com")
+	providers, err = newIdentityProviders()
+	assert.NoError(t, err)
+	if assert.Len(t, providers, 2) {
+		assert.Equal(t, "keycloak", providers[0].Name())
+		assert.Equal(t, "google", providers[1].Name())
+	}
+
+	t.Setenv("OIDC_GOOGLE_AUDIENCE", "")
+	_, err = newIdentityProviders()
+	assert.Error(t, err)
+
+	t.Setenv("OIDC_PROVIDERS", "firebase")
+	_, err = newIdentityProviders()
+	assert.Error(t, err)
+}
+
+func TestRateLimitPolicy(t *testing.T) {
+	t.Setenv("RATE_LIMIT_API", "")
+	t.Setenv("RATE_LIMIT_API_PREMIUM", "5000/1h")
+	t.Setenv("RATE_LIMIT_API_FREE", "not-a-limit")
+
+	policy := rateLimitPolicy("api", defaultPlanRateLimits[domain.PlanFree], defaultPlanRateLimits)
+	assert.Equal(t, "api", policy.Group)
+	assert.Equal(t, defaultPlanRateLimits[domain.PlanFree], policy.Default)
+	assert.Equal(t, domain.RateLimit{Requests: 5000, Window: time.Hour}, policy.Plans[domain.PlanPremium])
+	assert.Equal(t, defaultPlanRateLimits[domain.PlanFree], policy.Plans[domain.PlanFree])
+}
+
+func TestNewPaymentProvider(t *testing.T) {
+	t.Setenv("STRIPE_WEBHOOK_SECRET", "")
+	assert.Nil(t, newPaymentProvider())
+
+	t.Setenv("STRIPE_WEBHOOK_SECRET", "whsec_test")
+	provider := newPaymentProvider()
+	assert.NotNil(t, provider)
+	assert.Equal(t, "Stripe-Signature", provider.SignatureHeader())
+}
+
+func TestSubscriptionGracePeriod(t *testing.T) {
+	t.Setenv("SUBSCRIPTION_GRACE_HOURS", "")
+	assert.Equal(t, usecases.DefaultSubscriptionGracePeriod, subscriptionGracePeriod())
+
+	t.Setenv("SUBSCRIPTION_GRACE_HOURS", "0")
+	assert.Equal(t, time.Duration(0), subscriptionGracePeriod())
+
+	t.Setenv("SUBSCRIPTION_GRACE_HOURS", "24")
+	assert.Equal(t, 24*time.Hour, subscriptionGracePeriod())
+}
+
+func TestIntervalMinutes(t *testing.T) {
+	assert.Equal(t, 5*time.Minute, intervalMinutes("SUBSCRIPTION_SWEEP_INTERVAL", 5))
+
+	for _, value := range []string{"0", "-3", "soon"} {
+		t.Setenv("SUBSCRIPTION_SWEEP_INTERVAL", value)
+		assert.Equal(t, 5*time.Minute, intervalMinutes("SUBSCRIPTION_SWEEP_INTERVAL", 5), value)
+	}
+
+	t.Setenv("SUBSCRIPTION_SWEEP_INTERVAL", "15")
+	assert.Equal(t, 15*time.Minute, intervalMinutes("SUBSCRIPTION_SWEEP_INTERVAL", 5))
+}
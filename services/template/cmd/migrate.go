@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	pg "github.com/nuhorizon/go-project-template/services/template/internal/infra/postgres"
+
+	"github.com/joho/godotenv"
+)
+
+var errMigrateUsage = errors.New(`usage: migrate <command>
+  up                     apply every pending migration
+  down [steps]           revert the last applied migrations (1 by default)
+  status                 list the migrations and when they were applied
+  create [-dir DIR] NAME write empty up and down files for the next migration`)
+
+// runMigrate runs the migrate subcommand with the arguments that follow it, reporting to out.
+// create only touches the source tree, the other commands use the PG_* database.
+func runMigrate(args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errMigrateUsage
+	}
+	command, args := args[0], args[1:]
+
+	steps := 1
+	switch command {
+	case "create":
+		return createMigration(args, out)
+	case "up", "status":
+		if len(args) > 0 {
+			return errMigrateUsage
+		}
+	case "down":
+		if len(args) > 1 {
+			return errMigrateUsage
+		}
+		if len(args) == 1 {
+			n, err := strconv.Atoi(args[0])
+			if err != nil || n < 1 {
+				return errMigrateUsage
+			}
+			steps = n
+		}
+	default:
+		return errMigrateUsage
+	}
+
+	// The variables may as well come from the environment, .env is optional here
+	_ = godotenv.Load()
+	db := pg.NewPGSql()
+	// The subcommand is the one deciding what gets applied
+	db.AutoMigrate = false
+	if err := db.InitDB(); err != nil {
+		return err
+	}
+	defer db.CloseDB()
+
+	migrator, err := pg.NewMigrator(db.GetDB(), pg.Migrations())
+	if err != nil {
+		return err
+	}
+	return migrate(context.Background(), migrator, command, steps, out)
+}
+
+// migrate runs up, down or status against the database of migrator
+func migrate(ctx context.Context, migrator *pg.Migrator, command string, steps int, out io.Writer) error {
+	switch command {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, migration := range applied {
+			fmt.Fprintln(out, "applied", migration)
+		}
+		if err == nil && len(applied) == 0 {
+			fmt.Fprintln(out, "schema is up to date")
+		}
+		return err
+	case "down":
+		reverted, err := migrator.Down(ctx, steps)
+		for _, migration := range reverted {
+			fmt.Fprintln(out, "reverted", migration)
+		}
+		if err == nil && len(reverted) == 0 {
+			fmt.Fprintln(out, "no migration to revert")
+		}
+		return err
+	default:
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "MIGRATION\tAPPLIED AT")
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if status.AppliedAt != nil {
+				appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(w, "%s\t%s\n", status.Migration, appliedAt)
+		}
+		return w.Flush()
+	}
+}
+
+func createMigration(args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("create", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	dir := flags.String("dir", pg.MigrationsDir, "directory of the migrations")
+	if err := flags.Parse(args); err != nil || flags.NArg() == 0 {
+		return errMigrateUsage
+	}
+
+	up, down, err := pg.CreateMigration(*dir, strings.Join(flags.Args(), " "))
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(out, "created", up)
+	fmt.Fprintln(out, "created", down)
+	return nil
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	pg "github.com/nuhorizon/go-project-template/services/template/internal/infra/postgres"
+)
+
+func TestRunMigrate_Usage(t *testing.T) {
+	for _, args := range [][]string{
+		nil,
+		{"sideways"},
+		{"up", "now"},
+		{"status", "all"},
+		{"down", "zero"},
+		{"down", "0"},
+		{"down", "1", "2"},
+		{"create"},
+		{"create", "-unknown", "name"},
+	} {
+		assert.ErrorIs(t, runMigrate(args, &bytes.Buffer{}), errMigrateUsage, "%v", args)
+	}
+}
+
+func TestRunMigrate_Create(t *testing.T) {
+	dir := t.TempDir()
+	var out bytes.Buffer
+
+	err := runMigrate([]string{"create", "-dir", dir, "add", "cat", "photos"}, &out)
+
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join(dir, "0001_add_cat_photos.up.sql"))
+	assert.FileExists(t, filepath.Join(dir, "0001_add_cat_photos.down.sql"))
+	assert.Contains(t, out.String(), "created "+filepath.Join(dir, "0001_add_cat_photos.up.sql"))
+}
+
+func TestMigrationsDir(t *testing.T) {
+	// `migrate create` runs from the service root, where the embedded migrations live
+	entries, err := os.ReadDir(filepath.Join("..", pg.MigrationsDir))
+	require.NoError(t, err)
+	assert.NotEmpty(t, entries)
+}
+
+func TestMigrate(t *testing.T) {
+	migrations := fstest.MapFS{
+		"0001_create_cats.up.sql":   {Data: []byte("CREATE TABLE cats (id UUID PRIMARY KEY);")},
+		"0001_create_cats.down.sql": {Data: []byte("DROP TABLE cats;")},
+	}
+	expectLocked := func(mock sqlmock.Sqlmock, rows *sqlmock.Rows) {
+		mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_lock($1)`)).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta(`CREATE TABLE IF NOT EXISTS schema_migrations`)).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT version, applied_at FROM schema_migrations`)).WillReturnRows(rows)
+	}
+
+	t.Run("up", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+		expectLocked(mock, sqlmock.NewRows([]string{"version", "applied_at"}))
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(`CREATE TABLE cats`)).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO schema_migrations`)).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+		mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_unlock($1)`)).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		migrator, err := pg.NewMigrator(db, migrations)
+		require.NoError(t, err)
+		var out bytes.Buffer
+
+		assert.NoError(t, migrate(context.Background(), migrator, "up", 1, &out))
+		assert.Equal(t, "applied 0001_create_cats\n", out.String())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("status", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+		expectLocked(mock, sqlmock.NewRows([]string{"version", "applied_at"}).AddRow(int64(1), time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)))
+		mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_unlock($1)`)).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		migrator, err := pg.NewMigrator(db, migrations)
+		require.NoError(t, err)
+		var out bytes.Buffer
+
+		assert.NoError(t, migrate(context.Background(), migrator, "status", 1, &out))
+		assert.Contains(t, out.String(), "0001_create_cats  2026-01-02 03:04:05")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,345 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/internal/models"
+	"github.com/nuhorizon/go-project-template/services/template/internal/usecases"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+)
+
+type AdminHandler interface {
+	AssignRole(w http.ResponseWriter, r *http.Request)
+	RemoveRole(w http.ResponseWriter, r *http.Request)
+	RevokeSessions(w http.ResponseWriter, r *http.Request)
+	ListUsers(w http.ResponseWriter, r *http.Request)
+	GetUser(w http.ResponseWriter, r *http.Request)
+	UpdatePlan(w http.ResponseWriter, r *http.Request)
+	DisableUser(w http.ResponseWriter, r *http.Request)
+	EnableUser(w http.ResponseWriter, r *http.Request)
+}
+
+type adminHandler struct {
+	adminUseCase usecases.AdminUseCase
+	validator    *validator.Validate
+}
+
+func NewAdminHandler(adminUC usecases.AdminUseCase) AdminHandler {
+	return &adminHandler{
+		adminUseCase: adminUC,
+		validator:    validator.New(),
+	}
+}
+
+// AssignRole godoc
+// @Summary Concede um papel (role) ao usuário
+// @Description Vincula o papel ao usuário. Os tokens já emitidos recebem o novo papel na próxima troca de refresh token.
+// @Tags Admin
+// @Security BearerAuth
+// @Param id path string true "ID do usuário"
+// @Param role path string true "Nome do papel"
+// @Success 204 "No Content"
+// @Failure 400 {string} string "Bad Request"
+// @Failure 403 {string} string "Forbidden"
+// @Failure 404 {string} string "Not Found"
+// @Failure 500 {string} string "Internal Server Error"
+// @Router /admin/users/{id}/roles/{role} [put]
+func (a *adminHandler) AssignRole(w http.ResponseWriter, r *http.Request) {
+	userID, ok := a.userIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	if err := a.adminUseCase.AssignRole(r.Context(), userID, chi.URLParam(r, "role")); err != nil {
+		a.handleError(w, "assign role", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RemoveRole godoc
+// @Summary Remove um papel (role) do usuário
+// @Description Desvincula o papel do usuário a partir da próxima troca de refresh token
+// @Tags Admin
+// @Security BearerAuth
+// @Param id path string true "ID do usuário"
+// @Param role path string true "Nome do papel"
+// @Success 204 "No Content"
+// @Failure 400 {string} string "Bad Request"
+// @Failure 403 {string} string "Forbidden"
+// @Failure 404 {string} string "Not Found"
+// @Failure 500 {string} string "Internal Server Error"
+// @Router /admin/users/{id}/roles/{role} [delete]
+func (a *adminHandler) RemoveRole(w http.ResponseWriter, r *http.Request) {
+	userID, ok := a.userIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	if err := a.adminUseCase.RemoveRole(r.Context(), userID, chi.URLParam(r, "role")); err != nil {
+		a.handleError(w, "remove role", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RevokeSessions godoc
+// @Summary Encerra todas as sessões do usuário
+// @Description Revoga todos os tokens de acesso e refresh tokens emitidos para o usuário
+// @Tags Admin
+// @Security BearerAuth
+// @Param id path string true "ID do usuário"
+// @Success 204 "No Content"
+// @Failure 400 {string} string "Bad Request"
+// @Failure 403 {string} string "Forbidden"
+// @Failure 404 {string} string "Not Found"
+// @Failure 500 {string} string "Internal Server Error"
+// @Router /admin/users/{id}/revoke-sessions [post]
+func (a *adminHandler) RevokeSessions(w http.ResponseWriter, r *http.Request) {
+	userID, ok := a.userIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	if err := a.adminUseCase.RevokeSessions(r.Context(), userID); err != nil {
+		a.handleError(w, "revoke sessions", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListUsers godoc
+// @Summary Lista os usuários com filtros, ordenação e paginação por cursor
+// @Description Retorna uma página de usuários. Para a próxima página envie o next_cursor recebido no parâmetro cursor, mantendo os mesmos filtros e ordenação.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param plan_type query string false "Plano (free, premium)"
+// @Param email_prefix query string false "Início do e-mail"
+// @Param created_after query string false "Criados a partir de (RFC 3339)"
+// @Param created_before query string false "Criados antes de (RFC 3339)"
+// @Param sort query string false "Ordenação: -created_at (padrão), created_at, email, -email"
+// @Param cursor query string false "Cursor da próxima página"
+// @Param limit query int false "Tamanho da página (padrão 50, máximo 100)"
+// @Success 200 {object} models.UserListResponse
+// @Failure 400 {string} string "Bad Request"
+// @Failure 403 {string} string "Forbidden"
+// @Failure 500 {string} string "Internal Server Error"
+// @Router /admin/users [get]
+func (a *adminHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseUserFilter(r)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	page, err := a.adminUseCase.ListUsers(r.Context(), filter)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidCursor) {
+			httpError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		a.handleError(w, "list users", err)
+		return
+	}
+
+	response := models.UserListResponse{
+		Users:      make([]models.AdminUserResponse, 0, len(page.Users)),
+		NextCursor: page.NextCursor,
+	}
+	for _, user := range page.Users {
+		response.Users = append(response.Users, toAdminUserResponse(&user))
+	}
+	httpSuccess(w, http.StatusOK, response)
+}
+
+// GetUser godoc
+// @Summary Retorna um usuário com seus papéis
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do usuário"
+// @Success 200 {object} models.AdminUserResponse
+// @Failure 400 {string} string "Bad Request"
+// @Failure 403 {string} string "Forbidden"
+// @Failure 404 {string} string "Not Found"
+// @Failure 500 {string} string "Internal Server Error"
+// @Router /admin/users/{id} [get]
+func (a *adminHandler) GetUser(w http.ResponseWriter, r *http.Request) {
+	userID, ok := a.userIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	user, err := a.adminUseCase.GetUser(r.Context(), userID)
+	if err != nil {
+		a.handleError(w, "get user", err)
+		return
+	}
+
+	httpSuccess(w, http.StatusOK, toAdminUserResponse(user))
+}
+
+// UpdatePlan godoc
+// @Summary Altera o plano do usuário
+// @Description Os tokens de acesso já emitidos deixam de valer; o novo plano vem na próxima troca de refresh token
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do usuário"
+// @Param plan body models.UpdatePlanRequest true "Plano e expiração"
+// @Success 200 {object} models.AdminUserResponse
+// @Failure 400 {string} string "Bad Request"
+// @Failure 403 {string} string "Forbidden"
+// @Failure 404 {string} string "Not Found"
+// @Failure 500 {string} string "Internal Server Error"
+// @Router /admin/users/{id}/plan [put]
+func (a *adminHandler) UpdatePlan(w http.ResponseWriter, r *http.Request) {
+	userID, ok := a.userIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.UpdatePlanRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpError(w, http.StatusBadRequest, "invalid request payload")
+		return
+	}
+
+	if err := a.validator.Struct(req); err != nil {
+		httpError(w, http.StatusBadRequest, "validation error")
+		return
+	}
+	if req.PlanExpiry != nil && !req.PlanExpiry.After(time.Now()) {
+		httpError(w, http.StatusBadRequest, "plan_expiry must be in the future")
+		return
+	}
+
+	user, err := a.adminUseCase.UpdatePlan(r.Context(), userID, req.PlanType, req.PlanExpiry)
+	if err != nil {
+		a.handleError(w, "update plan", err)
+		return
+	}
+
+	httpSuccess(w, http.StatusOK, toAdminUserResponse(user))
+}
+
+// DisableUser godoc
+// @Summary Desativa a conta do usuário
+// @Description Impede novos logins e encerra todas as sessões e tokens do usuário. As chaves de API deixam de funcionar enquanto a conta estiver desativada.
+// @Tags Admin
+// @Security BearerAuth
+// @Param id path string true "ID do usuário"
+// @Success 204 "No Content"
+// @Failure 400 {string} string "Bad Request"
+// @Failure 403 {string} string "Forbidden"
+// @Failure 404 {string} string "Not Found"
+// @Failure 500 {string} string "Internal Server Error"
+// @Router /admin/users/{id}/disable [post]
+func (a *adminHandler) DisableUser(w http.ResponseWriter, r *http.Request) {
+	userID, ok := a.userIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	if err := a.adminUseCase.DisableUser(r.Context(), userID); err != nil {
+		a.handleError(w, "disable user", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// EnableUser godoc
+// @Summary Reativa a conta do usuário
+// @Tags Admin
+// @Security BearerAuth
+// @Param id path string true "ID do usuário"
+// @Success 204 "No Content"
+// @Failure 400 {string} string "Bad Request"
+// @Failure 403 {string} string "Forbidden"
+// @Failure 404 {string} string "Not Found"
+// @Failure 500 {string} string "Internal Server Error"
+// @Router /admin/users/{id}/enable [post]
+func (a *adminHandler) EnableUser(w http.ResponseWriter, r *http.Request) {
+	userID, ok := a.userIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	if err := a.adminUseCase.EnableUser(r.Context(), userID); err != nil {
+		a.handleError(w, "enable user", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// parseUserFilter reads the filters of GET /admin/users from the query string
+func parseUserFilter(r *http.Request) (domain.UserFilter, error) {
+	query := r.URL.Query()
+	filter := domain.UserFilter{
+		PlanType:    query.Get("plan_type"),
+		EmailPrefix: query.Get("email_prefix"),
+		Sort:        query.Get("sort"),
+		Cursor:      query.Get("cursor"),
+	}
+
+	if filter.Sort != "" && !slices.Contains(domain.UserSorts, filter.Sort) {
+		return filter, errors.New("invalid sort")
+	}
+	if limit := query.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 || n > domain.MaxUserPageSize {
+			return filter, fmt.Errorf("limit must be between 1 and %d", domain.MaxUserPageSize)
+		}
+		filter.Limit = n
+	}
+	for name, target := range map[string]**time.Time{"created_after": &filter.CreatedAfter, "created_before": &filter.CreatedBefore} {
+		value := query.Get(name)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return filter, fmt.Errorf("%s must be an RFC 3339 timestamp", name)
+		}
+		*target = &t
+	}
+
+	return filter, nil
+}
+
+func (a *adminHandler) userIDParam(w http.ResponseWriter, r *http.Request) (string, bool) {
+	userID := chi.URLParam(r, "id")
+	if err := a.validator.Var(userID, "required,uuid"); err != nil {
+		httpError(w, http.StatusBadRequest, "invalid user id")
+		return "", false
+	}
+	return userID, true
+}
+
+func (a *adminHandler) handleError(w http.ResponseWriter, action string, err error) {
+	if errors.Is(err, domain.ErrUserNotFound) || errors.Is(err, domain.ErrRoleNotFound) {
+		httpError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if errors.Is(err, domain.ErrPlanNotFound) {
+		httpError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	httpError(w, http.StatusInternalServerError, fmt.Sprintf("%s failed: %v", action, err.Error()))
+}
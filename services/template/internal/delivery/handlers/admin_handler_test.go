@@ -0,0 +1,345 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/nuhorizon/go-project-template/services/template/internal/delivery/handlers"
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAdminUseCase struct {
+	mock.Mock
+}
+
+func (m *MockAdminUseCase) AssignRole(ctx context.Context, userID, role string) error {
+	args := m.Called(ctx, userID, role)
+	return args.Error(0)
+}
+
+func (m *MockAdminUseCase) RemoveRole(ctx context.Context, userID, role string) error {
+	args := m.Called(ctx, userID, role)
+	return args.Error(0)
+}
+
+func (m *MockAdminUseCase) RevokeSessions(ctx context.Context, userID string) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockAdminUseCase) ListUsers(ctx context.Context, filter domain.UserFilter) (*domain.UserPage, error) {
+	args := m.Called(ctx, filter)
+	page, _ := args.Get(0).(*domain.UserPage)
+	return page, args.Error(1)
+}
+
+func (m *MockAdminUseCase) GetUser(ctx context.Context, userID string) (*domain.User, error) {
+	args := m.Called(ctx, userID)
+	user, _ := args.Get(0).(*domain.User)
+	return user, args.Error(1)
+}
+
+func (m *MockAdminUseCase) UpdatePlan(ctx context.Context, userID, planType string, planExpiry *time.Time) (*domain.User, error) {
+	args := m.Called(ctx, userID, planType, planExpiry)
+	user, _ := args.Get(0).(*domain.User)
+	return user, args.Error(1)
+}
+
+func (m *MockAdminUseCase) DisableUser(ctx context.Context, userID string) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockAdminUseCase) EnableUser(ctx context.Context, userID string) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+const testUserID = "0b6f1f4e-6f2e-4c43-9d56-2b7f4bde7a10"
+
+// withURLParams simulates the chi router filling the path parameters
+func withURLParams(req *http.Request, params map[string]string) *http.Request {
+	rctx := chi.NewRouteContext()
+	for key, value := range params {
+		rctx.URLParams.Add(key, value)
+	}
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+}
+
+func TestAdminHandler_AssignRole(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         string
+		mockErr        error
+		expectCall     bool
+		expectedStatus int
+	}{
+		{"success", testUserID, nil, true, http.StatusNoContent},
+		{"invalid user id", "not-a-uuid", nil, false, http.StatusBadRequest},
+		{"unknown user", testUserID, domain.ErrUserNotFound, true, http.StatusNotFound},
+		{"unknown role", testUserID, domain.ErrRoleNotFound, true, http.StatusNotFound},
+		{"usecase failure", testUserID, errors.New("db error"), true, http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUC := new(MockAdminUseCase)
+			handler := handlers.NewAdminHandler(mockUC)
+
+			mockUC.On("AssignRole", mock.Anything, tt.userID, "admin").Return(tt.mockErr)
+
+			req := withURLParams(httptest.NewRequest(http.MethodPut, "/admin/users/"+tt.userID+"/roles/admin", nil),
+				map[string]string{"id": tt.userID, "role": "admin"})
+			rec := httptest.NewRecorder()
+
+			handler.AssignRole(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if !tt.expectCall {
+				mockUC.AssertNotCalled(t, "AssignRole", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestAdminHandler_RemoveRole(t *testing.T) {
+	mockUC := new(MockAdminUseCase)
+	handler := handlers.NewAdminHandler(mockUC)
+
+	mockUC.On("RemoveRole", mock.Anything, testUserID, "admin").Return(nil)
+
+	req := withURLParams(httptest.NewRequest(http.MethodDelete, "/admin/users/"+testUserID+"/roles/admin", nil),
+		map[string]string{"id": testUserID, "role": "admin"})
+	rec := httptest.NewRecorder()
+
+	handler.RemoveRole(rec, req)
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	mockUC.AssertExpectations(t)
+}
+
+func TestAdminHandler_RevokeSessions(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockErr        error
+		expectedStatus int
+	}{
+		{"success", nil, http.StatusNoContent},
+		{"unknown user", domain.ErrUserNotFound, http.StatusNotFound},
+		{"usecase failure", errors.New("db error"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUC := new(MockAdminUseCase)
+			handler := handlers.NewAdminHandler(mockUC)
+
+			mockUC.On("RevokeSessions", mock.Anything, testUserID).Return(tt.mockErr)
+
+			req := withURLParams(httptest.NewRequest(http.MethodPost, "/admin/users/"+testUserID+"/revoke-sessions", nil),
+				map[string]string{"id": testUserID})
+			rec := httptest.NewRecorder()
+
+			handler.RevokeSessions(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestAdminHandler_ListUsers(t *testing.T) {
+	createdAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		query          string
+		expectFilter   func(domain.UserFilter) bool
+		mockErr        error
+		expectedStatus int
+	}{
+		{
+			name:  "filters",
+			query: "?plan_type=premium&email_prefix=ann&created_after=2026-03-01T12:00:00Z&sort=email&limit=10&cursor=abc",
+			expectFilter: func(f domain.UserFilter) bool {
+				return f.PlanType == "premium" && f.EmailPrefix == "ann" && f.CreatedAfter.Equal(createdAt) &&
+					f.CreatedBefore == nil && f.Sort == domain.UserSortEmailAsc && f.Limit == 10 && f.Cursor == "abc"
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "no filters",
+			expectFilter:   func(f domain.UserFilter) bool { return f == domain.UserFilter{} },
+			expectedStatus: http.StatusOK,
+		},
+		{name: "unknown sort", query: "?sort=name", expectedStatus: http.StatusBadRequest},
+		{name: "limit too large", query: "?limit=1000", expectedStatus: http.StatusBadRequest},
+		{name: "invalid date", query: "?created_before=yesterday", expectedStatus: http.StatusBadRequest},
+		{
+			name:           "invalid cursor",
+			query:          "?cursor=abc",
+			expectFilter:   func(domain.UserFilter) bool { return true },
+			mockErr:        domain.ErrInvalidCursor,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "usecase failure",
+			expectFilter:   func(domain.UserFilter) bool { return true },
+			mockErr:        errors.New("db error"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUC := new(MockAdminUseCase)
+			handler := handlers.NewAdminHandler(mockUC)
+
+			if tt.expectFilter != nil {
+				var page *domain.UserPage
+				if tt.mockErr == nil {
+					page = &domain.UserPage{
+						Users:      []domain.User{{ID: "user-id", Email: "ann@example.com", CreatedAt: createdAt}},
+						NextCursor: "next-cursor",
+					}
+				}
+				mockUC.On("ListUsers", mock.Anything, mock.MatchedBy(tt.expectFilter)).Return(page, tt.mockErr)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/admin/users"+tt.query, nil)
+			rec := httptest.NewRecorder()
+
+			handler.ListUsers(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			mockUC.AssertExpectations(t)
+			if tt.expectedStatus == http.StatusOK {
+				var response models.UserListResponse
+				assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+				assert.Len(t, response.Users, 1)
+				assert.Equal(t, "ann@example.com", response.Users[0].Email)
+				assert.Equal(t, "next-cursor", response.NextCursor)
+			}
+		})
+	}
+}
+
+func TestAdminHandler_GetUser(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         string
+		mockErr        error
+		expectedStatus int
+	}{
+		{"success", testUserID, nil, http.StatusOK},
+		{"invalid user id", "not-a-uuid", nil, http.StatusBadRequest},
+		{"unknown user", testUserID, domain.ErrUserNotFound, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUC := new(MockAdminUseCase)
+			handler := handlers.NewAdminHandler(mockUC)
+
+			var user *domain.User
+			if tt.mockErr == nil {
+				user = &domain.User{ID: testUserID, Roles: []string{domain.RoleAdmin}}
+			}
+			mockUC.On("GetUser", mock.Anything, tt.userID).Return(user, tt.mockErr).Maybe()
+
+			req := withURLParams(httptest.NewRequest(http.MethodGet, "/admin/users/"+tt.userID, nil), map[string]string{"id": tt.userID})
+			rec := httptest.NewRecorder()
+
+			handler.GetUser(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedStatus == http.StatusOK {
+				assert.Contains(t, rec.Body.String(), `"roles":["admin"]`)
+			}
+		})
+	}
+}
+
+func TestAdminHandler_UpdatePlan(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         string
+		reqBody        string
+		callUseCase    bool
+		mockErr        error
+		expectedStatus int
+	}{
+		{"success", testUserID, `{"plan_type": "premium", "plan_expiry": "2999-01-01T00:00:00Z"}`, true, nil, http.StatusOK},
+		{"invalid user id", "not-a-uuid", `{"plan_type": "premium"}`, false, nil, http.StatusBadRequest},
+		{"unknown plan", testUserID, `{"plan_type": "gold"}`, true, domain.ErrPlanNotFound, http.StatusBadRequest},
+		{"missing plan", testUserID, `{}`, false, nil, http.StatusBadRequest},
+		{"expiry in the past", testUserID, `{"plan_type": "premium", "plan_expiry": "2000-01-01T00:00:00Z"}`, false, nil, http.StatusBadRequest},
+		{"invalid json", testUserID, `invalid-json`, false, nil, http.StatusBadRequest},
+		{"unknown user", testUserID, `{"plan_type": "free"}`, true, domain.ErrUserNotFound, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUC := new(MockAdminUseCase)
+			handler := handlers.NewAdminHandler(mockUC)
+
+			var user *domain.User
+			if tt.mockErr == nil {
+				user = &domain.User{ID: testUserID, PlanType: domain.PlanPremium}
+			}
+			mockUC.On("UpdatePlan", mock.Anything, tt.userID, mock.Anything, mock.Anything).Return(user, tt.mockErr)
+
+			req := withURLParams(httptest.NewRequest(http.MethodPut, "/admin/users/"+tt.userID+"/plan", bytes.NewBufferString(tt.reqBody)),
+				map[string]string{"id": tt.userID})
+			rec := httptest.NewRecorder()
+
+			handler.UpdatePlan(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if !tt.callUseCase {
+				mockUC.AssertNotCalled(t, "UpdatePlan", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestAdminHandler_DisableAndEnableUser(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         string
+		mockErr        error
+		expectedStatus int
+	}{
+		{"success", testUserID, nil, http.StatusNoContent},
+		{"invalid user id", "not-a-uuid", nil, http.StatusBadRequest},
+		{"unknown user", testUserID, domain.ErrUserNotFound, http.StatusNotFound},
+		{"usecase failure", testUserID, errors.New("db error"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUC := new(MockAdminUseCase)
+			handler := handlers.NewAdminHandler(mockUC)
+			mockUC.On("DisableUser", mock.Anything, tt.userID).Return(tt.mockErr).Maybe()
+			mockUC.On("EnableUser", mock.Anything, tt.userID).Return(tt.mockErr).Maybe()
+
+			for path, serve := range map[string]http.HandlerFunc{"disable": handler.DisableUser, "enable": handler.EnableUser} {
+				req := withURLParams(httptest.NewRequest(http.MethodPost, "/admin/users/"+tt.userID+"/"+path, nil), map[string]string{"id": tt.userID})
+				rec := httptest.NewRecorder()
+
+				serve(rec, req)
+
+				assert.Equal(t, tt.expectedStatus, rec.Code, path)
+			}
+		})
+	}
+}
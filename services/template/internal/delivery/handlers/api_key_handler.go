@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/internal/models"
+	"github.com/nuhorizon/go-project-template/services/template/internal/usecases"
+	"github.com/nuhorizon/go-project-template/services/template/pkg/middlewares"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+)
+
+type APIKeyHandler interface {
+	Create(w http.ResponseWriter, r *http.Request)
+	List(w http.ResponseWriter, r *http.Request)
+	Revoke(w http.ResponseWriter, r *http.Request)
+}
+
+type apiKeyHandler struct {
+	apiKeyUseCase usecases.APIKeyUseCase
+	validator     *validator.Validate
+}
+
+func NewAPIKeyHandler(apiKeyUC usecases.APIKeyUseCase) APIKeyHandler {
+	return &apiKeyHandler{
+		apiKeyUseCase: apiKeyUC,
+		validator:     validator.New(),
+	}
+}
+
+// Create godoc
+// @Summary Cria uma chave de API pessoal
+// @Description Gera uma chave restrita aos escopos informados (cats:read, cats:write, profile:read, profile:write ou permissões do usuário) para scripts e integrações, enviada em "Authorization: ApiKey <chave>". A chave é exibida uma única vez.
+// @Tags API Keys
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param apiKey body models.CreateAPIKeyRequest true "Nome, escopos e expiração"
+// @Success 201 {object} models.CreatedAPIKeyResponse
+// @Failure 400 {string} string "Bad Request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden: chaves de API não podem gerenciar chaves"
+// @Failure 500 {string} string "Internal Server Error"
+// @Router /api-keys [post]
+func (a *apiKeyHandler) Create(w http.ResponseWriter, r *http.Request) {
+	principal, ok := middlewares.PrincipalFromContext(r.Context())
+	if !ok {
+		httpError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req models.CreateAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpError(w, http.StatusBadRequest, "invalid request payload")
+		return
+	}
+
+	if err := a.validator.Struct(req); err != nil {
+		httpError(w, http.StatusBadRequest, "validation error")
+		return
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		httpError(w, http.StatusBadRequest, "expires_at must be in the future")
+		return
+	}
+
+	key, plain, err := a.apiKeyUseCase.Create(r.Context(), principal.UserID, req.Name, req.Scopes, req.ExpiresAt)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidAPIKeyScope):
+			httpError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, domain.ErrUserNotFound):
+			httpError(w, http.StatusNotFound, err.Error())
+		default:
+			httpError(w, http.StatusInternalServerError, fmt.Sprintf("create api key failed: %v", err.Error()))
+		}
+		return
+	}
+
+	httpSuccess(w, http.StatusCreated, models.CreatedAPIKeyResponse{
+		APIKeyResponse: toAPIKeyResponse(*key),
+		Key:            plain,
+	})
+}
+
+// List godoc
+// @Summary Lista as chaves de API do usuário autenticado
+// @Description Retorna as chaves (inclusive as revogadas) com prefixo, escopos e último uso, sem o valor da chave
+// @Tags API Keys
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.APIKeyResponse
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 500 {string} string "Internal Server Error"
+// @Router /api-keys [get]
+func (a *apiKeyHandler) List(w http.ResponseWriter, r *http.Request) {
+	principal, ok := middlewares.PrincipalFromContext(r.Context())
+	if !ok {
+		httpError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	keys, err := a.apiKeyUseCase.List(r.Context(), principal.UserID)
+	if err != nil {
+		httpError(w, http.StatusInternalServerError, fmt.Sprintf("list api keys failed: %v", err.Error()))
+		return
+	}
+
+	response := make([]models.APIKeyResponse, 0, len(keys))
+	for _, key := range keys {
+		response = append(response, toAPIKeyResponse(key))
+	}
+	httpSuccess(w, http.StatusOK, response)
+}
+
+// Revoke godoc
+// @Summary Revoga uma chave de API
+// @Description A chave deixa de autenticar requisições imediatamente
+// @Tags API Keys
+// @Security BearerAuth
+// @Param id path string true "ID da chave"
+// @Success 204 "No Content"
+// @Failure 400 {string} string "Bad Request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Not Found"
+// @Failure 500 {string} string "Internal Server Error"
+// @Router /api-keys/{id} [delete]
+func (a *apiKeyHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	principal, ok := middlewares.PrincipalFromContext(r.Context())
+	if !ok {
+		httpError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	keyID := chi.URLParam(r, "id")
+	if err := a.validator.Var(keyID, "required,uuid"); err != nil {
+		httpError(w, http.StatusBadRequest, "invalid api key id")
+		return
+	}
+
+	if err := a.apiKeyUseCase.Revoke(r.Context(), principal.UserID, keyID); err != nil {
+		if errors.Is(err, domain.ErrAPIKeyNotFound) {
+			httpError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		httpError(w, http.StatusInternalServerError, fmt.Sprintf("revoke api key failed: %v", err.Error()))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,177 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/delivery/handlers"
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAPIKeyUseCase struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyUseCase) Create(ctx context.Context, userID, name string, scopes []string, expiresAt *time.Time) (*domain.APIKey, string, error) {
+	args := m.Called(ctx, userID, name, scopes, expiresAt)
+	key, _ := args.Get(0).(*domain.APIKey)
+	return key, args.String(1), args.Error(2)
+}
+
+func (m *MockAPIKeyUseCase) List(ctx context.Context, userID string) ([]domain.APIKey, error) {
+	args := m.Called(ctx, userID)
+	keys, _ := args.Get(0).([]domain.APIKey)
+	return keys, args.Error(1)
+}
+
+func (m *MockAPIKeyUseCase) Revoke(ctx context.Context, userID, keyID string) error {
+	args := m.Called(ctx, userID, keyID)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyUseCase) AuthenticateAPIKey(ctx context.Context, key string) (*domain.Principal, error) {
+	args := m.Called(ctx, key)
+	principal, _ := args.Get(0).(*domain.Principal)
+	return principal, args.Error(1)
+}
+
+func TestAPIKeyHandler_Create(t *testing.T) {
+	tests := []struct {
+		name           string
+		reqBody        string
+		authenticated  bool
+		callUseCase    bool
+		mockErr        error
+		expectedStatus int
+	}{
+		{
+			name:           "success",
+			reqBody:        `{"name": "ci", "scopes": ["users:read"]}`,
+			authenticated:  true,
+			callUseCase:    true,
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "unauthenticated",
+			reqBody:        `{"name": "ci", "scopes": ["users:read"]}`,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "missing scopes",
+			reqBody:        `{"name": "ci", "scopes": []}`,
+			authenticated:  true,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "expiry in the past",
+			reqBody:        `{"name": "ci", "scopes": ["users:read"], "expires_at": "2000-01-01T00:00:00Z"}`,
+			authenticated:  true,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "scope not granted",
+			reqBody:        `{"name": "ci", "scopes": ["users:read"]}`,
+			authenticated:  true,
+			callUseCase:    true,
+			mockErr:        domain.ErrInvalidAPIKeyScope,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "usecase failure",
+			reqBody:        `{"name": "ci", "scopes": ["users:read"]}`,
+			authenticated:  true,
+			callUseCase:    true,
+			mockErr:        errors.New("db error"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUC := new(MockAPIKeyUseCase)
+			handler := handlers.NewAPIKeyHandler(mockUC)
+
+			if tt.callUseCase {
+				var key *domain.APIKey
+				if tt.mockErr == nil {
+					key = &domain.APIKey{ID: "key-id", Name: "ci", Prefix: "cwk_abcdefgh", Scopes: []string{"users:read"}}
+				}
+				mockUC.On("Create", mock.Anything, "user-id", "ci", []string{"users:read"}, (*time.Time)(nil)).
+					Return(key, "cwk_secret", tt.mockErr)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/api-keys", bytes.NewBufferString(tt.reqBody))
+			if tt.authenticated {
+				req = withUser(req)
+			}
+			rec := httptest.NewRecorder()
+
+			handler.Create(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedStatus == http.StatusCreated {
+				var response models.CreatedAPIKeyResponse
+				assert.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+				assert.Equal(t, "cwk_secret", response.Key)
+				assert.Equal(t, "cwk_abcdefgh", response.Prefix)
+			}
+			mockUC.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAPIKeyHandler_List(t *testing.T) {
+	mockUC := new(MockAPIKeyUseCase)
+	mockUC.On("List", mock.Anything, "user-id").
+		Return([]domain.APIKey{{ID: "key-id", Name: "ci", Prefix: "cwk_abcdefgh", KeyHash: "hash"}}, nil)
+	handler := handlers.NewAPIKeyHandler(mockUC)
+
+	rec := httptest.NewRecorder()
+	handler.List(rec, withUser(httptest.NewRequest(http.MethodGet, "/api-keys", nil)))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "hash")
+
+	var response []models.APIKeyResponse
+	assert.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+	if assert.Len(t, response, 1) {
+		assert.Equal(t, "key-id", response[0].ID)
+	}
+}
+
+func TestAPIKeyHandler_Revoke(t *testing.T) {
+	tests := []struct {
+		name           string
+		keyID          string
+		mockErr        error
+		expectedStatus int
+	}{
+		{"success", testUserID, nil, http.StatusNoContent},
+		{"invalid id", "not-a-uuid", nil, http.StatusBadRequest},
+		{"not found", testUserID, domain.ErrAPIKeyNotFound, http.StatusNotFound},
+		{"usecase failure", testUserID, errors.New("db error"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUC := new(MockAPIKeyUseCase)
+			mockUC.On("Revoke", mock.Anything, "user-id", tt.keyID).Return(tt.mockErr).Maybe()
+			handler := handlers.NewAPIKeyHandler(mockUC)
+
+			req := withUser(httptest.NewRequest(http.MethodDelete, "/api-keys/"+tt.keyID, nil))
+			rec := httptest.NewRecorder()
+			handler.Revoke(rec, withURLParams(req, map[string]string{"id": tt.keyID}))
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
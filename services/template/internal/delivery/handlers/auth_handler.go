@@ -2,44 +2,70 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log"
+	"math"
+	"net"
 	"net/http"
 
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
 	"github.com/nuhorizon/go-project-template/services/template/internal/models"
 	"github.com/nuhorizon/go-project-template/services/template/internal/usecases"
+	"github.com/nuhorizon/go-project-template/services/template/pkg/middlewares"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/go-playground/validator/v10"
 )
 
 type AuthHandler interface {
 	Login(w http.ResponseWriter, r *http.Request)
 	Register(w http.ResponseWriter, r *http.Request)
+	DirectLogin(w http.ResponseWriter, r *http.Request)
+	VerifyMFA(w http.ResponseWriter, r *http.Request)
+	RequestMagicLink(w http.ResponseWriter, r *http.Request)
+	VerifyMagicLink(w http.ResponseWriter, r *http.Request)
+	SendEmailVerification(w http.ResponseWriter, r *http.Request)
+	VerifyEmail(w http.ResponseWriter, r *http.Request)
 	ResetPassword(w http.ResponseWriter, r *http.Request)
 	ExchangeToken(w http.ResponseWriter, r *http.Request)
+	Logout(w http.ResponseWriter, r *http.Request)
+	LinkIdentity(w http.ResponseWriter, r *http.Request)
+	ListIdentities(w http.ResponseWriter, r *http.Request)
+	ListSessions(w http.ResponseWriter, r *http.Request)
+	RevokeSession(w http.ResponseWriter, r *http.Request)
 }
 
 type authHandler struct {
 	authUseCase usecases.AuthUseCase
+	loginGuard  usecases.LoginGuard
 	validator   *validator.Validate
 }
 
-func NewAuthHandler(authUC usecases.AuthUseCase) AuthHandler {
+func NewAuthHandler(authUC usecases.AuthUseCase, loginGuard usecases.LoginGuard) AuthHandler {
 	return &authHandler{
 		authUseCase: authUC,
+		loginGuard:  loginGuard,
 		validator:   validator.New(),
 	}
 }
 
 // Login godoc
-// @Summary Realiza o login e sincronização do usuário com Firebase
-// @Description Recebe o token do Firebase, valida, sincroniza e retorna o token da aplicação
+// @Summary Realiza o login e sincronização do usuário com Firebase ou outro provedor OpenID Connect
+// @Description Recebe o token do Firebase (ou o ID token do provedor informado), valida, sincroniza e retorna o token da aplicação
 // @Tags Auth
 // @Accept json
 // @Produce json
-// @Param login body models.LoginRequest true "Firebase Token"
+// @Param login body models.LoginRequest true "Firebase Token ou ID token"
 // @Success 200 {object} models.LoginResponse
 // @Failure 400 {string} string "Bad Request"
 // @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden: conta desativada por um administrador"
+// @Failure 409 {string} string "Conflict: o e-mail pertence a outra conta e o provedor não o verificou"
+// @Failure 429 {string} string "Too Many Requests: muitas tentativas com falha a partir deste IP"
+// @Failure 500 {string} string "Internal Server Error"
+// @Failure 501 {string} string "Not Implemented: login pelo Firebase não configurado"
 // @Router /auth/login [post]
 func (a *authHandler) Login(w http.ResponseWriter, r *http.Request) {
 	var req models.LoginRequest
@@ -53,41 +79,355 @@ func (a *authHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user, token, err := a.authUseCase.LoginOrRegister(r.Context(), req.FirebaseToken)
-	if err != nil {
-		httpError(w, http.StatusUnauthorized, fmt.Sprintf("invalid token: %v", err.Error()))
+	provider, token := domain.ProviderFirebase, req.FirebaseToken
+	if req.IDToken != "" {
+		provider, token = req.Provider, req.IDToken
+	}
+
+	ip := clientIP(r)
+	if !a.allowAttempt(w, r, ip, "") {
 		return
 	}
 
-	response := models.LoginResponse{
-		Token: token,
-		User: models.UserResponse{
-			ID:         user.ID,
-			Name:       user.Name,
-			Email:      user.Email,
-			PictureURL: user.PictureURL,
-			PlanType:   user.PlanType,
-		},
+	user, tokens, err := a.authUseCase.LoginOrRegister(r.Context(), provider, token)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrUnknownIdentityProvider), errors.Is(err, domain.ErrIdentityEmailMissing):
+			httpError(w, http.StatusBadRequest, err.Error())
+			return
+		case errors.Is(err, domain.ErrIdentityLinkRequired):
+			httpError(w, http.StatusConflict, err.Error())
+			return
+		case errors.Is(err, domain.ErrUserDisabled):
+			httpError(w, http.StatusForbidden, err.Error())
+			return
+		case errors.Is(err, domain.ErrFirebaseDisabled):
+			httpError(w, http.StatusNotImplemented, err.Error())
+			return
+		case errors.Is(err, domain.ErrInvalidIdentityToken):
+			// Only rejected tokens are guesses, an outage must not lock the clients out
+			a.recordFailure(r, ip, "")
+			httpError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+		httpError(w, http.StatusInternalServerError, fmt.Sprintf("login failed: %v", err.Error()))
+		return
 	}
 
-	httpSuccess(w, http.StatusOK, response)
+	httpSuccess(w, http.StatusOK, toLoginResponse(user, tokens))
 }
 
-// Register - (Opcional) Se suportar registro direto sem Firebase
+// Register godoc
+// @Summary Registra um usuário com e-mail e senha (sem Firebase)
+// @Description Cria o usuário com a senha em hash e retorna o token da aplicação. Um link de verificação é enviado ao e-mail informado.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param register body models.RegisterRequest true "Dados de cadastro"
+// @Success 201 {object} models.LoginResponse
+// @Failure 400 {string} string "Bad Request"
+// @Failure 409 {string} string "Conflict"
+// @Failure 500 {string} string "Internal Server Error"
+// @Router /auth/register [post]
 func (a *authHandler) Register(w http.ResponseWriter, r *http.Request) {
-	httpError(w, http.StatusNotImplemented, "not implemented")
+	var req models.RegisterRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpError(w, http.StatusBadRequest, "invalid request payload")
+		return
+	}
+
+	if err := a.validator.Struct(req); err != nil {
+		httpError(w, http.StatusBadRequest, "validation error")
+		return
+	}
+
+	user, tokens, err := a.authUseCase.Register(r.Context(), req.Name, req.Email, req.Password)
+	if err != nil {
+		if errors.Is(err, domain.ErrEmailAlreadyRegistered) {
+			httpError(w, http.StatusConflict, err.Error())
+			return
+		}
+		httpError(w, http.StatusInternalServerError, fmt.Sprintf("register failed: %v", err.Error()))
+		return
+	}
+
+	// The account works without the e-mail, the user can ask for another link later
+	if err := a.authUseCase.SendEmailVerification(r.Context(), user.ID); err != nil {
+		log.Printf("send email verification failed: %v", err)
+	}
+
+	httpSuccess(w, http.StatusCreated, toLoginResponse(user, tokens))
+}
+
+// DirectLogin godoc
+// @Summary Realiza o login com e-mail e senha (sem Firebase)
+// @Description Valida as credenciais do usuário registrado diretamente e retorna o token da aplicação. Com o segundo fator ativo, retorna 202 e o mfa_token a ser enviado para /auth/mfa/verify.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param login body models.DirectLoginRequest true "Credenciais"
+// @Success 200 {object} models.LoginResponse
+// @Success 202 {object} models.MFAChallengeResponse
+// @Failure 400 {string} string "Bad Request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden: conta desativada por um administrador"
+// @Failure 429 {string} string "Too Many Requests: IP ou conta bloqueados temporariamente após falhas seguidas"
+// @Failure 500 {string} string "Internal Server Error"
+// @Router /auth/direct-login [post]
+func (a *authHandler) DirectLogin(w http.ResponseWriter, r *http.Request) {
+	var req models.DirectLoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpError(w, http.StatusBadRequest, "invalid request payload")
+		return
+	}
+
+	if err := a.validator.Struct(req); err != nil {
+		httpError(w, http.StatusBadRequest, "validation error")
+		return
+	}
+
+	ip := clientIP(r)
+	if !a.allowAttempt(w, r, ip, req.Email) {
+		return
+	}
+
+	user, tokens, err := a.authUseCase.LoginWithPassword(r.Context(), req.Email, req.Password)
+	if err != nil {
+		if writeMFAChallenge(w, err) {
+			return
+		}
+		if errors.Is(err, domain.ErrUserDisabled) {
+			httpError(w, http.StatusForbidden, err.Error())
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidCredentials) {
+			a.recordFailure(r, ip, req.Email)
+			httpError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+		httpError(w, http.StatusInternalServerError, fmt.Sprintf("login failed: %v", err.Error()))
+		return
+	}
+
+	a.resetFailures(r, req.Email)
+	httpSuccess(w, http.StatusOK, toLoginResponse(user, tokens))
+}
+
+// VerifyMFA godoc
+// @Summary Conclui o login direto com o segundo fator
+// @Description Recebe o mfa_token do login direto e um código TOTP (ou de recuperação) e retorna o token da aplicação
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param verify body models.MFAVerifyRequest true "mfa_token e código"
+// @Success 200 {object} models.LoginResponse
+// @Failure 400 {string} string "Bad Request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden: conta desativada por um administrador"
+// @Failure 429 {string} string "Too Many Requests: muitas tentativas com falha a partir deste IP ou para este usuário"
+// @Failure 500 {string} string "Internal Server Error"
+// @Router /auth/mfa/verify [post]
+func (a *authHandler) VerifyMFA(w http.ResponseWriter, r *http.Request) {
+	var req models.MFAVerifyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpError(w, http.StatusBadRequest, "invalid request payload")
+		return
+	}
+
+	if err := a.validator.Struct(req); err != nil {
+		httpError(w, http.StatusBadRequest, "validation error")
+		return
+	}
+
+	ip := clientIP(r)
+	if !a.allowAttempt(w, r, ip, "") {
+		return
+	}
+
+	userID, err := a.authUseCase.PendingMFAUser(req.MFAToken)
+	if err != nil {
+		a.recordFailure(r, ip, "")
+		httpError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+	// Codes are guessed against the user of the token, whatever addresses the guesses come from
+	account := "mfa:" + userID
+	if !a.allowAttempt(w, r, "", account) {
+		return
+	}
+
+	user, tokens, err := a.authUseCase.CompleteMFALogin(r.Context(), req.MFAToken, req.Code)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserDisabled) {
+			httpError(w, http.StatusForbidden, err.Error())
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidMFAToken) || errors.Is(err, domain.ErrInvalidMFACode) || errors.Is(err, domain.ErrMFANotEnabled) {
+			a.recordFailure(r, ip, account)
+			httpError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+		httpError(w, http.StatusInternalServerError, fmt.Sprintf("mfa verification failed: %v", err.Error()))
+		return
+	}
+
+	a.resetFailures(r, user.Email)
+	a.resetFailures(r, account)
+	httpSuccess(w, http.StatusOK, toLoginResponse(user, tokens))
+}
+
+// RequestMagicLink godoc
+// @Summary Envia um link de login sem senha por e-mail
+// @Description Envia ao e-mail um link de uso único, válido por 15 minutos, que conclui o login em /auth/magic-link/verify. A conta é criada no primeiro acesso. A resposta é a mesma exista ou não uma conta com o e-mail.
+// @Tags Auth
+// @Accept json
+// @Param magicLink body models.MagicLinkRequest true "E-mail"
+// @Success 204 "No Content"
+// @Failure 400 {string} string "Bad Request"
+// @Failure 429 {string} string "Too Many Requests"
+// @Router /auth/magic-link [post]
+func (a *authHandler) RequestMagicLink(w http.ResponseWriter, r *http.Request) {
+	var req models.MagicLinkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpError(w, http.StatusBadRequest, "invalid request payload")
+		return
+	}
+
+	if err := a.validator.Struct(req); err != nil {
+		httpError(w, http.StatusBadRequest, "validation error")
+		return
+	}
+
+	// Like password resets, every request mails the address and is throttled
+	if !a.allowSend(w, r, "magic-link", req.Email) {
+		return
+	}
+
+	if err := a.authUseCase.RequestMagicLink(r.Context(), req.Email); err != nil {
+		log.Printf("request magic link failed: %v", err)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// VerifyMagicLink godoc
+// @Summary Conclui o login sem senha com o token do link enviado por e-mail
+// @Description Valida e consome o token do link e retorna o token da aplicação. Com o segundo fator ativo, retorna 202 e o mfa_token a ser enviado para /auth/mfa/verify.
+// @Tags Auth
+// @Produce json
+// @Param token query string true "Token do link"
+// @Success 200 {object} models.LoginResponse
+// @Success 202 {object} models.MFAChallengeResponse
+// @Failure 400 {string} string "Bad Request"
+// @Failure 401 {string} string "Unauthorized: link inválido, expirado ou já utilizado"
+// @Failure 403 {string} string "Forbidden: conta desativada por um administrador"
+// @Failure 429 {string} string "Too Many Requests: muitas tentativas com falha a partir deste IP"
+// @Failure 500 {string} string "Internal Server Error"
+// @Router /auth/magic-link/verify [get]
+func (a *authHandler) VerifyMagicLink(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		httpError(w, http.StatusBadRequest, "token is required")
+		return
+	}
+
+	ip := clientIP(r)
+	if !a.allowAttempt(w, r, ip, "") {
+		return
+	}
+
+	user, tokens, err := a.authUseCase.LoginWithMagicLink(r.Context(), token)
+	if err != nil {
+		if writeMFAChallenge(w, err) {
+			return
+		}
+		if errors.Is(err, domain.ErrUserDisabled) {
+			httpError(w, http.StatusForbidden, err.Error())
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidMagicLink) {
+			a.recordFailure(r, ip, "")
+			httpError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+		httpError(w, http.StatusInternalServerError, fmt.Sprintf("login failed: %v", err.Error()))
+		return
+	}
+
+	// Following the link proves the mailbox, which clears the failed logins of the account and the
+	// throttle its link requests built up
+	a.resetFailures(r, user.Email)
+	a.resetFailures(r, "magic-link:"+user.Email)
+	httpSuccess(w, http.StatusOK, toLoginResponse(user, tokens))
+}
+
+// SendEmailVerification godoc
+// @Summary Reenvia o link de verificação de e-mail do usuário autenticado
+// @Description Envia um novo link de verificação, válido por 24 horas. Depois de verificar o e-mail, renove o token (/auth/exchange-token) para acessar as rotas que exigem e-mail verificado.
+// @Tags Auth
+// @Security BearerAuth
+// @Success 204 "No Content"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 409 {string} string "Conflict: e-mail já verificado"
+// @Failure 500 {string} string "Internal Server Error"
+// @Router /auth/verify-email [post]
+func (a *authHandler) SendEmailVerification(w http.ResponseWriter, r *http.Request) {
+	principal, ok := middlewares.PrincipalFromContext(r.Context())
+	if !ok {
+		httpError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	if err := a.authUseCase.SendEmailVerification(r.Context(), principal.UserID); err != nil {
+		if errors.Is(err, domain.ErrEmailAlreadyVerified) {
+			httpError(w, http.StatusConflict, err.Error())
+			return
+		}
+		httpError(w, http.StatusInternalServerError, fmt.Sprintf("send email verification failed: %v", err.Error()))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// VerifyEmail godoc
+// @Summary Confirma o e-mail com o token do link de verificação
+// @Tags Auth
+// @Param token query string true "Token do link"
+// @Success 204 "No Content"
+// @Failure 400 {string} string "Bad Request: link inválido ou expirado"
+// @Failure 500 {string} string "Internal Server Error"
+// @Router /auth/verify-email/confirm [get]
+func (a *authHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		httpError(w, http.StatusBadRequest, "token is required")
+		return
+	}
+
+	if err := a.authUseCase.VerifyEmail(r.Context(), token); err != nil {
+		if errors.Is(err, domain.ErrInvalidEmailVerificationLink) || errors.Is(err, domain.ErrUserNotFound) {
+			httpError(w, http.StatusBadRequest, domain.ErrInvalidEmailVerificationLink.Error())
+			return
+		}
+		httpError(w, http.StatusInternalServerError, fmt.Sprintf("verify email failed: %v", err.Error()))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
 }
 
 // ResetPassword godoc
 // @Summary Envia o e-mail de recuperação de senha via Firebase
-// @Description Recebe o e-mail e dispara o fluxo de reset de senha
+// @Description Recebe o e-mail e dispara o fluxo de reset de senha. A resposta é a mesma exista ou não uma conta com o e-mail.
 // @Tags Auth
 // @Accept json
 // @Produce json
 // @Param resetPassword body models.ResetPasswordRequest true "Email para reset de senha"
 // @Success 204 "No Content"
 // @Failure 400 {string} string "Bad Request"
-// @Failure 500 {string} string "Internal Server Error"
+// @Failure 429 {string} string "Too Many Requests"
+// @Failure 501 {string} string "Not Implemented: Firebase não configurado"
 // @Router /auth/reset-password [post]
 func (a *authHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
 	var req models.ResetPasswordRequest
@@ -101,25 +441,312 @@ func (a *authHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := a.authUseCase.ResetPassword(r.Context(), req.Email); err != nil {
-		httpError(w, http.StatusInternalServerError, fmt.Sprintf("reset password failed: %v", err.Error()))
+	// Every reset request mails the account owner and must not be spammed
+	if !a.allowSend(w, r, "reset", req.Email) {
 		return
 	}
 
+	// Unknown e-mails and delivery failures get the same answer as a sent e-mail,
+	// otherwise the endpoint tells which addresses have an account
+	if err := a.authUseCase.ResetPassword(r.Context(), req.Email); err != nil {
+		if errors.Is(err, domain.ErrFirebaseDisabled) {
+			httpError(w, http.StatusNotImplemented, err.Error())
+			return
+		}
+		log.Printf("reset password failed: %v", err)
+	}
+
 	w.WriteHeader(http.StatusNoContent)
 }
 
 // ExchangeToken godoc
-// @Summary (Opcional) Troca o token de login por um novo token da aplicação
-// @Description Recebe um token de refresh ou de terceiro e retorna o token da aplicação
+// @Summary Troca o refresh token por um novo par de tokens da aplicação
+// @Description Recebe o refresh token, rotaciona e retorna um novo token de acesso e refresh token. Reutilizar um refresh token já trocado revoga toda a sessão.
 // @Tags Auth
 // @Accept json
 // @Produce json
-// @Param exchange body models.ExchangeTokenRequest true "Token para troca"
+// @Param exchange body models.ExchangeTokenRequest true "Refresh token"
 // @Success 200 {object} models.LoginResponse
 // @Failure 400 {string} string "Bad Request"
 // @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden: conta desativada por um administrador"
+// @Failure 500 {string} string "Internal Server Error"
 // @Router /auth/exchange-token [post]
 func (a *authHandler) ExchangeToken(w http.ResponseWriter, r *http.Request) {
-	httpError(w, http.StatusNotImplemented, "not implemented")
+	var req models.ExchangeTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpError(w, http.StatusBadRequest, "invalid request payload")
+		return
+	}
+
+	if err := a.validator.Struct(req); err != nil {
+		httpError(w, http.StatusBadRequest, "validation error")
+		return
+	}
+
+	user, tokens, err := a.authUseCase.ExchangeToken(r.Context(), req.RefreshToken)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserDisabled) {
+			httpError(w, http.StatusForbidden, err.Error())
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidRefreshToken) || errors.Is(err, domain.ErrRefreshTokenReused) {
+			httpError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+		httpError(w, http.StatusInternalServerError, fmt.Sprintf("exchange token failed: %v", err.Error()))
+		return
+	}
+
+	httpSuccess(w, http.StatusOK, toLoginResponse(user, tokens))
+}
+
+// Logout godoc
+// @Summary Encerra a sessão atual revogando os tokens
+// @Description Revoga o token de acesso em uso e, se enviado, o refresh token da sessão. Com all_sessions revoga todas as sessões do usuário.
+// @Tags Auth
+// @Accept json
+// @Security BearerAuth
+// @Param logout body models.LogoutRequest false "Refresh token da sessão"
+// @Success 204 "No Content"
+// @Failure 400 {string} string "Bad Request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 500 {string} string "Internal Server Error"
+// @Router /auth/logout [post]
+func (a *authHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	principal, ok := middlewares.PrincipalFromContext(r.Context())
+	if !ok {
+		httpError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req models.LogoutRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		httpError(w, http.StatusBadRequest, "invalid request payload")
+		return
+	}
+
+	if err := a.authUseCase.Logout(r.Context(), principal, req.RefreshToken, req.AllSessions); err != nil {
+		if errors.Is(err, domain.ErrInvalidRefreshToken) {
+			httpError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		httpError(w, http.StatusInternalServerError, fmt.Sprintf("logout failed: %v", err.Error()))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// LinkIdentity godoc
+// @Summary Vincula outro provedor de identidade à conta do usuário autenticado
+// @Description Valida o ID token do provedor e o vincula ao usuário, que passa a poder entrar na mesma conta por esse provedor
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param link body models.LinkIdentityRequest true "Provedor e ID token"
+// @Success 201 {object} models.IdentityResponse
+// @Failure 400 {string} string "Bad Request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 409 {string} string "Conflict: a identidade já está vinculada a outra conta"
+// @Failure 500 {string} string "Internal Server Error"
+// @Router /auth/identities [post]
+func (a *authHandler) LinkIdentity(w http.ResponseWriter, r *http.Request) {
+	principal, ok := middlewares.PrincipalFromContext(r.Context())
+	if !ok {
+		httpError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req models.LinkIdentityRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpError(w, http.StatusBadRequest, "invalid request payload")
+		return
+	}
+
+	if err := a.validator.Struct(req); err != nil {
+		httpError(w, http.StatusBadRequest, "validation error")
+		return
+	}
+
+	identity, err := a.authUseCase.LinkIdentity(r.Context(), principal.UserID, req.Provider, req.IDToken)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrUnknownIdentityProvider):
+			httpError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, domain.ErrIdentityAlreadyLinked):
+			httpError(w, http.StatusConflict, err.Error())
+		case errors.Is(err, domain.ErrFirebaseDisabled):
+			httpError(w, http.StatusNotImplemented, err.Error())
+		default:
+			httpError(w, http.StatusUnauthorized, fmt.Sprintf("invalid token: %v", err.Error()))
+		}
+		return
+	}
+
+	httpSuccess(w, http.StatusCreated, toIdentityResponse(*identity))
+}
+
+// ListIdentities godoc
+// @Summary Lista os provedores de identidade vinculados ao usuário autenticado
+// @Tags Auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.IdentityResponse
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 500 {string} string "Internal Server Error"
+// @Router /auth/identities [get]
+func (a *authHandler) ListIdentities(w http.ResponseWriter, r *http.Request) {
+	principal, ok := middlewares.PrincipalFromContext(r.Context())
+	if !ok {
+		httpError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	identities, err := a.authUseCase.ListIdentities(r.Context(), principal.UserID)
+	if err != nil {
+		httpError(w, http.StatusInternalServerError, fmt.Sprintf("list identities failed: %v", err.Error()))
+		return
+	}
+
+	response := make([]models.IdentityResponse, 0, len(identities))
+	for _, identity := range identities {
+		response = append(response, toIdentityResponse(identity))
+	}
+	httpSuccess(w, http.StatusOK, response)
+}
+
+// ListSessions godoc
+// @Summary Lista as sessões ativas (dispositivos) do usuário autenticado
+// @Description Retorna as sessões com refresh token ainda válido, da mais recente para a mais antiga. A sessão da requisição vem marcada com current.
+// @Tags Auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.SessionResponse
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 500 {string} string "Internal Server Error"
+// @Router /auth/sessions [get]
+func (a *authHandler) ListSessions(w http.ResponseWriter, r *http.Request) {
+	principal, ok := middlewares.PrincipalFromContext(r.Context())
+	if !ok {
+		httpError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	sessions, err := a.authUseCase.ListSessions(r.Context(), principal.UserID)
+	if err != nil {
+		httpError(w, http.StatusInternalServerError, fmt.Sprintf("list sessions failed: %v", err.Error()))
+		return
+	}
+
+	response := make([]models.SessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		response = append(response, toSessionResponse(session, principal.SessionID))
+	}
+	httpSuccess(w, http.StatusOK, response)
+}
+
+// RevokeSession godoc
+// @Summary Encerra uma sessão do usuário autenticado
+// @Description Revoga os refresh tokens da sessão e os tokens de acesso já emitidos para ela, desconectando o dispositivo
+// @Tags Auth
+// @Security BearerAuth
+// @Param id path string true "ID da sessão"
+// @Success 204 "No Content"
+// @Failure 400 {string} string "Bad Request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Not Found"
+// @Failure 500 {string} string "Internal Server Error"
+// @Router /auth/sessions/{id} [delete]
+func (a *authHandler) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	principal, ok := middlewares.PrincipalFromContext(r.Context())
+	if !ok {
+		httpError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	sessionID := chi.URLParam(r, "id")
+	if err := a.validator.Var(sessionID, "required,uuid"); err != nil {
+		httpError(w, http.StatusBadRequest, "invalid session id")
+		return
+	}
+
+	if err := a.authUseCase.RevokeSession(r.Context(), principal.UserID, sessionID); err != nil {
+		if errors.Is(err, domain.ErrSessionNotFound) {
+			httpError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		httpError(w, http.StatusInternalServerError, fmt.Sprintf("revoke session failed: %v", err.Error()))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeMFAChallenge answers 202 with the "mfa_pending" token when err asks for the second factor
+func writeMFAChallenge(w http.ResponseWriter, err error) bool {
+	var mfaErr *domain.MFARequiredError
+	if !errors.As(err, &mfaErr) {
+		return false
+	}
+
+	httpSuccess(w, http.StatusAccepted, models.MFAChallengeResponse{
+		MFARequired: true,
+		MFAToken:    mfaErr.Token,
+		ExpiresAt:   mfaErr.ExpiresAt,
+	})
+	return true
+}
+
+// allowAttempt rejects the request with 429 while the client IP or the account is locked out
+func (a *authHandler) allowAttempt(w http.ResponseWriter, r *http.Request, ip, account string) bool {
+	err := a.loginGuard.Check(r.Context(), ip, account)
+	if err == nil {
+		return true
+	}
+
+	var lockedErr *domain.LockedOutError
+	if errors.As(err, &lockedErr) {
+		w.Header().Set("Retry-After", fmt.Sprint(int(math.Ceil(lockedErr.RetryAfter.Seconds()))))
+		httpError(w, http.StatusTooManyRequests, err.Error())
+		return false
+	}
+	httpError(w, http.StatusInternalServerError, fmt.Sprintf("login attempt check failed: %v", err.Error()))
+	return false
+}
+
+// recordFailure counts a failed attempt. A store error must not change the answer to the
+// client, the attempt itself already failed.
+func (a *authHandler) recordFailure(r *http.Request, ip, account string) {
+	if err := a.loginGuard.Fail(r.Context(), ip, account); err != nil {
+		log.Printf("record failed login attempt: %v", err)
+	}
+}
+
+// allowSend throttles the e-mails a flow sends to an address, rejecting the request with 429
+// once too many were sent. Sends have their own "<flow>:<email>" counter, they are not failed
+// logins and must not lock the account out.
+func (a *authHandler) allowSend(w http.ResponseWriter, r *http.Request, flow, email string) bool {
+	key := flow + ":" + email
+	if !a.allowAttempt(w, r, "", key) {
+		return false
+	}
+	a.recordFailure(r, "", key)
+	return true
+}
+
+func (a *authHandler) resetFailures(r *http.Request, account string) {
+	if err := a.loginGuard.Reset(r.Context(), account); err != nil {
+		log.Printf("reset login attempts: %v", err)
+	}
+}
+
+// clientIP is the address resolved by chi's RealIP middleware (X-Real-IP / X-Forwarded-For),
+// which replaces RemoteAddr before the handlers run
+func clientIP(r *http.Request) string {
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
 }
@@ -4,12 +4,15 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/nuhorizon/go-project-template/services/template/internal/delivery/handlers"
 	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/pkg/middlewares"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -19,13 +22,111 @@ type MockAuthUseCase struct {
 	mock.Mock
 }
 
-func (m *MockAuthUseCase) LoginOrRegister(ctx context.Context, firebaseToken string) (*domain.User, string, error) {
-	args := m.Called(ctx, firebaseToken)
+func (m *MockAuthUseCase) LoginOrRegister(ctx context.Context, provider, idToken string) (*domain.User, *domain.TokenPair, error) {
+	args := m.Called(ctx, provider, idToken)
 	user := args.Get(0)
 	if user == nil {
-		return nil, "", args.Error(2)
+		return nil, nil, args.Error(2)
 	}
-	return user.(*domain.User), args.String(1), args.Error(2)
+	return user.(*domain.User), args.Get(1).(*domain.TokenPair), args.Error(2)
+}
+
+func (m *MockAuthUseCase) LinkIdentity(ctx context.Context, userID, provider, idToken string) (*domain.Identity, error) {
+	args := m.Called(ctx, userID, provider, idToken)
+	identity, _ := args.Get(0).(*domain.Identity)
+	return identity, args.Error(1)
+}
+
+func (m *MockAuthUseCase) ListIdentities(ctx context.Context, userID string) ([]domain.Identity, error) {
+	args := m.Called(ctx, userID)
+	identities, _ := args.Get(0).([]domain.Identity)
+	return identities, args.Error(1)
+}
+
+func (m *MockAuthUseCase) Register(ctx context.Context, name, email, password string) (*domain.User, *domain.TokenPair, error) {
+	args := m.Called(ctx, name, email, password)
+	user := args.Get(0)
+	if user == nil {
+		return nil, nil, args.Error(2)
+	}
+	return user.(*domain.User), args.Get(1).(*domain.TokenPair), args.Error(2)
+}
+
+func (m *MockAuthUseCase) LoginWithPassword(ctx context.Context, email, password string) (*domain.User, *domain.TokenPair, error) {
+	args := m.Called(ctx, email, password)
+	user := args.Get(0)
+	if user == nil {
+		return nil, nil, args.Error(2)
+	}
+	return user.(*domain.User), args.Get(1).(*domain.TokenPair), args.Error(2)
+}
+
+func (m *MockAuthUseCase) PendingMFAUser(mfaToken string) (string, error) {
+	args := m.Called(mfaToken)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockAuthUseCase) CompleteMFALogin(ctx context.Context, mfaToken, code string) (*domain.User, *domain.TokenPair, error) {
+	args := m.Called(ctx, mfaToken, code)
+	user := args.Get(0)
+	if user == nil {
+		return nil, nil, args.Error(2)
+	}
+	return user.(*domain.User), args.Get(1).(*domain.TokenPair), args.Error(2)
+}
+
+func (m *MockAuthUseCase) RequestMagicLink(ctx context.Context, email string) error {
+	args := m.Called(ctx, email)
+	return args.Error(0)
+}
+
+func (m *MockAuthUseCase) LoginWithMagicLink(ctx context.Context, token string) (*domain.User, *domain.TokenPair, error) {
+	args := m.Called(ctx, token)
+	user := args.Get(0)
+	if user == nil {
+		return nil, nil, args.Error(2)
+	}
+	return user.(*domain.User), args.Get(1).(*domain.TokenPair), args.Error(2)
+}
+
+func (m *MockAuthUseCase) SendEmailVerification(ctx context.Context, userID string) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockAuthUseCase) VerifyEmail(ctx context.Context, token string) error {
+	args := m.Called(ctx, token)
+	return args.Error(0)
+}
+
+func (m *MockAuthUseCase) ExchangeToken(ctx context.Context, refreshToken string) (*domain.User, *domain.TokenPair, error) {
+	args := m.Called(ctx, refreshToken)
+	user := args.Get(0)
+	if user == nil {
+		return nil, nil, args.Error(2)
+	}
+	return user.(*domain.User), args.Get(1).(*domain.TokenPair), args.Error(2)
+}
+
+func (m *MockAuthUseCase) Logout(ctx context.Context, principal *domain.Principal, refreshToken string, allSessions bool) error {
+	args := m.Called(ctx, principal, refreshToken, allSessions)
+	return args.Error(0)
+}
+
+func (m *MockAuthUseCase) RevokeAllSessions(ctx context.Context, userID string) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockAuthUseCase) ListSessions(ctx context.Context, userID string) ([]domain.Session, error) {
+	args := m.Called(ctx, userID)
+	sessions, _ := args.Get(0).([]domain.Session)
+	return sessions, args.Error(1)
+}
+
+func (m *MockAuthUseCase) RevokeSession(ctx context.Context, userID, sessionID string) error {
+	args := m.Called(ctx, userID, sessionID)
+	return args.Error(0)
 }
 
 func (m *MockAuthUseCase) ResetPassword(ctx context.Context, email string) error {
@@ -33,43 +134,124 @@ func (m *MockAuthUseCase) ResetPassword(ctx context.Context, email string) error
 	return args.Error(0)
 }
 
+// MockLoginGuard mocks the brute-force protection
+type MockLoginGuard struct {
+	mock.Mock
+}
+
+func (m *MockLoginGuard) Check(ctx context.Context, clientIP, account string) error {
+	args := m.Called(ctx, clientIP, account)
+	return args.Error(0)
+}
+
+func (m *MockLoginGuard) Fail(ctx context.Context, clientIP, account string) error {
+	args := m.Called(ctx, clientIP, account)
+	return args.Error(0)
+}
+
+func (m *MockLoginGuard) Reset(ctx context.Context, account string) error {
+	args := m.Called(ctx, account)
+	return args.Error(0)
+}
+
+// openGuard never locks anyone out
+func openGuard() *MockLoginGuard {
+	guard := new(MockLoginGuard)
+	guard.On("Check", mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	guard.On("Fail", mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	guard.On("Reset", mock.Anything, mock.Anything).Return(nil).Maybe()
+	return guard
+}
+
 func TestAuthHandler_Login(t *testing.T) {
 	tests := []struct {
 		name           string
 		reqBody        string
+		provider       string
 		mockUser       *domain.User
-		mockToken      string
+		mockTokens     *domain.TokenPair
 		mockErr        error
 		expectedStatus int
+		expectFailure  bool
 	}{
 		{
 			name:           "success",
 			reqBody:        `{"firebase_token": "valid-firebase-token"}`,
+			provider:       domain.ProviderFirebase,
+			mockUser:       &domain.User{ID: "user-id"},
+			mockTokens:     &domain.TokenPair{AccessToken: "jwt-token", RefreshToken: "refresh-token"},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "oidc provider",
+			reqBody:        `{"provider": "keycloak", "id_token": "valid-id-token"}`,
+			provider:       "keycloak",
 			mockUser:       &domain.User{ID: "user-id"},
-			mockToken:      "jwt-token",
+			mockTokens:     &domain.TokenPair{AccessToken: "jwt-token", RefreshToken: "refresh-token"},
 			expectedStatus: http.StatusOK,
 		},
+		{
+			name:           "id token without provider",
+			reqBody:        `{"id_token": "valid-id-token"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing token",
+			reqBody:        `{}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "unknown provider",
+			reqBody:        `{"provider": "other", "id_token": "valid-id-token"}`,
+			provider:       "other",
+			mockErr:        domain.ErrUnknownIdentityProvider,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "e-mail owned by another account",
+			reqBody:        `{"provider": "keycloak", "id_token": "valid-id-token"}`,
+			provider:       "keycloak",
+			mockErr:        domain.ErrIdentityLinkRequired,
+			expectedStatus: http.StatusConflict,
+		},
 		{
 			name:           "invalid json",
 			reqBody:        `invalid-json`,
 			expectedStatus: http.StatusBadRequest,
 		},
 		{
-			name:           "usecase error",
+			name:           "rejected token",
 			reqBody:        `{"firebase_token": "invalid-token"}`,
-			mockErr:        errors.New("firebase error"),
+			provider:       domain.ProviderFirebase,
+			mockErr:        fmt.Errorf("%w: token expired", domain.ErrInvalidIdentityToken),
 			expectedStatus: http.StatusUnauthorized,
+			expectFailure:  true,
+		},
+		{
+			name:           "firebase disabled",
+			reqBody:        `{"firebase_token": "valid-firebase-token"}`,
+			provider:       domain.ProviderFirebase,
+			mockErr:        domain.ErrFirebaseDisabled,
+			expectedStatus: http.StatusNotImplemented,
+		},
+		{
+			name:           "usecase error",
+			reqBody:        `{"firebase_token": "valid-firebase-token"}`,
+			provider:       domain.ProviderFirebase,
+			mockErr:        errors.New("db error"),
+			expectedStatus: http.StatusInternalServerError,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockUC := new(MockAuthUseCase)
-			handler := handlers.NewAuthHandler(mockUC)
+			guard := openGuard()
+			handler := handlers.NewAuthHandler(mockUC, guard)
 
 			if tt.mockUser != nil || tt.mockErr != nil {
-				mockUC.On("LoginOrRegister", mock.Anything, mock.Anything).
-					Return(tt.mockUser, tt.mockToken, tt.mockErr)
+				mockUC.On("LoginOrRegister", mock.Anything, tt.provider, mock.Anything).
+					Return(tt.mockUser, tt.mockTokens, tt.mockErr)
 			}
 
 			req := httptest.NewRequest(http.MethodPost, "/auth/login", bytes.NewBuffer([]byte(tt.reqBody)))
@@ -77,11 +259,310 @@ func TestAuthHandler_Login(t *testing.T) {
 
 			handler.Login(rec, req)
 
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectFailure {
+				guard.AssertCalled(t, "Fail", mock.Anything, mock.Anything, "")
+			} else {
+				guard.AssertNotCalled(t, "Fail", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestAuthHandler_Register(t *testing.T) {
+	tests := []struct {
+		name           string
+		reqBody        string
+		mockUser       *domain.User
+		mockErr        error
+		expectedStatus int
+	}{
+		{
+			name:           "success",
+			reqBody:        `{"name": "Test User", "email": "user@example.com", "password": "strong-password"}`,
+			mockUser:       &domain.User{ID: "user-id"},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "invalid json",
+			reqBody:        `invalid-json`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "short password",
+			reqBody:        `{"name": "Test User", "email": "user@example.com", "password": "short"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "email already registered",
+			reqBody:        `{"name": "Test User", "email": "user@example.com", "password": "strong-password"}`,
+			mockErr:        domain.ErrEmailAlreadyRegistered,
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "usecase failure",
+			reqBody:        `{"name": "Test User", "email": "user@example.com", "password": "strong-password"}`,
+			mockErr:        errors.New("db error"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUC := new(MockAuthUseCase)
+			handler := handlers.NewAuthHandler(mockUC, openGuard())
+
+			if tt.mockUser != nil || tt.mockErr != nil {
+				mockUC.On("Register", mock.Anything, "Test User", "user@example.com", "strong-password").
+					Return(tt.mockUser, &domain.TokenPair{AccessToken: "jwt-token"}, tt.mockErr)
+			}
+			if tt.mockErr == nil && tt.mockUser != nil {
+				// A failed e-mail must not fail the registration
+				mockUC.On("SendEmailVerification", mock.Anything, "user-id").Return(errors.New("smtp down"))
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/auth/register", bytes.NewBuffer([]byte(tt.reqBody)))
+			rec := httptest.NewRecorder()
+
+			handler.Register(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestAuthHandler_DirectLogin(t *testing.T) {
+	tests := []struct {
+		name           string
+		reqBody        string
+		mockUser       *domain.User
+		mockErr        error
+		expectedStatus int
+	}{
+		{
+			name:           "success",
+			reqBody:        `{"email": "user@example.com", "password": "strong-password"}`,
+			mockUser:       &domain.User{ID: "user-id"},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid email",
+			reqBody:        `{"email": "not-an-email", "password": "strong-password"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid credentials",
+			reqBody:        `{"email": "user@example.com", "password": "strong-password"}`,
+			mockErr:        domain.ErrInvalidCredentials,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "second factor required",
+			reqBody:        `{"email": "user@example.com", "password": "strong-password"}`,
+			mockErr:        &domain.MFARequiredError{Token: "mfa-token"},
+			expectedStatus: http.StatusAccepted,
+		},
+		{
+			name:           "disabled account",
+			reqBody:        `{"email": "user@example.com", "password": "strong-password"}`,
+			mockErr:        domain.ErrUserDisabled,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "usecase failure",
+			reqBody:        `{"email": "user@example.com", "password": "strong-password"}`,
+			mockErr:        errors.New("db error"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUC := new(MockAuthUseCase)
+			handler := handlers.NewAuthHandler(mockUC, openGuard())
+
+			if tt.mockUser != nil || tt.mockErr != nil {
+				mockUC.On("LoginWithPassword", mock.Anything, "user@example.com", "strong-password").
+					Return(tt.mockUser, &domain.TokenPair{AccessToken: "jwt-token"}, tt.mockErr)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/auth/direct-login", bytes.NewBuffer([]byte(tt.reqBody)))
+			rec := httptest.NewRecorder()
+
+			handler.DirectLogin(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestAuthHandler_DirectLoginLockout(t *testing.T) {
+	body := `{"email": "user@example.com", "password": "strong-password"}`
+
+	t.Run("locked out", func(t *testing.T) {
+		mockUC := new(MockAuthUseCase)
+		guard := new(MockLoginGuard)
+		guard.On("Check", mock.Anything, "192.0.2.1", "user@example.com").
+			Return(&domain.LockedOutError{RetryAfter: 1500 * time.Millisecond})
+		handler := handlers.NewAuthHandler(mockUC, guard)
+
+		req := httptest.NewRequest(http.MethodPost, "/auth/direct-login", bytes.NewBufferString(body))
+		req.RemoteAddr = "192.0.2.1:4321"
+		rec := httptest.NewRecorder()
+
+		handler.DirectLogin(rec, req)
+
+		assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+		assert.Equal(t, "2", rec.Header().Get("Retry-After"))
+		mockUC.AssertNotCalled(t, "LoginWithPassword", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("failure is counted", func(t *testing.T) {
+		mockUC := new(MockAuthUseCase)
+		mockUC.On("LoginWithPassword", mock.Anything, "user@example.com", "strong-password").
+			Return(nil, nil, domain.ErrInvalidCredentials)
+		guard := new(MockLoginGuard)
+		guard.On("Check", mock.Anything, "192.0.2.1", "user@example.com").Return(nil)
+		guard.On("Fail", mock.Anything, "192.0.2.1", "user@example.com").Return(nil)
+		handler := handlers.NewAuthHandler(mockUC, guard)
+
+		req := httptest.NewRequest(http.MethodPost, "/auth/direct-login", bytes.NewBufferString(body))
+		req.RemoteAddr = "192.0.2.1:4321"
+		rec := httptest.NewRecorder()
+
+		handler.DirectLogin(rec, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		guard.AssertExpectations(t)
+	})
+
+	t.Run("success resets the account", func(t *testing.T) {
+		mockUC := new(MockAuthUseCase)
+		mockUC.On("LoginWithPassword", mock.Anything, "user@example.com", "strong-password").
+			Return(&domain.User{ID: "user-id"}, &domain.TokenPair{AccessToken: "jwt-token"}, nil)
+		guard := new(MockLoginGuard)
+		guard.On("Check", mock.Anything, "192.0.2.1", "user@example.com").Return(nil)
+		guard.On("Reset", mock.Anything, "user@example.com").Return(nil)
+		handler := handlers.NewAuthHandler(mockUC, guard)
+
+		req := httptest.NewRequest(http.MethodPost, "/auth/direct-login", bytes.NewBufferString(body))
+		req.RemoteAddr = "192.0.2.1:4321"
+		rec := httptest.NewRecorder()
+
+		handler.DirectLogin(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		guard.AssertExpectations(t)
+	})
+}
+
+func TestAuthHandler_VerifyMFA(t *testing.T) {
+	tests := []struct {
+		name           string
+		reqBody        string
+		pendingErr     error
+		mockUser       *domain.User
+		mockErr        error
+		expectedStatus int
+	}{
+		{
+			name:           "success",
+			reqBody:        `{"mfa_token": "mfa-token", "code": "123456"}`,
+			mockUser:       &domain.User{ID: "user-id"},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "missing code",
+			reqBody:        `{"mfa_token": "mfa-token"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid code",
+			reqBody:        `{"mfa_token": "mfa-token", "code": "123456"}`,
+			mockErr:        domain.ErrInvalidMFACode,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "expired mfa token",
+			reqBody:        `{"mfa_token": "mfa-token", "code": "123456"}`,
+			pendingErr:     domain.ErrInvalidMFAToken,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "mfa token already used",
+			reqBody:        `{"mfa_token": "mfa-token", "code": "123456"}`,
+			mockErr:        domain.ErrInvalidMFAToken,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "usecase failure",
+			reqBody:        `{"mfa_token": "mfa-token", "code": "123456"}`,
+			mockErr:        errors.New("db error"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUC := new(MockAuthUseCase)
+			handler := handlers.NewAuthHandler(mockUC, openGuard())
+
+			mockUC.On("PendingMFAUser", "mfa-token").Return("user-id", tt.pendingErr)
+			mockUC.On("CompleteMFALogin", mock.Anything, "mfa-token", "123456").
+				Return(tt.mockUser, &domain.TokenPair{AccessToken: "jwt-token"}, tt.mockErr)
+
+			req := httptest.NewRequest(http.MethodPost, "/auth/mfa/verify", bytes.NewBuffer([]byte(tt.reqBody)))
+			rec := httptest.NewRecorder()
+
+			handler.VerifyMFA(rec, req)
+
 			assert.Equal(t, tt.expectedStatus, rec.Code)
 		})
 	}
 }
 
+func TestAuthHandler_VerifyMFALockout(t *testing.T) {
+	body := `{"mfa_token": "mfa-token", "code": "123456"}`
+
+	t.Run("locked out user", func(t *testing.T) {
+		mockUC := new(MockAuthUseCase)
+		mockUC.On("PendingMFAUser", "mfa-token").Return("user-id", nil)
+		guard := new(MockLoginGuard)
+		guard.On("Check", mock.Anything, "192.0.2.1", "").Return(nil)
+		guard.On("Check", mock.Anything, "", "mfa:user-id").Return(&domain.LockedOutError{RetryAfter: time.Minute})
+		handler := handlers.NewAuthHandler(mockUC, guard)
+
+		req := httptest.NewRequest(http.MethodPost, "/auth/mfa/verify", bytes.NewBufferString(body))
+		req.RemoteAddr = "192.0.2.1:4321"
+		rec := httptest.NewRecorder()
+
+		handler.VerifyMFA(rec, req)
+
+		assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+		mockUC.AssertNotCalled(t, "CompleteMFALogin", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("failure is counted against the user", func(t *testing.T) {
+		mockUC := new(MockAuthUseCase)
+		mockUC.On("PendingMFAUser", "mfa-token").Return("user-id", nil)
+		mockUC.On("CompleteMFALogin", mock.Anything, "mfa-token", "123456").Return(nil, nil, domain.ErrInvalidMFACode)
+		guard := new(MockLoginGuard)
+		guard.On("Check", mock.Anything, "192.0.2.1", "").Return(nil)
+		guard.On("Check", mock.Anything, "", "mfa:user-id").Return(nil)
+		guard.On("Fail", mock.Anything, "192.0.2.1", "mfa:user-id").Return(nil)
+		handler := handlers.NewAuthHandler(mockUC, guard)
+
+		req := httptest.NewRequest(http.MethodPost, "/auth/mfa/verify", bytes.NewBufferString(body))
+		req.RemoteAddr = "192.0.2.1:4321"
+		rec := httptest.NewRecorder()
+
+		handler.VerifyMFA(rec, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		guard.AssertExpectations(t)
+	})
+}
+
 func TestAuthHandler_ResetPassword(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -100,17 +581,23 @@ func TestAuthHandler_ResetPassword(t *testing.T) {
 			expectedStatus: http.StatusBadRequest,
 		},
 		{
-			name:           "usecase failure",
+			name:           "unknown e-mail answers like a sent one",
 			reqBody:        `{"email": "fail@example.com"}`,
 			mockErr:        errors.New("firebase error"),
-			expectedStatus: http.StatusInternalServerError,
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "firebase disabled",
+			reqBody:        `{"email": "user@example.com"}`,
+			mockErr:        domain.ErrFirebaseDisabled,
+			expectedStatus: http.StatusNotImplemented,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockUC := new(MockAuthUseCase)
-			handler := handlers.NewAuthHandler(mockUC)
+			handler := handlers.NewAuthHandler(mockUC, openGuard())
 
 			if tt.mockErr != nil || tt.name == "success" {
 				mockUC.On("ResetPassword", mock.Anything, mock.Anything).
@@ -126,3 +613,527 @@ func TestAuthHandler_ResetPassword(t *testing.T) {
 		})
 	}
 }
+
+func TestAuthHandler_ResetPasswordThrottle(t *testing.T) {
+	body := `{"email": "user@example.com"}`
+
+	t.Run("counted as a send, not a failed login", func(t *testing.T) {
+		mockUC := new(MockAuthUseCase)
+		mockUC.On("ResetPassword", mock.Anything, "user@example.com").Return(nil)
+		guard := new(MockLoginGuard)
+		guard.On("Check", mock.Anything, "", "reset:user@example.com").Return(nil)
+		guard.On("Fail", mock.Anything, "", "reset:user@example.com").Return(nil)
+		handler := handlers.NewAuthHandler(mockUC, guard)
+
+		req := httptest.NewRequest(http.MethodPost, "/auth/reset-password", bytes.NewBufferString(body))
+		req.RemoteAddr = "192.0.2.1:4321"
+		rec := httptest.NewRecorder()
+
+		handler.ResetPassword(rec, req)
+
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		guard.AssertExpectations(t)
+		guard.AssertNotCalled(t, "Fail", mock.Anything, "192.0.2.1", "user@example.com")
+	})
+
+	t.Run("too many sends", func(t *testing.T) {
+		mockUC := new(MockAuthUseCase)
+		guard := new(MockLoginGuard)
+		guard.On("Check", mock.Anything, "", "reset:user@example.com").Return(&domain.LockedOutError{RetryAfter: time.Minute})
+		handler := handlers.NewAuthHandler(mockUC, guard)
+
+		req := httptest.NewRequest(http.MethodPost, "/auth/reset-password", bytes.NewBufferString(body))
+		rec := httptest.NewRecorder()
+
+		handler.ResetPassword(rec, req)
+
+		assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+		mockUC.AssertNotCalled(t, "ResetPassword", mock.Anything, mock.Anything)
+	})
+}
+
+func TestAuthHandler_ExchangeToken(t *testing.T) {
+	tests := []struct {
+		name           string
+		reqBody        string
+		mockUser       *domain.User
+		mockTokens     *domain.TokenPair
+		mockErr        error
+		expectedStatus int
+	}{
+		{
+			name:           "success",
+			reqBody:        `{"refresh_token": "refresh-token"}`,
+			mockUser:       &domain.User{ID: "user-id"},
+			mockTokens:     &domain.TokenPair{AccessToken: "jwt-token", RefreshToken: "new-refresh-token"},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid json",
+			reqBody:        `invalid-json`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing refresh token",
+			reqBody:        `{}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid refresh token",
+			reqBody:        `{"refresh_token": "unknown"}`,
+			mockErr:        domain.ErrInvalidRefreshToken,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "reused refresh token",
+			reqBody:        `{"refresh_token": "rotated"}`,
+			mockErr:        domain.ErrRefreshTokenReused,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "disabled account",
+			reqBody:        `{"refresh_token": "refresh-token"}`,
+			mockErr:        domain.ErrUserDisabled,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "unexpected error",
+			reqBody:        `{"refresh_token": "refresh-token"}`,
+			mockErr:        errors.New("db error"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUC := new(MockAuthUseCase)
+			handler := handlers.NewAuthHandler(mockUC, openGuard())
+
+			if tt.mockUser != nil || tt.mockErr != nil {
+				mockUC.On("ExchangeToken", mock.Anything, mock.Anything).
+					Return(tt.mockUser, tt.mockTokens, tt.mockErr)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/auth/exchange-token", bytes.NewBuffer([]byte(tt.reqBody)))
+			rec := httptest.NewRecorder()
+
+			handler.ExchangeToken(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestAuthHandler_Logout(t *testing.T) {
+	principal := &domain.Principal{UserID: "user-id", TokenID: "jti-1"}
+
+	tests := []struct {
+		name           string
+		reqBody        string
+		principal      *domain.Principal
+		refreshToken   string
+		allSessions    bool
+		mockErr        error
+		expectedStatus int
+	}{
+		{
+			name:           "empty body",
+			principal:      principal,
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "with refresh token",
+			reqBody:        `{"refresh_token": "refresh-token"}`,
+			principal:      principal,
+			refreshToken:   "refresh-token",
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "all sessions",
+			reqBody:        `{"all_sessions": true}`,
+			principal:      principal,
+			allSessions:    true,
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "invalid json",
+			reqBody:        `invalid-json`,
+			principal:      principal,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing principal",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "refresh token of another session",
+			reqBody:        `{"refresh_token": "refresh-token"}`,
+			principal:      principal,
+			refreshToken:   "refresh-token",
+			mockErr:        domain.ErrInvalidRefreshToken,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "usecase failure",
+			principal:      principal,
+			mockErr:        errors.New("store error"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUC := new(MockAuthUseCase)
+			handler := handlers.NewAuthHandler(mockUC, openGuard())
+
+			mockUC.On("Logout", mock.Anything, principal, tt.refreshToken, tt.allSessions).Return(tt.mockErr)
+
+			req := httptest.NewRequest(http.MethodPost, "/auth/logout", bytes.NewBuffer([]byte(tt.reqBody)))
+			if tt.principal != nil {
+				req = req.WithContext(middlewares.WithPrincipal(req.Context(), tt.principal))
+			}
+			rec := httptest.NewRecorder()
+
+			handler.Logout(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestAuthHandler_LinkIdentity(t *testing.T) {
+	principal := &domain.Principal{UserID: "user-id"}
+
+	tests := []struct {
+		name           string
+		reqBody        string
+		principal      *domain.Principal
+		mockIdentity   *domain.Identity
+		mockErr        error
+		expectedStatus int
+	}{
+		{
+			name:           "success",
+			reqBody:        `{"provider": "google", "id_token": "id-token"}`,
+			principal:      principal,
+			mockIdentity:   &domain.Identity{Provider: "google", Email: "user@example.com"},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "missing principal",
+			reqBody:        `{"provider": "google", "id_token": "id-token"}`,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "missing provider",
+			reqBody:        `{"id_token": "id-token"}`,
+			principal:      principal,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "unknown provider",
+			reqBody:        `{"provider": "other", "id_token": "id-token"}`,
+			principal:      principal,
+			mockErr:        domain.ErrUnknownIdentityProvider,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "linked to another account",
+			reqBody:        `{"provider": "google", "id_token": "id-token"}`,
+			principal:      principal,
+			mockErr:        domain.ErrIdentityAlreadyLinked,
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "invalid token",
+			reqBody:        `{"provider": "google", "id_token": "id-token"}`,
+			principal:      principal,
+			mockErr:        errors.New("token is expired"),
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUC := new(MockAuthUseCase)
+			handler := handlers.NewAuthHandler(mockUC, openGuard())
+
+			mockUC.On("LinkIdentity", mock.Anything, "user-id", mock.Anything, "id-token").Return(tt.mockIdentity, tt.mockErr)
+
+			req := httptest.NewRequest(http.MethodPost, "/auth/identities", bytes.NewBuffer([]byte(tt.reqBody)))
+			if tt.principal != nil {
+				req = req.WithContext(middlewares.WithPrincipal(req.Context(), tt.principal))
+			}
+			rec := httptest.NewRecorder()
+
+			handler.LinkIdentity(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestAuthHandler_ListIdentities(t *testing.T) {
+	mockUC := new(MockAuthUseCase)
+	handler := handlers.NewAuthHandler(mockUC, openGuard())
+
+	mockUC.On("ListIdentities", mock.Anything, "user-id").Return([]domain.Identity{
+		{Provider: domain.ProviderFirebase, Email: "user@example.com"},
+		{Provider: "google", Email: "user@example.com"},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/auth/identities", nil)
+	req = req.WithContext(middlewares.WithPrincipal(req.Context(), &domain.Principal{UserID: "user-id"}))
+	rec := httptest.NewRecorder()
+
+	handler.ListIdentities(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"provider":"google"`)
+}
+
+func TestAuthHandler_ListSessions(t *testing.T) {
+	mockUC := new(MockAuthUseCase)
+	handler := handlers.NewAuthHandler(mockUC, openGuard())
+
+	mockUC.On("ListSessions", mock.Anything, "user-id").Return([]domain.Session{
+		{ID: "session-1", UserAgent: "Mozilla/5.0", IP: "203.0.113.7"},
+		{ID: "session-2", UserAgent: "curl/8.0"},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/auth/sessions", nil)
+	req = req.WithContext(middlewares.WithPrincipal(req.Context(), &domain.Principal{UserID: "user-id", SessionID: "session-1"}))
+	rec := httptest.NewRecorder()
+
+	handler.ListSessions(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"id":"session-1","user_agent":"Mozilla/5.0","ip":"203.0.113.7"`)
+	assert.Contains(t, rec.Body.String(), `"current":true`)
+	assert.Contains(t, rec.Body.String(), `"current":false`)
+}
+
+func TestAuthHandler_RevokeSession(t *testing.T) {
+	tests := []struct {
+		name           string
+		sessionID      string
+		mockErr        error
+		expectedStatus int
+	}{
+		{"success", testUserID, nil, http.StatusNoContent},
+		{"invalid id", "not-a-uuid", nil, http.StatusBadRequest},
+		{"not found", testUserID, domain.ErrSessionNotFound, http.StatusNotFound},
+		{"usecase failure", testUserID, errors.New("db error"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUC := new(MockAuthUseCase)
+			mockUC.On("RevokeSession", mock.Anything, "user-id", tt.sessionID).Return(tt.mockErr).Maybe()
+			handler := handlers.NewAuthHandler(mockUC, openGuard())
+
+			req := withUser(httptest.NewRequest(http.MethodDelete, "/auth/sessions/"+tt.sessionID, nil))
+			rec := httptest.NewRecorder()
+			handler.RevokeSession(rec, withURLParams(req, map[string]string{"id": tt.sessionID}))
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestAuthHandler_RequestMagicLink(t *testing.T) {
+	tests := []struct {
+		name           string
+		reqBody        string
+		mockErr        error
+		expectCall     bool
+		expectedStatus int
+	}{
+		{
+			name:           "success",
+			reqBody:        `{"email": "user@example.com"}`,
+			expectCall:     true,
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "invalid email",
+			reqBody:        `{"email": "not-an-email"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "delivery failure answers like a sent link",
+			reqBody:        `{"email": "user@example.com"}`,
+			mockErr:        errors.New("smtp error"),
+			expectCall:     true,
+			expectedStatus: http.StatusNoContent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUC := new(MockAuthUseCase)
+			handler := handlers.NewAuthHandler(mockUC, openGuard())
+
+			if tt.expectCall {
+				mockUC.On("RequestMagicLink", mock.Anything, "user@example.com").Return(tt.mockErr)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/auth/magic-link", bytes.NewBuffer([]byte(tt.reqBody)))
+			rec := httptest.NewRecorder()
+
+			handler.RequestMagicLink(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			mockUC.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuthHandler_RequestMagicLinkThrottle(t *testing.T) {
+	mockUC := new(MockAuthUseCase)
+	mockUC.On("RequestMagicLink", mock.Anything, "user@example.com").Return(nil)
+	guard := new(MockLoginGuard)
+	guard.On("Check", mock.Anything, "", "magic-link:user@example.com").Return(nil).Once()
+	guard.On("Fail", mock.Anything, "", "magic-link:user@example.com").Return(nil).Once()
+	guard.On("Check", mock.Anything, "", "magic-link:user@example.com").Return(&domain.LockedOutError{RetryAfter: time.Minute})
+	handler := handlers.NewAuthHandler(mockUC, guard)
+
+	for _, want := range []int{http.StatusNoContent, http.StatusTooManyRequests} {
+		req := httptest.NewRequest(http.MethodPost, "/auth/magic-link", bytes.NewBufferString(`{"email": "user@example.com"}`))
+		req.RemoteAddr = "192.0.2.1:4321"
+		rec := httptest.NewRecorder()
+
+		handler.RequestMagicLink(rec, req)
+
+		assert.Equal(t, want, rec.Code)
+	}
+	mockUC.AssertNumberOfCalls(t, "RequestMagicLink", 1)
+	guard.AssertNotCalled(t, "Fail", mock.Anything, "192.0.2.1", "user@example.com")
+}
+
+func TestAuthHandler_VerifyMagicLink(t *testing.T) {
+	tests := []struct {
+		name           string
+		token          string
+		mockUser       *domain.User
+		mockErr        error
+		expectedStatus int
+	}{
+		{
+			name:           "success",
+			token:          "link-token",
+			mockUser:       &domain.User{ID: "user-id", Email: "user@example.com"},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "missing token",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid or used link",
+			token:          "link-token",
+			mockErr:        domain.ErrInvalidMagicLink,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "second factor required",
+			token:          "link-token",
+			mockErr:        &domain.MFARequiredError{Token: "mfa-token"},
+			expectedStatus: http.StatusAccepted,
+		},
+		{
+			name:           "usecase failure",
+			token:          "link-token",
+			mockErr:        errors.New("db error"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUC := new(MockAuthUseCase)
+			guard := openGuard()
+			handler := handlers.NewAuthHandler(mockUC, guard)
+
+			if tt.mockUser != nil {
+				mockUC.On("LoginWithMagicLink", mock.Anything, tt.token).
+					Return(tt.mockUser, &domain.TokenPair{AccessToken: "jwt-token", RefreshToken: "refresh-token"}, nil)
+			} else if tt.mockErr != nil {
+				mockUC.On("LoginWithMagicLink", mock.Anything, tt.token).Return(nil, nil, tt.mockErr)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/auth/magic-link/verify?token="+tt.token, nil)
+			rec := httptest.NewRecorder()
+
+			handler.VerifyMagicLink(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedStatus == http.StatusOK {
+				assert.Contains(t, rec.Body.String(), `"token":"jwt-token"`)
+				assert.Contains(t, rec.Body.String(), `"refresh_token":"refresh-token"`)
+				guard.AssertCalled(t, "Reset", mock.Anything, "user@example.com")
+				guard.AssertCalled(t, "Reset", mock.Anything, "magic-link:user@example.com")
+			}
+		})
+	}
+}
+
+func TestAuthHandler_SendEmailVerification(t *testing.T) {
+	tests := []struct {
+		name           string
+		authenticated  bool
+		mockErr        error
+		expectedStatus int
+	}{
+		{"success", true, nil, http.StatusNoContent},
+		{"unauthenticated", false, nil, http.StatusUnauthorized},
+		{"already verified", true, domain.ErrEmailAlreadyVerified, http.StatusConflict},
+		{"usecase failure", true, errors.New("smtp down"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUC := new(MockAuthUseCase)
+			mockUC.On("SendEmailVerification", mock.Anything, "user-id").Return(tt.mockErr).Maybe()
+			handler := handlers.NewAuthHandler(mockUC, openGuard())
+
+			req := httptest.NewRequest(http.MethodPost, "/auth/verify-email", nil)
+			if tt.authenticated {
+				req = withUser(req)
+			}
+			rec := httptest.NewRecorder()
+			handler.SendEmailVerification(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestAuthHandler_VerifyEmail(t *testing.T) {
+	tests := []struct {
+		name           string
+		token          string
+		mockErr        error
+		expectedStatus int
+	}{
+		{"success", "verify-token", nil, http.StatusNoContent},
+		{"missing token", "", nil, http.StatusBadRequest},
+		{"invalid or expired link", "verify-token", domain.ErrInvalidEmailVerificationLink, http.StatusBadRequest},
+		{"deleted user", "verify-token", domain.ErrUserNotFound, http.StatusBadRequest},
+		{"usecase failure", "verify-token", errors.New("db error"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUC := new(MockAuthUseCase)
+			mockUC.On("VerifyEmail", mock.Anything, tt.token).Return(tt.mockErr).Maybe()
+			handler := handlers.NewAuthHandler(mockUC, openGuard())
+
+			req := httptest.NewRequest(http.MethodGet, "/auth/verify-email/confirm?token="+tt.token, nil)
+			rec := httptest.NewRecorder()
+			handler.VerifyEmail(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
@@ -0,0 +1,263 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/internal/models"
+	"github.com/nuhorizon/go-project-template/services/template/internal/usecases"
+	"github.com/nuhorizon/go-project-template/services/template/pkg/middlewares"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+)
+
+type CatHandler interface {
+	List(w http.ResponseWriter, r *http.Request)
+	Get(w http.ResponseWriter, r *http.Request)
+	Create(w http.ResponseWriter, r *http.Request)
+	Update(w http.ResponseWriter, r *http.Request)
+	Delete(w http.ResponseWriter, r *http.Request)
+}
+
+type catHandler struct {
+	catUseCase usecases.CatUseCase
+	validator  *validator.Validate
+}
+
+func NewCatHandler(catUC usecases.CatUseCase) CatHandler {
+	return &catHandler{
+		catUseCase: catUC,
+		validator:  validator.New(),
+	}
+}
+
+// List godoc
+// @Summary Lista os gatos do usuário autenticado
+// @Tags Cats
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.CatResponse
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 500 {string} string "Internal Server Error"
+// @Router /cats [get]
+func (c *catHandler) List(w http.ResponseWriter, r *http.Request) {
+	principal, ok := middlewares.PrincipalFromContext(r.Context())
+	if !ok {
+		httpError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	cats, err := c.catUseCase.List(r.Context(), principal.UserID)
+	if err != nil {
+		httpError(w, http.StatusInternalServerError, fmt.Sprintf("list cats failed: %v", err.Error()))
+		return
+	}
+
+	response := make([]models.CatResponse, 0, len(cats))
+	for i := range cats {
+		response = append(response, toCatResponse(&cats[i]))
+	}
+	httpSuccess(w, http.StatusOK, response)
+}
+
+// Get godoc
+// @Summary Retorna um gato do usuário autenticado
+// @Tags Cats
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do gato"
+// @Success 200 {object} models.CatResponse
+// @Failure 400 {string} string "Bad Request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Not Found"
+// @Failure 500 {string} string "Internal Server Error"
+// @Router /cats/{id} [get]
+func (c *catHandler) Get(w http.ResponseWriter, r *http.Request) {
+	principal, ok := middlewares.PrincipalFromContext(r.Context())
+	if !ok {
+		httpError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	catID, ok := c.catIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	cat, err := c.catUseCase.Get(r.Context(), principal.UserID, catID)
+	if err != nil {
+		c.handleError(w, "get cat", err)
+		return
+	}
+
+	httpSuccess(w, http.StatusOK, toCatResponse(cat))
+}
+
+// Create godoc
+// @Summary Cadastra um gato para o usuário autenticado
+// @Tags Cats
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param cat body models.CatRequest true "Dados do gato"
+// @Success 201 {object} models.CatResponse
+// @Failure 400 {string} string "Bad Request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 402 {object} models.EntitlementErrorResponse "Limite de gatos do plano atingido"
+// @Failure 500 {string} string "Internal Server Error"
+// @Router /cats [post]
+func (c *catHandler) Create(w http.ResponseWriter, r *http.Request) {
+	principal, ok := middlewares.PrincipalFromContext(r.Context())
+	if !ok {
+		httpError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	cat, ok := c.decodeCat(w, r)
+	if !ok {
+		return
+	}
+	cat.UserID = principal.UserID
+
+	created, err := c.catUseCase.Create(r.Context(), cat)
+	if err != nil {
+		c.handleError(w, "create cat", err)
+		return
+	}
+
+	httpSuccess(w, http.StatusCreated, toCatResponse(created))
+}
+
+// Update godoc
+// @Summary Atualiza um gato do usuário autenticado
+// @Description Substitui todos os dados do gato: campos opcionais omitidos são apagados
+// @Tags Cats
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do gato"
+// @Param cat body models.CatRequest true "Dados do gato"
+// @Success 200 {object} models.CatResponse
+// @Failure 400 {string} string "Bad Request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Not Found"
+// @Failure 500 {string} string "Internal Server Error"
+// @Router /cats/{id} [put]
+func (c *catHandler) Update(w http.ResponseWriter, r *http.Request) {
+	principal, ok := middlewares.PrincipalFromContext(r.Context())
+	if !ok {
+		httpError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	catID, ok := c.catIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	cat, ok := c.decodeCat(w, r)
+	if !ok {
+		return
+	}
+	// The owner comes from the token, never from the request, so only the user's own cats match
+	cat.ID = catID
+	cat.UserID = principal.UserID
+
+	updated, err := c.catUseCase.Update(r.Context(), cat)
+	if err != nil {
+		c.handleError(w, "update cat", err)
+		return
+	}
+
+	httpSuccess(w, http.StatusOK, toCatResponse(updated))
+}
+
+// Delete godoc
+// @Summary Remove um gato do usuário autenticado
+// @Tags Cats
+// @Security BearerAuth
+// @Param id path string true "ID do gato"
+// @Success 204 "No Content"
+// @Failure 400 {string} string "Bad Request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Not Found"
+// @Failure 500 {string} string "Internal Server Error"
+// @Router /cats/{id} [delete]
+func (c *catHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	principal, ok := middlewares.PrincipalFromContext(r.Context())
+	if !ok {
+		httpError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	catID, ok := c.catIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	if err := c.catUseCase.Delete(r.Context(), principal.UserID, catID); err != nil {
+		c.handleError(w, "delete cat", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (c *catHandler) catIDParam(w http.ResponseWriter, r *http.Request) (string, bool) {
+	catID := chi.URLParam(r, "id")
+	if err := c.validator.Var(catID, "required,uuid"); err != nil {
+		httpError(w, http.StatusBadRequest, "invalid cat id")
+		return "", false
+	}
+	return catID, true
+}
+
+// decodeCat validates the request body and converts it to a cat without ID nor owner
+func (c *catHandler) decodeCat(w http.ResponseWriter, r *http.Request) (*domain.Cat, bool) {
+	var req models.CatRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpError(w, http.StatusBadRequest, "invalid request payload")
+		return nil, false
+	}
+
+	// A blank name would pass the required check
+	req.Name = strings.TrimSpace(req.Name)
+	if err := c.validator.Struct(req); err != nil {
+		httpError(w, http.StatusBadRequest, "validation error")
+		return nil, false
+	}
+
+	cat := &domain.Cat{
+		Name:       req.Name,
+		Breed:      req.Breed,
+		Gender:     req.Gender,
+		WeightKg:   req.WeightKg,
+		PictureURL: req.PictureURL,
+		IsNeutered: req.IsNeutered,
+	}
+	if req.BirthDate != "" {
+		birthDate, _ := time.Parse(catDateLayout, req.BirthDate)
+		if birthDate.After(time.Now()) {
+			httpError(w, http.StatusBadRequest, "birth_date must not be in the future")
+			return nil, false
+		}
+		cat.BirthDate = &birthDate
+	}
+	return cat, true
+}
+
+func (c *catHandler) handleError(w http.ResponseWriter, action string, err error) {
+	if errors.Is(err, domain.ErrCatNotFound) {
+		httpError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	var entitlementErr *domain.EntitlementError
+	if errors.As(err, &entitlementErr) {
+		middlewares.WriteEntitlementError(w, entitlementErr)
+		return
+	}
+	httpError(w, http.StatusInternalServerError, fmt.Sprintf("%s failed: %v", action, err.Error()))
+}
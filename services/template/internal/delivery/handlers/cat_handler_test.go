@@ -0,0 +1,284 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/delivery/handlers"
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockCatUseCase struct {
+	mock.Mock
+}
+
+func (m *MockCatUseCase) List(ctx context.Context, userID string) ([]domain.Cat, error) {
+	args := m.Called(ctx, userID)
+	cats, _ := args.Get(0).([]domain.Cat)
+	return cats, args.Error(1)
+}
+
+func (m *MockCatUseCase) Get(ctx context.Context, userID, catID string) (*domain.Cat, error) {
+	args := m.Called(ctx, userID, catID)
+	cat, _ := args.Get(0).(*domain.Cat)
+	return cat, args.Error(1)
+}
+
+func (m *MockCatUseCase) Create(ctx context.Context, cat *domain.Cat) (*domain.Cat, error) {
+	args := m.Called(ctx, cat)
+	created, _ := args.Get(0).(*domain.Cat)
+	return created, args.Error(1)
+}
+
+func (m *MockCatUseCase) Update(ctx context.Context, cat *domain.Cat) (*domain.Cat, error) {
+	args := m.Called(ctx, cat)
+	updated, _ := args.Get(0).(*domain.Cat)
+	return updated, args.Error(1)
+}
+
+func (m *MockCatUseCase) Delete(ctx context.Context, userID, catID string) error {
+	args := m.Called(ctx, userID, catID)
+	return args.Error(0)
+}
+
+const testCatID = "5d1c7a3e-2b8f-4f0a-9c61-0e4b7f2a9d33"
+
+func TestCatHandler_List(t *testing.T) {
+	tests := []struct {
+		name           string
+		authenticated  bool
+		mockCats       []domain.Cat
+		mockErr        error
+		expectedStatus int
+		expectedCount  int
+	}{
+		{
+			name:           "success",
+			authenticated:  true,
+			mockCats:       []domain.Cat{{ID: "cat-1", Name: "Mingau"}, {ID: "cat-2", Name: "Frajola"}},
+			expectedStatus: http.StatusOK,
+			expectedCount:  2,
+		},
+		{name: "no cats is an empty list", authenticated: true, expectedStatus: http.StatusOK},
+		{name: "unauthenticated", expectedStatus: http.StatusUnauthorized},
+		{name: "usecase failure", authenticated: true, mockErr: errors.New("db error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUC := new(MockCatUseCase)
+			mockUC.On("List", mock.Anything, "user-id").Return(tt.mockCats, tt.mockErr).Maybe()
+			handler := handlers.NewCatHandler(mockUC)
+
+			req := httptest.NewRequest(http.MethodGet, "/cats", nil)
+			if tt.authenticated {
+				req = withUser(req)
+			}
+			rec := httptest.NewRecorder()
+			handler.List(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response []models.CatResponse
+				assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+				assert.NotNil(t, response)
+				assert.Len(t, response, tt.expectedCount)
+			}
+		})
+	}
+}
+
+func TestCatHandler_Get(t *testing.T) {
+	tests := []struct {
+		name           string
+		catID          string
+		mockErr        error
+		expectedStatus int
+	}{
+		{"success", testCatID, nil, http.StatusOK},
+		{"invalid cat id", "not-a-uuid", nil, http.StatusBadRequest},
+		{"cat of another user", testCatID, domain.ErrCatNotFound, http.StatusNotFound},
+		{"usecase failure", testCatID, errors.New("db error"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUC := new(MockCatUseCase)
+			var cat *domain.Cat
+			if tt.mockErr == nil {
+				cat = &domain.Cat{ID: testCatID, UserID: "user-id", Name: "Mingau"}
+			}
+			mockUC.On("Get", mock.Anything, "user-id", tt.catID).Return(cat, tt.mockErr).Maybe()
+			handler := handlers.NewCatHandler(mockUC)
+
+			req := withURLParams(withUser(httptest.NewRequest(http.MethodGet, "/cats/"+tt.catID, nil)), map[string]string{"id": tt.catID})
+			rec := httptest.NewRecorder()
+			handler.Get(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response models.CatResponse
+				assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+				assert.Equal(t, "Mingau", response.Name)
+			}
+		})
+	}
+}
+
+func TestCatHandler_Create(t *testing.T) {
+	tomorrow := time.Now().AddDate(0, 0, 1).Format("2006-01-02")
+
+	tests := []struct {
+		name           string
+		authenticated  bool
+		reqBody        string
+		mockErr        error
+		expectedStatus int
+	}{
+		{
+			name:           "success",
+			authenticated:  true,
+			reqBody:        `{"name": " Mingau ", "breed": "Siamês", "birth_date": "2021-04-10", "gender": "female", "weight_kg": 4.5, "is_neutered": true}`,
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "owner in the body is ignored",
+			authenticated:  true,
+			reqBody:        `{"name": "Mingau", "user_id": "someone-else"}`,
+			expectedStatus: http.StatusCreated,
+		},
+		{name: "unauthenticated", reqBody: `{"name": "Mingau"}`, expectedStatus: http.StatusUnauthorized},
+		{name: "invalid json", authenticated: true, reqBody: `invalid-json`, expectedStatus: http.StatusBadRequest},
+		{name: "blank name", authenticated: true, reqBody: `{"name": "   "}`, expectedStatus: http.StatusBadRequest},
+		{name: "invalid birth date", authenticated: true, reqBody: `{"name": "Mingau", "birth_date": "10/04/2021"}`, expectedStatus: http.StatusBadRequest},
+		{name: "birth date in the future", authenticated: true, reqBody: `{"name": "Mingau", "birth_date": "` + tomorrow + `"}`, expectedStatus: http.StatusBadRequest},
+		{name: "unknown gender", authenticated: true, reqBody: `{"name": "Mingau", "gender": "other"}`, expectedStatus: http.StatusBadRequest},
+		{name: "negative weight", authenticated: true, reqBody: `{"name": "Mingau", "weight_kg": -1}`, expectedStatus: http.StatusBadRequest},
+		{name: "invalid picture url", authenticated: true, reqBody: `{"name": "Mingau", "picture_url": "not a url"}`, expectedStatus: http.StatusBadRequest},
+		{
+			name:           "plan cat limit reached",
+			authenticated:  true,
+			reqBody:        `{"name": "Mingau"}`,
+			mockErr:        &domain.EntitlementError{Reason: domain.EntitlementCatLimitReached, Plan: domain.PlanFree, Limit: 1},
+			expectedStatus: http.StatusPaymentRequired,
+		},
+		{name: "usecase failure", authenticated: true, reqBody: `{"name": "Mingau"}`, mockErr: errors.New("db error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUC := new(MockCatUseCase)
+			var created *domain.Cat
+			if tt.mockErr == nil {
+				created = &domain.Cat{ID: testCatID, UserID: "user-id", Name: "Mingau"}
+			}
+			mockUC.On("Create", mock.Anything, mock.AnythingOfType("*domain.Cat")).Return(created, tt.mockErr).Maybe()
+			handler := handlers.NewCatHandler(mockUC)
+
+			req := httptest.NewRequest(http.MethodPost, "/cats", bytes.NewBufferString(tt.reqBody))
+			if tt.authenticated {
+				req = withUser(req)
+			}
+			rec := httptest.NewRecorder()
+			handler.Create(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedStatus != http.StatusCreated && tt.mockErr == nil {
+				mockUC.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+				return
+			}
+			if tt.expectedStatus == http.StatusPaymentRequired {
+				assert.Contains(t, rec.Body.String(), `"reason":"cat_limit_reached"`)
+				assert.Contains(t, rec.Body.String(), `"limit":1`)
+			}
+			if tt.expectedStatus == http.StatusCreated {
+				cat := mockUC.Calls[0].Arguments.Get(1).(*domain.Cat)
+				assert.Equal(t, "user-id", cat.UserID)
+				assert.Equal(t, "Mingau", cat.Name)
+			}
+		})
+	}
+}
+
+func TestCatHandler_Update(t *testing.T) {
+	tests := []struct {
+		name           string
+		catID          string
+		reqBody        string
+		mockErr        error
+		expectedStatus int
+	}{
+		{"success", testCatID, `{"name": "Frajola", "gender": "male"}`, nil, http.StatusOK},
+		{"invalid cat id", "not-a-uuid", `{"name": "Frajola"}`, nil, http.StatusBadRequest},
+		{"missing name", testCatID, `{"breed": "Persa"}`, nil, http.StatusBadRequest},
+		{"cat of another user", testCatID, `{"name": "Frajola"}`, domain.ErrCatNotFound, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUC := new(MockCatUseCase)
+			var updated *domain.Cat
+			if tt.mockErr == nil {
+				updated = &domain.Cat{ID: testCatID, UserID: "user-id", Name: "Frajola"}
+			}
+			mockUC.On("Update", mock.Anything, mock.AnythingOfType("*domain.Cat")).Return(updated, tt.mockErr).Maybe()
+			handler := handlers.NewCatHandler(mockUC)
+
+			req := withURLParams(withUser(httptest.NewRequest(http.MethodPut, "/cats/"+tt.catID, bytes.NewBufferString(tt.reqBody))),
+				map[string]string{"id": tt.catID})
+			rec := httptest.NewRecorder()
+			handler.Update(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedStatus == http.StatusBadRequest {
+				mockUC.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+				return
+			}
+			cat := mockUC.Calls[0].Arguments.Get(1).(*domain.Cat)
+			assert.Equal(t, testCatID, cat.ID)
+			assert.Equal(t, "user-id", cat.UserID)
+		})
+	}
+}
+
+func TestCatHandler_Delete(t *testing.T) {
+	tests := []struct {
+		name           string
+		authenticated  bool
+		catID          string
+		mockErr        error
+		expectedStatus int
+	}{
+		{"success", true, testCatID, nil, http.StatusNoContent},
+		{"unauthenticated", false, testCatID, nil, http.StatusUnauthorized},
+		{"invalid cat id", true, "not-a-uuid", nil, http.StatusBadRequest},
+		{"cat of another user", true, testCatID, domain.ErrCatNotFound, http.StatusNotFound},
+		{"usecase failure", true, testCatID, errors.New("db error"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUC := new(MockCatUseCase)
+			mockUC.On("Delete", mock.Anything, "user-id", tt.catID).Return(tt.mockErr).Maybe()
+			handler := handlers.NewCatHandler(mockUC)
+
+			req := withURLParams(httptest.NewRequest(http.MethodDelete, "/cats/"+tt.catID, nil), map[string]string{"id": tt.catID})
+			if tt.authenticated {
+				req = withUser(req)
+			}
+			rec := httptest.NewRecorder()
+			handler.Delete(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
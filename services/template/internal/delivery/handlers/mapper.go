@@ -1,19 +1,121 @@
 package handlers
 
-// import (
-// 	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
-// 	"github.com/nuhorizon/go-project-template/services/template/internal/models"
-// )
-
-// func toCatResponse(cat *domain.Cat) models.CatResponse {
-// 	return models.CatResponse{
-// 		ID:         cat.ID,
-// 		Name:       cat.Name,
-// 		Breed:      cat.Breed,
-// 		BirthDate:  cat.BirthDate,
-// 		Gender:     cat.Gender,
-// 		WeightKg:   cat.WeightKg,
-// 		PictureURL: cat.PictureURL,
-// 		IsNeutered: cat.IsNeutered,
-// 	}
-// }
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/internal/models"
+	"github.com/nuhorizon/go-project-template/services/template/internal/ports/services"
+)
+
+func toUserResponse(user *domain.User) models.UserResponse {
+	return models.UserResponse{
+		ID:            user.ID,
+		Name:          user.Name,
+		Email:         user.Email,
+		PictureURL:    user.PictureURL,
+		PlanType:      user.PlanType,
+		EmailVerified: user.EmailVerified,
+	}
+}
+
+func toLoginResponse(user *domain.User, tokens *domain.TokenPair) models.LoginResponse {
+	return models.LoginResponse{
+		Token:        tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		User:         toUserResponse(user),
+	}
+}
+
+func toIdentityResponse(identity domain.Identity) models.IdentityResponse {
+	return models.IdentityResponse{
+		Provider: identity.Provider,
+		Email:    identity.Email,
+		LinkedAt: identity.CreatedAt,
+	}
+}
+
+func toAdminUserResponse(user *domain.User) models.AdminUserResponse {
+	return models.AdminUserResponse{
+		ID:            user.ID,
+		Name:          user.Name,
+		Email:         user.Email,
+		PictureURL:    user.PictureURL,
+		PlanType:      user.PlanType,
+		PremiumSince:  user.PremiumSince,
+		PlanExpiry:    user.PlanExpiry,
+		PlanCancelled: user.PlanCancelled,
+		EmailVerified: user.EmailVerified,
+		Roles:         user.Roles,
+		DisabledAt:    user.DisabledAt,
+		CreatedAt:     user.CreatedAt,
+		UpdatedAt:     user.UpdatedAt,
+	}
+}
+
+func toAPIKeyResponse(key domain.APIKey) models.APIKeyResponse {
+	return models.APIKeyResponse{
+		ID:         key.ID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     key.Scopes,
+		LastUsedAt: key.LastUsedAt,
+		ExpiresAt:  key.ExpiresAt,
+		RevokedAt:  key.RevokedAt,
+		CreatedAt:  key.CreatedAt,
+	}
+}
+
+func toSessionResponse(session domain.Session, currentSessionID string) models.SessionResponse {
+	return models.SessionResponse{
+		ID:         session.ID,
+		UserAgent:  session.UserAgent,
+		IP:         session.IP,
+		CreatedAt:  session.CreatedAt,
+		LastSeenAt: session.LastSeenAt,
+		Current:    currentSessionID != "" && session.ID == currentSessionID,
+	}
+}
+
+func toJWKResponse(key services.PublicKey) (models.JWKResponse, bool) {
+	jwk := models.JWKResponse{Kid: key.Kid, Use: "sig", Alg: key.Algorithm}
+
+	switch pub := key.Key.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+	default:
+		return models.JWKResponse{}, false
+	}
+
+	return jwk, true
+}
+
+// catDateLayout is the format of the cat birth dates in requests and responses
+const catDateLayout = "2006-01-02"
+
+func toCatResponse(cat *domain.Cat) models.CatResponse {
+	response := models.CatResponse{
+		ID:         cat.ID,
+		Name:       cat.Name,
+		Breed:      cat.Breed,
+		Gender:     cat.Gender,
+		WeightKg:   cat.WeightKg,
+		PictureURL: cat.PictureURL,
+		IsNeutered: cat.IsNeutered,
+		CreatedAt:  cat.CreatedAt,
+		UpdatedAt:  cat.UpdatedAt,
+	}
+	if cat.BirthDate != nil {
+		response.BirthDate = cat.BirthDate.Format(catDateLayout)
+	}
+	return response
+}
@@ -1,25 +1,90 @@
 package handlers
 
-// func TestToCatResponse(t *testing.T) {
-// 	cat := &domain.Cat{
-// 		ID:         "1",
-// 		Name:       "Whiskers",
-// 		Breed:      "Siamese",
-// 		BirthDate:  time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC),
-// 		Gender:     "Male",
-// 		WeightKg:   5,
-// 		PictureURL: "https //example.com/cat.jpg",
-// 		IsNeutered: true,
-// 	}
-
-// 	response := toCatResponse(cat)
-// 	assert.Equal(t, "1", response.ID)
-// 	assert.Equal(t, "Whiskers", response.Name)
-// 	assert.Equal(t, "Siamese", response.Breed)
-// 	assert.Equal(t, time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC), response.BirthDate)
-// 	assert.Equal(t, "Male", response.Gender)
-// 	assert.Equal(t, 5.0, response.WeightKg)
-// 	assert.Equal(t, "https //example.com/cat.jpg", response.PictureURL)
-// 	assert.True(t, response.IsNeutered)
-
-// }
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+	"time"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/internal/ports/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToLoginResponse(t *testing.T) {
+	user := &domain.User{
+		ID:         "user-id",
+		Name:       "Test User",
+		Email:      "user@example.com",
+		PictureURL: "https://example.com/pic.jpg",
+		PlanType:   "free",
+	}
+
+	response := toLoginResponse(user, &domain.TokenPair{AccessToken: "jwt", RefreshToken: "refresh"})
+	assert.Equal(t, "jwt", response.Token)
+	assert.Equal(t, "refresh", response.RefreshToken)
+	assert.Equal(t, "user-id", response.User.ID)
+	assert.Equal(t, "Test User", response.User.Name)
+	assert.Equal(t, "user@example.com", response.User.Email)
+	assert.Equal(t, "https://example.com/pic.jpg", response.User.PictureURL)
+	assert.Equal(t, "free", response.User.PlanType)
+}
+
+func TestToJWKResponse(t *testing.T) {
+	t.Run("RSA", func(t *testing.T) {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		assert.NoError(t, err)
+
+		jwk, ok := toJWKResponse(services.PublicKey{Kid: "rsa", Algorithm: "RS256", Key: &key.PublicKey})
+		assert.True(t, ok)
+		assert.Equal(t, "RSA", jwk.Kty)
+		assert.Equal(t, "AQAB", jwk.E) // 65537
+		assert.NotEmpty(t, jwk.N)
+		assert.Equal(t, "sig", jwk.Use)
+	})
+
+	t.Run("Ed25519", func(t *testing.T) {
+		pub, _, err := ed25519.GenerateKey(rand.Reader)
+		assert.NoError(t, err)
+
+		jwk, ok := toJWKResponse(services.PublicKey{Kid: "ed", Algorithm: "EdDSA", Key: pub})
+		assert.True(t, ok)
+		assert.Equal(t, "OKP", jwk.Kty)
+		assert.Equal(t, "Ed25519", jwk.Crv)
+		assert.Len(t, jwk.X, 43)
+	})
+
+	t.Run("unsupported key", func(t *testing.T) {
+		_, ok := toJWKResponse(services.PublicKey{Kid: "hmac", Key: []byte("secret")})
+		assert.False(t, ok)
+	})
+}
+
+func TestToCatResponse(t *testing.T) {
+	birthDate := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	weight := 5.0
+	cat := &domain.Cat{
+		ID:         "1",
+		Name:       "Whiskers",
+		Breed:      "Siamese",
+		BirthDate:  &birthDate,
+		Gender:     domain.CatGenderMale,
+		WeightKg:   &weight,
+		PictureURL: "https://example.com/cat.jpg",
+		IsNeutered: true,
+	}
+
+	response := toCatResponse(cat)
+	assert.Equal(t, "1", response.ID)
+	assert.Equal(t, "Whiskers", response.Name)
+	assert.Equal(t, "Siamese", response.Breed)
+	assert.Equal(t, "2020-01-01", response.BirthDate)
+	assert.Equal(t, "male", response.Gender)
+	assert.Equal(t, 5.0, *response.WeightKg)
+	assert.Equal(t, "https://example.com/cat.jpg", response.PictureURL)
+	assert.True(t, response.IsNeutered)
+
+	cat.BirthDate = nil
+	assert.Empty(t, toCatResponse(cat).BirthDate)
+}
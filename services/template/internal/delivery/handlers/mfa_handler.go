@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/internal/models"
+	"github.com/nuhorizon/go-project-template/services/template/internal/usecases"
+	"github.com/nuhorizon/go-project-template/services/template/pkg/middlewares"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type MFAHandler interface {
+	EnrollTOTP(w http.ResponseWriter, r *http.Request)
+	ConfirmTOTP(w http.ResponseWriter, r *http.Request)
+	DisableTOTP(w http.ResponseWriter, r *http.Request)
+}
+
+type mfaHandler struct {
+	mfaUseCase usecases.MFAUseCase
+	validator  *validator.Validate
+}
+
+func NewMFAHandler(mfaUC usecases.MFAUseCase) MFAHandler {
+	return &mfaHandler{
+		mfaUseCase: mfaUC,
+		validator:  validator.New(),
+	}
+}
+
+// EnrollTOTP godoc
+// @Summary Inicia o cadastro de um aplicativo autenticador (TOTP)
+// @Description Gera o segredo e o payload otpauth:// do QR code. O segundo fator só passa a ser exigido após a confirmação com o primeiro código.
+// @Tags MFA
+// @Produce json
+// @Security BearerAuth
+// @Success 201 {object} models.TOTPEnrollmentResponse
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 409 {string} string "Conflict"
+// @Failure 422 {string} string "Unprocessable Entity: a conta não possui senha"
+// @Failure 500 {string} string "Internal Server Error"
+// @Router /mfa/totp [post]
+func (m *mfaHandler) EnrollTOTP(w http.ResponseWriter, r *http.Request) {
+	principal, ok := middlewares.PrincipalFromContext(r.Context())
+	if !ok {
+		httpError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	setup, err := m.mfaUseCase.EnrollTOTP(r.Context(), principal.UserID)
+	if err != nil {
+		m.handleError(w, "enroll totp", err)
+		return
+	}
+
+	httpSuccess(w, http.StatusCreated, models.TOTPEnrollmentResponse{
+		Secret:     setup.Secret,
+		OTPAuthURI: setup.ProvisioningURI,
+	})
+}
+
+// ConfirmTOTP godoc
+// @Summary Confirma o aplicativo autenticador e ativa o segundo fator
+// @Description Valida o primeiro código gerado pelo aplicativo e retorna os códigos de recuperação, exibidos uma única vez
+// @Tags MFA
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param confirm body models.MFACodeRequest true "Código TOTP"
+// @Success 200 {object} models.RecoveryCodesResponse
+// @Failure 400 {string} string "Bad Request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 409 {string} string "Conflict"
+// @Failure 500 {string} string "Internal Server Error"
+// @Router /mfa/totp/confirm [post]
+func (m *mfaHandler) ConfirmTOTP(w http.ResponseWriter, r *http.Request) {
+	principal, req, ok := m.codeRequest(w, r)
+	if !ok {
+		return
+	}
+
+	codes, err := m.mfaUseCase.ConfirmTOTP(r.Context(), principal.UserID, req.Code)
+	if err != nil {
+		m.handleError(w, "confirm totp", err)
+		return
+	}
+
+	httpSuccess(w, http.StatusOK, models.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// DisableTOTP godoc
+// @Summary Desativa o segundo fator
+// @Description Exige um código TOTP atual ou um código de recuperação
+// @Tags MFA
+// @Accept json
+// @Security BearerAuth
+// @Param disable body models.MFACodeRequest true "Código TOTP ou de recuperação"
+// @Success 204 "No Content"
+// @Failure 400 {string} string "Bad Request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 500 {string} string "Internal Server Error"
+// @Router /mfa/totp [delete]
+func (m *mfaHandler) DisableTOTP(w http.ResponseWriter, r *http.Request) {
+	principal, req, ok := m.codeRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if err := m.mfaUseCase.DisableTOTP(r.Context(), principal.UserID, req.Code); err != nil {
+		m.handleError(w, "disable totp", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (m *mfaHandler) codeRequest(w http.ResponseWriter, r *http.Request) (*domain.Principal, models.MFACodeRequest, bool) {
+	var req models.MFACodeRequest
+
+	principal, ok := middlewares.PrincipalFromContext(r.Context())
+	if !ok {
+		httpError(w, http.StatusUnauthorized, "unauthorized")
+		return nil, req, false
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpError(w, http.StatusBadRequest, "invalid request payload")
+		return nil, req, false
+	}
+
+	if err := m.validator.Struct(req); err != nil {
+		httpError(w, http.StatusBadRequest, "validation error")
+		return nil, req, false
+	}
+
+	return principal, req, true
+}
+
+func (m *mfaHandler) handleError(w http.ResponseWriter, action string, err error) {
+	switch {
+	case errors.Is(err, domain.ErrInvalidMFACode), errors.Is(err, domain.ErrMFANotEnabled):
+		httpError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, domain.ErrMFAAlreadyEnabled):
+		httpError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, domain.ErrMFAUnsupported):
+		httpError(w, http.StatusUnprocessableEntity, err.Error())
+	case errors.Is(err, domain.ErrUserNotFound):
+		httpError(w, http.StatusNotFound, err.Error())
+	default:
+		httpError(w, http.StatusInternalServerError, fmt.Sprintf("%s failed: %v", action, err.Error()))
+	}
+}
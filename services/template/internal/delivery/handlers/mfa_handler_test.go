@@ -0,0 +1,184 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/delivery/handlers"
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/pkg/middlewares"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockMFAUseCase struct {
+	mock.Mock
+}
+
+func (m *MockMFAUseCase) EnrollTOTP(ctx context.Context, userID string) (*domain.TOTPSetup, error) {
+	args := m.Called(ctx, userID)
+	setup, _ := args.Get(0).(*domain.TOTPSetup)
+	return setup, args.Error(1)
+}
+
+func (m *MockMFAUseCase) ConfirmTOTP(ctx context.Context, userID, code string) ([]string, error) {
+	args := m.Called(ctx, userID, code)
+	codes, _ := args.Get(0).([]string)
+	return codes, args.Error(1)
+}
+
+func (m *MockMFAUseCase) DisableTOTP(ctx context.Context, userID, code string) error {
+	args := m.Called(ctx, userID, code)
+	return args.Error(0)
+}
+
+func (m *MockMFAUseCase) Required(ctx context.Context, userID string) (bool, error) {
+	args := m.Called(ctx, userID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockMFAUseCase) Verify(ctx context.Context, userID, code string) error {
+	args := m.Called(ctx, userID, code)
+	return args.Error(0)
+}
+
+func withUser(req *http.Request) *http.Request {
+	return req.WithContext(middlewares.WithPrincipal(req.Context(), &domain.Principal{UserID: "user-id"}))
+}
+
+func TestMFAHandler_EnrollTOTP(t *testing.T) {
+	tests := []struct {
+		name           string
+		authenticated  bool
+		mockSetup      *domain.TOTPSetup
+		mockErr        error
+		expectedStatus int
+	}{
+		{
+			name:           "success",
+			authenticated:  true,
+			mockSetup:      &domain.TOTPSetup{Secret: "SECRET", ProvisioningURI: "otpauth://totp/x"},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "unauthenticated",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "already enabled",
+			authenticated:  true,
+			mockErr:        domain.ErrMFAAlreadyEnabled,
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "account without password",
+			authenticated:  true,
+			mockErr:        domain.ErrMFAUnsupported,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUC := new(MockMFAUseCase)
+			handler := handlers.NewMFAHandler(mockUC)
+
+			mockUC.On("EnrollTOTP", mock.Anything, "user-id").Return(tt.mockSetup, tt.mockErr)
+
+			req := httptest.NewRequest(http.MethodPost, "/mfa/totp", nil)
+			if tt.authenticated {
+				req = withUser(req)
+			}
+			rec := httptest.NewRecorder()
+
+			handler.EnrollTOTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.mockSetup != nil {
+				assert.Contains(t, rec.Body.String(), `"otpauth_uri":"otpauth://totp/x"`)
+			}
+		})
+	}
+}
+
+func TestMFAHandler_ConfirmTOTP(t *testing.T) {
+	tests := []struct {
+		name           string
+		reqBody        string
+		mockCodes      []string
+		mockErr        error
+		expectedStatus int
+	}{
+		{
+			name:           "success",
+			reqBody:        `{"code": "123456"}`,
+			mockCodes:      []string{"abcde-fghij"},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "missing code",
+			reqBody:        `{}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid code",
+			reqBody:        `{"code": "123456"}`,
+			mockErr:        domain.ErrInvalidMFACode,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "usecase failure",
+			reqBody:        `{"code": "123456"}`,
+			mockErr:        errors.New("db error"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUC := new(MockMFAUseCase)
+			handler := handlers.NewMFAHandler(mockUC)
+
+			mockUC.On("ConfirmTOTP", mock.Anything, "user-id", "123456").Return(tt.mockCodes, tt.mockErr)
+
+			req := withUser(httptest.NewRequest(http.MethodPost, "/mfa/totp/confirm", bytes.NewBufferString(tt.reqBody)))
+			rec := httptest.NewRecorder()
+
+			handler.ConfirmTOTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestMFAHandler_DisableTOTP(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockErr        error
+		expectedStatus int
+	}{
+		{name: "success", expectedStatus: http.StatusNoContent},
+		{name: "invalid code", mockErr: domain.ErrInvalidMFACode, expectedStatus: http.StatusBadRequest},
+		{name: "not enabled", mockErr: domain.ErrMFANotEnabled, expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUC := new(MockMFAUseCase)
+			handler := handlers.NewMFAHandler(mockUC)
+
+			mockUC.On("DisableTOTP", mock.Anything, "user-id", "123456").Return(tt.mockErr)
+
+			req := withUser(httptest.NewRequest(http.MethodDelete, "/mfa/totp", bytes.NewBufferString(`{"code": "123456"}`)))
+			rec := httptest.NewRecorder()
+
+			handler.DisableTOTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/internal/usecases"
+)
+
+// maxWebhookBytes bounds the body of a webhook, the signature can only be checked once it is read
+const maxWebhookBytes = 1 << 20
+
+type PaymentWebhookHandler interface {
+	Receive(w http.ResponseWriter, r *http.Request)
+}
+
+type paymentWebhookHandler struct {
+	paymentUseCase  usecases.PaymentUseCase
+	signatureHeader string
+}
+
+// NewPaymentWebhookHandler reads the signature of the webhooks from signatureHeader
+// (services.PaymentProvider.SignatureHeader)
+func NewPaymentWebhookHandler(paymentUC usecases.PaymentUseCase, signatureHeader string) PaymentWebhookHandler {
+	return &paymentWebhookHandler{
+		paymentUseCase:  paymentUC,
+		signatureHeader: signatureHeader,
+	}
+}
+
+// Receive godoc
+// @Summary Recebe os webhooks do provedor de pagamentos
+// @Description Verifica a assinatura HMAC (t=...,v1=...), registra o evento uma única vez e aplica a mudança da assinatura ao plano do usuário. Eventos repetidos são aceitos e ignorados.
+// @Tags Payments
+// @Accept json
+// @Param Stripe-Signature header string true "Assinatura do webhook"
+// @Success 204 "No Content"
+// @Failure 400 {string} string "Bad Request: assinatura ou payload inválido"
+// @Failure 500 {string} string "Internal Server Error: o provedor reenvia o evento"
+// @Router /webhooks/payments [post]
+func (h *paymentWebhookHandler) Receive(w http.ResponseWriter, r *http.Request) {
+	// The signature covers the exact bytes sent, the body is passed on untouched
+	payload, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBytes))
+	if err != nil {
+		httpError(w, http.StatusBadRequest, "invalid request payload")
+		return
+	}
+
+	if err := h.paymentUseCase.HandleWebhook(r.Context(), payload, r.Header.Get(h.signatureHeader)); err != nil {
+		h.handleError(w, "handle payment webhook", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *paymentWebhookHandler) handleError(w http.ResponseWriter, action string, err error) {
+	switch {
+	case errors.Is(err, domain.ErrInvalidWebhookSignature), errors.Is(err, domain.ErrInvalidWebhookPayload):
+		httpError(w, http.StatusBadRequest, err.Error())
+	default:
+		httpError(w, http.StatusInternalServerError, fmt.Sprintf("%s failed: %v", action, err.Error()))
+	}
+}
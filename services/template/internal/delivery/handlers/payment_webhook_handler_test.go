@@ -0,0 +1,70 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/delivery/handlers"
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPaymentUseCase struct {
+	mock.Mock
+}
+
+func (m *MockPaymentUseCase) HandleWebhook(ctx context.Context, payload []byte, signature string) error {
+	args := m.Called(ctx, payload, signature)
+	return args.Error(0)
+}
+
+func TestPaymentWebhookHandler_Receive(t *testing.T) {
+	payload := []byte(`{"id":"evt_1","type":"customer.subscription.updated"}`)
+
+	tests := []struct {
+		name           string
+		mockErr        error
+		expectedStatus int
+	}{
+		{name: "applied", expectedStatus: http.StatusNoContent},
+		{name: "invalid signature", mockErr: domain.ErrInvalidWebhookSignature, expectedStatus: http.StatusBadRequest},
+		{name: "invalid payload", mockErr: domain.ErrInvalidWebhookPayload, expectedStatus: http.StatusBadRequest},
+		{name: "failure is retried by the provider", mockErr: errors.New("db down"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUC := new(MockPaymentUseCase)
+			handler := handlers.NewPaymentWebhookHandler(mockUC, "Stripe-Signature")
+
+			mockUC.On("HandleWebhook", mock.Anything, payload, "t=1,v1=abc").Return(tt.mockErr)
+
+			req := httptest.NewRequest(http.MethodPost, "/webhooks/payments", bytes.NewReader(payload))
+			req.Header.Set("Stripe-Signature", "t=1,v1=abc")
+			rec := httptest.NewRecorder()
+
+			handler.Receive(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			mockUC.AssertExpectations(t)
+		})
+	}
+
+	t.Run("body too large", func(t *testing.T) {
+		mockUC := new(MockPaymentUseCase)
+		handler := handlers.NewPaymentWebhookHandler(mockUC, "Stripe-Signature")
+
+		req := httptest.NewRequest(http.MethodPost, "/webhooks/payments", bytes.NewReader(make([]byte, 1<<20+1)))
+		rec := httptest.NewRecorder()
+
+		handler.Receive(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		mockUC.AssertNotCalled(t, "HandleWebhook", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,240 @@
+package handlers
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/internal/models"
+	"github.com/nuhorizon/go-project-template/services/template/internal/usecases"
+	"github.com/nuhorizon/go-project-template/services/template/pkg/middlewares"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type UserHandler interface {
+	GetMe(w http.ResponseWriter, r *http.Request)
+	UpdateMe(w http.ResponseWriter, r *http.Request)
+	DeleteMe(w http.ResponseWriter, r *http.Request)
+	ExportMe(w http.ResponseWriter, r *http.Request)
+}
+
+type userHandler struct {
+	userUseCase usecases.UserUseCase
+	validator   *validator.Validate
+}
+
+func NewUserHandler(userUC usecases.UserUseCase) UserHandler {
+	return &userHandler{
+		userUseCase: userUC,
+		validator:   validator.New(),
+	}
+}
+
+// GetMe godoc
+// @Summary Retorna o perfil do usuário autenticado
+// @Tags Users
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.UserResponse
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Not Found"
+// @Failure 500 {string} string "Internal Server Error"
+// @Router /users/me [get]
+func (u *userHandler) GetMe(w http.ResponseWriter, r *http.Request) {
+	principal, ok := middlewares.PrincipalFromContext(r.Context())
+	if !ok {
+		httpError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	user, err := u.userUseCase.GetProfile(r.Context(), principal.UserID)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			httpError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		httpError(w, http.StatusInternalServerError, fmt.Sprintf("get profile failed: %v", err.Error()))
+		return
+	}
+
+	httpSuccess(w, http.StatusOK, toUserResponse(user))
+}
+
+// UpdateMe godoc
+// @Summary Atualiza o perfil do usuário autenticado
+// @Description Atualização parcial: apenas os campos enviados são alterados. Envie picture_url vazio para remover a foto.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param user body models.UpdateUserRequest true "Nome e/ou foto"
+// @Success 200 {object} models.UserResponse
+// @Failure 400 {string} string "Bad Request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Not Found"
+// @Failure 500 {string} string "Internal Server Error"
+// @Router /users/me [patch]
+func (u *userHandler) UpdateMe(w http.ResponseWriter, r *http.Request) {
+	principal, ok := middlewares.PrincipalFromContext(r.Context())
+	if !ok {
+		httpError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req models.UpdateUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpError(w, http.StatusBadRequest, "invalid request payload")
+		return
+	}
+
+	// A blank name would pass the length check
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		req.Name = &name
+	}
+	if err := u.validator.Struct(req); err != nil {
+		httpError(w, http.StatusBadRequest, "validation error")
+		return
+	}
+
+	user, err := u.userUseCase.UpdateProfile(r.Context(), principal.UserID, req.Name, req.PictureURL)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			httpError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		httpError(w, http.StatusInternalServerError, fmt.Sprintf("update profile failed: %v", err.Error()))
+		return
+	}
+
+	httpSuccess(w, http.StatusOK, toUserResponse(user))
+}
+
+// DeleteMe godoc
+// @Summary Exclui a conta do usuário autenticado
+// @Description A conta é desativada na hora (sessões encerradas e conta do Firebase removida) e os dados são apagados definitivamente após o período de retenção
+// @Tags Users
+// @Security BearerAuth
+// @Success 204 "No Content"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden: chaves de API não podem excluir a conta"
+// @Failure 404 {string} string "Not Found"
+// @Failure 500 {string} string "Internal Server Error"
+// @Router /users/me [delete]
+func (u *userHandler) DeleteMe(w http.ResponseWriter, r *http.Request) {
+	principal, ok := middlewares.PrincipalFromContext(r.Context())
+	if !ok {
+		httpError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	if err := u.userUseCase.DeleteAccount(r.Context(), principal.UserID); err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			httpError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		log.Printf("delete account of user %s: %v", principal.UserID, err)
+		httpError(w, http.StatusInternalServerError, fmt.Sprintf("delete account failed: %v", err.Error()))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ExportMe godoc
+// @Summary Exporta os dados do usuário autenticado (LGPD/GDPR)
+// @Description Retorna todos os registros do usuário (perfil, sessões, chaves de API, gatos, ...) sem senhas ou tokens. Com format=zip cada seção vira um arquivo JSON dentro do ZIP.
+// @Tags Users
+// @Produce json
+// @Produce application/zip
+// @Security BearerAuth
+// @Param format query string false "json (padrão) ou zip"
+// @Success 200 {object} models.UserDataExportResponse
+// @Failure 400 {string} string "Bad Request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden: chaves de API não podem exportar a conta"
+// @Failure 404 {string} string "Not Found"
+// @Failure 500 {string} string "Internal Server Error"
+// @Router /users/me/export [get]
+func (u *userHandler) ExportMe(w http.ResponseWriter, r *http.Request) {
+	principal, ok := middlewares.PrincipalFromContext(r.Context())
+	if !ok {
+		httpError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "zip" {
+		httpError(w, http.StatusBadRequest, "format must be json or zip")
+		return
+	}
+
+	export, err := u.userUseCase.ExportData(r.Context(), principal.UserID)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			httpError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		httpError(w, http.StatusInternalServerError, fmt.Sprintf("export failed: %v", err.Error()))
+		return
+	}
+
+	if format != "zip" {
+		httpSuccess(w, http.StatusOK, models.UserDataExportResponse{
+			UserID:      export.UserID,
+			GeneratedAt: export.GeneratedAt,
+			Data:        export.Sections,
+		})
+		return
+	}
+
+	archive, err := zipExport(export)
+	if err != nil {
+		httpError(w, http.StatusInternalServerError, fmt.Sprintf("export failed: %v", err.Error()))
+		return
+	}
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="export-%s.zip"`, export.GeneratedAt.Format("20060102")))
+	w.WriteHeader(http.StatusOK)
+	w.Write(archive)
+}
+
+// zipExport writes each section of the export to its own "<section>.json" file. The archive is
+// built in memory so a failure can still be reported with a proper status.
+func zipExport(export *domain.UserDataExport) ([]byte, error) {
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+
+	names := make([]string, 0, len(export.Sections))
+	for name := range export.Sections {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		file, err := archive.CreateHeader(&zip.FileHeader{Name: name + ".json", Method: zip.Deflate, Modified: export.GeneratedAt})
+		if err != nil {
+			return nil, err
+		}
+		var indented bytes.Buffer
+		if err := json.Indent(&indented, export.Sections[name], "", "  "); err != nil {
+			return nil, err
+		}
+		if _, err := file.Write(indented.Bytes()); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
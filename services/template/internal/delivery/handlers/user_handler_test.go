@@ -0,0 +1,310 @@
+package handlers_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/delivery/handlers"
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockUserUseCase struct {
+	mock.Mock
+}
+
+func (m *MockUserUseCase) GetProfile(ctx context.Context, userID string) (*domain.User, error) {
+	args := m.Called(ctx, userID)
+	user, _ := args.Get(0).(*domain.User)
+	return user, args.Error(1)
+}
+
+func (m *MockUserUseCase) UpdateProfile(ctx context.Context, userID string, name, pictureURL *string) (*domain.User, error) {
+	args := m.Called(ctx, userID, name, pictureURL)
+	user, _ := args.Get(0).(*domain.User)
+	return user, args.Error(1)
+}
+
+func (m *MockUserUseCase) DeleteAccount(ctx context.Context, userID string) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockUserUseCase) ExportData(ctx context.Context, userID string) (*domain.UserDataExport, error) {
+	args := m.Called(ctx, userID)
+	export, _ := args.Get(0).(*domain.UserDataExport)
+	return export, args.Error(1)
+}
+
+func TestUserHandler_GetMe(t *testing.T) {
+	tests := []struct {
+		name           string
+		authenticated  bool
+		mockUser       *domain.User
+		mockErr        error
+		expectedStatus int
+	}{
+		{
+			name:           "success",
+			authenticated:  true,
+			mockUser:       &domain.User{ID: "user-id", Name: "Test User", Email: "user@example.com", PlanType: "free", EmailVerified: true},
+			expectedStatus: http.StatusOK,
+		},
+		{name: "unauthenticated", expectedStatus: http.StatusUnauthorized},
+		{name: "deleted user", authenticated: true, mockErr: domain.ErrUserNotFound, expectedStatus: http.StatusNotFound},
+		{name: "usecase failure", authenticated: true, mockErr: errors.New("db error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUC := new(MockUserUseCase)
+			mockUC.On("GetProfile", mock.Anything, "user-id").Return(tt.mockUser, tt.mockErr).Maybe()
+			handler := handlers.NewUserHandler(mockUC)
+
+			req := httptest.NewRequest(http.MethodGet, "/users/me", nil)
+			if tt.authenticated {
+				req = withUser(req)
+			}
+			rec := httptest.NewRecorder()
+			handler.GetMe(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response models.UserResponse
+				assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+				assert.Equal(t, "user@example.com", response.Email)
+				assert.True(t, response.EmailVerified)
+			}
+		})
+	}
+}
+
+func TestUserHandler_UpdateMe(t *testing.T) {
+	tests := []struct {
+		name           string
+		reqBody        string
+		authenticated  bool
+		callUseCase    bool
+		expectName     *string
+		expectPicture  *string
+		mockErr        error
+		expectedStatus int
+	}{
+		{
+			name:           "name only",
+			reqBody:        `{"name": "  New Name "}`,
+			authenticated:  true,
+			callUseCase:    true,
+			expectName:     ptr("New Name"),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "picture only",
+			reqBody:        `{"picture_url": "https://cdn.example.com/me.png"}`,
+			authenticated:  true,
+			callUseCase:    true,
+			expectPicture:  ptr("https://cdn.example.com/me.png"),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "empty picture removes it",
+			reqBody:        `{"picture_url": ""}`,
+			authenticated:  true,
+			callUseCase:    true,
+			expectPicture:  ptr(""),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "unauthenticated",
+			reqBody:        `{"name": "New Name"}`,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "invalid json",
+			reqBody:        `invalid-json`,
+			authenticated:  true,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "blank name",
+			reqBody:        `{"name": "   "}`,
+			authenticated:  true,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "picture is not an http url",
+			reqBody:        `{"picture_url": "javascript:alert(1)"}`,
+			authenticated:  true,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "deleted user",
+			reqBody:        `{"name": "New Name"}`,
+			authenticated:  true,
+			callUseCase:    true,
+			expectName:     ptr("New Name"),
+			mockErr:        domain.ErrUserNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "usecase failure",
+			reqBody:        `{"name": "New Name"}`,
+			authenticated:  true,
+			callUseCase:    true,
+			expectName:     ptr("New Name"),
+			mockErr:        errors.New("db error"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUC := new(MockUserUseCase)
+			handler := handlers.NewUserHandler(mockUC)
+
+			if tt.callUseCase {
+				var user *domain.User
+				if tt.mockErr == nil {
+					user = &domain.User{ID: "user-id", Name: "New Name"}
+				}
+				mockUC.On("UpdateProfile", mock.Anything, "user-id", tt.expectName, tt.expectPicture).Return(user, tt.mockErr)
+			}
+
+			req := httptest.NewRequest(http.MethodPatch, "/users/me", bytes.NewBufferString(tt.reqBody))
+			if tt.authenticated {
+				req = withUser(req)
+			}
+			rec := httptest.NewRecorder()
+			handler.UpdateMe(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			mockUC.AssertExpectations(t)
+			if !tt.callUseCase {
+				mockUC.AssertNotCalled(t, "UpdateProfile", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func ptr(s string) *string {
+	return &s
+}
+
+func TestUserHandler_DeleteMe(t *testing.T) {
+	tests := []struct {
+		name           string
+		authenticated  bool
+		mockErr        error
+		expectedStatus int
+	}{
+		{name: "success", authenticated: true, expectedStatus: http.StatusNoContent},
+		{name: "unauthenticated", expectedStatus: http.StatusUnauthorized},
+		{name: "already deleted", authenticated: true, mockErr: domain.ErrUserNotFound, expectedStatus: http.StatusNotFound},
+		{name: "usecase failure", authenticated: true, mockErr: errors.New("firebase unavailable"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUC := new(MockUserUseCase)
+			mockUC.On("DeleteAccount", mock.Anything, "user-id").Return(tt.mockErr).Maybe()
+			handler := handlers.NewUserHandler(mockUC)
+
+			req := httptest.NewRequest(http.MethodDelete, "/users/me", nil)
+			if tt.authenticated {
+				req = withUser(req)
+			}
+			rec := httptest.NewRecorder()
+			handler.DeleteMe(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if !tt.authenticated {
+				mockUC.AssertNotCalled(t, "DeleteAccount", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestUserHandler_ExportMe(t *testing.T) {
+	export := &domain.UserDataExport{
+		UserID:      "user-id",
+		GeneratedAt: time.Date(2026, 5, 31, 12, 0, 0, 0, time.UTC),
+		Sections: map[string]json.RawMessage{
+			"user": json.RawMessage(`[{"id":"user-id","email":"user@example.com"}]`),
+			"cats": json.RawMessage(`[]`),
+		},
+	}
+
+	tests := []struct {
+		name           string
+		query          string
+		authenticated  bool
+		mockErr        error
+		expectedStatus int
+	}{
+		{name: "json", authenticated: true, expectedStatus: http.StatusOK},
+		{name: "zip", query: "?format=zip", authenticated: true, expectedStatus: http.StatusOK},
+		{name: "unknown format", query: "?format=csv", authenticated: true, expectedStatus: http.StatusBadRequest},
+		{name: "unauthenticated", expectedStatus: http.StatusUnauthorized},
+		{name: "deleted user", authenticated: true, mockErr: domain.ErrUserNotFound, expectedStatus: http.StatusNotFound},
+		{name: "usecase failure", authenticated: true, mockErr: errors.New("db error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUC := new(MockUserUseCase)
+			var result *domain.UserDataExport
+			if tt.mockErr == nil {
+				result = export
+			}
+			mockUC.On("ExportData", mock.Anything, "user-id").Return(result, tt.mockErr).Maybe()
+			handler := handlers.NewUserHandler(mockUC)
+
+			req := httptest.NewRequest(http.MethodGet, "/users/me/export"+tt.query, nil)
+			if tt.authenticated {
+				req = withUser(req)
+			}
+			rec := httptest.NewRecorder()
+			handler.ExportMe(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			if tt.query == "" {
+				var response models.UserDataExportResponse
+				assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+				assert.Equal(t, "user-id", response.UserID)
+				assert.JSONEq(t, `[{"id":"user-id","email":"user@example.com"}]`, string(response.Data["user"]))
+				assert.JSONEq(t, `[]`, string(response.Data["cats"]))
+				return
+			}
+
+			assert.Equal(t, "application/zip", rec.Header().Get("Content-Type"))
+			assert.Equal(t, `attachment; filename="export-20260531.zip"`, rec.Header().Get("Content-Disposition"))
+			archive, err := zip.NewReader(bytes.NewReader(rec.Body.Bytes()), int64(rec.Body.Len()))
+			assert.NoError(t, err)
+			files := map[string]string{}
+			for _, file := range archive.File {
+				f, err := file.Open()
+				assert.NoError(t, err)
+				content, _ := io.ReadAll(f)
+				f.Close()
+				files[file.Name] = string(content)
+			}
+			assert.Len(t, files, 2)
+			assert.JSONEq(t, `[{"id":"user-id","email":"user@example.com"}]`, files["user.json"])
+			assert.JSONEq(t, `[]`, files["cats.json"])
+		})
+	}
+}
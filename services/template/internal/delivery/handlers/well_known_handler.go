@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/models"
+	"github.com/nuhorizon/go-project-template/services/template/internal/ports/services"
+)
+
+type WellKnownHandler interface {
+	JWKS(w http.ResponseWriter, r *http.Request)
+}
+
+type wellKnownHandler struct {
+	jwtService services.JWTService
+}
+
+func NewWellKnownHandler(jwtService services.JWTService) WellKnownHandler {
+	return &wellKnownHandler{jwtService: jwtService}
+}
+
+// JWKS godoc
+// @Summary Publica as chaves públicas usadas para assinar os tokens da aplicação
+// @Description Retorna o JWK Set (RFC 7517) para que outros serviços validem os tokens sem compartilhar segredo
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} models.JWKSResponse
+// @Router /.well-known/jwks.json [get]
+func (h *wellKnownHandler) JWKS(w http.ResponseWriter, r *http.Request) {
+	response := models.JWKSResponse{Keys: []models.JWKResponse{}}
+	for _, key := range h.jwtService.PublicKeys() {
+		if jwk, ok := toJWKResponse(key); ok {
+			response.Keys = append(response.Keys, jwk)
+		}
+	}
+	sort.Slice(response.Keys, func(i, j int) bool { return response.Keys[i].Kid < response.Keys[j].Kid })
+
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	httpSuccess(w, http.StatusOK, response)
+}
@@ -0,0 +1,114 @@
+package handlers_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/delivery/handlers"
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/internal/models"
+	"github.com/nuhorizon/go-project-template/services/template/internal/ports/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockJWTService struct {
+	mock.Mock
+}
+
+func (m *MockJWTService) GenerateToken(user *domain.User, sessionID string) (string, error) {
+	args := m.Called(user, sessionID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockJWTService) ValidateToken(token string) (*domain.TokenClaims, error) {
+	args := m.Called(token)
+	claims, _ := args.Get(0).(*domain.TokenClaims)
+	return claims, args.Error(1)
+}
+
+func (m *MockJWTService) GenerateMFAToken(userID string) (string, error) {
+	args := m.Called(userID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockJWTService) ValidateMFAToken(token string) (*domain.TokenClaims, error) {
+	args := m.Called(token)
+	claims, _ := args.Get(0).(*domain.TokenClaims)
+	return claims, args.Error(1)
+}
+
+func (m *MockJWTService) GenerateMagicLinkToken(linkID, email string, expiresAt time.Time) (string, error) {
+	args := m.Called(linkID, email, expiresAt)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockJWTService) ValidateMagicLinkToken(token string) (string, string, error) {
+	args := m.Called(token)
+	return args.String(0), args.String(1), args.Error(2)
+}
+
+func (m *MockJWTService) GenerateEmailVerificationToken(userID, email string) (string, error) {
+	args := m.Called(userID, email)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockJWTService) ValidateEmailVerificationToken(token string) (string, string, error) {
+	args := m.Called(token)
+	return args.String(0), args.String(1), args.Error(2)
+}
+
+func (m *MockJWTService) PublicKeys() []services.PublicKey {
+	args := m.Called()
+	keys, _ := args.Get(0).([]services.PublicKey)
+	return keys
+}
+
+func TestWellKnownHandler_JWKS(t *testing.T) {
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		keys         []services.PublicKey
+		expectedKids []string
+	}{
+		{
+			name:         "publishes keys sorted by kid",
+			keys:         []services.PublicKey{{Kid: "b", Algorithm: "EdDSA", Key: pub}, {Kid: "a", Algorithm: "EdDSA", Key: pub}},
+			expectedKids: []string{"a", "b"},
+		},
+		{
+			name:         "HS256 returns an empty set",
+			expectedKids: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockJWT := new(MockJWTService)
+			mockJWT.On("PublicKeys").Return(tt.keys)
+			handler := handlers.NewWellKnownHandler(mockJWT)
+
+			req := httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+			rec := httptest.NewRecorder()
+
+			handler.JWKS(rec, req)
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+
+			var response models.JWKSResponse
+			assert.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+			kids := []string{}
+			for _, key := range response.Keys {
+				kids = append(kids, key.Kid)
+			}
+			assert.Equal(t, tt.expectedKids, kids)
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package routes
+
+import (
+	"net/http"
+
+	handlers "github.com/nuhorizon/go-project-template/services/template/internal/delivery/handlers"
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	middlewares "github.com/nuhorizon/go-project-template/services/template/pkg/middlewares"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func RegisterAdminRoutes(r chi.Router, h handlers.AdminHandler, authMiddleware func(http.Handler) http.Handler) {
+	r.Route("/admin", func(r chi.Router) {
+		r.Use(authMiddleware)
+		r.Use(middlewares.RequireRole(domain.RoleAdmin))
+
+		r.With(middlewares.RequirePermission(domain.PermissionUsersRead)).
+			Get("/users", h.ListUsers) // GET /admin/users - Lista os usuários (filtros, ordenação e cursor)
+		r.With(middlewares.RequirePermission(domain.PermissionUsersRead)).
+			Get("/users/{id}", h.GetUser) // GET /admin/users/{id} - Detalhes do usuário com seus papéis
+		r.With(middlewares.RequirePermission(domain.PermissionUsersWrite)).
+			Put("/users/{id}/plan", h.UpdatePlan) // PUT /admin/users/{id}/plan - Altera o plano do usuário
+		r.With(middlewares.RequirePermission(domain.PermissionUsersWrite)).
+			Post("/users/{id}/disable", h.DisableUser) // POST /admin/users/{id}/disable - Desativa a conta e encerra as sessões
+		r.With(middlewares.RequirePermission(domain.PermissionUsersWrite)).
+			Post("/users/{id}/enable", h.EnableUser) // POST /admin/users/{id}/enable - Reativa a conta
+		r.With(middlewares.RequirePermission(domain.PermissionRolesManage)).
+			Put("/users/{id}/roles/{role}", h.AssignRole) // PUT /admin/users/{id}/roles/{role} - Concede um papel ao usuário
+		r.With(middlewares.RequirePermission(domain.PermissionRolesManage)).
+			Delete("/users/{id}/roles/{role}", h.RemoveRole) // DELETE /admin/users/{id}/roles/{role} - Remove um papel do usuário
+		r.With(middlewares.RequirePermission(domain.PermissionSessionsRevoke)).
+			Post("/users/{id}/revoke-sessions", h.RevokeSessions) // POST /admin/users/{id}/revoke-sessions - Encerra todas as sessões do usuário
+	})
+}
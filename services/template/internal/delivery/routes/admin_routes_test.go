@@ -0,0 +1,117 @@
+package routes_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/nuhorizon/go-project-template/services/template/internal/delivery/routes"
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/pkg/middlewares"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAdminHandler struct {
+	mock.Mock
+}
+
+func (m *MockAdminHandler) AssignRole(w http.ResponseWriter, r *http.Request) {
+	m.Called(w, r)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (m *MockAdminHandler) RemoveRole(w http.ResponseWriter, r *http.Request) {
+	m.Called(w, r)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (m *MockAdminHandler) RevokeSessions(w http.ResponseWriter, r *http.Request) {
+	m.Called(w, r)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (m *MockAdminHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
+	m.Called(w, r)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (m *MockAdminHandler) GetUser(w http.ResponseWriter, r *http.Request) {
+	m.Called(w, r)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (m *MockAdminHandler) UpdatePlan(w http.ResponseWriter, r *http.Request) {
+	m.Called(w, r)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (m *MockAdminHandler) DisableUser(w http.ResponseWriter, r *http.Request) {
+	m.Called(w, r)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (m *MockAdminHandler) EnableUser(w http.ResponseWriter, r *http.Request) {
+	m.Called(w, r)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// withPrincipal stands in for the auth middleware, authenticating the given principal
+func withPrincipal(principal *domain.Principal) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(middlewares.WithPrincipal(r.Context(), principal)))
+		})
+	}
+}
+
+func TestRegisterAdminRoutes(t *testing.T) {
+	admin := &domain.Principal{
+		UserID:      "admin-id",
+		Roles:       []string{domain.RoleAdmin},
+		Permissions: []string{domain.PermissionUsersRead, domain.PermissionUsersWrite, domain.PermissionRolesManage, domain.PermissionSessionsRevoke},
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		principal  *domain.Principal
+		expectCode int
+		mockMethod string
+	}{
+		{"AssignRole route", http.MethodPut, "/admin/users/user-id/roles/admin", admin, http.StatusNoContent, "AssignRole"},
+		{"RemoveRole route", http.MethodDelete, "/admin/users/user-id/roles/admin", admin, http.StatusNoContent, "RemoveRole"},
+		{"RevokeSessions route", http.MethodPost, "/admin/users/user-id/revoke-sessions", admin, http.StatusNoContent, "RevokeSessions"},
+		{"ListUsers route", http.MethodGet, "/admin/users", admin, http.StatusOK, "ListUsers"},
+		{"GetUser route", http.MethodGet, "/admin/users/user-id", admin, http.StatusOK, "GetUser"},
+		{"UpdatePlan route", http.MethodPut, "/admin/users/user-id/plan", admin, http.StatusOK, "UpdatePlan"},
+		{"DisableUser route", http.MethodPost, "/admin/users/user-id/disable", admin, http.StatusNoContent, "DisableUser"},
+		{"EnableUser route", http.MethodPost, "/admin/users/user-id/enable", admin, http.StatusNoContent, "EnableUser"},
+		{"read-only admin can't disable users", http.MethodPost, "/admin/users/user-id/disable", &domain.Principal{UserID: "admin-id", Roles: []string{domain.RoleAdmin}, Permissions: []string{domain.PermissionUsersRead}}, http.StatusForbidden, ""},
+		{"non admin is forbidden", http.MethodPost, "/admin/users/user-id/revoke-sessions", &domain.Principal{UserID: "user-id"}, http.StatusForbidden, ""},
+		{"admin without permission is forbidden", http.MethodPut, "/admin/users/user-id/roles/admin", &domain.Principal{UserID: "admin-id", Roles: []string{domain.RoleAdmin}}, http.StatusForbidden, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := chi.NewRouter()
+			mockHandler := new(MockAdminHandler)
+
+			if tt.mockMethod != "" {
+				mockHandler.On(tt.mockMethod, mock.Anything, mock.Anything).Once()
+			}
+
+			routes.RegisterAdminRoutes(r, mockHandler, withPrincipal(tt.principal))
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			rec := httptest.NewRecorder()
+
+			r.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectCode, rec.Code)
+			mockHandler.AssertExpectations(t)
+		})
+	}
+}
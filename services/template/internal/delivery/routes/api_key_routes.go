@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"net/http"
+
+	handlers "github.com/nuhorizon/go-project-template/services/template/internal/delivery/handlers"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func RegisterAPIKeyRoutes(r chi.Router, h handlers.APIKeyHandler, authMiddleware func(http.Handler) http.Handler) {
+	r.Route("/api-keys", func(r chi.Router) {
+		r.Use(authMiddleware)
+
+		r.Post("/", h.Create)       // POST /api-keys - Cria uma chave de API pessoal
+		r.Get("/", h.List)          // GET /api-keys - Lista as chaves de API do usuário
+		r.Delete("/{id}", h.Revoke) // DELETE /api-keys/{id} - Revoga uma chave de API
+	})
+}
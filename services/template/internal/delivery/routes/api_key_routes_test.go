@@ -0,0 +1,64 @@
+package routes_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/nuhorizon/go-project-template/services/template/internal/delivery/routes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAPIKeyHandler struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyHandler) Create(w http.ResponseWriter, r *http.Request) {
+	m.Called(w, r)
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (m *MockAPIKeyHandler) List(w http.ResponseWriter, r *http.Request) {
+	m.Called(w, r)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (m *MockAPIKeyHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	m.Called(w, r)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func TestRegisterAPIKeyRoutes(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		expectCode int
+		mockMethod string
+	}{
+		{"Create route", http.MethodPost, "/api-keys", http.StatusCreated, "Create"},
+		{"List route", http.MethodGet, "/api-keys", http.StatusOK, "List"},
+		{"Revoke route", http.MethodDelete, "/api-keys/key-id", http.StatusNoContent, "Revoke"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := chi.NewRouter()
+			mockHandler := new(MockAPIKeyHandler)
+			mockHandler.On(tt.mockMethod, mock.Anything, mock.Anything).Once()
+
+			routes.RegisterAPIKeyRoutes(r, mockHandler, passThrough)
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			rec := httptest.NewRecorder()
+
+			r.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectCode, rec.Code)
+			assert.Equal(t, "true", rec.Header().Get("X-Auth-Checked"))
+			mockHandler.AssertExpectations(t)
+		})
+	}
+}
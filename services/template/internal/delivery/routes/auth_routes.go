@@ -1,16 +1,30 @@
 package routes
 
 import (
+	"net/http"
+
 	handlers "github.com/nuhorizon/go-project-template/services/template/internal/delivery/handlers"
 
 	"github.com/go-chi/chi/v5"
 )
 
-func RegisterAuthRoutes(r chi.Router, h handlers.AuthHandler) {
+func RegisterAuthRoutes(r chi.Router, h handlers.AuthHandler, authMiddleware func(http.Handler) http.Handler) {
 	r.Route("/auth", func(r chi.Router) {
-		r.Post("/login", h.Login)                  // POST /auth/login - Recebe Firebase token e faz login/sync
-		r.Post("/register", h.Register)            // POST /auth/register - (Opcional) registro direto
-		r.Post("/reset-password", h.ResetPassword) // POST /auth/reset-password - Esqueci minha senha
-		r.Post("/exchange-token", h.ExchangeToken) // POST /auth/exchange-token - (opcional) fluxo para trocar token
+		r.Post("/login", h.Login)                      // POST /auth/login - Recebe o token do Firebase ou de um provedor OIDC e faz login/sync
+		r.Post("/register", h.Register)                // POST /auth/register - Registro direto com e-mail e senha
+		r.Post("/direct-login", h.DirectLogin)         // POST /auth/direct-login - Login direto com e-mail e senha
+		r.Post("/mfa/verify", h.VerifyMFA)             // POST /auth/mfa/verify - Conclui o login direto com o segundo fator
+		r.Post("/reset-password", h.ResetPassword)     // POST /auth/reset-password - Esqueci minha senha
+		r.Post("/magic-link", h.RequestMagicLink)      // POST /auth/magic-link - Envia o link de login sem senha por e-mail
+		r.Get("/magic-link/verify", h.VerifyMagicLink) // GET /auth/magic-link/verify - Conclui o login com o token do link
+		r.Get("/verify-email/confirm", h.VerifyEmail)  // GET /auth/verify-email/confirm - Confirma o e-mail com o token do link
+		r.Post("/exchange-token", h.ExchangeToken)     // POST /auth/exchange-token - Troca o refresh token por um novo par
+
+		r.With(authMiddleware).Post("/verify-email", h.SendEmailVerification) // POST /auth/verify-email - Reenvia o link de verificação de e-mail
+		r.With(authMiddleware).Post("/logout", h.Logout)                      // POST /auth/logout - Revoga os tokens da sessão atual
+		r.With(authMiddleware).Get("/identities", h.ListIdentities)           // GET /auth/identities - Provedores vinculados à conta
+		r.With(authMiddleware).Post("/identities", h.LinkIdentity)            // POST /auth/identities - Vincula outro provedor à conta
+		r.With(authMiddleware).Get("/sessions", h.ListSessions)               // GET /auth/sessions - Sessões ativas (dispositivos) do usuário
+		r.With(authMiddleware).Delete("/sessions/{id}", h.RevokeSession)      // DELETE /auth/sessions/{id} - Encerra uma sessão
 	})
 }
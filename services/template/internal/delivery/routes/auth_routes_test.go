@@ -26,6 +26,36 @@ func (m *MockAuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusCreated)
 }
 
+func (m *MockAuthHandler) DirectLogin(w http.ResponseWriter, r *http.Request) {
+	m.Called(w, r)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (m *MockAuthHandler) VerifyMFA(w http.ResponseWriter, r *http.Request) {
+	m.Called(w, r)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (m *MockAuthHandler) RequestMagicLink(w http.ResponseWriter, r *http.Request) {
+	m.Called(w, r)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (m *MockAuthHandler) VerifyMagicLink(w http.ResponseWriter, r *http.Request) {
+	m.Called(w, r)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (m *MockAuthHandler) SendEmailVerification(w http.ResponseWriter, r *http.Request) {
+	m.Called(w, r)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (m *MockAuthHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	m.Called(w, r)
+	w.WriteHeader(http.StatusNoContent)
+}
+
 func (m *MockAuthHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
 	m.Called(w, r)
 	w.WriteHeader(http.StatusAccepted)
@@ -36,6 +66,39 @@ func (m *MockAuthHandler) ExchangeToken(w http.ResponseWriter, r *http.Request)
 	w.WriteHeader(http.StatusNoContent)
 }
 
+func (m *MockAuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	m.Called(w, r)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (m *MockAuthHandler) LinkIdentity(w http.ResponseWriter, r *http.Request) {
+	m.Called(w, r)
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (m *MockAuthHandler) ListIdentities(w http.ResponseWriter, r *http.Request) {
+	m.Called(w, r)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (m *MockAuthHandler) ListSessions(w http.ResponseWriter, r *http.Request) {
+	m.Called(w, r)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (m *MockAuthHandler) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	m.Called(w, r)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// passThrough stands in for the auth middleware, flagging requests that went through it
+func passThrough(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Auth-Checked", "true")
+		next.ServeHTTP(w, r)
+	})
+}
+
 func TestRegisterAuthRoutes(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -43,11 +106,23 @@ func TestRegisterAuthRoutes(t *testing.T) {
 		path       string
 		expectCode int
 		mockMethod string
+		protected  bool
 	}{
-		{"Login route", http.MethodPost, "/auth/login", http.StatusOK, "Login"},
-		{"Register route", http.MethodPost, "/auth/register", http.StatusCreated, "Register"},
-		{"ResetPassword route", http.MethodPost, "/auth/reset-password", http.StatusAccepted, "ResetPassword"},
-		{"ExchangeToken route", http.MethodPost, "/auth/exchange-token", http.StatusNoContent, "ExchangeToken"},
+		{"Login route", http.MethodPost, "/auth/login", http.StatusOK, "Login", false},
+		{"Register route", http.MethodPost, "/auth/register", http.StatusCreated, "Register", false},
+		{"DirectLogin route", http.MethodPost, "/auth/direct-login", http.StatusOK, "DirectLogin", false},
+		{"VerifyMFA route", http.MethodPost, "/auth/mfa/verify", http.StatusOK, "VerifyMFA", false},
+		{"ResetPassword route", http.MethodPost, "/auth/reset-password", http.StatusAccepted, "ResetPassword", false},
+		{"RequestMagicLink route", http.MethodPost, "/auth/magic-link", http.StatusNoContent, "RequestMagicLink", false},
+		{"VerifyMagicLink route", http.MethodGet, "/auth/magic-link/verify?token=abc", http.StatusOK, "VerifyMagicLink", false},
+		{"SendEmailVerification route", http.MethodPost, "/auth/verify-email", http.StatusNoContent, "SendEmailVerification", true},
+		{"VerifyEmail route", http.MethodGet, "/auth/verify-email/confirm?token=abc", http.StatusNoContent, "VerifyEmail", false},
+		{"ExchangeToken route", http.MethodPost, "/auth/exchange-token", http.StatusNoContent, "ExchangeToken", false},
+		{"Logout route", http.MethodPost, "/auth/logout", http.StatusNoContent, "Logout", true},
+		{"ListIdentities route", http.MethodGet, "/auth/identities", http.StatusOK, "ListIdentities", true},
+		{"LinkIdentity route", http.MethodPost, "/auth/identities", http.StatusCreated, "LinkIdentity", true},
+		{"ListSessions route", http.MethodGet, "/auth/sessions", http.StatusOK, "ListSessions", true},
+		{"RevokeSession route", http.MethodDelete, "/auth/sessions/session-id", http.StatusNoContent, "RevokeSession", true},
 	}
 
 	for _, tt := range tests {
@@ -59,7 +134,7 @@ func TestRegisterAuthRoutes(t *testing.T) {
 			mockHandler.On(tt.mockMethod, mock.Anything, mock.Anything).Once()
 
 			// Register routes with the mock
-			routes.RegisterAuthRoutes(r, mockHandler)
+			routes.RegisterAuthRoutes(r, mockHandler, passThrough)
 
 			req := httptest.NewRequest(tt.method, tt.path, nil)
 			rec := httptest.NewRecorder()
@@ -67,6 +142,7 @@ func TestRegisterAuthRoutes(t *testing.T) {
 			r.ServeHTTP(rec, req)
 
 			assert.Equal(t, tt.expectCode, rec.Code)
+			assert.Equal(t, tt.protected, rec.Header().Get("X-Auth-Checked") == "true")
 			mockHandler.AssertExpectations(t)
 		})
 	}
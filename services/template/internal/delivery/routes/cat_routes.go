@@ -0,0 +1,34 @@
+package routes
+
+import (
+	"net/http"
+
+	handlers "github.com/nuhorizon/go-project-template/services/template/internal/delivery/handlers"
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	middlewares "github.com/nuhorizon/go-project-template/services/template/pkg/middlewares"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// RegisterCatRoutes registers the cat routes behind authMiddleware. API keys need cats:read to
+// read and cats:write to change cats.
+func RegisterCatRoutes(r chi.Router, h handlers.CatHandler, authMiddleware func(http.Handler) http.Handler) {
+	r.Route("/cats", func(r chi.Router) {
+		r.Use(authMiddleware)
+
+		r.Group(func(r chi.Router) {
+			r.Use(middlewares.RequireScope(domain.ScopeCatsRead))
+
+			r.Get("/", h.List)    // GET /cats - Lista os gatos do usuário
+			r.Get("/{id}", h.Get) // GET /cats/{id} - Retorna um gato do usuário
+		})
+
+		r.Group(func(r chi.Router) {
+			r.Use(middlewares.RequireScope(domain.ScopeCatsWrite))
+
+			r.Post("/", h.Create)       // POST /cats - Cadastra um gato
+			r.Put("/{id}", h.Update)    // PUT /cats/{id} - Atualiza um gato do usuário
+			r.Delete("/{id}", h.Delete) // DELETE /cats/{id} - Remove um gato do usuário
+		})
+	})
+}
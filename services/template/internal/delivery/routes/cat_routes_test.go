@@ -0,0 +1,97 @@
+package routes_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/nuhorizon/go-project-template/services/template/internal/delivery/routes"
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockCatHandler struct {
+	mock.Mock
+}
+
+func (m *MockCatHandler) List(w http.ResponseWriter, r *http.Request) {
+	m.Called(w, r)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (m *MockCatHandler) Get(w http.ResponseWriter, r *http.Request) {
+	m.Called(w, r)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (m *MockCatHandler) Create(w http.ResponseWriter, r *http.Request) {
+	m.Called(w, r)
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (m *MockCatHandler) Update(w http.ResponseWriter, r *http.Request) {
+	m.Called(w, r)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (m *MockCatHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	m.Called(w, r)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// authenticatedAs stands in for the auth middleware, authenticating every request as principal
+func authenticatedAs(principal *domain.Principal) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return passThrough(withPrincipal(principal)(next))
+	}
+}
+
+func apiKeyPrincipal(scopes ...string) *domain.Principal {
+	return &domain.Principal{UserID: "user-id", AuthMethod: domain.AuthMethodAPIKey, Scopes: scopes}
+}
+
+func TestRegisterCatRoutes(t *testing.T) {
+	token := &domain.Principal{UserID: "user-id", AuthMethod: domain.AuthMethodToken}
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		principal  *domain.Principal
+		expectCode int
+		mockMethod string
+	}{
+		{"List route", http.MethodGet, "/cats", token, http.StatusOK, "List"},
+		{"Create route", http.MethodPost, "/cats", token, http.StatusCreated, "Create"},
+		{"Get route", http.MethodGet, "/cats/cat-id", token, http.StatusOK, "Get"},
+		{"Update route", http.MethodPut, "/cats/cat-id", token, http.StatusOK, "Update"},
+		{"Delete route", http.MethodDelete, "/cats/cat-id", token, http.StatusNoContent, "Delete"},
+		{"api key with cats:read lists", http.MethodGet, "/cats", apiKeyPrincipal(domain.ScopeCatsRead), http.StatusOK, "List"},
+		{"api key with cats:read can't create", http.MethodPost, "/cats", apiKeyPrincipal(domain.ScopeCatsRead), http.StatusForbidden, ""},
+		{"api key with cats:write deletes", http.MethodDelete, "/cats/cat-id", apiKeyPrincipal(domain.ScopeCatsWrite), http.StatusNoContent, "Delete"},
+		{"api key without cat scopes", http.MethodGet, "/cats/cat-id", apiKeyPrincipal(domain.PermissionUsersRead), http.StatusForbidden, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := chi.NewRouter()
+			mockHandler := new(MockCatHandler)
+			if tt.mockMethod != "" {
+				mockHandler.On(tt.mockMethod, mock.Anything, mock.Anything).Once()
+			}
+
+			routes.RegisterCatRoutes(r, mockHandler, authenticatedAs(tt.principal))
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			rec := httptest.NewRecorder()
+
+			r.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectCode, rec.Code)
+			assert.Equal(t, "true", rec.Header().Get("X-Auth-Checked"))
+			mockHandler.AssertExpectations(t)
+		})
+	}
+}
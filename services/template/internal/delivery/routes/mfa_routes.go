@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"net/http"
+
+	handlers "github.com/nuhorizon/go-project-template/services/template/internal/delivery/handlers"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func RegisterMFARoutes(r chi.Router, h handlers.MFAHandler, authMiddleware func(http.Handler) http.Handler) {
+	r.Route("/mfa", func(r chi.Router) {
+		r.Use(authMiddleware)
+
+		r.Post("/totp", h.EnrollTOTP)          // POST /mfa/totp - Gera o segredo do aplicativo autenticador
+		r.Post("/totp/confirm", h.ConfirmTOTP) // POST /mfa/totp/confirm - Ativa o segundo fator e retorna os códigos de recuperação
+		r.Delete("/totp", h.DisableTOTP)       // DELETE /mfa/totp - Desativa o segundo fator
+	})
+}
@@ -0,0 +1,64 @@
+package routes_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/nuhorizon/go-project-template/services/template/internal/delivery/routes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockMFAHandler struct {
+	mock.Mock
+}
+
+func (m *MockMFAHandler) EnrollTOTP(w http.ResponseWriter, r *http.Request) {
+	m.Called(w, r)
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (m *MockMFAHandler) ConfirmTOTP(w http.ResponseWriter, r *http.Request) {
+	m.Called(w, r)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (m *MockMFAHandler) DisableTOTP(w http.ResponseWriter, r *http.Request) {
+	m.Called(w, r)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func TestRegisterMFARoutes(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		expectCode int
+		mockMethod string
+	}{
+		{"EnrollTOTP route", http.MethodPost, "/mfa/totp", http.StatusCreated, "EnrollTOTP"},
+		{"ConfirmTOTP route", http.MethodPost, "/mfa/totp/confirm", http.StatusOK, "ConfirmTOTP"},
+		{"DisableTOTP route", http.MethodDelete, "/mfa/totp", http.StatusNoContent, "DisableTOTP"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := chi.NewRouter()
+			mockHandler := new(MockMFAHandler)
+			mockHandler.On(tt.mockMethod, mock.Anything, mock.Anything).Once()
+
+			routes.RegisterMFARoutes(r, mockHandler, passThrough)
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			rec := httptest.NewRecorder()
+
+			r.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectCode, rec.Code)
+			assert.Equal(t, "true", rec.Header().Get("X-Auth-Checked"))
+			mockHandler.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,13 @@
+package routes
+
+import (
+	handlers "github.com/nuhorizon/go-project-template/services/template/internal/delivery/handlers"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func RegisterPaymentRoutes(r chi.Router, h handlers.PaymentWebhookHandler) {
+	r.Route("/webhooks", func(r chi.Router) {
+		r.Post("/payments", h.Receive) // POST /webhooks/payments - Eventos do provedor de pagamentos
+	})
+}
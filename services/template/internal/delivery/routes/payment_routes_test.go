@@ -0,0 +1,36 @@
+package routes_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/nuhorizon/go-project-template/services/template/internal/delivery/routes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPaymentWebhookHandler struct {
+	mock.Mock
+}
+
+func (m *MockPaymentWebhookHandler) Receive(w http.ResponseWriter, r *http.Request) {
+	m.Called(w, r)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func TestRegisterPaymentRoutes(t *testing.T) {
+	r := chi.NewRouter()
+	mockHandler := new(MockPaymentWebhookHandler)
+	mockHandler.On("Receive", mock.Anything, mock.Anything).Once()
+
+	routes.RegisterPaymentRoutes(r, mockHandler)
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/payments", nil)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	mockHandler.AssertExpectations(t)
+}
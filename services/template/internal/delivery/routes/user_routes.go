@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"net/http"
+
+	handlers "github.com/nuhorizon/go-project-template/services/template/internal/delivery/handlers"
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	middlewares "github.com/nuhorizon/go-project-template/services/template/pkg/middlewares"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// RegisterUserRoutes registers the profile routes behind authMiddleware, API keys need
+// profile:read or profile:write. Deleting and exporting the account goes through
+// sessionMiddleware instead, which doesn't accept API keys.
+func RegisterUserRoutes(r chi.Router, h handlers.UserHandler, authMiddleware, sessionMiddleware func(http.Handler) http.Handler) {
+	r.Route("/users", func(r chi.Router) {
+		r.With(authMiddleware, middlewares.RequireScope(domain.ScopeProfileRead)).Get("/me", h.GetMe)       // GET /users/me - Perfil do usuário autenticado
+		r.With(authMiddleware, middlewares.RequireScope(domain.ScopeProfileWrite)).Patch("/me", h.UpdateMe) // PATCH /users/me - Atualiza nome e/ou foto do usuário
+
+		r.With(sessionMiddleware).Delete("/me", h.DeleteMe)     // DELETE /users/me - Exclui a conta do usuário
+		r.With(sessionMiddleware).Get("/me/export", h.ExportMe) // GET /users/me/export - Exporta os dados do usuário (LGPD/GDPR)
+	})
+}
@@ -0,0 +1,121 @@
+package routes_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/nuhorizon/go-project-template/services/template/internal/delivery/routes"
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockUserHandler struct {
+	mock.Mock
+}
+
+func (m *MockUserHandler) GetMe(w http.ResponseWriter, r *http.Request) {
+	m.Called(w, r)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (m *MockUserHandler) UpdateMe(w http.ResponseWriter, r *http.Request) {
+	m.Called(w, r)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (m *MockUserHandler) DeleteMe(w http.ResponseWriter, r *http.Request) {
+	m.Called(w, r)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (m *MockUserHandler) ExportMe(w http.ResponseWriter, r *http.Request) {
+	m.Called(w, r)
+	w.WriteHeader(http.StatusOK)
+}
+
+// sessionOnly stands in for the middleware that doesn't accept API keys
+func sessionOnly(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Session-Checked", "true")
+		next.ServeHTTP(w, r)
+	})
+}
+
+func TestRegisterUserRoutes(t *testing.T) {
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		expectCode  int
+		mockMethod  string
+		sessionOnly bool
+	}{
+		{"GetMe route", http.MethodGet, "/users/me", http.StatusOK, "GetMe", false},
+		{"UpdateMe route", http.MethodPatch, "/users/me", http.StatusOK, "UpdateMe", false},
+		{"DeleteMe route", http.MethodDelete, "/users/me", http.StatusNoContent, "DeleteMe", true},
+		{"ExportMe route", http.MethodGet, "/users/me/export", http.StatusOK, "ExportMe", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := chi.NewRouter()
+			mockHandler := new(MockUserHandler)
+			mockHandler.On(tt.mockMethod, mock.Anything, mock.Anything).Once()
+
+			routes.RegisterUserRoutes(r, mockHandler, authenticatedAs(&domain.Principal{UserID: "user-id", AuthMethod: domain.AuthMethodToken}), sessionOnly)
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			rec := httptest.NewRecorder()
+
+			r.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectCode, rec.Code)
+			if tt.sessionOnly {
+				assert.Equal(t, "true", rec.Header().Get("X-Session-Checked"))
+				assert.Empty(t, rec.Header().Get("X-Auth-Checked"))
+			} else {
+				assert.Equal(t, "true", rec.Header().Get("X-Auth-Checked"))
+				assert.Empty(t, rec.Header().Get("X-Session-Checked"))
+			}
+			mockHandler.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRegisterUserRoutes_APIKeyScopes(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		principal  *domain.Principal
+		expectCode int
+		mockMethod string
+	}{
+		{"profile:read reads the profile", http.MethodGet, apiKeyPrincipal(domain.ScopeProfileRead), http.StatusOK, "GetMe"},
+		{"profile:read can't update the profile", http.MethodPatch, apiKeyPrincipal(domain.ScopeProfileRead), http.StatusForbidden, ""},
+		{"profile:write updates the profile", http.MethodPatch, apiKeyPrincipal(domain.ScopeProfileWrite), http.StatusOK, "UpdateMe"},
+		{"cat scopes don't reach the profile", http.MethodGet, apiKeyPrincipal(domain.ScopeCatsRead), http.StatusForbidden, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := chi.NewRouter()
+			mockHandler := new(MockUserHandler)
+			if tt.mockMethod != "" {
+				mockHandler.On(tt.mockMethod, mock.Anything, mock.Anything).Once()
+			}
+
+			routes.RegisterUserRoutes(r, mockHandler, authenticatedAs(tt.principal), sessionOnly)
+
+			req := httptest.NewRequest(tt.method, "/users/me", nil)
+			rec := httptest.NewRecorder()
+
+			r.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectCode, rec.Code)
+			mockHandler.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,13 @@
+package routes
+
+import (
+	handlers "github.com/nuhorizon/go-project-template/services/template/internal/delivery/handlers"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func RegisterWellKnownRoutes(r chi.Router, h handlers.WellKnownHandler) {
+	r.Route("/.well-known", func(r chi.Router) {
+		r.Get("/jwks.json", h.JWKS) // GET /.well-known/jwks.json - Chaves públicas para validar os tokens
+	})
+}
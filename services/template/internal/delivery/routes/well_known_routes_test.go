@@ -0,0 +1,36 @@
+package routes_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/nuhorizon/go-project-template/services/template/internal/delivery/routes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockWellKnownHandler struct {
+	mock.Mock
+}
+
+func (m *MockWellKnownHandler) JWKS(w http.ResponseWriter, r *http.Request) {
+	m.Called(w, r)
+	w.WriteHeader(http.StatusOK)
+}
+
+func TestRegisterWellKnownRoutes(t *testing.T) {
+	r := chi.NewRouter()
+	mockHandler := new(MockWellKnownHandler)
+	mockHandler.On("JWKS", mock.Anything, mock.Anything).Once()
+
+	routes.RegisterWellKnownRoutes(r, mockHandler)
+
+	req := httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	mockHandler.AssertExpectations(t)
+}
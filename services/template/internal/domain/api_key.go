@@ -0,0 +1,43 @@
+package domain
+
+import "time"
+
+// APIKeyPrefix starts every API key, so leaked keys are easy to spot in logs and secret scanners
+const APIKeyPrefix = "cwk_"
+
+// Scopes of the resources every user owns. Unlike permissions they don't depend on roles, any
+// user can grant them to a key; access tokens are not scoped and reach these resources anyway.
+const (
+	ScopeCatsRead     = "cats:read"
+	ScopeCatsWrite    = "cats:write"
+	ScopeProfileRead  = "profile:read"
+	ScopeProfileWrite = "profile:write"
+)
+
+// ResourceScopes lists the scopes an API key can carry besides the permissions of its owner
+var ResourceScopes = []string{
+	ScopeCatsRead,
+	ScopeCatsWrite,
+	ScopeProfileRead,
+	ScopeProfileWrite,
+}
+
+// APIKey is a personal key for scripts and integrations. Only the hash of the key is persisted;
+// Prefix is its first characters, kept in clear so the owner can tell keys apart.
+type APIKey struct {
+	ID         string
+	UserID     string
+	Name       string
+	Prefix     string
+	KeyHash    string
+	Scopes     []string
+	LastUsedAt *time.Time
+	ExpiresAt  *time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+}
+
+// Active reports whether the key can still authenticate requests
+func (k *APIKey) Active(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
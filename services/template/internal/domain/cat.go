@@ -0,0 +1,24 @@
+package domain
+
+import "time"
+
+// Cat is a pet registered by a user, every cat belongs to exactly one user
+type Cat struct {
+	ID         string
+	UserID     string
+	Name       string
+	Breed      string
+	BirthDate  *time.Time // Date only, nil when unknown
+	Gender     string     // CatGenderMale, CatGenderFemale or empty when unknown
+	WeightKg   *float64
+	PictureURL string
+	IsNeutered bool
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// Genders a cat can be registered with (Cat.Gender)
+const (
+	CatGenderMale   = "male"
+	CatGenderFemale = "female"
+)
@@ -0,0 +1,48 @@
+package domain
+
+import "errors"
+
+var (
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token reuse detected")
+	ErrSessionNotFound     = errors.New("session not found")
+
+	ErrEmailAlreadyRegistered = errors.New("email already registered")
+	ErrInvalidCredentials     = errors.New("invalid email or password")
+	ErrFirebaseDisabled       = errors.New("firebase authentication is not configured")
+
+	ErrUnknownIdentityProvider = errors.New("unknown identity provider")
+	ErrInvalidIdentityToken    = errors.New("invalid identity token")
+	ErrIdentityEmailMissing    = errors.New("identity provider did not assert an email")
+	ErrIdentityLinkRequired    = errors.New("email belongs to an existing account, sign in and link the identity")
+	ErrIdentityAlreadyLinked   = errors.New("identity is linked to another account")
+
+	ErrMFAAlreadyEnabled = errors.New("multi-factor authentication already enabled")
+	ErrMFANotEnabled     = errors.New("multi-factor authentication not enabled")
+	ErrMFAUnsupported    = errors.New("multi-factor authentication requires a password account")
+	ErrInvalidMFACode    = errors.New("invalid verification code")
+	ErrInvalidMFAToken   = errors.New("invalid or expired mfa token")
+
+	ErrInvalidMagicLink = errors.New("invalid, used or expired magic link")
+
+	ErrEmailNotVerified             = errors.New("email address not verified")
+	ErrEmailAlreadyVerified         = errors.New("email address already verified")
+	ErrInvalidEmailVerificationLink = errors.New("invalid or expired email verification link")
+
+	ErrInvalidAPIKey      = errors.New("invalid or revoked api key")
+	ErrInvalidAPIKeyScope = errors.New("api key scopes must be resource scopes or permissions granted to the user")
+	ErrAPIKeyNotFound     = errors.New("api key not found")
+
+	ErrUserNotFound  = errors.New("user not found")
+	ErrUserDisabled  = errors.New("user account is disabled")
+	ErrInvalidCursor = errors.New("invalid pagination cursor")
+	ErrRoleNotFound  = errors.New("role not found")
+
+	ErrCatNotFound = errors.New("cat not found")
+
+	ErrPlanNotFound         = errors.New("plan not found")
+	ErrNoActiveSubscription = errors.New("user has no paid plan")
+
+	ErrInvalidWebhookSignature = errors.New("invalid or expired webhook signature")
+	ErrInvalidWebhookPayload   = errors.New("invalid webhook payload")
+)
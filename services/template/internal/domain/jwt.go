@@ -6,6 +6,24 @@ import (
 	"time"
 )
 
+// MFATokenTTL bounds the time between the password step and the second factor of a login
+const MFATokenTTL = 5 * time.Minute
+
+// TokenTypeMFAPending marks tokens that only allow completing a login with the second factor
+const TokenTypeMFAPending = "mfa_pending"
+
+// MagicLinkTTL bounds the time between requesting a magic link and following it
+const MagicLinkTTL = 15 * time.Minute
+
+// TokenTypeMagicLink marks the tokens of the magic links mailed for passwordless logins
+const TokenTypeMagicLink = "magic_link"
+
+// EmailVerificationTTL bounds the time a verify-email link stays valid
+const EmailVerificationTTL = 24 * time.Hour
+
+// TokenTypeEmailVerification marks the tokens of the verify-email links
+const TokenTypeEmailVerification = "email_verification"
+
 func TokenExpiry() int64 {
 	expireTime, err := strconv.Atoi(os.Getenv("TOKEN_EXPIRE_TIME"))
 	if err != nil || expireTime <= 0 {
@@ -14,3 +32,13 @@ func TokenExpiry() int64 {
 
 	return time.Now().Add(time.Duration(expireTime) * time.Hour).Unix()
 }
+
+// RefreshTokenExpiry returns the expiration of a newly issued refresh token (default 30 days)
+func RefreshTokenExpiry() time.Time {
+	expireTime, err := strconv.Atoi(os.Getenv("REFRESH_TOKEN_EXPIRE_TIME"))
+	if err != nil || expireTime <= 0 {
+		expireTime = 720
+	}
+
+	return time.Now().Add(time.Duration(expireTime) * time.Hour)
+}
@@ -36,3 +36,33 @@ func TestTokenExpiry(t *testing.T) {
 		})
 	}
 }
+
+func TestRefreshTokenExpiry(t *testing.T) {
+	tests := []struct {
+		name        string
+		envValue    string
+		expectedDur time.Duration
+	}{
+		{"Test with default value", "", 720 * time.Hour},
+		{"Test with custom value", "48", 48 * time.Hour},
+		{"Test with invalid value", "-1", 720 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.envValue != "" {
+				os.Setenv("REFRESH_TOKEN_EXPIRE_TIME", tt.envValue)
+			} else {
+				os.Unsetenv("REFRESH_TOKEN_EXPIRE_TIME")
+			}
+			defer os.Unsetenv("REFRESH_TOKEN_EXPIRE_TIME")
+
+			expected := time.Now().Add(tt.expectedDur)
+			got := RefreshTokenExpiry()
+
+			if got.Sub(expected).Abs() > 10*time.Second {
+				t.Errorf("RefreshTokenExpiry() = %v, expected around %v", got, expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// LoginAttempts is the failure history tracked for a client IP or an account
+type LoginAttempts struct {
+	Failures      int
+	LastFailureAt time.Time
+}
+
+// LockedOutError is returned while a client IP or an account is locked out after too many
+// failed attempts. RetryAfter is how long until the next attempt is accepted.
+type LockedOutError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LockedOutError) Error() string {
+	return fmt.Sprintf("too many failed attempts, retry in %s", e.RetryAfter.Round(time.Second))
+}
@@ -0,0 +1,13 @@
+package domain
+
+import "time"
+
+// MagicLink is the persisted side of a magic-link token. The token is signed, the row
+// makes it single-use: it is consumed by the first login that presents it.
+type MagicLink struct {
+	ID        string // jti of the signed token
+	Email     string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
@@ -0,0 +1,35 @@
+package domain
+
+import "time"
+
+// MFAEnrollment is the TOTP second factor of a user. EnabledAt stays nil until the user
+// confirms the enrolment with a first valid code.
+type MFAEnrollment struct {
+	UserID       string
+	Secret       string
+	LastUsedStep int64 // Last accepted TOTP time step, codes of this step or older are rejected
+	EnabledAt    *time.Time
+	CreatedAt    time.Time
+}
+
+// Enabled reports whether logins of the user require the second factor
+func (e *MFAEnrollment) Enabled() bool {
+	return e != nil && e.EnabledAt != nil
+}
+
+// TOTPSetup is handed to the user when enrolling an authenticator app
+type TOTPSetup struct {
+	Secret          string
+	ProvisioningURI string
+}
+
+// MFARequiredError is returned by a password login that still needs the second factor.
+// Token is the "mfa_pending" token that completes the login together with a code.
+type MFARequiredError struct {
+	Token     string
+	ExpiresAt time.Time
+}
+
+func (e *MFARequiredError) Error() string {
+	return "multi-factor authentication required"
+}
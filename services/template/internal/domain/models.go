@@ -3,14 +3,84 @@ package domain
 import "time"
 
 type User struct {
-	ID           string
-	FirebaseUID  string
-	Email        string
-	Name         string
-	PictureURL   string
-	PlanType     string
-	PremiumSince *time.Time
-	PlanExpiry   *time.Time
-	CreatedAt    time.Time
-	UpdatedAt    time.Time
+	ID            string
+	FirebaseUID   string
+	Email         string
+	Name          string
+	PictureURL    string
+	PlanType      string // Code of the plan, kept next to PlanID for the token claims and rate limits
+	PlanID        string // Row of the plans table, empty for users that never had a plan assigned
+	PremiumSince  *time.Time
+	PlanExpiry    *time.Time // End of the paid period, nil on the free plan and on plans granted without an end
+	PlanCancelled bool       // Cancelled at period end, the plan ends at PlanExpiry without a grace period
+	PasswordHash  string     // Empty for users that only sign in through Firebase
+	EmailVerified bool       // Set once the user proved they own Email (provider, verify-email link or magic link)
+	DisabledAt    *time.Time // Set by an administrator, disabled users can't sign in
+	Roles         []string   // Loaded from the role repository before tokens are issued
+	Permissions   []string   // Union of the permissions granted by Roles
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// Disabled reports whether an administrator disabled the account
+func (u *User) Disabled() bool {
+	return u.DisabledAt != nil
+}
+
+// Plan types a user can be on (User.PlanType). Users without a plan are treated as PlanFree.
+const (
+	PlanFree    = "free"
+	PlanPremium = "premium"
+)
+
+// Role groups permissions that can be granted to users
+type Role struct {
+	Name        string
+	Permissions []string
+}
+
+// ProviderFirebase is the identity provider name of Firebase logins (the default on /auth/login)
+const ProviderFirebase = "firebase"
+
+// Identity links a user to the subject an external identity provider knows them by
+type Identity struct {
+	ID        string
+	UserID    string
+	Provider  string
+	Subject   string
+	Email     string
+	CreatedAt time.Time
+}
+
+// RefreshToken is the persisted (hashed) side of an opaque refresh token.
+// Tokens issued from the same login share a FamilyID so reuse of a rotated token
+// can revoke the whole chain.
+type RefreshToken struct {
+	ID         string
+	UserID     string
+	FamilyID   string
+	TokenHash  string
+	ExpiresAt  time.Time
+	RevokedAt  *time.Time
+	ReplacedBy *string
+	CreatedAt  time.Time
+}
+
+// TokenClaims are the claims carried by a validated app access token
+type TokenClaims struct {
+	UserID        string
+	PlanType      string
+	Roles         []string
+	Permissions   []string
+	TokenID       string // jti, used to revoke a single token
+	SessionID     string // sid, the session (refresh token family) the token was issued for
+	EmailVerified bool   // As of issuance, a later verification is picked up on the next refresh
+	IssuedAt      time.Time
+	ExpiresAt     time.Time
+}
+
+// TokenPair is what the app hands back to clients after a successful authentication
+type TokenPair struct {
+	AccessToken  string
+	RefreshToken string
 }
@@ -0,0 +1,38 @@
+package domain
+
+import "time"
+
+// Statuses of a PaymentSubscription
+const (
+	PaymentSubscriptionActive  = "active"   // Paid (or trialing) until PeriodEnd
+	PaymentSubscriptionPastDue = "past_due" // Renewal failed, the provider is still retrying it
+	PaymentSubscriptionEnded   = "ended"    // Cancelled or unpaid for good
+)
+
+// Why a PaymentEvent was recorded without being applied (PaymentEvent.Rejection)
+const (
+	PaymentEventUnknownPlan = "unknown_plan" // The subscription pays for a plan missing from the plans table
+	PaymentEventOutOfOrder  = "out_of_order" // Older than the last event applied to the user
+)
+
+// PaymentEvent is a webhook of the payment provider whose signature was verified
+type PaymentEvent struct {
+	ID       string // ID given by the provider, each event is applied once
+	Provider string
+	Type     string // Event type as named by the provider
+	// UserID is the user the event is about, cleared when recording it if the user doesn't exist
+	UserID       string
+	Subscription *PaymentSubscription // nil for events that don't change a subscription
+	Payload      []byte               // Body as received, kept for auditing
+	CreatedAt    time.Time
+	Rejection    string // Empty unless the event was recorded without being applied
+}
+
+// PaymentSubscription is the state of a subscription as reported by the payment provider
+type PaymentSubscription struct {
+	UserID            string // Our user ID, attached to the subscription when checking out
+	Plan              string // Code of the plan the subscription pays for
+	Status            string
+	PeriodEnd         *time.Time
+	CancelAtPeriodEnd bool
+}
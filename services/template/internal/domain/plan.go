@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// Plan is a row of the plans table, it decides what the users on it are entitled to
+type Plan struct {
+	ID               string
+	Code             string // Stable key matching User.PlanType (PlanFree, PlanPremium, ...)
+	Name             string
+	Description      string
+	Price            float64
+	MaxCats          int
+	HasImageAnalysis bool
+	HasConsultation  bool
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+// Features a plan may or may not include
+const (
+	FeatureImageAnalysis = "image_analysis"
+	FeatureConsultation  = "consultation"
+)
+
+// HasFeature reports whether the plan includes the feature, unknown features are never included
+func (p *Plan) HasFeature(feature string) bool {
+	switch feature {
+	case FeatureImageAnalysis:
+		return p.HasImageAnalysis
+	case FeatureConsultation:
+		return p.HasConsultation
+	default:
+		return false
+	}
+}
+
+// Reasons an EntitlementError is raised for
+const (
+	EntitlementFeatureNotInPlan = "feature_not_in_plan"
+	EntitlementCatLimitReached  = "cat_limit_reached"
+)
+
+// EntitlementError is returned when the plan of the user doesn't allow what they tried to do.
+// Upgrading to another plan is the way out, so it carries enough details to tell the client which.
+type EntitlementError struct {
+	Reason  string // EntitlementFeatureNotInPlan or EntitlementCatLimitReached
+	Plan    string // Code of the user's current plan
+	Feature string // Set for EntitlementFeatureNotInPlan
+	Limit   int    // Set for EntitlementCatLimitReached
+}
+
+func (e *EntitlementError) Error() string {
+	if e.Reason == EntitlementCatLimitReached {
+		return fmt.Sprintf("plan %q allows at most %d cats", e.Plan, e.Limit)
+	}
+	return fmt.Sprintf("plan %q does not include %s", e.Plan, e.Feature)
+}
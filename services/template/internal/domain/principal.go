@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+// How the caller of a request authenticated (Principal.AuthMethod)
+const (
+	AuthMethodToken  = "token"   // App access token (JWT)
+	AuthMethodAPIKey = "api_key" // Personal API key
+)
+
+// Principal is the authenticated caller of a request, built from the validated
+// credentials so handlers don't need to reload the user
+type Principal struct {
+	UserID        string
+	PlanType      string
+	AuthMethod    string
+	Roles         []string
+	Permissions   []string
+	EmailVerified bool
+	Scopes        []string  // Scopes of the API key, empty for access tokens
+	TokenID       string    // jti of the access token, empty when not authenticated by a JWT
+	SessionID     string    // Session of the access token, empty when not authenticated by a JWT
+	APIKeyID      string    // Empty when not authenticated by an API key
+	ExpiresAt     time.Time // Zero for API keys without expiry
+}
+
+// NewPrincipalFromClaims builds the principal of a request authenticated with an access token
+func NewPrincipalFromClaims(claims *TokenClaims) *Principal {
+	return &Principal{
+		UserID:        claims.UserID,
+		PlanType:      claims.PlanType,
+		AuthMethod:    AuthMethodToken,
+		Roles:         claims.Roles,
+		Permissions:   claims.Permissions,
+		EmailVerified: claims.EmailVerified,
+		TokenID:       claims.TokenID,
+		SessionID:     claims.SessionID,
+		ExpiresAt:     claims.ExpiresAt,
+	}
+}
+
+// NewPrincipalFromAPIKey builds the principal of a request authenticated with an API key. The
+// user must have its roles loaded: the key only grants the permissions among its scopes the
+// user still holds, so taking a role away also narrows the keys.
+func NewPrincipalFromAPIKey(user *User, key *APIKey) *Principal {
+	var permissions []string
+	for _, scope := range key.Scopes {
+		if slices.Contains(user.Permissions, scope) {
+			permissions = append(permissions, scope)
+		}
+	}
+
+	principal := &Principal{
+		UserID:        user.ID,
+		PlanType:      user.PlanType,
+		AuthMethod:    AuthMethodAPIKey,
+		Roles:         user.Roles,
+		Permissions:   permissions,
+		EmailVerified: user.EmailVerified,
+		Scopes:        key.Scopes,
+		APIKeyID:      key.ID,
+	}
+	if key.ExpiresAt != nil {
+		principal.ExpiresAt = *key.ExpiresAt
+	}
+	return principal
+}
+
+// HasRole reports whether the principal was granted the given role
+func (p *Principal) HasRole(role string) bool {
+	return slices.Contains(p.Roles, role)
+}
+
+// HasScope reports whether the principal may reach the resources of the scope. Only API keys are
+// restricted to their scopes.
+func (p *Principal) HasScope(scope string) bool {
+	return p.AuthMethod != AuthMethodAPIKey || slices.Contains(p.Scopes, scope)
+}
+
+// HasPermission reports whether any role of the principal grants the permission
+func (p *Principal) HasPermission(permission string) bool {
+	return slices.Contains(p.Permissions, permission)
+}
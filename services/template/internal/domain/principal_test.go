@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewPrincipalFromClaims(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour)
+	claims := &TokenClaims{
+		UserID:    "user-id",
+		PlanType:  "premium",
+		Roles:     []string{RoleAdmin},
+		TokenID:   "jti-1",
+		SessionID: "session-1",
+		IssuedAt:  time.Now(),
+		ExpiresAt: expiresAt,
+	}
+
+	p := NewPrincipalFromClaims(claims)
+	if p.UserID != "user-id" || p.PlanType != "premium" || p.AuthMethod != AuthMethodToken || p.TokenID != "jti-1" || p.SessionID != "session-1" || !p.ExpiresAt.Equal(expiresAt) {
+		t.Errorf("unexpected principal %+v", p)
+	}
+}
+
+func TestPrincipal_HasRoleAndPermission(t *testing.T) {
+	p := &Principal{
+		Roles:       []string{RoleAdmin},
+		Permissions: []string{PermissionUsersRead},
+	}
+
+	if !p.HasRole(RoleAdmin) {
+		t.Errorf("expected role %q", RoleAdmin)
+	}
+	if p.HasRole("support") {
+		t.Errorf("unexpected role support")
+	}
+	if !p.HasPermission(PermissionUsersRead) {
+		t.Errorf("expected permission %q", PermissionUsersRead)
+	}
+	if p.HasPermission(PermissionUsersWrite) {
+		t.Errorf("unexpected permission %q", PermissionUsersWrite)
+	}
+}
+
+func TestPrincipal_HasScope(t *testing.T) {
+	token := &Principal{AuthMethod: AuthMethodToken}
+	if !token.HasScope(ScopeCatsWrite) {
+		t.Errorf("access tokens are not restricted to scopes")
+	}
+
+	key := &Principal{AuthMethod: AuthMethodAPIKey, Scopes: []string{ScopeCatsRead}}
+	if !key.HasScope(ScopeCatsRead) {
+		t.Errorf("expected scope %q", ScopeCatsRead)
+	}
+	if key.HasScope(ScopeCatsWrite) {
+		t.Errorf("unexpected scope %q", ScopeCatsWrite)
+	}
+}
+
+func TestNewPrincipalFromAPIKey(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour)
+	user := &User{ID: "user-id", PlanType: "premium", Roles: []string{"support"}, Permissions: []string{PermissionUsersRead}}
+	key := &APIKey{ID: "key-id", Scopes: []string{PermissionUsersRead, PermissionUsersWrite}, ExpiresAt: &expiresAt}
+
+	p := NewPrincipalFromAPIKey(user, key)
+	if p.AuthMethod != AuthMethodAPIKey || p.APIKeyID != "key-id" || p.TokenID != "" || !p.ExpiresAt.Equal(expiresAt) {
+		t.Errorf("unexpected principal %+v", p)
+	}
+	// users:write is a scope of the key the user no longer holds
+	if !p.HasPermission(PermissionUsersRead) || p.HasPermission(PermissionUsersWrite) {
+		t.Errorf("unexpected permissions %v", p.Permissions)
+	}
+}
@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RateLimit is a quota of Requests per Window
+type RateLimit struct {
+	Requests int
+	Window   time.Duration
+}
+
+// ParseRateLimit reads a quota written as "<requests>/<window>", e.g. "100/1m" or "5000/1h"
+func ParseRateLimit(value string) (RateLimit, error) {
+	requests, window, ok := strings.Cut(strings.TrimSpace(value), "/")
+	if !ok {
+		return RateLimit{}, fmt.Errorf("invalid rate limit %q, expected <requests>/<window>", value)
+	}
+
+	n, err := strconv.Atoi(requests)
+	if err != nil || n <= 0 {
+		return RateLimit{}, fmt.Errorf("invalid rate limit %q: requests must be a positive integer", value)
+	}
+	d, err := time.ParseDuration(window)
+	if err != nil || d < time.Second {
+		return RateLimit{}, fmt.Errorf("invalid rate limit %q: window must be a duration of at least 1s", value)
+	}
+	return RateLimit{Requests: n, Window: d}, nil
+}
+
+func (l RateLimit) String() string {
+	return fmt.Sprintf("%d/%s", l.Requests, l.Window)
+}
+
+// RateLimitCounts are the hits of a key in the current fixed window and in the one before,
+// from which the sliding window estimate is computed
+type RateLimitCounts struct {
+	Current  int
+	Previous int
+}
@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRateLimit(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    RateLimit
+		wantErr bool
+	}{
+		{value: "100/1m", want: RateLimit{Requests: 100, Window: time.Minute}},
+		{value: " 5000/1h ", want: RateLimit{Requests: 5000, Window: time.Hour}},
+		{value: "100", wantErr: true},
+		{value: "0/1m", wantErr: true},
+		{value: "ten/1m", wantErr: true},
+		{value: "100/minute", wantErr: true},
+		{value: "100/1ms", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseRateLimit(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRateLimit(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseRateLimit(%q) = %+v, want %+v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
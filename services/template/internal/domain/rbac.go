@@ -0,0 +1,20 @@
+package domain
+
+// Built-in role, seeded by the initial migration
+const RoleAdmin = "admin"
+
+// Permissions granted through roles (see role_permissions)
+const (
+	PermissionUsersRead      = "users:read"
+	PermissionUsersWrite     = "users:write"
+	PermissionRolesManage    = "roles:manage"
+	PermissionSessionsRevoke = "sessions:revoke"
+)
+
+// Permissions lists every permission, the scopes an API key can be restricted to
+var Permissions = []string{
+	PermissionUsersRead,
+	PermissionUsersWrite,
+	PermissionRolesManage,
+	PermissionSessionsRevoke,
+}
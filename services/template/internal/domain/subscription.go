@@ -0,0 +1,22 @@
+package domain
+
+import "time"
+
+// Types of SubscriptionEvent
+const (
+	SubscriptionUpgraded   = "subscription.upgraded"   // Moved to another paid plan
+	SubscriptionRenewed    = "subscription.renewed"    // Paid period of the same plan extended
+	SubscriptionCancelled  = "subscription.cancelled"  // Plan kept until PlanExpiry, then moved to free
+	SubscriptionDowngraded = "subscription.downgraded" // Moved to the free plan right away
+	SubscriptionExpired    = "subscription.expired"    // Moved to the free plan once the paid period (and grace period) ended
+)
+
+// SubscriptionEvent records a change of the plan of a user
+type SubscriptionEvent struct {
+	Type         string
+	UserID       string
+	Plan         string // Code of the plan after the change
+	PreviousPlan string
+	PlanExpiry   *time.Time
+	OccurredAt   time.Time
+}
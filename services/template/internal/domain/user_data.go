@@ -0,0 +1,15 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// UserDataExport is a copy of everything stored about a user, handed to them on request
+// (LGPD/GDPR data portability). Sections maps each kind of record ("user", "cats", ...) to its
+// rows as a JSON array, secrets such as password and token hashes are left out.
+type UserDataExport struct {
+	UserID      string
+	GeneratedAt time.Time
+	Sections    map[string]json.RawMessage
+}
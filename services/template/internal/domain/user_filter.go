@@ -0,0 +1,36 @@
+package domain
+
+import "time"
+
+// Orders users can be listed in (UserFilter.Sort), the "-" prefix sorts descending
+const (
+	UserSortCreatedDesc = "-created_at"
+	UserSortCreatedAsc  = "created_at"
+	UserSortEmailAsc    = "email"
+	UserSortEmailDesc   = "-email"
+)
+
+// UserSorts lists the accepted UserFilter.Sort values
+var UserSorts = []string{UserSortCreatedDesc, UserSortCreatedAsc, UserSortEmailAsc, UserSortEmailDesc}
+
+const (
+	DefaultUserPageSize = 50
+	MaxUserPageSize     = 100
+)
+
+// UserFilter narrows and orders the users listed by administrators. Empty fields don't filter.
+type UserFilter struct {
+	PlanType      string
+	EmailPrefix   string
+	CreatedAfter  *time.Time // Inclusive
+	CreatedBefore *time.Time // Exclusive
+	Sort          string     // One of UserSorts, UserSortCreatedDesc when empty
+	Cursor        string     // NextCursor of the previous page, only valid with the same Sort
+	Limit         int
+}
+
+// UserPage is a page of users, NextCursor is empty on the last page
+type UserPage struct {
+	Users      []User
+	NextCursor string
+}
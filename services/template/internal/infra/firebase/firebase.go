@@ -2,6 +2,7 @@ package firebase
 
 import (
 	"context"
+	"errors"
 	"os"
 
 	firebase "firebase.google.com/go/v4"
@@ -19,15 +20,31 @@ var FirebaseNewAppFunc = func(ctx context.Context, config *firebase.Config, opts
 	return app, nil
 }
 
-// NewFirebaseClient inicializa e retorna o *auth.Client do Firebase
+// NewFirebaseClient inicializa e retorna o *auth.Client do Firebase.
+// Com FIREBASE_AUTH_EMULATOR_HOST definido o client usa o Firebase Auth Emulator, sem credenciais
+// (o SDK aceita os tokens do emulador, que não são assinados); basta informar FIREBASE_PROJECT_ID.
 func NewFirebaseClient() (*auth.Client, error) {
-	credentialsPath := os.Getenv("FIREBASE_CREDENTIALS_JSON")
-	if credentialsPath == "" {
-		return nil, os.ErrNotExist
+	var (
+		config *firebase.Config
+		opt    option.ClientOption
+	)
+
+	if os.Getenv("FIREBASE_AUTH_EMULATOR_HOST") != "" {
+		projectID := os.Getenv("FIREBASE_PROJECT_ID")
+		if projectID == "" {
+			return nil, errors.New("FIREBASE_PROJECT_ID is required when FIREBASE_AUTH_EMULATOR_HOST is set")
+		}
+		config = &firebase.Config{ProjectID: projectID}
+		opt = option.WithoutAuthentication()
+	} else {
+		credentialsPath := os.Getenv("FIREBASE_CREDENTIALS_JSON")
+		if credentialsPath == "" {
+			return nil, os.ErrNotExist
+		}
+		opt = option.WithCredentialsFile(credentialsPath)
 	}
 
-	opt := option.WithCredentialsFile(credentialsPath)
-	app, err := FirebaseNewAppFunc(context.Background(), nil, opt)
+	app, err := FirebaseNewAppFunc(context.Background(), config, opt)
 	if err != nil {
 		return nil, err
 	}
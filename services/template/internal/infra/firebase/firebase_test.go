@@ -73,6 +73,49 @@ func TestNewFirebaseClient_Success(t *testing.T) {
 	assert.Equal(t, mockAuthClient, client)
 }
 
+func TestNewFirebaseClient_Emulator(t *testing.T) {
+	original := FirebaseNewAppFunc
+	defer func() { FirebaseNewAppFunc = original }()
+
+	t.Setenv("FIREBASE_AUTH_EMULATOR_HOST", "localhost:9099")
+	t.Setenv("FIREBASE_CREDENTIALS_JSON", "")
+
+	t.Run("uses the project id without credentials", func(t *testing.T) {
+		t.Setenv("FIREBASE_PROJECT_ID", "demo-template")
+
+		mockAuthClient := &auth.Client{}
+		var gotConfig *firebase.Config
+		FirebaseNewAppFunc = func(ctx context.Context, config *firebase.Config, opts ...option.ClientOption) (services.AppInterface, error) {
+			gotConfig = config
+			return &MockFirebaseApp{authClient: mockAuthClient}, nil
+		}
+
+		client, err := NewFirebaseClient()
+		require.NoError(t, err)
+		assert.Equal(t, mockAuthClient, client)
+		require.NotNil(t, gotConfig)
+		assert.Equal(t, "demo-template", gotConfig.ProjectID)
+	})
+
+	t.Run("requires the project id", func(t *testing.T) {
+		t.Setenv("FIREBASE_PROJECT_ID", "")
+
+		client, err := NewFirebaseClient()
+		assert.Nil(t, client)
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("builds a real client without credentials", func(t *testing.T) {
+		FirebaseNewAppFunc = original
+		t.Setenv("FIREBASE_PROJECT_ID", "demo-template")
+
+		client, err := NewFirebaseClient()
+		require.NoError(t, err)
+		assert.NotNil(t, client)
+	})
+}
+
 func TestNewFirebaseClient_MissingEnv(t *testing.T) {
 	os.Unsetenv("FIREBASE_CREDENTIALS_JSON")
 	client, err := NewFirebaseClient()
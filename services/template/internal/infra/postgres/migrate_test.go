@@ -0,0 +1,245 @@
+package postgres_test
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/nuhorizon/go-project-template/services/template/internal/infra/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testMigrations = fstest.MapFS{
+	"0001_create_cats.up.sql":      {Data: []byte("CREATE TABLE cats (id UUID PRIMARY KEY);")},
+	"0001_create_cats.down.sql":    {Data: []byte("DROP TABLE cats;")},
+	"0002_add_cat_name.up.sql":     {Data: []byte("ALTER TABLE cats ADD COLUMN name TEXT;")},
+	"0002_add_cat_name.down.sql":   {Data: []byte("ALTER TABLE cats DROP COLUMN name;")},
+	"0010_index_cat_name.up.sql":   {Data: []byte("CREATE INDEX idx_cats_name ON cats(name);")},
+	"0010_index_cat_name.down.sql": {Data: []byte("DROP INDEX idx_cats_name;")},
+}
+
+// expectLocked expects the lock and the bookkeeping table every Migrator operation starts with,
+// followed by the applied versions
+func expectLocked(mock sqlmock.Sqlmock, applied ...int64) {
+	mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_lock($1)`)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`CREATE TABLE IF NOT EXISTS schema_migrations`)).WillReturnResult(sqlmock.NewResult(0, 0))
+	rows := sqlmock.NewRows([]string{"version", "applied_at"})
+	for _, version := range applied {
+		rows.AddRow(version, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	}
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT version, applied_at FROM schema_migrations`)).WillReturnRows(rows)
+}
+
+func expectUnlock(mock sqlmock.Sqlmock) {
+	mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_unlock($1)`)).WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func TestLoadMigrations(t *testing.T) {
+	t.Run("ordered by version", func(t *testing.T) {
+		migrations, err := postgres.LoadMigrations(testMigrations)
+
+		require.NoError(t, err)
+		require.Len(t, migrations, 3)
+		assert.Equal(t, "0001_create_cats", migrations[0].String())
+		assert.Equal(t, "0002_add_cat_name", migrations[1].String())
+		assert.Equal(t, int64(10), migrations[2].Version)
+		assert.Equal(t, "DROP INDEX idx_cats_name;", migrations[2].Down)
+	})
+
+	tests := []struct {
+		name   string
+		source fstest.MapFS
+	}{
+		{name: "missing down", source: fstest.MapFS{"0001_create_cats.up.sql": {Data: []byte("SELECT 1;")}}},
+		{name: "unexpected file", source: fstest.MapFS{"create_cats.sql": {Data: []byte("SELECT 1;")}}},
+		{name: "version with two names", source: fstest.MapFS{
+			"0001_create_cats.up.sql":   {Data: []byte("SELECT 1;")},
+			"0001_create_dogs.down.sql": {Data: []byte("SELECT 1;")},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := postgres.LoadMigrations(tt.source)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestMigrations_Embedded(t *testing.T) {
+	migrations, err := postgres.LoadMigrations(postgres.Migrations())
+
+	require.NoError(t, err)
+	require.NotEmpty(t, migrations)
+	for i, migration := range migrations {
+		assert.Equal(t, int64(i+1), migration.Version, "migrations are numbered without gaps")
+	}
+	// The baseline adopts databases created by the old init.sql, every later change is plain DDL
+	assert.Contains(t, migrations[0].Up, "CREATE TABLE IF NOT EXISTS users")
+	for _, migration := range migrations[1:] {
+		assert.NotContains(t, migration.Up, "IF NOT EXISTS", migration.String())
+	}
+	assert.Contains(t, migrations[len(migrations)-1].Up, "CREATE TABLE payment_events")
+}
+
+func TestMigrator_Up(t *testing.T) {
+	t.Run("applies the pending migrations in order", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+
+		expectLocked(mock, 1)
+		for _, m := range []struct {
+			version int64
+			name    string
+			script  string
+		}{
+			{2, "add_cat_name", "ALTER TABLE cats ADD COLUMN name TEXT;"},
+			{10, "index_cat_name", "CREATE INDEX idx_cats_name ON cats(name);"},
+		} {
+			mock.ExpectBegin()
+			mock.ExpectExec(regexp.QuoteMeta(m.script)).WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`)).
+				WithArgs(m.version, m.name).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectCommit()
+		}
+		expectUnlock(mock)
+
+		migrator, err := postgres.NewMigrator(db, testMigrations)
+		require.NoError(t, err)
+		applied, err := migrator.Up(context.Background())
+
+		assert.NoError(t, err)
+		require.Len(t, applied, 2)
+		assert.Equal(t, int64(2), applied[0].Version)
+		assert.Equal(t, int64(10), applied[1].Version)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("failed migration is rolled back and stops the run", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+
+		expectLocked(mock, 1)
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("ALTER TABLE cats ADD COLUMN name TEXT;")).WillReturnError(sql.ErrConnDone)
+		mock.ExpectRollback()
+		expectUnlock(mock)
+
+		migrator, err := postgres.NewMigrator(db, testMigrations)
+		require.NoError(t, err)
+		applied, err := migrator.Up(context.Background())
+
+		assert.ErrorIs(t, err, sql.ErrConnDone)
+		assert.ErrorContains(t, err, "0002_add_cat_name")
+		assert.Empty(t, applied)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("up to date", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+
+		expectLocked(mock, 1, 2, 10)
+		expectUnlock(mock)
+
+		migrator, err := postgres.NewMigrator(db, testMigrations)
+		require.NoError(t, err)
+		applied, err := migrator.Up(context.Background())
+
+		assert.NoError(t, err)
+		assert.Empty(t, applied)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestMigrator_Down(t *testing.T) {
+	t.Run("reverts the newest migrations", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+
+		expectLocked(mock, 1, 2, 10)
+		for _, m := range []struct {
+			version int64
+			script  string
+		}{
+			{10, "DROP INDEX idx_cats_name;"},
+			{2, "ALTER TABLE cats DROP COLUMN name;"},
+		} {
+			mock.ExpectBegin()
+			mock.ExpectExec(regexp.QuoteMeta(m.script)).WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM schema_migrations WHERE version=$1`)).
+				WithArgs(m.version).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectCommit()
+		}
+		expectUnlock(mock)
+
+		migrator, err := postgres.NewMigrator(db, testMigrations)
+		require.NoError(t, err)
+		reverted, err := migrator.Down(context.Background(), 2)
+
+		assert.NoError(t, err)
+		require.Len(t, reverted, 2)
+		assert.Equal(t, int64(10), reverted[0].Version)
+		assert.Equal(t, int64(2), reverted[1].Version)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("applied migration missing from the build", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+
+		expectLocked(mock, 1, 2, 10, 11)
+		expectUnlock(mock)
+
+		migrator, err := postgres.NewMigrator(db, testMigrations)
+		require.NoError(t, err)
+		_, err = migrator.Down(context.Background(), 1)
+
+		assert.ErrorContains(t, err, "migration 11")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestMigrator_Status(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	expectLocked(mock, 1)
+	expectUnlock(mock)
+
+	migrator, err := postgres.NewMigrator(db, testMigrations)
+	require.NoError(t, err)
+	statuses, err := migrator.Status(context.Background())
+
+	assert.NoError(t, err)
+	require.Len(t, statuses, 3)
+	assert.Equal(t, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), *statuses[0].AppliedAt)
+	assert.Nil(t, statuses[1].AppliedAt)
+	assert.Nil(t, statuses[2].AppliedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateMigration(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "0007_create_cats.up.sql"), []byte("SELECT 1;"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "0007_create_cats.down.sql"), []byte("SELECT 1;"), 0o644))
+
+	up, down, err := postgres.CreateMigration(dir, "Add Cat Photos!")
+
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "0008_add_cat_photos.up.sql"), up)
+	assert.Equal(t, filepath.Join(dir, "0008_add_cat_photos.down.sql"), down)
+	_, err = postgres.LoadMigrations(os.DirFS(dir))
+	assert.NoError(t, err)
+
+	_, _, err = postgres.CreateMigration(dir, "!!!")
+	assert.Error(t, err)
+}
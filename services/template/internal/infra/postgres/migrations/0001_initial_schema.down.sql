@@ -0,0 +1,4 @@
+-- Remove o esquema inicial, na ordem inversa das dependências
+DROP TABLE IF EXISTS cats;
+DROP TABLE IF EXISTS users;
+DROP TABLE IF EXISTS plans;
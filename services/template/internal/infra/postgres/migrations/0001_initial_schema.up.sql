@@ -1,3 +1,7 @@
+-- Esquema do antigo project/sql/init.sql, anterior às migrações. Os comandos são idempotentes (IF NOT EXISTS),
+-- então bancos criados pelo init.sql passam a ser versionados sem alterações; as mudanças seguintes vêm
+-- cada uma na sua migração.
+
 -- Tabela de usuários
 CREATE TABLE IF NOT EXISTS users (
     id UUID PRIMARY KEY,
@@ -0,0 +1,3 @@
+-- Falha enquanto houver usuários cadastrados pelo login direto (sem firebase_uid)
+ALTER TABLE users DROP COLUMN password_hash;
+ALTER TABLE users ALTER COLUMN firebase_uid SET NOT NULL;
@@ -0,0 +1,3 @@
+-- Login direto (sem Firebase): firebase_uid passa a ser opcional e a senha fica em hash
+ALTER TABLE users ALTER COLUMN firebase_uid DROP NOT NULL;
+ALTER TABLE users ADD COLUMN password_hash VARCHAR(255);
@@ -0,0 +1,15 @@
+-- Tokens de acesso revogados individualmente (logout), mantidos até o exp original
+CREATE TABLE revoked_tokens (
+    jti TEXT PRIMARY KEY,
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP DEFAULT NOW()
+);
+
+-- Índice para limpeza dos tokens já expirados
+CREATE INDEX idx_revoked_tokens_expires_at ON revoked_tokens (expires_at);
+
+-- Revogação de todas as sessões de um usuário: tokens emitidos antes de revoked_before são rejeitados
+CREATE TABLE user_token_revocations (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    revoked_before TIMESTAMP NOT NULL
+);
@@ -0,0 +1,28 @@
+-- Papéis (roles) e permissões concedidas por cada papel
+CREATE TABLE roles (
+    name VARCHAR(50) PRIMARY KEY,
+    description TEXT,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE TABLE role_permissions (
+    role VARCHAR(50) REFERENCES roles(name) ON DELETE CASCADE,
+    permission VARCHAR(100) NOT NULL,
+    PRIMARY KEY (role, permission)
+);
+
+-- Papéis atribuídos a cada usuário (embutidos nas claims do JWT)
+CREATE TABLE user_roles (
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(50) REFERENCES roles(name) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT NOW(),
+    PRIMARY KEY (user_id, role)
+);
+
+-- Papel de administrador com as permissões das rotas /admin
+INSERT INTO roles (name, description) VALUES ('admin', 'Administrador do sistema');
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'users:read'),
+    ('admin', 'users:write'),
+    ('admin', 'roles:manage'),
+    ('admin', 'sessions:revoke');
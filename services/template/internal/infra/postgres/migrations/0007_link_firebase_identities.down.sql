@@ -0,0 +1,4 @@
+-- Remove as identidades do Firebase que correspondem ao firebase_uid do usuário, inclusive as criadas depois no login
+DELETE FROM user_identities
+USING users
+WHERE user_identities.user_id = users.id AND user_identities.provider = 'firebase' AND user_identities.subject = users.firebase_uid;
@@ -0,0 +1,5 @@
+-- Usuários do Firebase anteriores à tabela de identidades passam a ser encontrados pelo par (provider, subject).
+-- gen_random_uuid() é nativa a partir do PostgreSQL 13; nas versões anteriores exige a extensão pgcrypto
+-- (CREATE EXTENSION pgcrypto), criada antes de aplicar as migrações.
+INSERT INTO user_identities (id, user_id, provider, subject, email, created_at)
+SELECT gen_random_uuid(), id, 'firebase', firebase_uid, email, created_at FROM users WHERE firebase_uid IS NOT NULL;
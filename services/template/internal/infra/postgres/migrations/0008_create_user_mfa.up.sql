@@ -0,0 +1,17 @@
+-- Segundo fator (TOTP) do login direto; enabled_at fica nulo até a confirmação do primeiro código
+CREATE TABLE user_mfa (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    secret VARCHAR(64) NOT NULL,
+    last_used_step BIGINT NOT NULL DEFAULT 0,
+    enabled_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+-- Códigos de recuperação do segundo fator (somente o hash é persistido, cada código vale uma vez)
+CREATE TABLE mfa_recovery_codes (
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    code_hash TEXT NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW(),
+    PRIMARY KEY (user_id, code_hash)
+);
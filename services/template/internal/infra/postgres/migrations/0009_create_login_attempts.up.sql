@@ -0,0 +1,6 @@
+-- Tentativas de autenticação com falha por IP ("ip:<endereço>") ou por conta ("account:<e-mail>"), usadas no bloqueio progressivo
+CREATE TABLE login_attempts (
+    key VARCHAR(320) PRIMARY KEY,
+    failures INT NOT NULL,
+    last_failure_at TIMESTAMP NOT NULL
+);
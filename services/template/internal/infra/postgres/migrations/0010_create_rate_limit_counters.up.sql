@@ -0,0 +1,7 @@
+-- Contadores do rate limiting por janela fixa; a chave combina o grupo de rotas com o usuário ("user:<id>") ou o IP ("ip:<endereço>")
+CREATE TABLE rate_limit_counters (
+    key VARCHAR(255) NOT NULL,
+    window_start TIMESTAMP NOT NULL,
+    hits INT NOT NULL,
+    PRIMARY KEY (key, window_start)
+);
@@ -0,0 +1,10 @@
+-- Links de login sem senha enviados por e-mail; o id é o jti do token assinado e o link vale uma única vez
+CREATE TABLE magic_links (
+    id UUID PRIMARY KEY,
+    email VARCHAR(255) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_magic_links_expires_at ON magic_links(expires_at);
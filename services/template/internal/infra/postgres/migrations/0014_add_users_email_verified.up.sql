@@ -0,0 +1,2 @@
+-- Verificação de e-mail: vem do provedor de identidade, do link de verificação ou do link de login sem senha
+ALTER TABLE users ADD COLUMN email_verified BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,4 @@
+DROP INDEX idx_users_plan_type;
+DROP INDEX idx_users_email_prefix;
+DROP INDEX idx_users_created_at_id;
+ALTER TABLE users DROP COLUMN disabled_at;
@@ -0,0 +1,7 @@
+-- Contas desativadas por um administrador não conseguem entrar
+ALTER TABLE users ADD COLUMN disabled_at TIMESTAMP;
+
+-- Listagem paginada de usuários (/admin/users): ordenação por criação e busca por prefixo de e-mail
+CREATE INDEX idx_users_created_at_id ON users(created_at, id);
+CREATE INDEX idx_users_email_prefix ON users(email text_pattern_ops);
+CREATE INDEX idx_users_plan_type ON users(plan_type);
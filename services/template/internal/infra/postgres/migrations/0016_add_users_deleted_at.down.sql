@@ -0,0 +1,7 @@
+-- Falha enquanto houver uma conta excluída com o mesmo e-mail ou firebase_uid de outra
+DROP INDEX idx_users_firebase_uid_active;
+DROP INDEX idx_users_email_active;
+ALTER TABLE users ADD CONSTRAINT users_firebase_uid_key UNIQUE (firebase_uid);
+ALTER TABLE users ADD CONSTRAINT users_email_key UNIQUE (email);
+DROP INDEX idx_users_deleted_at;
+ALTER TABLE users DROP COLUMN deleted_at;
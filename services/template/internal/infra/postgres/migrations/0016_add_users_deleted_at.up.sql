@@ -0,0 +1,9 @@
+-- Exclusão de conta (LGPD/GDPR): a conta fica oculta e é apagada definitivamente após o período de retenção
+ALTER TABLE users ADD COLUMN deleted_at TIMESTAMP;
+CREATE INDEX idx_users_deleted_at ON users(deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- E-mail e firebase_uid só precisam ser únicos entre as contas ativas, assim quem excluiu a conta pode se cadastrar de novo
+ALTER TABLE users DROP CONSTRAINT users_email_key;
+ALTER TABLE users DROP CONSTRAINT users_firebase_uid_key;
+CREATE UNIQUE INDEX idx_users_email_active ON users(email) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX idx_users_firebase_uid_active ON users(firebase_uid) WHERE deleted_at IS NULL;
@@ -0,0 +1,3 @@
+ALTER TABLE users DROP COLUMN plan_id;
+DELETE FROM plans WHERE code IN ('free', 'premium');
+ALTER TABLE plans DROP COLUMN code;
@@ -0,0 +1,11 @@
+-- Planos identificados por um código estável (o mesmo de users.plan_type), usados nos limites e recursos de cada usuário
+ALTER TABLE plans ADD COLUMN code VARCHAR(50);
+CREATE UNIQUE INDEX idx_plans_code ON plans(code);
+INSERT INTO plans (id, code, name, description, price, max_cats, has_image_analysis, has_consultation) VALUES
+    ('8f2d6c1e-0a4b-4c3e-9f51-2b7d0e6a1c01', 'free', 'Gratuito', 'Cadastro de gatos sem recursos pagos', 0, 1, FALSE, FALSE),
+    ('8f2d6c1e-0a4b-4c3e-9f51-2b7d0e6a1c02', 'premium', 'Premium', 'Mais gatos, análise de imagens e consultas', 19.90, 10, TRUE, TRUE);
+
+-- Usuários passam a referenciar o plano pelo id; os existentes são vinculados pelo plan_type (sem plano = gratuito)
+ALTER TABLE users ADD COLUMN plan_id UUID REFERENCES plans(id);
+UPDATE users SET plan_id = plans.id FROM plans WHERE plans.code = COALESCE(NULLIF(users.plan_type, ''), 'free');
+CREATE INDEX idx_users_plan_id ON users(plan_id);
@@ -0,0 +1,2 @@
+DROP INDEX idx_users_plan_expiry;
+ALTER TABLE users DROP COLUMN plan_cancelled;
@@ -0,0 +1,3 @@
+-- Assinaturas: cancelamento ao fim do período pago e índice para a varredura dos planos vencidos
+ALTER TABLE users ADD COLUMN plan_cancelled BOOLEAN NOT NULL DEFAULT FALSE;
+CREATE INDEX idx_users_plan_expiry ON users(plan_expiry) WHERE plan_expiry IS NOT NULL;
@@ -0,0 +1,2 @@
+ALTER TABLE users DROP COLUMN plan_event_at;
+DROP TABLE payment_events;
@@ -0,0 +1,17 @@
+-- Webhooks do provedor de pagamentos, registrados uma única vez por evento
+CREATE TABLE payment_events (
+    provider VARCHAR(50) NOT NULL,
+    event_id VARCHAR(255) NOT NULL,
+    type VARCHAR(100) NOT NULL,
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE, -- O payload traz dados pessoais, sai junto com o usuário
+    payload JSONB NOT NULL,
+    created_at TIMESTAMP NOT NULL, -- Quando o provedor gerou o evento
+    received_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    rejection VARCHAR(50), -- Por que o evento foi registrado sem ser aplicado (unknown_plan, out_of_order)
+    PRIMARY KEY (provider, event_id)
+);
+
+CREATE INDEX idx_payment_events_user_id ON payment_events(user_id);
+
+-- Data do último evento de pagamento aplicado ao usuário, os eventos mais antigos que chegam depois são ignorados
+ALTER TABLE users ADD COLUMN plan_event_at TIMESTAMP;
@@ -1,12 +1,15 @@
 package postgres
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
 	"os"
 	"time"
 
+	"github.com/nuhorizon/go-project-template/services/template/pkg/utils"
+
 	"github.com/cenkalti/backoff/v4"
 	_ "github.com/lib/pq"
 )
@@ -14,6 +17,8 @@ import (
 type Pgsql struct {
 	DB  *sql.DB
 	Dsn string
+	// AutoMigrate applies the pending migrations once connected (PG_AUTO_MIGRATE)
+	AutoMigrate bool
 }
 
 var SQLOpen = sql.Open
@@ -36,7 +41,7 @@ func NewPGSql() *Pgsql {
 
 		user, password, host, port, dbName, sslMode)
 
-	return &Pgsql{Dsn: dsn}
+	return &Pgsql{Dsn: dsn, AutoMigrate: utils.GetEnvAsBool("PG_AUTO_MIGRATE", false)}
 }
 
 // InitDB uses a default backoff config
@@ -75,6 +80,26 @@ func (d *Pgsql) InitDBWithBackoff(b backoff.BackOff) error {
 		return err
 	}
 
+	if d.AutoMigrate {
+		return d.migrate()
+	}
+	return nil
+}
+
+// migrate applies the migrations embedded in the binary
+func (d *Pgsql) migrate() error {
+	migrator, err := NewMigrator(d.DB, Migrations())
+	if err != nil {
+		return err
+	}
+	applied, err := migrator.Up(context.Background())
+	for _, migration := range applied {
+		log.Println("[Postgres] Applied migration", migration)
+	}
+	if err != nil {
+		log.Println("[Postgres] Migration failed:", err)
+		return err
+	}
 	return nil
 }
 
@@ -3,6 +3,7 @@ package postgres_test
 import (
 	"database/sql"
 	"errors"
+	"regexp"
 	"testing"
 	"time"
 
@@ -73,6 +74,38 @@ func TestPgsql_InitDBWithBackoff(t *testing.T) {
 		assert.NotNil(t, pg.DB)
 	})
 
+	t.Run("applies the migrations when AutoMigrate is set", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		migrations, err := postgres.LoadMigrations(postgres.Migrations())
+		require.NoError(t, err)
+
+		// A new database gets every embedded migration, the baseline first
+		expectLocked(mock)
+		for _, migration := range migrations {
+			mock.ExpectBegin()
+			mock.ExpectExec(regexp.QuoteMeta(migration.Up)).WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`)).
+				WithArgs(migration.Version, migration.Name).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectCommit()
+		}
+		expectUnlock(mock)
+
+		originalSQLOpen := postgres.SQLOpen
+		postgres.SQLOpen = func(driverName, dataSourceName string) (*sql.DB, error) {
+			return db, nil
+		}
+		defer func() { postgres.SQLOpen = originalSQLOpen }()
+
+		pg := &postgres.Pgsql{Dsn: "ignored_dsn_for_mock", AutoMigrate: true}
+		err = pg.InitDBWithBackoff(backoff.NewExponentialBackOff())
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
 	t.Run("connection fails and retries until timeout", func(t *testing.T) {
 		originalSQLOpen := postgres.SQLOpen
 		postgres.SQLOpen = func(driverName, dataSourceName string) (*sql.DB, error) {
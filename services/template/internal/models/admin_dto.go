@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// AdminUserResponse is the view administrators get of an account
+type AdminUserResponse struct {
+	ID            string     `json:"id"`
+	Name          string     `json:"name"`
+	Email         string     `json:"email"`
+	PictureURL    string     `json:"picture_url,omitempty"`
+	PlanType      string     `json:"plan_type"`
+	PremiumSince  *time.Time `json:"premium_since,omitempty"`
+	PlanExpiry    *time.Time `json:"plan_expiry,omitempty"`
+	PlanCancelled bool       `json:"plan_cancelled"` // The plan ends at plan_expiry without renewal
+	EmailVerified bool       `json:"email_verified"`
+	Roles         []string   `json:"roles,omitempty"` // Only on GET /admin/users/{id}
+	DisabledAt    *time.Time `json:"disabled_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+type UserListResponse struct {
+	Users      []AdminUserResponse `json:"users"`
+	NextCursor string              `json:"next_cursor,omitempty"` // Omitted on the last page
+}
+
+type UpdatePlanRequest struct {
+	PlanType   string     `json:"plan_type" validate:"required,max=50"` // Code of a row of the plans table
+	PlanExpiry *time.Time `json:"plan_expiry,omitempty"`                // Ignored on the free plan, never expires when omitted
+}
@@ -1,18 +1,53 @@
 package models
 
+import "time"
+
 type ExchangeTokenRequest struct {
 	RefreshToken string `json:"refresh_token" validate:"required"`
 }
 
-// LoginRequest sent by mobile with Firebase Token
+// LoginRequest sent by mobile with Firebase Token, or with the ID token of
+// another identity provider (OpenID Connect) named in Provider
 type LoginRequest struct {
-	FirebaseToken string `json:"firebase_token" validate:"required"`
+	FirebaseToken string `json:"firebase_token,omitempty" validate:"required_without=IDToken"`
+	Provider      string `json:"provider,omitempty" validate:"required_with=IDToken"`
+	IDToken       string `json:"id_token,omitempty" validate:"required_without=FirebaseToken"`
+}
+
+// LinkIdentityRequest links another identity provider account to the signed-in user
+type LinkIdentityRequest struct {
+	Provider string `json:"provider" validate:"required"` // "firebase" or a configured OpenID Connect provider
+	IDToken  string `json:"id_token" validate:"required"`
+}
+
+// IdentityResponse is an identity provider linked to the user
+type IdentityResponse struct {
+	Provider string    `json:"provider"`
+	Email    string    `json:"email,omitempty"`
+	LinkedAt time.Time `json:"linked_at"`
+}
+
+// SessionResponse is a signed-in device of the user
+type SessionResponse struct {
+	ID         string    `json:"id"`
+	UserAgent  string    `json:"user_agent,omitempty"`
+	IP         string    `json:"ip,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	Current    bool      `json:"current"` // Session of the token used in the request
 }
 
 // LoginResponse contains the app token + user basic info
 type LoginResponse struct {
-	Token string       `json:"token"`
-	User  UserResponse `json:"user"` // Imported from user_dto.go
+	Token        string       `json:"token"`
+	RefreshToken string       `json:"refresh_token"`
+	User         UserResponse `json:"user"` // Imported from user_dto.go
+}
+
+// LogoutRequest optionally carries the refresh token of the session being closed
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token,omitempty"`
+	AllSessions  bool   `json:"all_sessions,omitempty"` // Revokes every session of the user
 }
 
 // ResetPasswordRequest for password reset flow
@@ -20,6 +55,18 @@ type ResetPasswordRequest struct {
 	Email string `json:"email" validate:"required,email"`
 }
 
+// MagicLinkRequest asks for a passwordless login link
+type MagicLinkRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// RegisterRequest creates an account with e-mail/password, without Firebase
+type RegisterRequest struct {
+	Name     string `json:"name" validate:"required,max=255"`
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required,min=8,max=72"` // bcrypt ignores bytes past 72
+}
+
 // DirectLoginRequest (optional) if allowing backend auth
 type DirectLoginRequest struct {
 	Email    string `json:"email" validate:"required,email"`
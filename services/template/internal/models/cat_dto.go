@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// CatRequest registers a cat or replaces all of its details, omitted optional fields are cleared
+type CatRequest struct {
+	Name       string   `json:"name" validate:"required,max=255"`
+	Breed      string   `json:"breed,omitempty" validate:"max=255"`
+	BirthDate  string   `json:"birth_date,omitempty" validate:"omitempty,datetime=2006-01-02"` // YYYY-MM-DD
+	Gender     string   `json:"gender,omitempty" validate:"omitempty,oneof=male female"`
+	WeightKg   *float64 `json:"weight_kg,omitempty" validate:"omitnil,gt=0,lt=1000"`
+	PictureURL string   `json:"picture_url,omitempty" validate:"omitempty,max=2048,http_url"`
+	IsNeutered bool     `json:"is_neutered"`
+}
+
+type CatResponse struct {
+	ID         string    `json:"id"`
+	Name       string    `json:"name"`
+	Breed      string    `json:"breed,omitempty"`
+	BirthDate  string    `json:"birth_date,omitempty"` // YYYY-MM-DD
+	Gender     string    `json:"gender,omitempty"`
+	WeightKg   *float64  `json:"weight_kg,omitempty"`
+	PictureURL string    `json:"picture_url,omitempty"`
+	IsNeutered bool      `json:"is_neutered"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
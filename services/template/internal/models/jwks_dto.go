@@ -0,0 +1,18 @@
+package models
+
+// JWKResponse is a public key in RFC 7517 format
+type JWKResponse struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`   // RSA modulus
+	E   string `json:"e,omitempty"`   // RSA exponent
+	Crv string `json:"crv,omitempty"` // OKP curve
+	X   string `json:"x,omitempty"`   // OKP public key
+}
+
+// JWKSResponse is served at /.well-known/jwks.json
+type JWKSResponse struct {
+	Keys []JWKResponse `json:"keys"`
+}
@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// MFAChallengeResponse is returned by a password login that still needs the second factor
+type MFAChallengeResponse struct {
+	MFARequired bool      `json:"mfa_required"`
+	MFAToken    string    `json:"mfa_token"` // Sent back to /auth/mfa/verify along with the code
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// MFAVerifyRequest completes a password login with a TOTP or recovery code
+type MFAVerifyRequest struct {
+	MFAToken string `json:"mfa_token" validate:"required"`
+	Code     string `json:"code" validate:"required,max=32"`
+}
+
+// TOTPEnrollmentResponse carries the secret to register in an authenticator app
+type TOTPEnrollmentResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"` // Payload of the enrolment QR code
+}
+
+// MFACodeRequest proves possession of the second factor (TOTP or recovery code)
+type MFACodeRequest struct {
+	Code string `json:"code" validate:"required,max=32"`
+}
+
+// RecoveryCodesResponse lists the single-use recovery codes, shown only once
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
@@ -0,0 +1,11 @@
+package models
+
+// EntitlementErrorResponse is the body of the 402/403 answers given when the plan of the user
+// doesn't allow the request, clients use it to offer an upgrade
+type EntitlementErrorResponse struct {
+	Error   string `json:"error"`
+	Reason  string `json:"reason"` // feature_not_in_plan or cat_limit_reached
+	Plan    string `json:"plan"`
+	Feature string `json:"feature,omitempty"`
+	Limit   *int   `json:"limit,omitempty"` // Set for cat_limit_reached
+}
@@ -1,14 +1,33 @@
 package models
 
+import (
+	"encoding/json"
+	"time"
+)
+
 type CreateUserRequest struct {
 	Name  string `json:"name" validate:"required"`
 	Email string `json:"email" validate:"required,email"`
 }
 
+// UpdateUserRequest is a partial update, omitted fields are kept
+type UpdateUserRequest struct {
+	Name       *string `json:"name,omitempty" validate:"omitnil,min=1,max=100"`
+	PictureURL *string `json:"picture_url,omitempty" validate:"omitnil,max=2048,http_url|len=0"`
+}
+
 type UserResponse struct {
-	ID         string `json:"id"`
-	Name       string `json:"name"`
-	Email      string `json:"email"`
-	PictureURL string `json:"picture_url,omitempty"`
-	PlanType   string `json:"plan_type"`
+	ID            string `json:"id"`
+	Name          string `json:"name"`
+	Email         string `json:"email"`
+	PictureURL    string `json:"picture_url,omitempty"`
+	PlanType      string `json:"plan_type"`
+	EmailVerified bool   `json:"email_verified"`
+}
+
+// UserDataExportResponse holds everything stored about the user, each section is a list of rows
+type UserDataExportResponse struct {
+	UserID      string                     `json:"user_id"`
+	GeneratedAt time.Time                  `json:"generated_at"`
+	Data        map[string]json.RawMessage `json:"data" swaggertype:"object"`
 }
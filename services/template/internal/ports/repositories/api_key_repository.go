@@ -0,0 +1,27 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+)
+
+// APIKeyRepository persists the hashed personal API keys of each user
+type APIKeyRepository interface {
+	// Create stores a newly issued key
+	Create(ctx context.Context, key *domain.APIKey) error
+
+	// FindByHash retrieves a key (revoked ones included) by the hash of its value
+	FindByHash(ctx context.Context, keyHash string) (*domain.APIKey, error)
+
+	// ListByUserID retrieves every key of the user, newest first
+	ListByUserID(ctx context.Context, userID string) ([]domain.APIKey, error)
+
+	// Revoke revokes a key of the user. Returns domain.ErrAPIKeyNotFound when the user has no
+	// active key with that ID.
+	Revoke(ctx context.Context, userID, keyID string) error
+
+	// TouchLastUsed records that the key authenticated a request at the given instant
+	TouchLastUsed(ctx context.Context, keyID string, at time.Time) error
+}
@@ -0,0 +1,27 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+)
+
+// CatRepository persists the cats of each user. Every lookup is scoped to the owner, a cat of
+// another user is reported as missing.
+type CatRepository interface {
+	// Create stores a new cat, filling in its timestamps, unless the user already has as many cats
+	// as their plan allows: then it fails with a *domain.EntitlementError. The limit is read along
+	// with the count and creations for the same user are serialized, so neither a concurrent
+	// creation nor a plan change can let the user past it. Fails with sql.ErrNoRows when the user
+	// doesn't exist and domain.ErrPlanNotFound when their plan doesn't.
+	Create(ctx context.Context, cat *domain.Cat) error
+	// FindByID retrieves a cat of the user, failing with sql.ErrNoRows when the user has no cat with that ID
+	FindByID(ctx context.Context, userID, id string) (*domain.Cat, error)
+	// ListByUserID retrieves the cats of the user, oldest first
+	ListByUserID(ctx context.Context, userID string) ([]domain.Cat, error)
+	// Update replaces the details of a cat of the user, filling in its timestamps.
+	// Returns domain.ErrCatNotFound when the user has no cat with that ID.
+	Update(ctx context.Context, cat *domain.Cat) error
+	// Delete removes a cat of the user. Returns domain.ErrCatNotFound when the user has no cat with that ID.
+	Delete(ctx context.Context, userID, id string) error
+}
@@ -0,0 +1,22 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+)
+
+// IdentityRepository links users to the (provider, subject) pairs they sign in with
+type IdentityRepository interface {
+	// FindByProviderSubject retrieves the identity a provider asserted for a subject
+	FindByProviderSubject(ctx context.Context, provider, subject string) (*domain.Identity, error)
+
+	// ListByUserID retrieves every identity linked to a user
+	ListByUserID(ctx context.Context, userID string) ([]domain.Identity, error)
+
+	// Create links a new identity to an existing user
+	Create(ctx context.Context, identity *domain.Identity) error
+
+	// CreateWithUser stores a new user and its first identity in a single transaction
+	CreateWithUser(ctx context.Context, user *domain.User, identity *domain.Identity) error
+}
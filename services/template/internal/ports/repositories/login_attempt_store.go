@@ -0,0 +1,21 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+)
+
+// LoginAttemptStore counts failed authentication attempts per key (client IP or account)
+type LoginAttemptStore interface {
+	// Get returns the failure history of the key, a zero value when there is none
+	Get(ctx context.Context, key string) (domain.LoginAttempts, error)
+
+	// RecordFailure counts a failure at the given instant and returns the updated history.
+	// Failures older than resetAfter are forgotten and the count starts over.
+	RecordFailure(ctx context.Context, key string, at time.Time, resetAfter time.Duration) (domain.LoginAttempts, error)
+
+	// Reset forgets the failure history of the key
+	Reset(ctx context.Context, key string) error
+}
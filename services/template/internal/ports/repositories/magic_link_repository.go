@@ -0,0 +1,21 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+)
+
+// MagicLinkRepository persists the magic links of the passwordless e-mail login
+type MagicLinkRepository interface {
+	Create(ctx context.Context, link *domain.MagicLink) error
+
+	// Consume marks an unused, unexpired link as used at the given time and returns it,
+	// failing with sql.ErrNoRows when the link is unknown, already used or expired
+	Consume(ctx context.Context, id string, at time.Time) (*domain.MagicLink, error)
+
+	// PruneExpired removes the links that expired before now, used or not, since they can't be
+	// consumed anymore. Returns how many were removed.
+	PruneExpired(ctx context.Context, now time.Time) (int64, error)
+}
@@ -0,0 +1,28 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+)
+
+// MFARepository persists the TOTP second factor and the hashed recovery codes of a user
+type MFARepository interface {
+	// FindByUserID retrieves the enrolment of a user, pending or enabled
+	FindByUserID(ctx context.Context, userID string) (*domain.MFAEnrollment, error)
+
+	// SavePending stores a new secret awaiting confirmation, replacing a previous pending one
+	SavePending(ctx context.Context, userID, secret string) error
+
+	// Enable activates the enrolment and replaces the recovery codes in a single transaction
+	Enable(ctx context.Context, userID string, step int64, recoveryCodeHashes []string) error
+
+	// Delete removes the enrolment and its recovery codes
+	Delete(ctx context.Context, userID string) error
+
+	// RecordStep marks a TOTP time step as used, failing with ErrInvalidMFACode on replays
+	RecordStep(ctx context.Context, userID string, step int64) error
+
+	// UseRecoveryCode consumes an unused recovery code, failing with ErrInvalidMFACode otherwise
+	UseRecoveryCode(ctx context.Context, userID, codeHash string) error
+}
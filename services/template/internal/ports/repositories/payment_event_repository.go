@@ -0,0 +1,21 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+)
+
+// PaymentPlanChange updates the plan of the user an event is about, in place, and reports whether
+// it changed
+type PaymentPlanChange func(user *domain.User) bool
+
+// PaymentEventRepository keeps the webhooks received from the payment provider
+type PaymentEventRepository interface {
+	// Record stores the event once. The user of the event (event.UserID) is locked until the event
+	// is stored and apply, when not nil, changes their plan in the same transaction. Events older
+	// than the last one applied to the user are stored as domain.PaymentEventOutOfOrder without
+	// calling apply. event.UserID is cleared when the user doesn't exist. Returns false without
+	// changing anything when the event was already recorded.
+	Record(ctx context.Context, event *domain.PaymentEvent, apply PaymentPlanChange) (bool, error)
+}
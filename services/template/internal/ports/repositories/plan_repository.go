@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+)
+
+// PlanRepository reads the plans users can be on
+type PlanRepository interface {
+	// FindByID retrieves a plan, failing with sql.ErrNoRows when there is none with that ID
+	FindByID(ctx context.Context, id string) (*domain.Plan, error)
+	// FindByCode retrieves a plan by its code (domain.PlanFree, ...), failing with sql.ErrNoRows when there is none
+	FindByCode(ctx context.Context, code string) (*domain.Plan, error)
+	// List retrieves every plan, cheapest first
+	List(ctx context.Context) ([]domain.Plan, error)
+}
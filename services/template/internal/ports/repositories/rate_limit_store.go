@@ -0,0 +1,15 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+)
+
+// RateLimitStore counts requests per key in fixed windows of the given length
+type RateLimitStore interface {
+	// Hit counts a request in the window starting at windowStart and returns the hits of that
+	// window (this one included) and of the window right before it
+	Hit(ctx context.Context, key string, windowStart time.Time, window time.Duration) (domain.RateLimitCounts, error)
+}
@@ -0,0 +1,26 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+)
+
+// RefreshTokenRepository persists hashed refresh tokens and their rotation chain
+type RefreshTokenRepository interface {
+	// Create stores a newly issued refresh token
+	Create(ctx context.Context, token *domain.RefreshToken) error
+
+	// FindByHash retrieves a refresh token by the hash of its opaque value
+	FindByHash(ctx context.Context, tokenHash string) (*domain.RefreshToken, error)
+
+	// Rotate revokes the current token and stores its replacement in a single transaction.
+	// Returns domain.ErrRefreshTokenReused if the current token was already revoked.
+	Rotate(ctx context.Context, current *domain.RefreshToken, next *domain.RefreshToken) error
+
+	// RevokeFamily revokes every token issued from the same login (used on reuse detection)
+	RevokeFamily(ctx context.Context, familyID string) error
+
+	// RevokeAllForUser revokes every active refresh token of the user (logout everywhere)
+	RevokeAllForUser(ctx context.Context, userID string) error
+}
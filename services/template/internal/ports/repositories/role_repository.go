@@ -0,0 +1,19 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+)
+
+// RoleRepository persists the roles granted to each user and the permissions of each role
+type RoleRepository interface {
+	// FindByUserID retrieves the roles of a user with their permissions
+	FindByUserID(ctx context.Context, userID string) ([]domain.Role, error)
+
+	// AssignRole grants a role to the user. Returns domain.ErrRoleNotFound for unknown roles.
+	AssignRole(ctx context.Context, userID, role string) error
+
+	// RemoveRole takes a role away from the user
+	RemoveRole(ctx context.Context, userID, role string) error
+}
@@ -0,0 +1,20 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+)
+
+// SessionRepository persists the signed-in devices of each user
+type SessionRepository interface {
+	// Upsert records a new session or, for an existing one, its latest client and last-seen time
+	Upsert(ctx context.Context, session *domain.Session) error
+
+	// FindByID retrieves a session by its ID (the refresh token family ID)
+	FindByID(ctx context.Context, id string) (*domain.Session, error)
+
+	// ListActiveByUserID retrieves the sessions of the user that still hold a usable refresh
+	// token, most recently seen first
+	ListActiveByUserID(ctx context.Context, userID string) ([]domain.Session, error)
+}
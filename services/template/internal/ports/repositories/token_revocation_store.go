@@ -0,0 +1,28 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+)
+
+// TokenRevocationStore keeps track of access tokens that must be rejected before their exp
+type TokenRevocationStore interface {
+	// RevokeToken blocks a single token (by jti) until it would have expired anyway
+	RevokeToken(ctx context.Context, tokenID string, expiresAt time.Time) error
+
+	// RevokeUserTokens blocks every token of the user issued up to the given instant, included.
+	// Callers pass a whole second since iat has a one second resolution.
+	RevokeUserTokens(ctx context.Context, userID string, before time.Time) error
+
+	// RevokeSession blocks every token issued for the session (sid), until the last of them expires
+	RevokeSession(ctx context.Context, sessionID string, expiresAt time.Time) error
+
+	// IsRevoked reports whether the token was revoked by its jti, its session or through its user
+	IsRevoked(ctx context.Context, claims *domain.TokenClaims) (bool, error)
+
+	// PruneExpired forgets the revoked tokens and sessions whose tokens all expired before now,
+	// their exp rejects them anyway. Returns how many were removed.
+	PruneExpired(ctx context.Context, now time.Time) (int64, error)
+}
@@ -0,0 +1,13 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+)
+
+// UserDataRepository gathers the rows of every table that belong to a user
+type UserDataRepository interface {
+	// Export collects the user's records in a single consistent snapshot
+	Export(ctx context.Context, userID string) (*domain.UserDataExport, error)
+}
@@ -2,27 +2,53 @@ package repositories
 
 import (
 	"context"
+	"time"
 
 	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
 )
 
 // UserRepository defines the methods the use case expects from any User repository implementation (DB, mock, etc.)
 type UserRepository interface {
-	// Create a new user in the database
+	// Create a new user in the database. Fails with domain.ErrEmailAlreadyRegistered when another
+	// active account owns the e-mail.
 	Create(ctx context.Context, user *domain.User) error
 
-	// Update updates an existing user
+	// Update updates the profile of an existing user (e-mail, name, picture, verification).
+	// The plan is left alone, it only changes through UpdatePlan.
 	Update(ctx context.Context, user *domain.User) error
 
-	// UpsertByFirebaseUID creates or updates a user based on Firebase UID (used in login/sync)
-	UpsertByFirebaseUID(ctx context.Context, user *domain.User) (*domain.User, error)
+	// UpdatePlan stores the plan fields of the user (plan, premium period, expiry, cancellation).
+	// Returns domain.ErrUserNotFound when there is no such user.
+	UpdatePlan(ctx context.Context, user *domain.User) error
+
+	// ListLapsedPlans returns up to limit users whose paid plan ended: cancelled plans that expired
+	// before cancelledBefore, the others once they expired before expiredBefore
+	ListLapsedPlans(ctx context.Context, cancelledBefore, expiredBefore time.Time, limit int) ([]domain.User, error)
+
+	// MarkEmailVerified records that the user proved ownership of their e-mail
+	MarkEmailVerified(ctx context.Context, id string) error
+
+	// ClearPassword removes the local password of the user, who can no longer sign in with it
+	ClearPassword(ctx context.Context, id string) error
+
+	// SetDisabled disables the user's account, or enables it again when disabledAt is nil
+	SetDisabled(ctx context.Context, id string, disabledAt *time.Time) error
+
+	// SoftDelete hides the user from every lookup and unlinks their identities, the rows are kept
+	// until PurgeDeleted removes them
+	SoftDelete(ctx context.Context, id string, deletedAt time.Time) error
+
+	// PurgeDeleted permanently removes the users soft deleted before the given instant, along with
+	// everything they own and the rows keyed by their e-mail, and returns how many were removed
+	PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int64, error)
 
 	// FindByID retrieves a user by internal system UUID
 	FindByID(ctx context.Context, id string) (*domain.User, error)
 
-	// FindByFirebaseUID retrieves a user by Firebase UID
-	FindByFirebaseUID(ctx context.Context, firebaseUID string) (*domain.User, error)
-
 	// FindByEmail retrieves a user by email (useful if supporting direct login)
 	FindByEmail(ctx context.Context, email string) (*domain.User, error)
+
+	// List returns a page of the users matching the filter and the cursor of the next page
+	// (empty on the last one). A cursor from another sort fails with domain.ErrInvalidCursor.
+	List(ctx context.Context, filter domain.UserFilter) ([]domain.User, string, error)
 }
@@ -0,0 +1,13 @@
+package services
+
+import (
+	"context"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+)
+
+// SubscriptionEventPublisher delivers the plan changes of users to whoever reacts to them
+// (billing, analytics, e-mails)
+type SubscriptionEventPublisher interface {
+	Publish(ctx context.Context, event domain.SubscriptionEvent) error
+}
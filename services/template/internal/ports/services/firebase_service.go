@@ -13,18 +13,22 @@ type AppInterface interface {
 type FirebaseAuthService interface {
 	VerifyToken(ctx context.Context, firebaseToken string) (*FirebaseUser, error)
 	SendPasswordReset(ctx context.Context, email string) error
+	// DeleteUser removes the Firebase account, succeeding when it no longer exists
+	DeleteUser(ctx context.Context, uid string) error
 }
 
 type FirebaseClient interface {
 	VerifyIDToken(ctx context.Context, idToken string) (*auth.Token, error)
 	GetUser(ctx context.Context, uid string) (*auth.UserRecord, error)
 	PasswordResetLinkWithSettings(ctx context.Context, email string, settings *auth.ActionCodeSettings) (string, error)
+	DeleteUser(ctx context.Context, uid string) error
 }
 
 type FirebaseUser struct {
-	UID       string
-	Email     string
-	Name      string
-	Picture   string
-	AppUserID string // Optional: for DB PK if you need to inject/generate
+	UID           string
+	Email         string
+	EmailVerified bool
+	Name          string
+	Picture       string
+	AppUserID     string // Optional: for DB PK if you need to inject/generate
 }
@@ -0,0 +1,20 @@
+package services
+
+import "context"
+
+// ExternalIdentity is the identity asserted by a verified identity provider token
+type ExternalIdentity struct {
+	Provider      string
+	Subject       string // Stable user identifier at the provider (sub claim)
+	Email         string
+	EmailVerified bool
+	Name          string
+	Picture       string
+}
+
+// IdentityProvider verifies tokens issued by an external identity provider (OpenID Connect, ...)
+type IdentityProvider interface {
+	// Name identifies the provider in login requests and in the user_identities table
+	Name() string
+	VerifyToken(ctx context.Context, token string) (*ExternalIdentity, error)
+}
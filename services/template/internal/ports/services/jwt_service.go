@@ -1,10 +1,35 @@
 package services
 
 import (
+	"crypto"
+	"time"
+
 	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
 )
 
 type JWTService interface {
-	GenerateToken(user *domain.User) (string, error)
-	ValidateToken(token string) (*domain.User, error)
+	// GenerateToken issues an access token of the session (refresh token family) sessionID
+	GenerateToken(user *domain.User, sessionID string) (string, error)
+	ValidateToken(token string) (*domain.TokenClaims, error)
+	// GenerateMFAToken issues the short-lived "mfa_pending" token of a password login awaiting its second factor
+	GenerateMFAToken(userID string) (string, error)
+	// ValidateMFAToken returns the user and jti of a "mfa_pending" token; access tokens are rejected
+	ValidateMFAToken(token string) (*domain.TokenClaims, error)
+	// GenerateMagicLinkToken signs the "magic_link" token of the link linkID mailed to email
+	GenerateMagicLinkToken(linkID, email string, expiresAt time.Time) (string, error)
+	// ValidateMagicLinkToken returns the link ID and e-mail of a "magic_link" token; other tokens are rejected
+	ValidateMagicLinkToken(token string) (linkID, email string, err error)
+	// GenerateEmailVerificationToken signs the "email_verification" token proving userID owns email
+	GenerateEmailVerificationToken(userID, email string) (string, error)
+	// ValidateEmailVerificationToken returns the user and e-mail of an "email_verification" token
+	ValidateEmailVerificationToken(token string) (userID, email string, err error)
+	// PublicKeys lists the keys other services may use to verify app tokens (empty for HS256)
+	PublicKeys() []PublicKey
+}
+
+// PublicKey is a verification key published through the JWKS endpoint
+type PublicKey struct {
+	Kid       string
+	Algorithm string
+	Key       crypto.PublicKey
 }
@@ -0,0 +1,36 @@
+package services
+
+import "context"
+
+// Templates available to Mailer.Send (see internal/services/templates/email)
+const (
+	EmailTemplatePasswordReset = "password_reset"
+	EmailTemplateMagicLink     = "magic_link"
+	EmailTemplateVerifyEmail   = "verify_email"
+)
+
+// Mailer renders a transactional e-mail template with data and delivers it to a single recipient
+type Mailer interface {
+	Send(ctx context.Context, to string, template string, data any) error
+}
+
+// PasswordResetEmail is the data of the password_reset template
+type PasswordResetEmail struct {
+	Email string
+	Link  string
+}
+
+// MagicLinkEmail is the data of the magic_link template
+type MagicLinkEmail struct {
+	Email            string
+	Link             string
+	ExpiresInMinutes int
+}
+
+// VerifyEmailEmail is the data of the verify_email template
+type VerifyEmailEmail struct {
+	Name           string
+	Email          string
+	Link           string
+	ExpiresInHours int
+}
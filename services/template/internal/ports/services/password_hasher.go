@@ -0,0 +1,7 @@
+package services
+
+// PasswordHasher hashes and verifies passwords for the direct (non-Firebase) login flow
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	Compare(hash, password string) error
+}
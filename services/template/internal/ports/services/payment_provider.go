@@ -0,0 +1,16 @@
+package services
+
+import (
+	"time"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+)
+
+// PaymentProvider reads the webhooks sent by the payment provider
+type PaymentProvider interface {
+	// SignatureHeader is the HTTP header carrying the signature of the webhooks
+	SignatureHeader() string
+	// ParseWebhook verifies the signature of payload as of now and decodes it. Fails with
+	// domain.ErrInvalidWebhookSignature or domain.ErrInvalidWebhookPayload.
+	ParseWebhook(payload []byte, signature string, now time.Time) (*domain.PaymentEvent, error)
+}
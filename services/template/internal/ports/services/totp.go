@@ -0,0 +1,12 @@
+package services
+
+import "time"
+
+// TOTP generates and checks time-based one-time passwords (RFC 6238)
+type TOTP interface {
+	GenerateSecret() (string, error)
+	// ProvisioningURI is the otpauth:// payload authenticator apps read from the enrolment QR code
+	ProvisioningURI(secret, account string) string
+	// Validate returns the time step the code belongs to, accepting one step of clock drift
+	Validate(secret, code string, at time.Time) (int64, bool)
+}
@@ -0,0 +1,63 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/internal/ports/repositories"
+)
+
+// loginAttemptMemory is a process-local attempt store, meant for single instance deployments
+// and tests. Counters are lost on restart and are not shared between instances.
+type loginAttemptMemory struct {
+	mu        sync.Mutex
+	attempts  map[string]domain.LoginAttempts
+	lastSweep time.Time
+}
+
+func NewLoginAttemptMemory() repositories.LoginAttemptStore {
+	return &loginAttemptMemory{attempts: make(map[string]domain.LoginAttempts)}
+}
+
+func (m *loginAttemptMemory) Get(ctx context.Context, key string) (domain.LoginAttempts, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.attempts[key], nil
+}
+
+func (m *loginAttemptMemory) RecordFailure(ctx context.Context, key string, at time.Time, resetAfter time.Duration) (domain.LoginAttempts, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	current := m.attempts[key]
+	if at.Sub(current.LastFailureAt) > resetAfter {
+		current = domain.LoginAttempts{}
+	}
+
+	// Histories past the reset window would start over anyway, drop them to bound the map. One
+	// sweep per window is enough, a scan on every failure would slow down the attacks it counts.
+	if at.Sub(m.lastSweep) > resetAfter {
+		for k, a := range m.attempts {
+			if at.Sub(a.LastFailureAt) > resetAfter {
+				delete(m.attempts, k)
+			}
+		}
+		m.lastSweep = at
+	}
+
+	current.Failures++
+	current.LastFailureAt = at
+	m.attempts[key] = current
+	return current, nil
+}
+
+func (m *loginAttemptMemory) Reset(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.attempts, key)
+	return nil
+}
@@ -0,0 +1,58 @@
+package memory_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/repository/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoginAttemptMemory(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+
+	t.Run("counts failures", func(t *testing.T) {
+		store := memory.NewLoginAttemptMemory()
+
+		attempts, err := store.Get(ctx, "ip:192.0.2.1")
+		assert.NoError(t, err)
+		assert.Zero(t, attempts.Failures)
+
+		store.RecordFailure(ctx, "ip:192.0.2.1", now, time.Hour)
+		attempts, err = store.RecordFailure(ctx, "ip:192.0.2.1", now.Add(time.Second), time.Hour)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, attempts.Failures)
+		assert.Equal(t, now.Add(time.Second), attempts.LastFailureAt)
+
+		other, _ := store.Get(ctx, "ip:192.0.2.2")
+		assert.Zero(t, other.Failures)
+	})
+
+	t.Run("starts over after the reset window", func(t *testing.T) {
+		store := memory.NewLoginAttemptMemory()
+		store.RecordFailure(ctx, "account:user@example.com", now, time.Hour)
+
+		attempts, _ := store.RecordFailure(ctx, "account:user@example.com", now.Add(2*time.Hour), time.Hour)
+		assert.Equal(t, 1, attempts.Failures)
+	})
+
+	t.Run("drops the stale histories of other keys", func(t *testing.T) {
+		store := memory.NewLoginAttemptMemory()
+		store.RecordFailure(ctx, "ip:192.0.2.1", now, time.Hour)
+		store.RecordFailure(ctx, "ip:192.0.2.2", now.Add(2*time.Hour), time.Hour)
+
+		attempts, _ := store.Get(ctx, "ip:192.0.2.1")
+		assert.Zero(t, attempts.Failures)
+	})
+
+	t.Run("reset", func(t *testing.T) {
+		store := memory.NewLoginAttemptMemory()
+		store.RecordFailure(ctx, "account:user@example.com", now, time.Hour)
+
+		assert.NoError(t, store.Reset(ctx, "account:user@example.com"))
+		attempts, _ := store.Get(ctx, "account:user@example.com")
+		assert.Zero(t, attempts.Failures)
+	})
+}
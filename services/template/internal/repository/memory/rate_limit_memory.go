@@ -0,0 +1,59 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/internal/ports/repositories"
+)
+
+type rateLimitWindow struct {
+	start time.Time
+	hits  int
+}
+
+type rateLimitEntry struct {
+	current  rateLimitWindow
+	previous rateLimitWindow
+}
+
+// rateLimitMemory is a process-local rate limit store, meant for single instance deployments
+// and tests. With several instances every one of them enforces the full quota.
+type rateLimitMemory struct {
+	mu      sync.Mutex
+	entries map[string]*rateLimitEntry
+}
+
+func NewRateLimitMemory() repositories.RateLimitStore {
+	return &rateLimitMemory{entries: make(map[string]*rateLimitEntry)}
+}
+
+func (m *rateLimitMemory) Hit(ctx context.Context, key string, windowStart time.Time, window time.Duration) (domain.RateLimitCounts, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.entries[key]
+	if !ok {
+		// Keys idle for two windows no longer affect any estimate, drop them to bound the map
+		for k, e := range m.entries {
+			if windowStart.Sub(e.current.start) > window {
+				delete(m.entries, k)
+			}
+		}
+		entry = &rateLimitEntry{current: rateLimitWindow{start: windowStart}}
+		m.entries[key] = entry
+	}
+
+	switch {
+	case entry.current.start.Equal(windowStart):
+	case entry.current.start.Add(window).Equal(windowStart):
+		entry.previous, entry.current = entry.current, rateLimitWindow{start: windowStart}
+	default:
+		entry.previous, entry.current = rateLimitWindow{}, rateLimitWindow{start: windowStart}
+	}
+
+	entry.current.hits++
+	return domain.RateLimitCounts{Current: entry.current.hits, Previous: entry.previous.hits}, nil
+}
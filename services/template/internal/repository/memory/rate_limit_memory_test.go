@@ -0,0 +1,37 @@
+package memory_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/internal/repository/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimitMemory(t *testing.T) {
+	ctx := context.Background()
+	window := time.Minute
+	start := time.Now().Truncate(window)
+
+	store := memory.NewRateLimitMemory()
+
+	counts, err := store.Hit(ctx, "api:user:1", start, window)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.RateLimitCounts{Current: 1}, counts)
+
+	counts, _ = store.Hit(ctx, "api:user:1", start, window)
+	assert.Equal(t, domain.RateLimitCounts{Current: 2}, counts)
+
+	other, _ := store.Hit(ctx, "api:user:2", start, window)
+	assert.Equal(t, domain.RateLimitCounts{Current: 1}, other)
+
+	// The next window sees the hits of this one as its previous window
+	counts, _ = store.Hit(ctx, "api:user:1", start.Add(window), window)
+	assert.Equal(t, domain.RateLimitCounts{Current: 1, Previous: 2}, counts)
+
+	// After an idle window both counts start over
+	counts, _ = store.Hit(ctx, "api:user:1", start.Add(3*window), window)
+	assert.Equal(t, domain.RateLimitCounts{Current: 1}, counts)
+}
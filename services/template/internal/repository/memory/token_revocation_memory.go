@@ -0,0 +1,86 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/internal/ports/repositories"
+)
+
+// tokenRevocationMemory is a process-local revocation store, meant for single instance
+// deployments and tests. Revocations are lost on restart.
+type tokenRevocationMemory struct {
+	mu          sync.RWMutex
+	tokens      map[string]time.Time // jti -> token expiry
+	sessions    map[string]time.Time // sid -> expiry of the last token of the session
+	userCutoffs map[string]time.Time // user_id -> tokens issued before are revoked
+}
+
+func NewTokenRevocationMemory() repositories.TokenRevocationStore {
+	return &tokenRevocationMemory{
+		tokens:      make(map[string]time.Time),
+		sessions:    make(map[string]time.Time),
+		userCutoffs: make(map[string]time.Time),
+	}
+}
+
+func (m *tokenRevocationMemory) RevokeToken(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.tokens[tokenID] = expiresAt
+	return nil
+}
+
+func (m *tokenRevocationMemory) RevokeSession(ctx context.Context, sessionID string, expiresAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.sessions[sessionID] = expiresAt
+	return nil
+}
+
+func (m *tokenRevocationMemory) RevokeUserTokens(ctx context.Context, userID string, before time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if current, ok := m.userCutoffs[userID]; !ok || before.After(current) {
+		m.userCutoffs[userID] = before
+	}
+	return nil
+}
+
+func (m *tokenRevocationMemory) IsRevoked(ctx context.Context, claims *domain.TokenClaims) (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if _, ok := m.tokens[claims.TokenID]; ok {
+		return true, nil
+	}
+	if _, ok := m.sessions[claims.SessionID]; ok && claims.SessionID != "" {
+		return true, nil
+	}
+	if cutoff, ok := m.userCutoffs[claims.UserID]; ok && !claims.IssuedAt.After(cutoff) {
+		return true, nil
+	}
+	return false, nil
+}
+
+// PruneExpired drops the entries whose tokens expired, the JWT validation rejects them anyway
+func (m *tokenRevocationMemory) PruneExpired(ctx context.Context, now time.Time) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var pruned int64
+	for _, entries := range []map[string]time.Time{m.tokens, m.sessions} {
+		for id, exp := range entries {
+			if exp.Before(now) {
+				delete(entries, id)
+				pruned++
+			}
+		}
+	}
+	return pruned, nil
+}
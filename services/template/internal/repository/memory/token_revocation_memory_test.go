@@ -0,0 +1,90 @@
+package memory_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/internal/repository/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenRevocationMemory(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+
+	t.Run("revoked jti", func(t *testing.T) {
+		store := memory.NewTokenRevocationMemory()
+		claims := &domain.TokenClaims{UserID: "user-id", TokenID: "jti-1", IssuedAt: now}
+
+		revoked, err := store.IsRevoked(ctx, claims)
+		assert.NoError(t, err)
+		assert.False(t, revoked)
+
+		assert.NoError(t, store.RevokeToken(ctx, "jti-1", now.Add(time.Hour)))
+
+		revoked, err = store.IsRevoked(ctx, claims)
+		assert.NoError(t, err)
+		assert.True(t, revoked)
+
+		other, _ := store.IsRevoked(ctx, &domain.TokenClaims{UserID: "user-id", TokenID: "jti-2", IssuedAt: now})
+		assert.False(t, other)
+	})
+
+	t.Run("revoked user tokens", func(t *testing.T) {
+		store := memory.NewTokenRevocationMemory()
+		assert.NoError(t, store.RevokeUserTokens(ctx, "user-id", now))
+
+		before, _ := store.IsRevoked(ctx, &domain.TokenClaims{UserID: "user-id", TokenID: "old", IssuedAt: now.Add(-time.Minute)})
+		assert.True(t, before)
+
+		sameSecond, _ := store.IsRevoked(ctx, &domain.TokenClaims{UserID: "user-id", TokenID: "same", IssuedAt: now})
+		assert.True(t, sameSecond)
+
+		after, _ := store.IsRevoked(ctx, &domain.TokenClaims{UserID: "user-id", TokenID: "new", IssuedAt: now.Add(time.Second)})
+		assert.False(t, after)
+
+		otherUser, _ := store.IsRevoked(ctx, &domain.TokenClaims{UserID: "other-id", TokenID: "old", IssuedAt: now.Add(-time.Minute)})
+		assert.False(t, otherUser)
+	})
+
+	t.Run("revoked session", func(t *testing.T) {
+		store := memory.NewTokenRevocationMemory()
+		assert.NoError(t, store.RevokeSession(ctx, "session-1", now.Add(time.Hour)))
+
+		revoked, _ := store.IsRevoked(ctx, &domain.TokenClaims{UserID: "user-id", TokenID: "jti-1", SessionID: "session-1", IssuedAt: now})
+		assert.True(t, revoked)
+
+		other, _ := store.IsRevoked(ctx, &domain.TokenClaims{UserID: "user-id", TokenID: "jti-2", SessionID: "session-2", IssuedAt: now})
+		assert.False(t, other)
+
+		noSession, _ := store.IsRevoked(ctx, &domain.TokenClaims{UserID: "user-id", TokenID: "jti-3", IssuedAt: now})
+		assert.False(t, noSession)
+	})
+
+	t.Run("prunes expired entries", func(t *testing.T) {
+		store := memory.NewTokenRevocationMemory()
+		assert.NoError(t, store.RevokeToken(ctx, "expired", now.Add(-time.Minute)))
+		assert.NoError(t, store.RevokeToken(ctx, "live", now.Add(time.Hour)))
+		assert.NoError(t, store.RevokeSession(ctx, "expired-session", now.Add(-time.Minute)))
+
+		pruned, err := store.PruneExpired(ctx, now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), pruned)
+		live, _ := store.IsRevoked(ctx, &domain.TokenClaims{UserID: "user-id", TokenID: "live", IssuedAt: now})
+		assert.True(t, live)
+		expired, _ := store.IsRevoked(ctx, &domain.TokenClaims{UserID: "user-id", TokenID: "expired", SessionID: "expired-session", IssuedAt: now})
+		assert.False(t, expired)
+	})
+
+	t.Run("older cutoff does not override newer one", func(t *testing.T) {
+		store := memory.NewTokenRevocationMemory()
+		assert.NoError(t, store.RevokeUserTokens(ctx, "user-id", now))
+		assert.NoError(t, store.RevokeUserTokens(ctx, "user-id", now.Add(-time.Hour)))
+
+		revoked, _ := store.IsRevoked(ctx, &domain.TokenClaims{UserID: "user-id", IssuedAt: now.Add(-time.Minute)})
+		assert.True(t, revoked)
+	})
+}
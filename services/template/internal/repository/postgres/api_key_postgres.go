@@ -0,0 +1,99 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/internal/ports/repositories"
+
+	"github.com/lib/pq"
+)
+
+type apiKeyPostgres struct {
+	db *sql.DB
+}
+
+func NewAPIKeyPostgres(db *sql.DB) repositories.APIKeyRepository {
+	return &apiKeyPostgres{db: db}
+}
+
+const apiKeyColumns = `id, user_id, name, prefix, key_hash, scopes, last_used_at, expires_at, revoked_at, created_at`
+
+func (r *apiKeyPostgres) Create(ctx context.Context, key *domain.APIKey) error {
+	query := `INSERT INTO api_keys (id, user_id, name, prefix, key_hash, scopes, expires_at, created_at)
+	          VALUES ($1, $2, $3, $4, $5, $6, $7, NOW())`
+	_, err := r.db.ExecContext(ctx, query,
+		key.ID, key.UserID, key.Name, key.Prefix, key.KeyHash, pq.Array(key.Scopes), key.ExpiresAt,
+	)
+	return err
+}
+
+func (r *apiKeyPostgres) FindByHash(ctx context.Context, keyHash string) (*domain.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE key_hash=$1`
+	return scanAPIKey(r.db.QueryRowContext(ctx, query, keyHash))
+}
+
+func (r *apiKeyPostgres) ListByUserID(ctx context.Context, userID string) ([]domain.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE user_id=$1 ORDER BY created_at DESC`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []domain.APIKey
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, *key)
+	}
+	return keys, rows.Err()
+}
+
+func (r *apiKeyPostgres) Revoke(ctx context.Context, userID, keyID string) error {
+	query := `UPDATE api_keys SET revoked_at=NOW() WHERE id=$1 AND user_id=$2 AND revoked_at IS NULL`
+	res, err := r.db.ExecContext(ctx, query, keyID, userID)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return domain.ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+// TouchLastUsed only writes once a minute per key, busy integrations would otherwise
+// turn every request into an UPDATE
+func (r *apiKeyPostgres) TouchLastUsed(ctx context.Context, keyID string, at time.Time) error {
+	query := `UPDATE api_keys SET last_used_at=$2 WHERE id=$1 AND (last_used_at IS NULL OR last_used_at < $3)`
+	_, err := r.db.ExecContext(ctx, query, keyID, at, at.Add(-time.Minute))
+	return err
+}
+
+func scanAPIKey(row interface{ Scan(dest ...any) error }) (*domain.APIKey, error) {
+	var key domain.APIKey
+	err := row.Scan(
+		&key.ID,
+		&key.UserID,
+		&key.Name,
+		&key.Prefix,
+		&key.KeyHash,
+		pq.Array(&key.Scopes),
+		&key.LastUsedAt,
+		&key.ExpiresAt,
+		&key.RevokedAt,
+		&key.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
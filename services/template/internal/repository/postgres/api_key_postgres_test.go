@@ -0,0 +1,154 @@
+package postgres_test
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/internal/repository/postgres"
+	"github.com/stretchr/testify/assert"
+)
+
+var apiKeyRowColumns = []string{"id", "user_id", "name", "prefix", "key_hash", "scopes", "last_used_at", "expires_at", "revoked_at", "created_at"}
+
+func TestAPIKeyPostgres_Create(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	key := &domain.APIKey{ID: "key-id", UserID: "user-id", Name: "ci", Prefix: "cwk_abcdefgh", KeyHash: "hash", Scopes: []string{"users:read"}}
+	mock.ExpectExec(regexp.QuoteMeta(`
+		INSERT INTO api_keys (id, user_id, name, prefix, key_hash, scopes, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW())
+	`)).
+		WithArgs("key-id", "user-id", "ci", "cwk_abcdefgh", "hash", "{\"users:read\"}", nil).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	repo := postgres.NewAPIKeyPostgres(db)
+	assert.NoError(t, repo.Create(context.Background(), key))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAPIKeyPostgres_FindByHash(t *testing.T) {
+	now := time.Now()
+	query := `SELECT id, user_id, name, prefix, key_hash, scopes, last_used_at, expires_at, revoked_at, created_at FROM api_keys WHERE key_hash=$1`
+
+	tests := []struct {
+		name      string
+		setupMock func(sqlmock.Sqlmock)
+		wantErr   error
+	}{
+		{
+			name: "found",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs("hash").
+					WillReturnRows(sqlmock.NewRows(apiKeyRowColumns).
+						AddRow("key-id", "user-id", "ci", "cwk_abcdefgh", "hash", "{users:read,users:write}", now, nil, nil, now))
+			},
+		},
+		{
+			name: "not found",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs("hash").
+					WillReturnError(sql.ErrNoRows)
+			},
+			wantErr: sql.ErrNoRows,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+			tt.setupMock(mock)
+
+			repo := postgres.NewAPIKeyPostgres(db)
+			key, err := repo.FindByHash(context.Background(), "hash")
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "key-id", key.ID)
+				assert.Equal(t, []string{"users:read", "users:write"}, key.Scopes)
+				assert.NotNil(t, key.LastUsedAt)
+				assert.Nil(t, key.RevokedAt)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestAPIKeyPostgres_ListByUserID(t *testing.T) {
+	now := time.Now()
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, user_id, name, prefix, key_hash, scopes, last_used_at, expires_at, revoked_at, created_at FROM api_keys WHERE user_id=$1 ORDER BY created_at DESC`)).
+		WithArgs("user-id").
+		WillReturnRows(sqlmock.NewRows(apiKeyRowColumns).
+			AddRow("key-2", "user-id", "deploy", "cwk_zyxwvuts", "hash-2", "{}", nil, now, now, now).
+			AddRow("key-1", "user-id", "ci", "cwk_abcdefgh", "hash-1", "{users:read}", now, nil, nil, now))
+
+	repo := postgres.NewAPIKeyPostgres(db)
+	keys, err := repo.ListByUserID(context.Background(), "user-id")
+
+	assert.NoError(t, err)
+	if assert.Len(t, keys, 2) {
+		assert.Equal(t, "key-2", keys[0].ID)
+		assert.NotNil(t, keys[0].RevokedAt)
+		assert.Equal(t, []string{"users:read"}, keys[1].Scopes)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAPIKeyPostgres_Revoke(t *testing.T) {
+	tests := []struct {
+		name     string
+		affected int64
+		wantErr  error
+	}{
+		{"revoked", 1, nil},
+		{"unknown or already revoked", 0, domain.ErrAPIKeyNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+
+			mock.ExpectExec(regexp.QuoteMeta(`UPDATE api_keys SET revoked_at=NOW() WHERE id=$1 AND user_id=$2 AND revoked_at IS NULL`)).
+				WithArgs("key-id", "user-id").
+				WillReturnResult(sqlmock.NewResult(0, tt.affected))
+
+			repo := postgres.NewAPIKeyPostgres(db)
+			err := repo.Revoke(context.Background(), "user-id", "key-id")
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestAPIKeyPostgres_TouchLastUsed(t *testing.T) {
+	at := time.Now()
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE api_keys SET last_used_at=$2 WHERE id=$1 AND (last_used_at IS NULL OR last_used_at < $3)`)).
+		WithArgs("key-id", at, at.Add(-time.Minute)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := postgres.NewAPIKeyPostgres(db)
+	assert.NoError(t, repo.TouchLastUsed(context.Background(), "key-id", at))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,137 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/internal/ports/repositories"
+)
+
+// catColumns is the column list scanned by scanCat, optional text columns are coalesced
+const catColumns = `id, user_id, name, COALESCE(breed, ''), birth_date, COALESCE(gender, ''), weight_kg, COALESCE(picture_url, ''), is_neutered, created_at, updated_at`
+
+type catPostgres struct {
+	db *sql.DB
+}
+
+func NewCatPostgres(db *sql.DB) repositories.CatRepository {
+	return &catPostgres{db: db}
+}
+
+// Create locks the owner row, which plan changes update too, so the plan and the count still
+// hold when the cat is inserted. The plan is resolved as EntitlementUseCase.PlanOf does: plan_id,
+// else the plan_type code, else free.
+func (r *catPostgres) Create(ctx context.Context, cat *domain.Cat) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `SELECT COALESCE(p.code, ''), p.max_cats FROM users u
+	          LEFT JOIN plans p ON p.id = COALESCE(u.plan_id, (SELECT id FROM plans WHERE code = COALESCE(NULLIF(u.plan_type, ''), 'free')))
+	          WHERE u.id=$1 AND u.deleted_at IS NULL
+	          FOR UPDATE OF u`
+	var planCode string
+	var maxCats sql.NullInt64
+	if err := tx.QueryRowContext(ctx, query, cat.UserID).Scan(&planCode, &maxCats); err != nil {
+		return err
+	}
+	if !maxCats.Valid {
+		return domain.ErrPlanNotFound
+	}
+
+	var count int
+	if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM cats WHERE user_id=$1`, cat.UserID).Scan(&count); err != nil {
+		return err
+	}
+	if count >= int(maxCats.Int64) {
+		return &domain.EntitlementError{Reason: domain.EntitlementCatLimitReached, Plan: planCode, Limit: int(maxCats.Int64)}
+	}
+
+	query = `INSERT INTO cats (id, user_id, name, breed, birth_date, gender, weight_kg, picture_url, is_neutered, created_at, updated_at)
+	          VALUES ($1, $2, $3, NULLIF($4, ''), $5, NULLIF($6, ''), $7, NULLIF($8, ''), $9, NOW(), NOW())
+	          RETURNING created_at, updated_at`
+	err = tx.QueryRowContext(ctx, query,
+		cat.ID, cat.UserID, cat.Name, cat.Breed, cat.BirthDate, cat.Gender, cat.WeightKg, cat.PictureURL, cat.IsNeutered,
+	).Scan(&cat.CreatedAt, &cat.UpdatedAt)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *catPostgres) FindByID(ctx context.Context, userID, id string) (*domain.Cat, error) {
+	query := `SELECT ` + catColumns + ` FROM cats WHERE id=$1 AND user_id=$2`
+	return scanCat(r.db.QueryRowContext(ctx, query, id, userID))
+}
+
+func (r *catPostgres) ListByUserID(ctx context.Context, userID string) ([]domain.Cat, error) {
+	query := `SELECT ` + catColumns + ` FROM cats WHERE user_id=$1 ORDER BY created_at, id`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var cats []domain.Cat
+	for rows.Next() {
+		cat, err := scanCat(rows)
+		if err != nil {
+			return nil, err
+		}
+		cats = append(cats, *cat)
+	}
+	return cats, rows.Err()
+}
+
+func (r *catPostgres) Update(ctx context.Context, cat *domain.Cat) error {
+	query := `UPDATE cats SET name=$1, breed=NULLIF($2, ''), birth_date=$3, gender=NULLIF($4, ''), weight_kg=$5, picture_url=NULLIF($6, ''), is_neutered=$7, updated_at=NOW()
+	          WHERE id=$8 AND user_id=$9
+	          RETURNING created_at, updated_at`
+	err := r.db.QueryRowContext(ctx, query,
+		cat.Name, cat.Breed, cat.BirthDate, cat.Gender, cat.WeightKg, cat.PictureURL, cat.IsNeutered, cat.ID, cat.UserID,
+	).Scan(&cat.CreatedAt, &cat.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrCatNotFound
+	}
+	return err
+}
+
+func (r *catPostgres) Delete(ctx context.Context, userID, id string) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM cats WHERE id=$1 AND user_id=$2`, id, userID)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return domain.ErrCatNotFound
+	}
+	return nil
+}
+
+func scanCat(row interface{ Scan(dest ...any) error }) (*domain.Cat, error) {
+	var cat domain.Cat
+	err := row.Scan(
+		&cat.ID,
+		&cat.UserID,
+		&cat.Name,
+		&cat.Breed,
+		&cat.BirthDate,
+		&cat.Gender,
+		&cat.WeightKg,
+		&cat.PictureURL,
+		&cat.IsNeutered,
+		&cat.CreatedAt,
+		&cat.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &cat, nil
+}
@@ -0,0 +1,260 @@
+package postgres_test
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/internal/repository/postgres"
+	"github.com/stretchr/testify/assert"
+)
+
+var catRowColumns = []string{"id", "user_id", "name", "breed", "birth_date", "gender", "weight_kg", "picture_url", "is_neutered", "created_at", "updated_at"}
+
+const catSelect = `SELECT id, user_id, name, COALESCE(breed, ''), birth_date, COALESCE(gender, ''), weight_kg, COALESCE(picture_url, ''), is_neutered, created_at, updated_at FROM cats`
+
+func TestCatPostgres_Create(t *testing.T) {
+	now := time.Now()
+	birthDate := time.Date(2021, 4, 10, 0, 0, 0, 0, time.UTC)
+	weight := 4.5
+
+	const lockQuery = `
+		SELECT COALESCE(p.code, ''), p.max_cats FROM users u
+		LEFT JOIN plans p ON p.id = COALESCE(u.plan_id, (SELECT id FROM plans WHERE code = COALESCE(NULLIF(u.plan_type, ''), 'free')))
+		WHERE u.id=$1 AND u.deleted_at IS NULL
+		FOR UPDATE OF u
+	`
+	expectCount := func(mock sqlmock.Sqlmock, count int) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(lockQuery)).
+			WithArgs("user-id").
+			WillReturnRows(sqlmock.NewRows([]string{"code", "max_cats"}).AddRow("premium", 3))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM cats WHERE user_id=$1`)).
+			WithArgs("user-id").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(count))
+	}
+
+	tests := []struct {
+		name      string
+		setupMock func(sqlmock.Sqlmock)
+		wantErr   error
+		wantLimit *domain.EntitlementError
+	}{
+		{
+			name: "free slot",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				expectCount(mock, 2)
+				mock.ExpectQuery(regexp.QuoteMeta(`
+					INSERT INTO cats (id, user_id, name, breed, birth_date, gender, weight_kg, picture_url, is_neutered, created_at, updated_at)
+					VALUES ($1, $2, $3, NULLIF($4, ''), $5, NULLIF($6, ''), $7, NULLIF($8, ''), $9, NOW(), NOW())
+					RETURNING created_at, updated_at
+				`)).
+					WithArgs("cat-id", "user-id", "Mingau", "", &birthDate, "female", &weight, "", true).
+					WillReturnRows(sqlmock.NewRows([]string{"created_at", "updated_at"}).AddRow(now, now))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "limit reached",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				expectCount(mock, 3)
+				mock.ExpectRollback()
+			},
+			wantLimit: &domain.EntitlementError{Reason: domain.EntitlementCatLimitReached, Plan: "premium", Limit: 3},
+		},
+		{
+			name: "unknown user",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(lockQuery)).
+					WithArgs("user-id").
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+			},
+			wantErr: sql.ErrNoRows,
+		},
+		{
+			name: "plan missing",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(lockQuery)).
+					WithArgs("user-id").
+					WillReturnRows(sqlmock.NewRows([]string{"code", "max_cats"}).AddRow("", nil))
+				mock.ExpectRollback()
+			},
+			wantErr: domain.ErrPlanNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+			tt.setupMock(mock)
+
+			cat := &domain.Cat{ID: "cat-id", UserID: "user-id", Name: "Mingau", BirthDate: &birthDate, Gender: domain.CatGenderFemale, WeightKg: &weight, IsNeutered: true}
+			repo := postgres.NewCatPostgres(db)
+			err := repo.Create(context.Background(), cat)
+
+			switch {
+			case tt.wantLimit != nil:
+				var limitErr *domain.EntitlementError
+				assert.ErrorAs(t, err, &limitErr)
+				assert.Equal(t, tt.wantLimit, limitErr)
+			case tt.wantErr != nil:
+				assert.ErrorIs(t, err, tt.wantErr)
+			default:
+				assert.NoError(t, err)
+				assert.Equal(t, now, cat.CreatedAt)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestCatPostgres_FindByID(t *testing.T) {
+	now := time.Now()
+	birthDate := time.Date(2021, 4, 10, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		setupMock func(sqlmock.Sqlmock)
+		wantErr   error
+	}{
+		{
+			name: "found",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(catSelect+` WHERE id=$1 AND user_id=$2`)).
+					WithArgs("cat-id", "user-id").
+					WillReturnRows(sqlmock.NewRows(catRowColumns).
+						AddRow("cat-id", "user-id", "Mingau", "Siamês", birthDate, "female", []byte("4.50"), "", true, now, now))
+			},
+		},
+		{
+			name: "not found or owned by another user",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(catSelect+` WHERE id=$1 AND user_id=$2`)).
+					WithArgs("cat-id", "user-id").
+					WillReturnError(sql.ErrNoRows)
+			},
+			wantErr: sql.ErrNoRows,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+			tt.setupMock(mock)
+
+			repo := postgres.NewCatPostgres(db)
+			cat, err := repo.FindByID(context.Background(), "user-id", "cat-id")
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "Mingau", cat.Name)
+				assert.Equal(t, "Siamês", cat.Breed)
+				assert.Equal(t, birthDate, *cat.BirthDate)
+				assert.Equal(t, 4.5, *cat.WeightKg)
+				assert.True(t, cat.IsNeutered)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestCatPostgres_ListByUserID(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	now := time.Now()
+	mock.ExpectQuery(regexp.QuoteMeta(catSelect + ` WHERE user_id=$1 ORDER BY created_at, id`)).
+		WithArgs("user-id").
+		WillReturnRows(sqlmock.NewRows(catRowColumns).
+			AddRow("cat-1", "user-id", "Mingau", "", nil, "", nil, "", false, now, now).
+			AddRow("cat-2", "user-id", "Frajola", "", nil, "male", nil, "", true, now, now))
+
+	repo := postgres.NewCatPostgres(db)
+	cats, err := repo.ListByUserID(context.Background(), "user-id")
+
+	assert.NoError(t, err)
+	assert.Len(t, cats, 2)
+	assert.Nil(t, cats[0].BirthDate)
+	assert.Nil(t, cats[0].WeightKg)
+	assert.Equal(t, "Frajola", cats[1].Name)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCatPostgres_Update(t *testing.T) {
+	query := `UPDATE cats SET name=$1, breed=NULLIF($2, ''), birth_date=$3, gender=NULLIF($4, ''), weight_kg=$5, picture_url=NULLIF($6, ''), is_neutered=$7, updated_at=NOW()
+		WHERE id=$8 AND user_id=$9
+		RETURNING created_at, updated_at`
+
+	tests := []struct {
+		name    string
+		rows    *sqlmock.Rows
+		wantErr error
+	}{
+		{name: "updated", rows: sqlmock.NewRows([]string{"created_at", "updated_at"}).AddRow(time.Now(), time.Now())},
+		{name: "not found or owned by another user", rows: sqlmock.NewRows([]string{"created_at", "updated_at"}), wantErr: domain.ErrCatNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+
+			mock.ExpectQuery(regexp.QuoteMeta(query)).
+				WithArgs("Mingau", "Persa", nil, "", nil, "", false, "cat-id", "user-id").
+				WillReturnRows(tt.rows)
+
+			repo := postgres.NewCatPostgres(db)
+			err := repo.Update(context.Background(), &domain.Cat{ID: "cat-id", UserID: "user-id", Name: "Mingau", Breed: "Persa"})
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestCatPostgres_Delete(t *testing.T) {
+	tests := []struct {
+		name     string
+		affected int64
+		wantErr  error
+	}{
+		{name: "deleted", affected: 1},
+		{name: "not found or owned by another user", affected: 0, wantErr: domain.ErrCatNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+
+			mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM cats WHERE id=$1 AND user_id=$2`)).
+				WithArgs("cat-id", "user-id").
+				WillReturnResult(sqlmock.NewResult(0, tt.affected))
+
+			repo := postgres.NewCatPostgres(db)
+			err := repo.Delete(context.Background(), "user-id", "cat-id")
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
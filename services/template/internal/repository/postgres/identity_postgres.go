@@ -0,0 +1,93 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/internal/ports/repositories"
+)
+
+type identityPostgres struct {
+	db *sql.DB
+}
+
+func NewIdentityPostgres(db *sql.DB) repositories.IdentityRepository {
+	return &identityPostgres{db: db}
+}
+
+func (r *identityPostgres) FindByProviderSubject(ctx context.Context, provider, subject string) (*domain.Identity, error) {
+	query := `SELECT id, user_id, provider, subject, COALESCE(email, ''), created_at FROM user_identities WHERE provider=$1 AND subject=$2`
+	row := r.db.QueryRowContext(ctx, query, provider, subject)
+
+	var identity domain.Identity
+	err := row.Scan(
+		&identity.ID,
+		&identity.UserID,
+		&identity.Provider,
+		&identity.Subject,
+		&identity.Email,
+		&identity.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
+
+func (r *identityPostgres) ListByUserID(ctx context.Context, userID string) ([]domain.Identity, error) {
+	query := `SELECT id, user_id, provider, subject, COALESCE(email, ''), created_at FROM user_identities WHERE user_id=$1 ORDER BY created_at`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var identities []domain.Identity
+	for rows.Next() {
+		var identity domain.Identity
+		if err := rows.Scan(
+			&identity.ID,
+			&identity.UserID,
+			&identity.Provider,
+			&identity.Subject,
+			&identity.Email,
+			&identity.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		identities = append(identities, identity)
+	}
+
+	return identities, rows.Err()
+}
+
+func (r *identityPostgres) Create(ctx context.Context, identity *domain.Identity) error {
+	query := `INSERT INTO user_identities (id, user_id, provider, subject, email, created_at) VALUES ($1, $2, $3, $4, NULLIF($5, ''), NOW())`
+	_, err := r.db.ExecContext(ctx, query,
+		identity.ID, identity.UserID, identity.Provider, identity.Subject, identity.Email,
+	)
+	return err
+}
+
+func (r *identityPostgres) CreateWithUser(ctx context.Context, user *domain.User, identity *domain.Identity) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := insertUser(ctx, tx, user); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO user_identities (id, user_id, provider, subject, email, created_at) VALUES ($1, $2, $3, $4, NULLIF($5, ''), NOW())`,
+		identity.ID, identity.UserID, identity.Provider, identity.Subject, identity.Email,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,154 @@
+package postgres_test
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/internal/repository/postgres"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdentityPostgres_FindByProviderSubject(t *testing.T) {
+	tests := []struct {
+		name      string
+		setupMock func(sqlmock.Sqlmock)
+		wantErr   bool
+	}{
+		{
+			name: "found",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(`
+					SELECT id, user_id, provider, subject, COALESCE(email, ''), created_at FROM user_identities WHERE provider=$1 AND subject=$2
+				`)).
+					WithArgs("keycloak", "subject-1").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "provider", "subject", "email", "created_at"}).
+						AddRow("identity-id", "user-id", "keycloak", "subject-1", "user@example.com", time.Now()))
+			},
+		},
+		{
+			name: "not found",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, user_id, provider`)).
+					WithArgs("keycloak", "subject-1").
+					WillReturnError(sql.ErrNoRows)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+
+			tt.setupMock(mock)
+
+			repo := postgres.NewIdentityPostgres(db)
+			identity, err := repo.FindByProviderSubject(context.Background(), "keycloak", "subject-1")
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "user-id", identity.UserID)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestIdentityPostgres_ListByUserID(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	now := time.Now()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, user_id, provider, subject, COALESCE(email, ''), created_at FROM user_identities WHERE user_id=$1 ORDER BY created_at`)).
+		WithArgs("user-id").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "provider", "subject", "email", "created_at"}).
+			AddRow("identity-1", "user-id", "firebase", "firebase-uid", "user@example.com", now).
+			AddRow("identity-2", "user-id", "keycloak", "subject-1", "", now))
+
+	repo := postgres.NewIdentityPostgres(db)
+	identities, err := repo.ListByUserID(context.Background(), "user-id")
+	assert.NoError(t, err)
+	if assert.Len(t, identities, 2) {
+		assert.Equal(t, "firebase", identities[0].Provider)
+		assert.Equal(t, "keycloak", identities[1].Provider)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIdentityPostgres_Create(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO user_identities (id, user_id, provider, subject, email, created_at) VALUES ($1, $2, $3, $4, NULLIF($5, ''), NOW())`)).
+		WithArgs("identity-id", "user-id", "keycloak", "subject-1", "user@example.com").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	repo := postgres.NewIdentityPostgres(db)
+	err := repo.Create(context.Background(), &domain.Identity{
+		ID: "identity-id", UserID: "user-id", Provider: "keycloak", Subject: "subject-1", Email: "user@example.com",
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIdentityPostgres_CreateWithUser(t *testing.T) {
+	user := &domain.User{ID: "user-id", Email: "user@example.com", Name: "Test User"}
+	identity := &domain.Identity{ID: "identity-id", UserID: "user-id", Provider: "keycloak", Subject: "subject-1", Email: "user@example.com"}
+
+	tests := []struct {
+		name      string
+		setupMock func(sqlmock.Sqlmock)
+		wantErr   bool
+	}{
+		{
+			name: "success",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO users`)).
+					WithArgs("user-id", "", "user@example.com", "Test User", "", "", nil, nil, "", false, "").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO user_identities (id, user_id, provider, subject, email, created_at) VALUES ($1, $2, $3, $4, NULLIF($5, ''), NOW())`)).
+					WithArgs("identity-id", "user-id", "keycloak", "subject-1", "user@example.com").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "identity insert fails rolls back the user",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO users`)).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO user_identities`)).
+					WillReturnError(sql.ErrConnDone)
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+
+			tt.setupMock(mock)
+
+			repo := postgres.NewIdentityPostgres(db)
+			err := repo.CreateWithUser(context.Background(), user, identity)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/internal/ports/repositories"
+)
+
+type loginAttemptPostgres struct {
+	db *sql.DB
+}
+
+func NewLoginAttemptPostgres(db *sql.DB) repositories.LoginAttemptStore {
+	return &loginAttemptPostgres{db: db}
+}
+
+func (r *loginAttemptPostgres) Get(ctx context.Context, key string) (domain.LoginAttempts, error) {
+	query := `SELECT failures, last_failure_at FROM login_attempts WHERE key=$1`
+	var attempts domain.LoginAttempts
+	err := r.db.QueryRowContext(ctx, query, key).Scan(&attempts.Failures, &attempts.LastFailureAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.LoginAttempts{}, nil
+	}
+	return attempts, err
+}
+
+func (r *loginAttemptPostgres) RecordFailure(ctx context.Context, key string, at time.Time, resetAfter time.Duration) (domain.LoginAttempts, error) {
+	query := `INSERT INTO login_attempts (key, failures, last_failure_at) VALUES ($1, 1, $2)
+	          ON CONFLICT (key) DO UPDATE SET
+	            failures = CASE WHEN login_attempts.last_failure_at < $3 THEN 1 ELSE login_attempts.failures + 1 END,
+	            last_failure_at = EXCLUDED.last_failure_at
+	          RETURNING failures, last_failure_at`
+	var attempts domain.LoginAttempts
+	err := r.db.QueryRowContext(ctx, query, key, at, at.Add(-resetAfter)).Scan(&attempts.Failures, &attempts.LastFailureAt)
+	return attempts, err
+}
+
+func (r *loginAttemptPostgres) Reset(ctx context.Context, key string) error {
+	query := `DELETE FROM login_attempts WHERE key=$1`
+	_, err := r.db.ExecContext(ctx, query, key)
+	return err
+}
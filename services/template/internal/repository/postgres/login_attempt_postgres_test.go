@@ -0,0 +1,108 @@
+package postgres_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/nuhorizon/go-project-template/services/template/internal/repository/postgres"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoginAttemptPostgres_Get(t *testing.T) {
+	lastFailure := time.Now()
+
+	tests := []struct {
+		name         string
+		setupMock    func(sqlmock.Sqlmock)
+		wantFailures int
+		wantErr      bool
+	}{
+		{
+			name: "found",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT failures, last_failure_at FROM login_attempts WHERE key=$1`)).
+					WithArgs("ip:192.0.2.1").
+					WillReturnRows(sqlmock.NewRows([]string{"failures", "last_failure_at"}).AddRow(3, lastFailure))
+			},
+			wantFailures: 3,
+		},
+		{
+			name: "no failures",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT failures, last_failure_at FROM login_attempts WHERE key=$1`)).
+					WithArgs("ip:192.0.2.1").
+					WillReturnError(sql.ErrNoRows)
+			},
+		},
+		{
+			name: "db error",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT failures, last_failure_at FROM login_attempts WHERE key=$1`)).
+					WithArgs("ip:192.0.2.1").
+					WillReturnError(errors.New("db error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+			tt.setupMock(mock)
+
+			store := postgres.NewLoginAttemptPostgres(db)
+			attempts, err := store.Get(context.Background(), "ip:192.0.2.1")
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantFailures, attempts.Failures)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestLoginAttemptPostgres_RecordFailure(t *testing.T) {
+	at := time.Now()
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`
+		INSERT INTO login_attempts (key, failures, last_failure_at) VALUES ($1, 1, $2)
+		ON CONFLICT (key) DO UPDATE SET
+		  failures = CASE WHEN login_attempts.last_failure_at < $3 THEN 1 ELSE login_attempts.failures + 1 END,
+		  last_failure_at = EXCLUDED.last_failure_at
+		RETURNING failures, last_failure_at
+	`)).
+		WithArgs("account:user@example.com", at, at.Add(-time.Hour)).
+		WillReturnRows(sqlmock.NewRows([]string{"failures", "last_failure_at"}).AddRow(4, at))
+
+	store := postgres.NewLoginAttemptPostgres(db)
+	attempts, err := store.RecordFailure(context.Background(), "account:user@example.com", at, time.Hour)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 4, attempts.Failures)
+	assert.Equal(t, at, attempts.LastFailureAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLoginAttemptPostgres_Reset(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM login_attempts WHERE key=$1`)).
+		WithArgs("account:user@example.com").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	store := postgres.NewLoginAttemptPostgres(db)
+	assert.NoError(t, store.Reset(context.Background(), "account:user@example.com"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,51 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/internal/ports/repositories"
+)
+
+type magicLinkPostgres struct {
+	db *sql.DB
+}
+
+func NewMagicLinkPostgres(db *sql.DB) repositories.MagicLinkRepository {
+	return &magicLinkPostgres{db: db}
+}
+
+func (r *magicLinkPostgres) Create(ctx context.Context, link *domain.MagicLink) error {
+	query := `INSERT INTO magic_links (id, email, expires_at, created_at) VALUES ($1, $2, $3, NOW())`
+	_, err := r.db.ExecContext(ctx, query, link.ID, link.Email, link.ExpiresAt)
+	return err
+}
+
+// Consume is a single conditional update, so two requests racing with the same link
+// can't both log in
+func (r *magicLinkPostgres) Consume(ctx context.Context, id string, at time.Time) (*domain.MagicLink, error) {
+	query := `UPDATE magic_links SET used_at=$2 WHERE id=$1 AND used_at IS NULL AND expires_at > $2
+	          RETURNING id, email, expires_at, used_at, created_at`
+	var link domain.MagicLink
+	err := r.db.QueryRowContext(ctx, query, id, at).Scan(
+		&link.ID,
+		&link.Email,
+		&link.ExpiresAt,
+		&link.UsedAt,
+		&link.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+func (r *magicLinkPostgres) PruneExpired(ctx context.Context, now time.Time) (int64, error) {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM magic_links WHERE expires_at < $1`, now)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
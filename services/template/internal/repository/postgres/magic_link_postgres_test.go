@@ -0,0 +1,116 @@
+package postgres_test
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/internal/repository/postgres"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMagicLinkPostgres_Create(t *testing.T) {
+	expiresAt := time.Now().Add(domain.MagicLinkTTL)
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO magic_links (id, email, expires_at, created_at) VALUES ($1, $2, $3, NOW())`)).
+		WithArgs("link-id", "user@example.com", expiresAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	repo := postgres.NewMagicLinkPostgres(db)
+	err := repo.Create(context.Background(), &domain.MagicLink{ID: "link-id", Email: "user@example.com", ExpiresAt: expiresAt})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMagicLinkPostgres_Consume(t *testing.T) {
+	now := time.Now()
+	query := `
+		UPDATE magic_links SET used_at=$2 WHERE id=$1 AND used_at IS NULL AND expires_at > $2
+		RETURNING id, email, expires_at, used_at, created_at
+	`
+
+	tests := []struct {
+		name      string
+		setupMock func(sqlmock.Sqlmock)
+		wantErr   error
+	}{
+		{
+			name: "consumed",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs("link-id", now).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "email", "expires_at", "used_at", "created_at"}).
+						AddRow("link-id", "user@example.com", now.Add(time.Minute), now, now.Add(-time.Minute)))
+			},
+		},
+		{
+			name: "unknown, used or expired",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs("link-id", now).
+					WillReturnError(sql.ErrNoRows)
+			},
+			wantErr: sql.ErrNoRows,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+			tt.setupMock(mock)
+
+			repo := postgres.NewMagicLinkPostgres(db)
+			link, err := repo.Consume(context.Background(), "link-id", now)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, link)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "user@example.com", link.Email)
+				assert.NotNil(t, link.UsedAt)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestMagicLinkPostgres_PruneExpired(t *testing.T) {
+	now := time.Now()
+
+	t.Run("deletes the expired links", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+
+		mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM magic_links WHERE expires_at < $1`)).
+			WithArgs(now).
+			WillReturnResult(sqlmock.NewResult(0, 4))
+
+		repo := postgres.NewMagicLinkPostgres(db)
+		pruned, err := repo.PruneExpired(context.Background(), now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(4), pruned)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("db error", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+
+		mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM magic_links`)).WillReturnError(sql.ErrConnDone)
+
+		repo := postgres.NewMagicLinkPostgres(db)
+		_, err := repo.PruneExpired(context.Background(), now)
+
+		assert.ErrorIs(t, err, sql.ErrConnDone)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,135 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/internal/ports/repositories"
+)
+
+type mfaPostgres struct {
+	db *sql.DB
+}
+
+func NewMFAPostgres(db *sql.DB) repositories.MFARepository {
+	return &mfaPostgres{db: db}
+}
+
+func (r *mfaPostgres) FindByUserID(ctx context.Context, userID string) (*domain.MFAEnrollment, error) {
+	query := `SELECT user_id, secret, last_used_step, enabled_at, created_at FROM user_mfa WHERE user_id=$1`
+	row := r.db.QueryRowContext(ctx, query, userID)
+
+	var enrollment domain.MFAEnrollment
+	err := row.Scan(
+		&enrollment.UserID,
+		&enrollment.Secret,
+		&enrollment.LastUsedStep,
+		&enrollment.EnabledAt,
+		&enrollment.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &enrollment, nil
+}
+
+func (r *mfaPostgres) SavePending(ctx context.Context, userID, secret string) error {
+	// An enabled enrolment is never overwritten, it has to be disabled first
+	query := `INSERT INTO user_mfa (user_id, secret, last_used_step, created_at) VALUES ($1, $2, 0, NOW())
+	          ON CONFLICT (user_id) DO UPDATE SET secret=EXCLUDED.secret, last_used_step=0, created_at=NOW()
+	          WHERE user_mfa.enabled_at IS NULL`
+	res, err := r.db.ExecContext(ctx, query, userID, secret)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return domain.ErrMFAAlreadyEnabled
+	}
+	return nil
+}
+
+func (r *mfaPostgres) Enable(ctx context.Context, userID string, step int64, recoveryCodeHashes []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx,
+		`UPDATE user_mfa SET enabled_at=NOW(), last_used_step=$2 WHERE user_id=$1 AND enabled_at IS NULL`,
+		userID, step,
+	)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return domain.ErrMFAAlreadyEnabled
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM mfa_recovery_codes WHERE user_id=$1`, userID); err != nil {
+		return err
+	}
+	for _, hash := range recoveryCodeHashes {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO mfa_recovery_codes (user_id, code_hash, created_at) VALUES ($1, $2, NOW())`,
+			userID, hash,
+		); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *mfaPostgres) Delete(ctx context.Context, userID string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM mfa_recovery_codes WHERE user_id=$1`, userID); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM user_mfa WHERE user_id=$1`, userID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *mfaPostgres) RecordStep(ctx context.Context, userID string, step int64) error {
+	// Accepting only steps newer than the last one used makes each code single-use
+	query := `UPDATE user_mfa SET last_used_step=$2 WHERE user_id=$1 AND last_used_step < $2`
+	return r.execExpectingRow(ctx, query, userID, step)
+}
+
+func (r *mfaPostgres) UseRecoveryCode(ctx context.Context, userID, codeHash string) error {
+	query := `UPDATE mfa_recovery_codes SET used_at=NOW() WHERE user_id=$1 AND code_hash=$2 AND used_at IS NULL`
+	return r.execExpectingRow(ctx, query, userID, codeHash)
+}
+
+// execExpectingRow runs a conditional update, reporting ErrInvalidMFACode when no row matched
+func (r *mfaPostgres) execExpectingRow(ctx context.Context, query string, args ...any) error {
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return domain.ErrInvalidMFACode
+	}
+	return nil
+}
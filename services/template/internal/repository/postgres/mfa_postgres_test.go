@@ -0,0 +1,213 @@
+package postgres_test
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/internal/repository/postgres"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMFAPostgres_FindByUserID(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	now := time.Now()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT user_id, secret, last_used_step, enabled_at, created_at FROM user_mfa WHERE user_id=$1`)).
+		WithArgs("user-id").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "secret", "last_used_step", "enabled_at", "created_at"}).
+			AddRow("user-id", "SECRET", 42, now, now))
+
+	repo := postgres.NewMFAPostgres(db)
+	enrollment, err := repo.FindByUserID(context.Background(), "user-id")
+	assert.NoError(t, err)
+	assert.True(t, enrollment.Enabled())
+	assert.Equal(t, int64(42), enrollment.LastUsedStep)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMFAPostgres_SavePending(t *testing.T) {
+	tests := []struct {
+		name     string
+		affected int64
+		wantErr  error
+	}{
+		{name: "stored", affected: 1},
+		{name: "already enabled", affected: 0, wantErr: domain.ErrMFAAlreadyEnabled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+
+			mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO user_mfa (user_id, secret, last_used_step, created_at) VALUES ($1, $2, 0, NOW())`)).
+				WithArgs("user-id", "SECRET").
+				WillReturnResult(sqlmock.NewResult(0, tt.affected))
+
+			repo := postgres.NewMFAPostgres(db)
+			err := repo.SavePending(context.Background(), "user-id", "SECRET")
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestMFAPostgres_Enable(t *testing.T) {
+	tests := []struct {
+		name      string
+		setupMock func(sqlmock.Sqlmock)
+		wantErr   error
+	}{
+		{
+			name: "success",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(`UPDATE user_mfa SET enabled_at=NOW(), last_used_step=$2 WHERE user_id=$1 AND enabled_at IS NULL`)).
+					WithArgs("user-id", int64(7)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM mfa_recovery_codes WHERE user_id=$1`)).
+					WithArgs("user-id").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO mfa_recovery_codes (user_id, code_hash, created_at) VALUES ($1, $2, NOW())`)).
+					WithArgs("user-id", "hash-1").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO mfa_recovery_codes`)).
+					WithArgs("user-id", "hash-2").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "already enabled",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(`UPDATE user_mfa SET enabled_at=NOW()`)).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			wantErr: domain.ErrMFAAlreadyEnabled,
+		},
+		{
+			name: "recovery code insert fails",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(`UPDATE user_mfa SET enabled_at=NOW()`)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM mfa_recovery_codes`)).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO mfa_recovery_codes`)).
+					WillReturnError(sql.ErrConnDone)
+				mock.ExpectRollback()
+			},
+			wantErr: sql.ErrConnDone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+
+			tt.setupMock(mock)
+
+			repo := postgres.NewMFAPostgres(db)
+			err := repo.Enable(context.Background(), "user-id", 7, []string{"hash-1", "hash-2"})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestMFAPostgres_Delete(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM mfa_recovery_codes WHERE user_id=$1`)).
+		WithArgs("user-id").
+		WillReturnResult(sqlmock.NewResult(0, 10))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM user_mfa WHERE user_id=$1`)).
+		WithArgs("user-id").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	repo := postgres.NewMFAPostgres(db)
+	assert.NoError(t, repo.Delete(context.Background(), "user-id"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMFAPostgres_RecordStep(t *testing.T) {
+	tests := []struct {
+		name     string
+		affected int64
+		wantErr  error
+	}{
+		{name: "newer step", affected: 1},
+		{name: "replayed step", affected: 0, wantErr: domain.ErrInvalidMFACode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+
+			mock.ExpectExec(regexp.QuoteMeta(`UPDATE user_mfa SET last_used_step=$2 WHERE user_id=$1 AND last_used_step < $2`)).
+				WithArgs("user-id", int64(100)).
+				WillReturnResult(sqlmock.NewResult(0, tt.affected))
+
+			repo := postgres.NewMFAPostgres(db)
+			err := repo.RecordStep(context.Background(), "user-id", 100)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestMFAPostgres_UseRecoveryCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		affected int64
+		wantErr  error
+	}{
+		{name: "unused code", affected: 1},
+		{name: "used or unknown code", affected: 0, wantErr: domain.ErrInvalidMFACode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+
+			mock.ExpectExec(regexp.QuoteMeta(`UPDATE mfa_recovery_codes SET used_at=NOW() WHERE user_id=$1 AND code_hash=$2 AND used_at IS NULL`)).
+				WithArgs("user-id", "hash").
+				WillReturnResult(sqlmock.NewResult(0, tt.affected))
+
+			repo := postgres.NewMFAPostgres(db)
+			err := repo.UseRecoveryCode(context.Background(), "user-id", "hash")
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
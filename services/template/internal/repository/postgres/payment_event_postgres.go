@@ -0,0 +1,78 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/internal/ports/repositories"
+)
+
+type paymentEventPostgres struct {
+	db *sql.DB
+}
+
+func NewPaymentEventPostgres(db *sql.DB) repositories.PaymentEventRepository {
+	return &paymentEventPostgres{db: db}
+}
+
+func (r *paymentEventPostgres) Record(ctx context.Context, event *domain.PaymentEvent, apply repositories.PaymentPlanChange) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	// The lock keeps the plan from changing under apply and makes the events of a user wait for each other
+	var user *domain.User
+	if event.UserID != "" {
+		var lastEventAt *time.Time
+		err := tx.QueryRowContext(ctx, `SELECT plan_event_at FROM users WHERE id=$1 AND deleted_at IS NULL FOR UPDATE`, event.UserID).Scan(&lastEventAt)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			event.UserID = ""
+		case err != nil:
+			return false, err
+		case apply == nil:
+			// Only linked to the user, there is no plan to change
+		case lastEventAt != nil && event.CreatedAt.Before(*lastEventAt):
+			event.Rejection = domain.PaymentEventOutOfOrder
+		default:
+			user, err = scanUser(tx.QueryRowContext(ctx, `SELECT `+userColumns+` FROM users WHERE id=$1`, event.UserID))
+			if err != nil {
+				return false, err
+			}
+		}
+	}
+
+	// The primary key makes concurrent deliveries of the same event wait for each other, only one inserts it
+	res, err := tx.ExecContext(ctx, `INSERT INTO payment_events (provider, event_id, type, user_id, payload, created_at, rejection, received_at)
+	                                 VALUES ($1, $2, $3, NULLIF($4, '')::uuid, $5, $6, NULLIF($7, ''), NOW())
+	                                 ON CONFLICT (provider, event_id) DO NOTHING`,
+		event.Provider, event.ID, event.Type, event.UserID, string(event.Payload), event.CreatedAt, event.Rejection,
+	)
+	if err != nil {
+		return false, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if affected == 0 {
+		return false, nil
+	}
+
+	if user != nil {
+		if apply(user) {
+			if _, err := tx.ExecContext(ctx, updatePlanQuery, updatePlanArgs(user)...); err != nil {
+				return false, err
+			}
+		}
+		if _, err := tx.ExecContext(ctx, `UPDATE users SET plan_event_at=$1 WHERE id=$2`, event.CreatedAt, user.ID); err != nil {
+			return false, err
+		}
+	}
+	return true, tx.Commit()
+}
@@ -0,0 +1,191 @@
+package postgres_test
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/internal/repository/postgres"
+	"github.com/stretchr/testify/assert"
+)
+
+const insertPaymentEventQuery = `INSERT INTO payment_events (provider, event_id, type, user_id, payload, created_at, rejection, received_at)
+	VALUES ($1, $2, $3, NULLIF($4, '')::uuid, $5, $6, NULLIF($7, ''), NOW())
+	ON CONFLICT (provider, event_id) DO NOTHING`
+
+const updateUserPlanQuery = `UPDATE users SET plan_type=$1, plan_id=NULLIF($2, '')::uuid, premium_since=$3, plan_expiry=$4, plan_cancelled=$5, updated_at=NOW()
+	WHERE id=$6 AND deleted_at IS NULL`
+
+const lockPaymentUserQuery = `SELECT plan_event_at FROM users WHERE id=$1 AND deleted_at IS NULL FOR UPDATE`
+
+const selectPaymentUserQuery = `SELECT id, COALESCE(firebase_uid, ''), email, name, picture_url, plan_type, COALESCE(plan_id::text, ''), premium_since, plan_expiry, plan_cancelled, COALESCE(password_hash, ''), email_verified, disabled_at, created_at, updated_at FROM users WHERE id=$1`
+
+var paymentUserColumns = []string{
+	"id", "firebase_uid", "email", "name", "picture_url", "plan_type", "plan_id", "premium_since", "plan_expiry", "plan_cancelled", "password_hash", "email_verified", "disabled_at", "created_at", "updated_at",
+}
+
+func TestPaymentEventPostgres_Record(t *testing.T) {
+	createdAt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	expiry := createdAt.Add(31 * 24 * time.Hour)
+	newEvent := func(userID string) *domain.PaymentEvent {
+		return &domain.PaymentEvent{ID: "evt_1", Provider: "stripe", Type: "customer.subscription.updated", UserID: userID, Payload: []byte(`{"id":"evt_1"}`), CreatedAt: createdAt}
+	}
+	upgrade := func(user *domain.User) bool {
+		user.PlanType, user.PlanID, user.PremiumSince, user.PlanExpiry = domain.PlanPremium, "premium-id", &createdAt, &expiry
+		return true
+	}
+	expectUser := func(mock sqlmock.Sqlmock, lastEventAt any) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(lockPaymentUserQuery)).
+			WithArgs("user-id").
+			WillReturnRows(sqlmock.NewRows([]string{"plan_event_at"}).AddRow(lastEventAt))
+	}
+	expectUserRow := func(mock sqlmock.Sqlmock) {
+		mock.ExpectQuery(regexp.QuoteMeta(selectPaymentUserQuery)).
+			WithArgs("user-id").
+			WillReturnRows(sqlmock.NewRows(paymentUserColumns).
+				AddRow("user-id", "", "user@example.com", "User", "", domain.PlanFree, "free-id", nil, nil, false, "", true, nil, createdAt, createdAt))
+	}
+
+	tests := []struct {
+		name          string
+		userID        string
+		apply         func(user *domain.User) bool
+		setupMock     func(sqlmock.Sqlmock)
+		wantRecord    bool
+		wantUserID    string
+		wantRejection string
+		wantErr       error
+	}{
+		{
+			name:   "stores the event and the plan together",
+			userID: "user-id",
+			apply:  upgrade,
+			setupMock: func(mock sqlmock.Sqlmock) {
+				expectUser(mock, createdAt.Add(-time.Hour))
+				expectUserRow(mock)
+				mock.ExpectExec(regexp.QuoteMeta(insertPaymentEventQuery)).
+					WithArgs("stripe", "evt_1", "customer.subscription.updated", "user-id", `{"id":"evt_1"}`, createdAt, "").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta(updateUserPlanQuery)).
+					WithArgs(domain.PlanPremium, "premium-id", &createdAt, &expiry, false, "user-id").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta(`UPDATE users SET plan_event_at=$1 WHERE id=$2`)).
+					WithArgs(createdAt, "user-id").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			wantRecord: true,
+			wantUserID: "user-id",
+		},
+		{
+			name:   "unchanged plan only moves the last event time",
+			userID: "user-id",
+			apply:  func(*domain.User) bool { return false },
+			setupMock: func(mock sqlmock.Sqlmock) {
+				expectUser(mock, nil)
+				expectUserRow(mock)
+				mock.ExpectExec(regexp.QuoteMeta(insertPaymentEventQuery)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta(`UPDATE users SET plan_event_at=$1 WHERE id=$2`)).
+					WithArgs(createdAt, "user-id").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			wantRecord: true,
+			wantUserID: "user-id",
+		},
+		{
+			name:   "older event than the last applied is rejected",
+			userID: "user-id",
+			apply:  upgrade,
+			setupMock: func(mock sqlmock.Sqlmock) {
+				expectUser(mock, createdAt.Add(time.Minute))
+				mock.ExpectExec(regexp.QuoteMeta(insertPaymentEventQuery)).
+					WithArgs("stripe", "evt_1", "customer.subscription.updated", "user-id", `{"id":"evt_1"}`, createdAt, domain.PaymentEventOutOfOrder).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			wantRecord:    true,
+			wantUserID:    "user-id",
+			wantRejection: domain.PaymentEventOutOfOrder,
+		},
+		{
+			name:   "unknown user is only recorded",
+			userID: "user-id",
+			apply:  upgrade,
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(lockPaymentUserQuery)).
+					WithArgs("user-id").
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectExec(regexp.QuoteMeta(insertPaymentEventQuery)).
+					WithArgs("stripe", "evt_1", "customer.subscription.updated", "", `{"id":"evt_1"}`, createdAt, "").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			wantRecord: true,
+		},
+		{
+			name: "event without a user",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(insertPaymentEventQuery)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			wantRecord: true,
+		},
+		{
+			name:   "already recorded",
+			userID: "user-id",
+			apply:  upgrade,
+			setupMock: func(mock sqlmock.Sqlmock) {
+				expectUser(mock, nil)
+				expectUserRow(mock)
+				mock.ExpectExec(regexp.QuoteMeta(insertPaymentEventQuery)).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			wantUserID: "user-id",
+		},
+		{
+			name:   "plan update failure rolls the event back",
+			userID: "user-id",
+			apply:  upgrade,
+			setupMock: func(mock sqlmock.Sqlmock) {
+				expectUser(mock, nil)
+				expectUserRow(mock)
+				mock.ExpectExec(regexp.QuoteMeta(insertPaymentEventQuery)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta(updateUserPlanQuery)).
+					WillReturnError(sql.ErrConnDone)
+				mock.ExpectRollback()
+			},
+			wantUserID: "user-id",
+			wantErr:    sql.ErrConnDone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+			tt.setupMock(mock)
+
+			event := newEvent(tt.userID)
+			repo := postgres.NewPaymentEventPostgres(db)
+			recorded, err := repo.Record(context.Background(), event, tt.apply)
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.wantRecord, recorded)
+			assert.Equal(t, tt.wantUserID, event.UserID)
+			assert.Equal(t, tt.wantRejection, event.Rejection)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/internal/ports/repositories"
+)
+
+// planColumns is the column list scanned by scanPlan
+const planColumns = `id, code, name, COALESCE(description, ''), price, max_cats, COALESCE(has_image_analysis, FALSE), COALESCE(has_consultation, FALSE), created_at, updated_at`
+
+type planPostgres struct {
+	db *sql.DB
+}
+
+func NewPlanPostgres(db *sql.DB) repositories.PlanRepository {
+	return &planPostgres{db: db}
+}
+
+func (r *planPostgres) FindByID(ctx context.Context, id string) (*domain.Plan, error) {
+	query := `SELECT ` + planColumns + ` FROM plans WHERE id=$1`
+	return scanPlan(r.db.QueryRowContext(ctx, query, id))
+}
+
+func (r *planPostgres) FindByCode(ctx context.Context, code string) (*domain.Plan, error) {
+	query := `SELECT ` + planColumns + ` FROM plans WHERE code=$1`
+	return scanPlan(r.db.QueryRowContext(ctx, query, code))
+}
+
+func (r *planPostgres) List(ctx context.Context) ([]domain.Plan, error) {
+	query := `SELECT ` + planColumns + ` FROM plans ORDER BY price, code`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var plans []domain.Plan
+	for rows.Next() {
+		plan, err := scanPlan(rows)
+		if err != nil {
+			return nil, err
+		}
+		plans = append(plans, *plan)
+	}
+	return plans, rows.Err()
+}
+
+func scanPlan(row interface{ Scan(dest ...any) error }) (*domain.Plan, error) {
+	var plan domain.Plan
+	err := row.Scan(
+		&plan.ID,
+		&plan.Code,
+		&plan.Name,
+		&plan.Description,
+		&plan.Price,
+		&plan.MaxCats,
+		&plan.HasImageAnalysis,
+		&plan.HasConsultation,
+		&plan.CreatedAt,
+		&plan.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
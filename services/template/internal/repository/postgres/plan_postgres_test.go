@@ -0,0 +1,108 @@
+package postgres_test
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/nuhorizon/go-project-template/services/template/internal/repository/postgres"
+	"github.com/stretchr/testify/assert"
+)
+
+var planRowColumns = []string{"id", "code", "name", "description", "price", "max_cats", "has_image_analysis", "has_consultation", "created_at", "updated_at"}
+
+const planSelect = `SELECT id, code, name, COALESCE(description, ''), price, max_cats, COALESCE(has_image_analysis, FALSE), COALESCE(has_consultation, FALSE), created_at, updated_at FROM plans`
+
+func TestPlanPostgres_FindByID(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name      string
+		setupMock func(sqlmock.Sqlmock)
+		wantErr   error
+	}{
+		{
+			name: "found",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(planSelect + ` WHERE id=$1`)).
+					WithArgs("plan-id").
+					WillReturnRows(sqlmock.NewRows(planRowColumns).
+						AddRow("plan-id", "premium", "Premium", "", []byte("19.90"), 10, true, true, now, now))
+			},
+		},
+		{
+			name: "not found",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(planSelect + ` WHERE id=$1`)).
+					WithArgs("plan-id").
+					WillReturnError(sql.ErrNoRows)
+			},
+			wantErr: sql.ErrNoRows,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+			tt.setupMock(mock)
+
+			repo := postgres.NewPlanPostgres(db)
+			plan, err := repo.FindByID(context.Background(), "plan-id")
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, plan)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "premium", plan.Code)
+				assert.Equal(t, 19.9, plan.Price)
+				assert.Equal(t, 10, plan.MaxCats)
+				assert.True(t, plan.HasImageAnalysis)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestPlanPostgres_FindByCode(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	now := time.Now()
+	mock.ExpectQuery(regexp.QuoteMeta(planSelect + ` WHERE code=$1`)).
+		WithArgs("free").
+		WillReturnRows(sqlmock.NewRows(planRowColumns).
+			AddRow("plan-id", "free", "Free", "Plano gratuito", []byte("0.00"), 1, false, false, now, now))
+
+	repo := postgres.NewPlanPostgres(db)
+	plan, err := repo.FindByCode(context.Background(), "free")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "plan-id", plan.ID)
+	assert.Equal(t, 1, plan.MaxCats)
+	assert.False(t, plan.HasConsultation)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPlanPostgres_List(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	now := time.Now()
+	mock.ExpectQuery(regexp.QuoteMeta(planSelect + ` ORDER BY price, code`)).
+		WillReturnRows(sqlmock.NewRows(planRowColumns).
+			AddRow("free-id", "free", "Free", "", []byte("0.00"), 1, false, false, now, now).
+			AddRow("premium-id", "premium", "Premium", "", []byte("19.90"), 10, true, true, now, now))
+
+	repo := postgres.NewPlanPostgres(db)
+	plans, err := repo.List(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, plans, 2)
+	assert.Equal(t, "premium", plans[1].Code)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,34 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/internal/ports/repositories"
+)
+
+type rateLimitPostgres struct {
+	db *sql.DB
+}
+
+func NewRateLimitPostgres(db *sql.DB) repositories.RateLimitStore {
+	return &rateLimitPostgres{db: db}
+}
+
+// Hit increments the current window and reads the previous one in a single round trip. Windows
+// older than the previous one no longer count and are purged along the way.
+func (r *rateLimitPostgres) Hit(ctx context.Context, key string, windowStart time.Time, window time.Duration) (domain.RateLimitCounts, error) {
+	query := `WITH purge AS (
+	            DELETE FROM rate_limit_counters WHERE key=$1 AND window_start < $3
+	          ), hit AS (
+	            INSERT INTO rate_limit_counters (key, window_start, hits) VALUES ($1, $2, 1)
+	            ON CONFLICT (key, window_start) DO UPDATE SET hits = rate_limit_counters.hits + 1
+	            RETURNING hits
+	          )
+	          SELECT hit.hits, COALESCE((SELECT hits FROM rate_limit_counters WHERE key=$1 AND window_start=$3), 0) FROM hit`
+	var counts domain.RateLimitCounts
+	err := r.db.QueryRowContext(ctx, query, key, windowStart, windowStart.Add(-window)).Scan(&counts.Current, &counts.Previous)
+	return counts, err
+}
@@ -0,0 +1,73 @@
+package postgres_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/internal/repository/postgres"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimitPostgres_Hit(t *testing.T) {
+	windowStart := time.Now().Truncate(time.Minute)
+	query := `
+		WITH purge AS (
+		  DELETE FROM rate_limit_counters WHERE key=$1 AND window_start < $3
+		), hit AS (
+		  INSERT INTO rate_limit_counters (key, window_start, hits) VALUES ($1, $2, 1)
+		  ON CONFLICT (key, window_start) DO UPDATE SET hits = rate_limit_counters.hits + 1
+		  RETURNING hits
+		)
+		SELECT hit.hits, COALESCE((SELECT hits FROM rate_limit_counters WHERE key=$1 AND window_start=$3), 0) FROM hit
+	`
+
+	tests := []struct {
+		name       string
+		setupMock  func(sqlmock.Sqlmock)
+		wantCounts domain.RateLimitCounts
+		wantErr    bool
+	}{
+		{
+			name: "success",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs("api:user:1", windowStart, windowStart.Add(-time.Minute)).
+					WillReturnRows(sqlmock.NewRows([]string{"hits", "previous"}).AddRow(3, 10))
+			},
+			wantCounts: domain.RateLimitCounts{Current: 3, Previous: 10},
+		},
+		{
+			name: "db error",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs("api:user:1", windowStart, windowStart.Add(-time.Minute)).
+					WillReturnError(errors.New("db error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+			tt.setupMock(mock)
+
+			store := postgres.NewRateLimitPostgres(db)
+			counts, err := store.Hit(context.Background(), "api:user:1", windowStart, time.Minute)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantCounts, counts)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
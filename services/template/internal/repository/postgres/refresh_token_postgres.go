@@ -0,0 +1,94 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/internal/ports/repositories"
+)
+
+type refreshTokenPostgres struct {
+	db *sql.DB
+}
+
+func NewRefreshTokenPostgres(db *sql.DB) repositories.RefreshTokenRepository {
+	return &refreshTokenPostgres{db: db}
+}
+
+func (r *refreshTokenPostgres) Create(ctx context.Context, token *domain.RefreshToken) error {
+	query := `INSERT INTO refresh_tokens (id, user_id, family_id, token_hash, expires_at, created_at)
+	          VALUES ($1, $2, $3, $4, $5, NOW())`
+	_, err := r.db.ExecContext(ctx, query,
+		token.ID, token.UserID, token.FamilyID, token.TokenHash, token.ExpiresAt,
+	)
+	return err
+}
+
+func (r *refreshTokenPostgres) FindByHash(ctx context.Context, tokenHash string) (*domain.RefreshToken, error) {
+	query := `SELECT id, user_id, family_id, token_hash, expires_at, revoked_at, replaced_by, created_at FROM refresh_tokens WHERE token_hash=$1`
+	row := r.db.QueryRowContext(ctx, query, tokenHash)
+
+	var token domain.RefreshToken
+	err := row.Scan(
+		&token.ID,
+		&token.UserID,
+		&token.FamilyID,
+		&token.TokenHash,
+		&token.ExpiresAt,
+		&token.RevokedAt,
+		&token.ReplacedBy,
+		&token.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *refreshTokenPostgres) Rotate(ctx context.Context, current *domain.RefreshToken, next *domain.RefreshToken) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Only an unrevoked token can be rotated, this guards against two concurrent exchanges
+	res, err := tx.ExecContext(ctx,
+		`UPDATE refresh_tokens SET revoked_at=NOW(), replaced_by=$1 WHERE id=$2 AND revoked_at IS NULL`,
+		next.ID, current.ID,
+	)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return domain.ErrRefreshTokenReused
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO refresh_tokens (id, user_id, family_id, token_hash, expires_at, created_at)
+	          VALUES ($1, $2, $3, $4, $5, NOW())`,
+		next.ID, next.UserID, next.FamilyID, next.TokenHash, next.ExpiresAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *refreshTokenPostgres) RevokeFamily(ctx context.Context, familyID string) error {
+	query := `UPDATE refresh_tokens SET revoked_at=NOW() WHERE family_id=$1 AND revoked_at IS NULL`
+	_, err := r.db.ExecContext(ctx, query, familyID)
+	return err
+}
+
+func (r *refreshTokenPostgres) RevokeAllForUser(ctx context.Context, userID string) error {
+	query := `UPDATE refresh_tokens SET revoked_at=NOW() WHERE user_id=$1 AND revoked_at IS NULL`
+	_, err := r.db.ExecContext(ctx, query, userID)
+	return err
+}
@@ -0,0 +1,212 @@
+package postgres_test
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/internal/repository/postgres"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRefreshTokenPostgres_Create(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour)
+	tests := []struct {
+		name      string
+		setupMock func(sqlmock.Sqlmock)
+		wantErr   bool
+	}{
+		{
+			name: "success",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(`
+					INSERT INTO refresh_tokens (id, user_id, family_id, token_hash, expires_at, created_at)
+					VALUES ($1, $2, $3, $4, $5, NOW())
+				`)).
+					WithArgs("token-id", "user-id", "family-id", "hash", expiresAt).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+		},
+		{
+			name: "db error",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO refresh_tokens`)).
+					WillReturnError(sql.ErrConnDone)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+
+			tt.setupMock(mock)
+
+			repo := postgres.NewRefreshTokenPostgres(db)
+			err := repo.Create(context.Background(), &domain.RefreshToken{
+				ID:        "token-id",
+				UserID:    "user-id",
+				FamilyID:  "family-id",
+				TokenHash: "hash",
+				ExpiresAt: expiresAt,
+			})
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRefreshTokenPostgres_FindByHash(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name      string
+		setupMock func(sqlmock.Sqlmock)
+		wantErr   bool
+	}{
+		{
+			name: "found",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(`
+					SELECT id, user_id, family_id, token_hash, expires_at, revoked_at, replaced_by, created_at FROM refresh_tokens WHERE token_hash=$1
+				`)).
+					WithArgs("hash").
+					WillReturnRows(sqlmock.NewRows([]string{
+						"id", "user_id", "family_id", "token_hash", "expires_at", "revoked_at", "replaced_by", "created_at",
+					}).AddRow("token-id", "user-id", "family-id", "hash", now, nil, nil, now))
+			},
+		},
+		{
+			name: "not found",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, user_id, family_id`)).
+					WithArgs("hash").
+					WillReturnError(sql.ErrNoRows)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+
+			tt.setupMock(mock)
+
+			repo := postgres.NewRefreshTokenPostgres(db)
+			token, err := repo.FindByHash(context.Background(), "hash")
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "family-id", token.FamilyID)
+				assert.Nil(t, token.RevokedAt)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRefreshTokenPostgres_Rotate(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour)
+	current := &domain.RefreshToken{ID: "old-id"}
+	next := &domain.RefreshToken{ID: "new-id", UserID: "user-id", FamilyID: "family-id", TokenHash: "new-hash", ExpiresAt: expiresAt}
+
+	tests := []struct {
+		name      string
+		setupMock func(sqlmock.Sqlmock)
+		wantErr   error
+	}{
+		{
+			name: "success",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(`UPDATE refresh_tokens SET revoked_at=NOW(), replaced_by=$1 WHERE id=$2 AND revoked_at IS NULL`)).
+					WithArgs("new-id", "old-id").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO refresh_tokens`)).
+					WithArgs("new-id", "user-id", "family-id", "new-hash", expiresAt).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "already rotated",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(`UPDATE refresh_tokens SET revoked_at=NOW()`)).
+					WithArgs("new-id", "old-id").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			wantErr: domain.ErrRefreshTokenReused,
+		},
+		{
+			name: "insert fails",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(`UPDATE refresh_tokens SET revoked_at=NOW()`)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO refresh_tokens`)).
+					WillReturnError(sql.ErrConnDone)
+				mock.ExpectRollback()
+			},
+			wantErr: sql.ErrConnDone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+
+			tt.setupMock(mock)
+
+			repo := postgres.NewRefreshTokenPostgres(db)
+			err := repo.Rotate(context.Background(), current, next)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRefreshTokenPostgres_RevokeFamily(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE refresh_tokens SET revoked_at=NOW() WHERE family_id=$1 AND revoked_at IS NULL`)).
+		WithArgs("family-id").
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	repo := postgres.NewRefreshTokenPostgres(db)
+	assert.NoError(t, repo.RevokeFamily(context.Background(), "family-id"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRefreshTokenPostgres_RevokeAllForUser(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE refresh_tokens SET revoked_at=NOW() WHERE user_id=$1 AND revoked_at IS NULL`)).
+		WithArgs("user-id").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	repo := postgres.NewRefreshTokenPostgres(db)
+	assert.NoError(t, repo.RevokeAllForUser(context.Background(), "user-id"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,67 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/internal/ports/repositories"
+)
+
+type rolePostgres struct {
+	db *sql.DB
+}
+
+func NewRolePostgres(db *sql.DB) repositories.RoleRepository {
+	return &rolePostgres{db: db}
+}
+
+func (r *rolePostgres) FindByUserID(ctx context.Context, userID string) ([]domain.Role, error) {
+	query := `SELECT ur.role, COALESCE(rp.permission, '') FROM user_roles ur
+	          LEFT JOIN role_permissions rp ON rp.role = ur.role
+	          WHERE ur.user_id=$1 ORDER BY ur.role, rp.permission`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var roles []domain.Role
+	for rows.Next() {
+		var role, permission string
+		if err := rows.Scan(&role, &permission); err != nil {
+			return nil, err
+		}
+
+		// Rows are ordered by role, so a new name starts a new entry
+		if len(roles) == 0 || roles[len(roles)-1].Name != role {
+			roles = append(roles, domain.Role{Name: role})
+		}
+		if permission != "" {
+			last := &roles[len(roles)-1]
+			last.Permissions = append(last.Permissions, permission)
+		}
+	}
+
+	return roles, rows.Err()
+}
+
+func (r *rolePostgres) AssignRole(ctx context.Context, userID, role string) error {
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM roles WHERE name=$1)`, role).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return domain.ErrRoleNotFound
+	}
+
+	query := `INSERT INTO user_roles (user_id, role, created_at) VALUES ($1, $2, NOW()) ON CONFLICT (user_id, role) DO NOTHING`
+	_, err := r.db.ExecContext(ctx, query, userID, role)
+	return err
+}
+
+func (r *rolePostgres) RemoveRole(ctx context.Context, userID, role string) error {
+	query := `DELETE FROM user_roles WHERE user_id=$1 AND role=$2`
+	_, err := r.db.ExecContext(ctx, query, userID, role)
+	return err
+}
@@ -0,0 +1,138 @@
+package postgres_test
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/internal/repository/postgres"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRolePostgres_FindByUserID(t *testing.T) {
+	tests := []struct {
+		name      string
+		setupMock func(sqlmock.Sqlmock)
+		want      []domain.Role
+		wantErr   bool
+	}{
+		{
+			name: "groups permissions by role",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(`
+					SELECT ur.role, COALESCE(rp.permission, '') FROM user_roles ur
+					LEFT JOIN role_permissions rp ON rp.role = ur.role
+					WHERE ur.user_id=$1 ORDER BY ur.role, rp.permission
+				`)).
+					WithArgs("user-id").
+					WillReturnRows(sqlmock.NewRows([]string{"role", "permission"}).
+						AddRow("admin", "users:read").
+						AddRow("admin", "users:write").
+						AddRow("support", ""))
+			},
+			want: []domain.Role{
+				{Name: "admin", Permissions: []string{"users:read", "users:write"}},
+				{Name: "support"},
+			},
+		},
+		{
+			name: "no roles",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT ur.role`)).
+					WithArgs("user-id").
+					WillReturnRows(sqlmock.NewRows([]string{"role", "permission"}))
+			},
+			want: nil,
+		},
+		{
+			name: "db error",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT ur.role`)).
+					WillReturnError(sql.ErrConnDone)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+
+			tt.setupMock(mock)
+
+			repo := postgres.NewRolePostgres(db)
+			roles, err := repo.FindByUserID(context.Background(), "user-id")
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, roles)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRolePostgres_AssignRole(t *testing.T) {
+	tests := []struct {
+		name      string
+		setupMock func(sqlmock.Sqlmock)
+		wantErr   error
+	}{
+		{
+			name: "success",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT EXISTS (SELECT 1 FROM roles WHERE name=$1)`)).
+					WithArgs("admin").
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+				mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO user_roles (user_id, role, created_at) VALUES ($1, $2, NOW()) ON CONFLICT (user_id, role) DO NOTHING`)).
+					WithArgs("user-id", "admin").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+		},
+		{
+			name: "unknown role",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT EXISTS`)).
+					WithArgs("admin").
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+			},
+			wantErr: domain.ErrRoleNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+
+			tt.setupMock(mock)
+
+			repo := postgres.NewRolePostgres(db)
+			err := repo.AssignRole(context.Background(), "user-id", "admin")
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRolePostgres_RemoveRole(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM user_roles WHERE user_id=$1 AND role=$2`)).
+		WithArgs("user-id", "admin").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := postgres.NewRolePostgres(db)
+	assert.NoError(t, repo.RemoveRole(context.Background(), "user-id", "admin"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
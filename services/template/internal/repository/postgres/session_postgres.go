@@ -0,0 +1,73 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/internal/ports/repositories"
+)
+
+type sessionPostgres struct {
+	db *sql.DB
+}
+
+func NewSessionPostgres(db *sql.DB) repositories.SessionRepository {
+	return &sessionPostgres{db: db}
+}
+
+func (r *sessionPostgres) Upsert(ctx context.Context, session *domain.Session) error {
+	query := `INSERT INTO sessions (id, user_id, user_agent, ip, created_at, last_seen_at) VALUES ($1, $2, $3, $4, $5, $5)
+	          ON CONFLICT (id) DO UPDATE SET user_agent=EXCLUDED.user_agent, ip=EXCLUDED.ip, last_seen_at=EXCLUDED.last_seen_at`
+	_, err := r.db.ExecContext(ctx, query, session.ID, session.UserID, session.UserAgent, session.IP, session.LastSeenAt)
+	return err
+}
+
+func (r *sessionPostgres) FindByID(ctx context.Context, id string) (*domain.Session, error) {
+	query := `SELECT id, user_id, user_agent, ip, created_at, last_seen_at FROM sessions WHERE id=$1`
+	var session domain.Session
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&session.ID,
+		&session.UserID,
+		&session.UserAgent,
+		&session.IP,
+		&session.CreatedAt,
+		&session.LastSeenAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// ListActiveByUserID derives the state from the refresh tokens, so sessions ended by a logout,
+// a reuse detection or a "sign out everywhere" disappear without further bookkeeping
+func (r *sessionPostgres) ListActiveByUserID(ctx context.Context, userID string) ([]domain.Session, error) {
+	query := `SELECT s.id, s.user_id, s.user_agent, s.ip, s.created_at, s.last_seen_at FROM sessions s
+	          WHERE s.user_id=$1 AND EXISTS (
+	            SELECT 1 FROM refresh_tokens rt WHERE rt.family_id = s.id AND rt.revoked_at IS NULL AND rt.expires_at > NOW()
+	          )
+	          ORDER BY s.last_seen_at DESC`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sessions []domain.Session
+	for rows.Next() {
+		var session domain.Session
+		if err := rows.Scan(
+			&session.ID,
+			&session.UserID,
+			&session.UserAgent,
+			&session.IP,
+			&session.CreatedAt,
+			&session.LastSeenAt,
+		); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, rows.Err()
+}
@@ -0,0 +1,112 @@
+package postgres_test
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/internal/repository/postgres"
+	"github.com/stretchr/testify/assert"
+)
+
+var sessionRowColumns = []string{"id", "user_id", "user_agent", "ip", "created_at", "last_seen_at"}
+
+func TestSessionPostgres_Upsert(t *testing.T) {
+	now := time.Now()
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	session := &domain.Session{ID: "session-id", UserID: "user-id", UserAgent: "Mozilla/5.0", IP: "203.0.113.7", CreatedAt: now, LastSeenAt: now}
+	mock.ExpectExec(regexp.QuoteMeta(`
+		INSERT INTO sessions (id, user_id, user_agent, ip, created_at, last_seen_at) VALUES ($1, $2, $3, $4, $5, $5)
+		ON CONFLICT (id) DO UPDATE SET user_agent=EXCLUDED.user_agent, ip=EXCLUDED.ip, last_seen_at=EXCLUDED.last_seen_at
+	`)).
+		WithArgs("session-id", "user-id", "Mozilla/5.0", "203.0.113.7", now).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	repo := postgres.NewSessionPostgres(db)
+	assert.NoError(t, repo.Upsert(context.Background(), session))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSessionPostgres_FindByID(t *testing.T) {
+	now := time.Now()
+	query := `SELECT id, user_id, user_agent, ip, created_at, last_seen_at FROM sessions WHERE id=$1`
+
+	tests := []struct {
+		name      string
+		setupMock func(sqlmock.Sqlmock)
+		wantErr   error
+	}{
+		{
+			name: "found",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs("session-id").
+					WillReturnRows(sqlmock.NewRows(sessionRowColumns).
+						AddRow("session-id", "user-id", "Mozilla/5.0", "203.0.113.7", now, now))
+			},
+		},
+		{
+			name: "not found",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs("session-id").
+					WillReturnError(sql.ErrNoRows)
+			},
+			wantErr: sql.ErrNoRows,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+			tt.setupMock(mock)
+
+			repo := postgres.NewSessionPostgres(db)
+			session, err := repo.FindByID(context.Background(), "session-id")
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, session)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "user-id", session.UserID)
+				assert.Equal(t, "203.0.113.7", session.IP)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSessionPostgres_ListActiveByUserID(t *testing.T) {
+	now := time.Now()
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`
+		SELECT s.id, s.user_id, s.user_agent, s.ip, s.created_at, s.last_seen_at FROM sessions s
+		WHERE s.user_id=$1 AND EXISTS (
+		  SELECT 1 FROM refresh_tokens rt WHERE rt.family_id = s.id AND rt.revoked_at IS NULL AND rt.expires_at > NOW()
+		)
+		ORDER BY s.last_seen_at DESC
+	`)).
+		WithArgs("user-id").
+		WillReturnRows(sqlmock.NewRows(sessionRowColumns).
+			AddRow("session-2", "user-id", "curl/8.0", "198.51.100.1", now, now).
+			AddRow("session-1", "user-id", "Mozilla/5.0", "203.0.113.7", now.Add(-time.Hour), now.Add(-time.Minute)))
+
+	repo := postgres.NewSessionPostgres(db)
+	sessions, err := repo.ListActiveByUserID(context.Background(), "user-id")
+
+	assert.NoError(t, err)
+	assert.Len(t, sessions, 2)
+	assert.Equal(t, "session-2", sessions[0].ID)
+	assert.Equal(t, "Mozilla/5.0", sessions[1].UserAgent)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
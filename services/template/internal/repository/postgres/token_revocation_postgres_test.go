@@ -0,0 +1,173 @@
+package postgres_test
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/internal/repository/postgres"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenRevocationPostgres_RevokeToken(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour)
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO revoked_tokens (jti, expires_at, revoked_at) VALUES ($1, $2, NOW()) ON CONFLICT (jti) DO NOTHING`)).
+		WithArgs("jti-1", expiresAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	store := postgres.NewTokenRevocationPostgres(db)
+	assert.NoError(t, store.RevokeToken(context.Background(), "jti-1", expiresAt))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTokenRevocationPostgres_RevokeUserTokens(t *testing.T) {
+	before := time.Now()
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta(`
+		INSERT INTO user_token_revocations (user_id, revoked_before) VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET revoked_before = GREATEST(user_token_revocations.revoked_before, EXCLUDED.revoked_before)
+	`)).
+		WithArgs("user-id", before).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	store := postgres.NewTokenRevocationPostgres(db)
+	assert.NoError(t, store.RevokeUserTokens(context.Background(), "user-id", before))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTokenRevocationPostgres_RevokeSession(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour)
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta(`
+		INSERT INTO revoked_sessions (session_id, expires_at, revoked_at) VALUES ($1, $2, NOW())
+		ON CONFLICT (session_id) DO UPDATE SET expires_at = GREATEST(revoked_sessions.expires_at, EXCLUDED.expires_at)
+	`)).
+		WithArgs("session-id", expiresAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	store := postgres.NewTokenRevocationPostgres(db)
+	assert.NoError(t, store.RevokeSession(context.Background(), "session-id", expiresAt))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTokenRevocationPostgres_IsRevoked(t *testing.T) {
+	issuedAt := time.Now()
+	claims := &domain.TokenClaims{UserID: "user-id", TokenID: "jti-1", SessionID: "session-id", IssuedAt: issuedAt}
+
+	tests := []struct {
+		name        string
+		claims      *domain.TokenClaims
+		setupMock   func(sqlmock.Sqlmock)
+		wantRevoked bool
+		wantErr     bool
+	}{
+		{
+			name: "revoked",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(`
+					SELECT EXISTS (SELECT 1 FROM revoked_tokens WHERE jti=$1)
+					OR ($4 <> '' AND EXISTS (SELECT 1 FROM revoked_sessions WHERE session_id=$4))
+					OR EXISTS (SELECT 1 FROM user_token_revocations WHERE user_id=$2 AND revoked_before >= $3)
+				`)).
+					WithArgs("jti-1", "user-id", issuedAt, "session-id").
+					WillReturnRows(sqlmock.NewRows([]string{"revoked"}).AddRow(true))
+			},
+			wantRevoked: true,
+		},
+		{
+			name: "not revoked",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT EXISTS`)).
+					WithArgs("jti-1", "user-id", issuedAt, "session-id").
+					WillReturnRows(sqlmock.NewRows([]string{"revoked"}).AddRow(false))
+			},
+		},
+		{
+			name:   "token without a session",
+			claims: &domain.TokenClaims{UserID: "user-id", TokenID: "jti-1", IssuedAt: issuedAt},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT EXISTS`)).
+					WithArgs("jti-1", "user-id", issuedAt, "").
+					WillReturnRows(sqlmock.NewRows([]string{"revoked"}).AddRow(false))
+			},
+		},
+		{
+			name: "db error",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT EXISTS`)).
+					WillReturnError(sql.ErrConnDone)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+
+			tt.setupMock(mock)
+
+			tokenClaims := claims
+			if tt.claims != nil {
+				tokenClaims = tt.claims
+			}
+			store := postgres.NewTokenRevocationPostgres(db)
+			revoked, err := store.IsRevoked(context.Background(), tokenClaims)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantRevoked, revoked)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestTokenRevocationPostgres_PruneExpired(t *testing.T) {
+	now := time.Now()
+
+	t.Run("deletes the expired tokens and sessions", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+
+		mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM revoked_tokens WHERE expires_at < $1`)).
+			WithArgs(now).
+			WillReturnResult(sqlmock.NewResult(0, 3))
+		mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM revoked_sessions WHERE expires_at < $1`)).
+			WithArgs(now).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+
+		store := postgres.NewTokenRevocationPostgres(db)
+		pruned, err := store.PruneExpired(context.Background(), now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(5), pruned)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("db error", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+
+		mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM revoked_tokens`)).WillReturnError(sql.ErrConnDone)
+
+		store := postgres.NewTokenRevocationPostgres(db)
+		_, err := store.PruneExpired(context.Background(), now)
+
+		assert.ErrorIs(t, err, sql.ErrConnDone)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
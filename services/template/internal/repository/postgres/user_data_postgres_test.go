@@ -0,0 +1,68 @@
+package postgres_test
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/nuhorizon/go-project-template/services/template/internal/repository/postgres"
+	"github.com/stretchr/testify/assert"
+)
+
+var userDataSectionNames = []string{"user", "roles", "identities", "sessions", "api_keys", "mfa", "cats", "magic_links", "login_attempts", "payments"}
+
+func TestUserDataPostgres_Export(t *testing.T) {
+	t.Run("collects every section", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT COALESCE(json_agg(t), '[]'::json) FROM (SELECT id, email, name, picture_url, plan_type, premium_since, plan_expiry, plan_cancelled, email_verified, created_at, updated_at FROM users WHERE id=$1) t`)).
+			WithArgs("user-id").
+			WillReturnRows(sqlmock.NewRows([]string{"json_agg"}).AddRow([]byte(`[{"id":"user-id","email":"user@example.com"}]`)))
+		for _, name := range userDataSectionNames[1:] {
+			rows := []byte(`[]`)
+			switch name {
+			case "cats":
+				rows = []byte(`[{"id":"cat-id","name":"Mingau"}]`)
+			case "payments":
+				rows = []byte(`[{"provider":"stripe","event_id":"evt_1"}]`)
+			}
+			mock.ExpectQuery(regexp.QuoteMeta(`SELECT COALESCE(json_agg(t), '[]'::json) FROM (SELECT `)).
+				WithArgs("user-id").
+				WillReturnRows(sqlmock.NewRows([]string{"json_agg"}).AddRow(rows))
+		}
+		mock.ExpectCommit()
+
+		repo := postgres.NewUserDataPostgres(db)
+		export, err := repo.Export(context.Background(), "user-id")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "user-id", export.UserID)
+		assert.False(t, export.GeneratedAt.IsZero())
+		assert.Len(t, export.Sections, len(userDataSectionNames))
+		assert.JSONEq(t, `[{"id":"user-id","email":"user@example.com"}]`, string(export.Sections["user"]))
+		assert.JSONEq(t, `[{"id":"cat-id","name":"Mingau"}]`, string(export.Sections["cats"]))
+		assert.JSONEq(t, `[{"provider":"stripe","event_id":"evt_1"}]`, string(export.Sections["payments"]))
+		assert.JSONEq(t, `[]`, string(export.Sections["mfa"]))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("query failure", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT COALESCE(json_agg(t)`)).
+			WillReturnError(sql.ErrConnDone)
+		mock.ExpectRollback()
+
+		repo := postgres.NewUserDataPostgres(db)
+		_, err := repo.Export(context.Background(), "user-id")
+
+		assert.ErrorIs(t, err, sql.ErrConnDone)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
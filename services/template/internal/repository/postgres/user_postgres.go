@@ -3,12 +3,56 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
 	"github.com/nuhorizon/go-project-template/services/template/internal/ports/repositories"
+
+	"github.com/lib/pq"
 )
 
+// userColumns is the column list scanned by scanUser. Nullable text columns are coalesced
+// so users created without Firebase (or without a password) scan into plain strings.
+const userColumns = `id, COALESCE(firebase_uid, ''), email, name, picture_url, plan_type, COALESCE(plan_id::text, ''), premium_since, plan_expiry, plan_cancelled, COALESCE(password_hash, ''), email_verified, disabled_at, created_at, updated_at`
+
+// updatePlanQuery stores the plan fields of a user, with the arguments from updatePlanArgs
+const updatePlanQuery = `UPDATE users SET plan_type=$1, plan_id=NULLIF($2, '')::uuid, premium_since=$3, plan_expiry=$4, plan_cancelled=$5, updated_at=NOW()
+                         WHERE id=$6 AND deleted_at IS NULL`
+
+func updatePlanArgs(user *domain.User) []any {
+	return []any{user.PlanType, user.PlanID, user.PremiumSince, user.PlanExpiry, user.PlanCancelled, user.ID}
+}
+
+// execer is implemented by both *sql.DB and *sql.Tx, so a statement can be shared by the
+// repositories that run it inside their own transaction
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// insertUser inserts a new user through db, which may be a transaction. Users without a PlanID
+// reference the plan of their plan_type, the free plan when they have none. An e-mail taken by
+// another active account fails with domain.ErrEmailAlreadyRegistered, even when the account was
+// created after the caller checked.
+func insertUser(ctx context.Context, db execer, user *domain.User) error {
+	query := `INSERT INTO users (id, firebase_uid, email, name, picture_url, plan_type, premium_since, plan_expiry, password_hash, email_verified, plan_id, created_at, updated_at)
+	          VALUES ($1, NULLIF($2, ''), $3, $4, $5, $6, $7, $8, NULLIF($9, ''), $10,
+	                  COALESCE(NULLIF($11, '')::uuid, (SELECT id FROM plans WHERE code = COALESCE(NULLIF($6, ''), 'free'))), NOW(), NOW())`
+	_, err := db.ExecContext(ctx, query,
+		user.ID, user.FirebaseUID, user.Email, user.Name, user.PictureURL, user.PlanType, user.PremiumSince, user.PlanExpiry, user.PasswordHash, user.EmailVerified, user.PlanID,
+	)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" && pqErr.Constraint == "idx_users_email_active" {
+		return domain.ErrEmailAlreadyRegistered
+	}
+	return err
+}
+
 type userPostgres struct {
 	db *sql.DB
 }
@@ -18,66 +62,261 @@ func NewUserPostgres(db *sql.DB) repositories.UserRepository {
 }
 
 func (r *userPostgres) Create(ctx context.Context, user *domain.User) error {
-	query := `INSERT INTO users (id, firebase_uid, email, name, picture_url, plan_type, premium_since, plan_expiry, created_at, updated_at)
-	          VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW(), NOW())`
-	_, err := r.db.ExecContext(ctx, query,
-		user.ID, user.FirebaseUID, user.Email, user.Name, user.PictureURL, user.PlanType, user.PremiumSince, user.PlanExpiry,
-	)
-	return err
+	return insertUser(ctx, r.db, user)
 }
 
 func (r *userPostgres) Update(ctx context.Context, user *domain.User) error {
-	query := `UPDATE users SET email=$1, name=$2, picture_url=$3, plan_type=$4, premium_since=$5, plan_expiry=$6, updated_at=NOW() WHERE id=$7`
-	_, err := r.db.ExecContext(ctx, query,
-		user.Email, user.Name, user.PictureURL, user.PlanType, user.PremiumSince, user.PlanExpiry, user.ID,
-	)
+	query := `UPDATE users SET email=$1, name=$2, picture_url=$3, email_verified=$4, updated_at=NOW() WHERE id=$5`
+	_, err := r.db.ExecContext(ctx, query, user.Email, user.Name, user.PictureURL, user.EmailVerified, user.ID)
 	return err
 }
 
-func (r *userPostgres) UpsertByFirebaseUID(ctx context.Context, user *domain.User) (*domain.User, error) {
-	existing, err := r.FindByFirebaseUID(ctx, user.FirebaseUID)
-	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+func (r *userPostgres) UpdatePlan(ctx context.Context, user *domain.User) error {
+	res, err := r.db.ExecContext(ctx, updatePlanQuery, updatePlanArgs(user)...)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return domain.ErrUserNotFound
+	}
+	return nil
+}
+
+func (r *userPostgres) ListLapsedPlans(ctx context.Context, cancelledBefore, expiredBefore time.Time, limit int) ([]domain.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users
+	          WHERE deleted_at IS NULL AND plan_expiry IS NOT NULL
+	            AND ((plan_cancelled AND plan_expiry < $1) OR plan_expiry < $2)
+	          ORDER BY plan_expiry, id LIMIT $3`
+	rows, err := r.db.QueryContext(ctx, query, cancelledBefore, expiredBefore, limit)
+	if err != nil {
 		return nil, err
 	}
-	if existing == nil {
-		if err := r.Create(ctx, user); err != nil {
+	defer rows.Close()
+
+	var users []domain.User
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
 			return nil, err
 		}
-		return user, nil
-	}
-	// Update existing user
-	existing.Email = user.Email
-	existing.Name = user.Name
-	existing.PictureURL = user.PictureURL
-	existing.PlanType = user.PlanType
-	existing.PremiumSince = user.PremiumSince
-	existing.PlanExpiry = user.PlanExpiry
-	if err := r.Update(ctx, existing); err != nil {
-		return nil, err
+		users = append(users, *user)
 	}
-	return existing, nil
+	return users, rows.Err()
 }
 
-func (r *userPostgres) FindByID(ctx context.Context, id string) (*domain.User, error) {
-	query := `SELECT id, firebase_uid, email, name, picture_url, plan_type, premium_since, plan_expiry, created_at, updated_at FROM users WHERE id=$1`
-	row := r.db.QueryRowContext(ctx, query, id)
-	return scanUser(row)
+func (r *userPostgres) MarkEmailVerified(ctx context.Context, id string) error {
+	query := `UPDATE users SET email_verified=TRUE, updated_at=NOW() WHERE id=$1`
+	_, err := r.db.ExecContext(ctx, query, id)
+	return err
+}
+
+func (r *userPostgres) ClearPassword(ctx context.Context, id string) error {
+	query := `UPDATE users SET password_hash=NULL, updated_at=NOW() WHERE id=$1`
+	_, err := r.db.ExecContext(ctx, query, id)
+	return err
 }
 
-func (r *userPostgres) FindByFirebaseUID(ctx context.Context, firebaseUID string) (*domain.User, error) {
-	query := `SELECT id, firebase_uid, email, name, picture_url, plan_type, premium_since, plan_expiry, created_at, updated_at FROM users WHERE firebase_uid=$1`
-	row := r.db.QueryRowContext(ctx, query, firebaseUID)
+func (r *userPostgres) SetDisabled(ctx context.Context, id string, disabledAt *time.Time) error {
+	query := `UPDATE users SET disabled_at=$1, updated_at=NOW() WHERE id=$2`
+	_, err := r.db.ExecContext(ctx, query, disabledAt, id)
+	return err
+}
+
+func (r *userPostgres) SoftDelete(ctx context.Context, id string, deletedAt time.Time) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `UPDATE users SET deleted_at=$1, updated_at=NOW() WHERE id=$2 AND deleted_at IS NULL`, deletedAt, id); err != nil {
+		return err
+	}
+	// Without its identities the provider accounts are free to sign up again
+	if _, err := tx.ExecContext(ctx, `DELETE FROM user_identities WHERE user_id=$1`, id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// purgedEmails selects the e-mails of the users PurgeDeleted removes ($1), unless an active account
+// signed up again with the same e-mail and now owns the rows keyed by it
+const purgedEmails = `SELECT lower(u.email) FROM users u WHERE u.deleted_at < $1
+	AND NOT EXISTS (SELECT 1 FROM users a WHERE lower(a.email) = lower(u.email) AND a.deleted_at IS NULL)`
+
+// PurgeDeleted relies on the ON DELETE CASCADE of the tables referencing users (cats, sessions, ...).
+// The rows keyed by e-mail instead of the user id, magic links and the LoginGuard counters
+// ("account:<email>" and the "account:<flow>:<email>" of the e-mail throttles), are deleted first.
+func (r *userPostgres) PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	for _, query := range []string{
+		`DELETE FROM magic_links WHERE lower(email) IN (` + purgedEmails + `)`,
+		`DELETE FROM login_attempts WHERE key LIKE 'account:%' AND EXISTS (
+			SELECT 1 FROM (` + purgedEmails + `) e(email) WHERE right(key, length(e.email) + 1) = ':' || e.email)`,
+	} {
+		if _, err := tx.ExecContext(ctx, query, deletedBefore); err != nil {
+			return 0, err
+		}
+	}
+
+	res, err := tx.ExecContext(ctx, `DELETE FROM users WHERE deleted_at < $1`, deletedBefore)
+	if err != nil {
+		return 0, err
+	}
+	purged, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return purged, tx.Commit()
+}
+
+func (r *userPostgres) FindByID(ctx context.Context, id string) (*domain.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE id=$1 AND deleted_at IS NULL`
+	row := r.db.QueryRowContext(ctx, query, id)
 	return scanUser(row)
 }
 
 func (r *userPostgres) FindByEmail(ctx context.Context, email string) (*domain.User, error) {
-	query := `SELECT id, firebase_uid, email, name, picture_url, plan_type, premium_since, plan_expiry, created_at, updated_at FROM users WHERE email=$1`
+	query := `SELECT ` + userColumns + ` FROM users WHERE email=$1 AND deleted_at IS NULL`
 	row := r.db.QueryRowContext(ctx, query, email)
 	return scanUser(row)
 }
 
+// List pages through the users with keyset pagination on (sort column, id), so pages stay
+// consistent while users sign up
+func (r *userPostgres) List(ctx context.Context, filter domain.UserFilter) ([]domain.User, string, error) {
+	column, descending := userSortColumn(filter.Sort)
+
+	conditions := []string{"deleted_at IS NULL"}
+	var args []any
+	arg := func(value any) string {
+		args = append(args, value)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	if filter.PlanType != "" {
+		conditions = append(conditions, "plan_type="+arg(filter.PlanType))
+	}
+	if filter.EmailPrefix != "" {
+		conditions = append(conditions, "email LIKE "+arg(escapeLike(filter.EmailPrefix)+"%"))
+	}
+	if filter.CreatedAfter != nil {
+		conditions = append(conditions, "created_at >= "+arg(*filter.CreatedAfter))
+	}
+	if filter.CreatedBefore != nil {
+		conditions = append(conditions, "created_at < "+arg(*filter.CreatedBefore))
+	}
+	if filter.Cursor != "" {
+		value, id, err := decodeUserCursor(filter.Cursor, column)
+		if err != nil {
+			return nil, "", err
+		}
+		operator := ">"
+		if descending {
+			operator = "<"
+		}
+		conditions = append(conditions, fmt.Sprintf("(%s, id) %s (%s, %s)", column, operator, arg(value), arg(id)))
+	}
+
+	direction := "ASC"
+	if descending {
+		direction = "DESC"
+	}
+
+	query := `SELECT ` + userColumns + ` FROM users WHERE ` + strings.Join(conditions, " AND ")
+	// One extra row tells whether there is a next page
+	query += fmt.Sprintf(` ORDER BY %s %s, id %s LIMIT %s`, column, direction, direction, arg(filter.Limit+1))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	var users []domain.User
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, "", err
+		}
+		users = append(users, *user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+
+	if len(users) <= filter.Limit {
+		return users, "", nil
+	}
+	users = users[:filter.Limit]
+	return users, encodeUserCursor(users[len(users)-1], column), nil
+}
+
+// userSortColumn maps a domain.UserSorts value to its column, defaulting to the newest users first
+func userSortColumn(sort string) (column string, descending bool) {
+	switch sort {
+	case domain.UserSortCreatedAsc:
+		return "created_at", false
+	case domain.UserSortEmailAsc:
+		return "email", false
+	case domain.UserSortEmailDesc:
+		return "email", true
+	default:
+		return "created_at", true
+	}
+}
+
+// userCursor is the position after the last user of a page, the column ties the cursor to its sort
+type userCursor struct {
+	Column string `json:"c"`
+	Value  string `json:"v"`
+	ID     string `json:"id"`
+}
+
+func encodeUserCursor(user domain.User, column string) string {
+	cursor := userCursor{Column: column, Value: user.Email, ID: user.ID}
+	if column == "created_at" {
+		cursor.Value = user.CreatedAt.UTC().Format(time.RFC3339Nano)
+	}
+	raw, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeUserCursor(encoded, column string) (any, string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, "", domain.ErrInvalidCursor
+	}
+	var cursor userCursor
+	if err := json.Unmarshal(raw, &cursor); err != nil || cursor.Column != column || cursor.ID == "" {
+		return nil, "", domain.ErrInvalidCursor
+	}
+	if column != "created_at" {
+		return cursor.Value, cursor.ID, nil
+	}
+	createdAt, err := time.Parse(time.RFC3339Nano, cursor.Value)
+	if err != nil {
+		return nil, "", domain.ErrInvalidCursor
+	}
+	return createdAt, cursor.ID, nil
+}
+
+// escapeLike makes the LIKE wildcards of s match literally
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
 // Helper to scan SQL row into domain.User
-func scanUser(row *sql.Row) (*domain.User, error) {
+func scanUser(row interface{ Scan(dest ...any) error }) (*domain.User, error) {
 	var user domain.User
 	err := row.Scan(
 		&user.ID,
@@ -86,8 +325,13 @@ func scanUser(row *sql.Row) (*domain.User, error) {
 		&user.Name,
 		&user.PictureURL,
 		&user.PlanType,
+		&user.PlanID,
 		&user.PremiumSince,
 		&user.PlanExpiry,
+		&user.PlanCancelled,
+		&user.PasswordHash,
+		&user.EmailVerified,
+		&user.DisabledAt,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
 	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
 	"github.com/nuhorizon/go-project-template/services/template/internal/repository/postgres"
 	"github.com/stretchr/testify/assert"
@@ -18,15 +19,17 @@ func TestUserPostgres_Create(t *testing.T) {
 		name      string
 		setupMock func(sqlmock.Sqlmock)
 		wantErr   bool
+		errIs     error
 	}{
 		{
 			name: "success",
 			setupMock: func(mock sqlmock.Sqlmock) {
 				mock.ExpectExec(regexp.QuoteMeta(`
-					INSERT INTO users (id, firebase_uid, email, name, picture_url, plan_type, premium_since, plan_expiry, created_at, updated_at)
-					VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW(), NOW())
+					INSERT INTO users (id, firebase_uid, email, name, picture_url, plan_type, premium_since, plan_expiry, password_hash, email_verified, plan_id, created_at, updated_at)
+					VALUES ($1, NULLIF($2, ''), $3, $4, $5, $6, $7, $8, NULLIF($9, ''), $10,
+					        COALESCE(NULLIF($11, '')::uuid, (SELECT id FROM plans WHERE code = COALESCE(NULLIF($6, ''), 'free'))), NOW(), NOW())
 				`)).
-					WithArgs("user-id", "firebase-uid", "test@example.com", "Test User", "", "", nil, nil).
+					WithArgs("user-id", "firebase-uid", "test@example.com", "Test User", "", "", nil, nil, "", false, "").
 					WillReturnResult(sqlmock.NewResult(1, 1))
 			},
 		},
@@ -34,14 +37,24 @@ func TestUserPostgres_Create(t *testing.T) {
 			name: "db error",
 			setupMock: func(mock sqlmock.Sqlmock) {
 				mock.ExpectExec(regexp.QuoteMeta(`
-					INSERT INTO users (id, firebase_uid, email, name, picture_url, plan_type, premium_since, plan_expiry, created_at, updated_at)
-					VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW(), NOW())
+					INSERT INTO users (id, firebase_uid, email, name, picture_url, plan_type, premium_since, plan_expiry, password_hash, email_verified, plan_id, created_at, updated_at)
+					VALUES ($1, NULLIF($2, ''), $3, $4, $5, $6, $7, $8, NULLIF($9, ''), $10,
+					        COALESCE(NULLIF($11, '')::uuid, (SELECT id FROM plans WHERE code = COALESCE(NULLIF($6, ''), 'free'))), NOW(), NOW())
 				`)).
-					WithArgs("user-id", "firebase-uid", "test@example.com", "Test User", "", "", nil, nil).
+					WithArgs("user-id", "firebase-uid", "test@example.com", "Test User", "", "", nil, nil, "", false, "").
 					WillReturnError(sql.ErrConnDone)
 			},
 			wantErr: true,
 		},
+		{
+			name: "e-mail taken by a concurrent sign-up",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO users`)).
+					WillReturnError(&pq.Error{Code: "23505", Constraint: "idx_users_email_active"})
+			},
+			wantErr: true,
+			errIs:   domain.ErrEmailAlreadyRegistered,
+		},
 	}
 
 	for _, tt := range tests {
@@ -61,6 +74,9 @@ func TestUserPostgres_Create(t *testing.T) {
 
 			if tt.wantErr {
 				assert.Error(t, err)
+				if tt.errIs != nil {
+					assert.ErrorIs(t, err, tt.errIs)
+				}
 			} else {
 				assert.NoError(t, err)
 			}
@@ -79,9 +95,9 @@ func TestUserPostgres_Update(t *testing.T) {
 			name: "success",
 			setupMock: func(mock sqlmock.Sqlmock) {
 				mock.ExpectExec(regexp.QuoteMeta(`
-					UPDATE users SET email=$1, name=$2, picture_url=$3, plan_type=$4, premium_since=$5, plan_expiry=$6, updated_at=NOW() WHERE id=$7
+					UPDATE users SET email=$1, name=$2, picture_url=$3, email_verified=$4, updated_at=NOW() WHERE id=$5
 				`)).
-					WithArgs("test@example.com", "Test User", "", "", nil, nil, "user-id").
+					WithArgs("test@example.com", "Test User", "", false, "user-id").
 					WillReturnResult(sqlmock.NewResult(1, 1))
 			},
 		},
@@ -89,9 +105,9 @@ func TestUserPostgres_Update(t *testing.T) {
 			name: "db error",
 			setupMock: func(mock sqlmock.Sqlmock) {
 				mock.ExpectExec(regexp.QuoteMeta(`
-					UPDATE users SET email=$1, name=$2, picture_url=$3, plan_type=$4, premium_since=$5, plan_expiry=$6, updated_at=NOW() WHERE id=$7
+					UPDATE users SET email=$1, name=$2, picture_url=$3, email_verified=$4, updated_at=NOW() WHERE id=$5
 				`)).
-					WithArgs("test@example.com", "Test User", "", "", nil, nil, "user-id").
+					WithArgs("test@example.com", "Test User", "", false, "user-id").
 					WillReturnError(sql.ErrConnDone)
 			},
 			wantErr: true,
@@ -132,19 +148,19 @@ func TestUserPostgres_FindByID(t *testing.T) {
 			name: "found",
 			setupMock: func(mock sqlmock.Sqlmock) {
 				mock.ExpectQuery(regexp.QuoteMeta(`
-					SELECT id, firebase_uid, email, name, picture_url, plan_type, premium_since, plan_expiry, created_at, updated_at FROM users WHERE id=$1
+					SELECT id, COALESCE(firebase_uid, ''), email, name, picture_url, plan_type, COALESCE(plan_id::text, ''), premium_since, plan_expiry, plan_cancelled, COALESCE(password_hash, ''), email_verified, disabled_at, created_at, updated_at FROM users WHERE id=$1 AND deleted_at IS NULL
 				`)).
 					WithArgs("user-id").
 					WillReturnRows(sqlmock.NewRows([]string{
-						"id", "firebase_uid", "email", "name", "picture_url", "plan_type", "premium_since", "plan_expiry", "created_at", "updated_at",
-					}).AddRow("user-id", "firebase-uid", "test@example.com", "Test User", "", "free", now, now, now, now))
+						"id", "firebase_uid", "email", "name", "picture_url", "plan_type", "plan_id", "premium_since", "plan_expiry", "plan_cancelled", "password_hash", "email_verified", "disabled_at", "created_at", "updated_at",
+					}).AddRow("user-id", "firebase-uid", "test@example.com", "Test User", "", "free", "", now, now, false, "", true, nil, now, now))
 			},
 		},
 		{
 			name: "db error",
 			setupMock: func(mock sqlmock.Sqlmock) {
 				mock.ExpectQuery(regexp.QuoteMeta(`
-					SELECT id, firebase_uid, email, name, picture_url, plan_type, premium_since, plan_expiry, created_at, updated_at FROM users WHERE id=$1
+					SELECT id, COALESCE(firebase_uid, ''), email, name, picture_url, plan_type, COALESCE(plan_id::text, ''), premium_since, plan_expiry, plan_cancelled, COALESCE(password_hash, ''), email_verified, disabled_at, created_at, updated_at FROM users WHERE id=$1 AND deleted_at IS NULL
 				`)).
 					WithArgs("user-id").
 					WillReturnError(sql.ErrNoRows)
@@ -171,7 +187,7 @@ func TestUserPostgres_FindByID(t *testing.T) {
 	}
 }
 
-func TestUserPostgres_FindByFirebaseUID(t *testing.T) {
+func TestUserPostgres_FindByEmail(t *testing.T) {
 	now := time.Now()
 	tests := []struct {
 		name      string
@@ -182,21 +198,21 @@ func TestUserPostgres_FindByFirebaseUID(t *testing.T) {
 			name: "found",
 			setupMock: func(mock sqlmock.Sqlmock) {
 				mock.ExpectQuery(regexp.QuoteMeta(`
-					SELECT id, firebase_uid, email, name, picture_url, plan_type, premium_since, plan_expiry, created_at, updated_at FROM users WHERE firebase_uid=$1
+					SELECT id, COALESCE(firebase_uid, ''), email, name, picture_url, plan_type, COALESCE(plan_id::text, ''), premium_since, plan_expiry, plan_cancelled, COALESCE(password_hash, ''), email_verified, disabled_at, created_at, updated_at FROM users WHERE email=$1 AND deleted_at IS NULL
 				`)).
-					WithArgs("firebase-uid").
+					WithArgs("test@example.com").
 					WillReturnRows(sqlmock.NewRows([]string{
-						"id", "firebase_uid", "email", "name", "picture_url", "plan_type", "premium_since", "plan_expiry", "created_at", "updated_at",
-					}).AddRow("user-id", "firebase-uid", "test@example.com", "Test User", "", "free", now, now, now, now))
+						"id", "firebase_uid", "email", "name", "picture_url", "plan_type", "plan_id", "premium_since", "plan_expiry", "plan_cancelled", "password_hash", "email_verified", "disabled_at", "created_at", "updated_at",
+					}).AddRow("user-id", "firebase-uid", "test@example.com", "Test User", "", "free", "", now, now, false, "", true, nil, now, now))
 			},
 		},
 		{
 			name: "db error",
 			setupMock: func(mock sqlmock.Sqlmock) {
 				mock.ExpectQuery(regexp.QuoteMeta(`
-					SELECT id, firebase_uid, email, name, picture_url, plan_type, premium_since, plan_expiry, created_at, updated_at FROM users WHERE firebase_uid=$1
+					SELECT id, COALESCE(firebase_uid, ''), email, name, picture_url, plan_type, COALESCE(plan_id::text, ''), premium_since, plan_expiry, plan_cancelled, COALESCE(password_hash, ''), email_verified, disabled_at, created_at, updated_at FROM users WHERE email=$1 AND deleted_at IS NULL
 				`)).
-					WithArgs("firebase-uid").
+					WithArgs("test@example.com").
 					WillReturnError(sql.ErrNoRows)
 			},
 			wantErr: true,
@@ -210,7 +226,7 @@ func TestUserPostgres_FindByFirebaseUID(t *testing.T) {
 			tt.setupMock(mock)
 
 			repo := postgres.NewUserPostgres(db)
-			_, err := repo.FindByFirebaseUID(context.Background(), "firebase-uid")
+			_, err := repo.FindByEmail(context.Background(), "test@example.com")
 			if tt.wantErr {
 				assert.Error(t, err)
 			} else {
@@ -221,33 +237,26 @@ func TestUserPostgres_FindByFirebaseUID(t *testing.T) {
 	}
 }
 
-func TestUserPostgres_FindByEmail(t *testing.T) {
-	now := time.Now()
+func TestUserPostgres_MarkEmailVerified(t *testing.T) {
 	tests := []struct {
 		name      string
 		setupMock func(sqlmock.Sqlmock)
 		wantErr   bool
 	}{
 		{
-			name: "found",
+			name: "success",
 			setupMock: func(mock sqlmock.Sqlmock) {
-				mock.ExpectQuery(regexp.QuoteMeta(`
-					SELECT id, firebase_uid, email, name, picture_url, plan_type, premium_since, plan_expiry, created_at, updated_at FROM users WHERE email=$1
-				`)).
-					WithArgs("test@example.com").
-					WillReturnRows(sqlmock.NewRows([]string{
-						"id", "firebase_uid", "email", "name", "picture_url", "plan_type", "premium_since", "plan_expiry", "created_at", "updated_at",
-					}).AddRow("user-id", "firebase-uid", "test@example.com", "Test User", "", "free", now, now, now, now))
+				mock.ExpectExec(regexp.QuoteMeta(`UPDATE users SET email_verified=TRUE, updated_at=NOW() WHERE id=$1`)).
+					WithArgs("user-id").
+					WillReturnResult(sqlmock.NewResult(0, 1))
 			},
 		},
 		{
 			name: "db error",
 			setupMock: func(mock sqlmock.Sqlmock) {
-				mock.ExpectQuery(regexp.QuoteMeta(`
-					SELECT id, firebase_uid, email, name, picture_url, plan_type, premium_since, plan_expiry, created_at, updated_at FROM users WHERE email=$1
-				`)).
-					WithArgs("test@example.com").
-					WillReturnError(sql.ErrNoRows)
+				mock.ExpectExec(regexp.QuoteMeta(`UPDATE users SET email_verified=TRUE, updated_at=NOW() WHERE id=$1`)).
+					WithArgs("user-id").
+					WillReturnError(sql.ErrConnDone)
 			},
 			wantErr: true,
 		},
@@ -260,7 +269,7 @@ func TestUserPostgres_FindByEmail(t *testing.T) {
 			tt.setupMock(mock)
 
 			repo := postgres.NewUserPostgres(db)
-			_, err := repo.FindByEmail(context.Background(), "test@example.com")
+			err := repo.MarkEmailVerified(context.Background(), "user-id")
 			if tt.wantErr {
 				assert.Error(t, err)
 			} else {
@@ -270,3 +279,278 @@ func TestUserPostgres_FindByEmail(t *testing.T) {
 		})
 	}
 }
+
+func TestUserPostgres_ClearPassword(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE users SET password_hash=NULL, updated_at=NOW() WHERE id=$1`)).
+		WithArgs("user-id").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := postgres.NewUserPostgres(db)
+	err := repo.ClearPassword(context.Background(), "user-id")
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserPostgres_SetDisabled(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name       string
+		disabledAt *time.Time
+	}{
+		{"disable", &now},
+		{"enable", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+
+			mock.ExpectExec(regexp.QuoteMeta(`UPDATE users SET disabled_at=$1, updated_at=NOW() WHERE id=$2`)).
+				WithArgs(tt.disabledAt, "user-id").
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			repo := postgres.NewUserPostgres(db)
+			assert.NoError(t, repo.SetDisabled(context.Background(), "user-id", tt.disabledAt))
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestUserPostgres_UpdatePlan(t *testing.T) {
+	expiry := time.Now().Add(30 * 24 * time.Hour)
+	since := time.Now()
+	user := &domain.User{ID: "user-id", PlanType: domain.PlanPremium, PlanID: "premium-id", PremiumSince: &since, PlanExpiry: &expiry, PlanCancelled: true}
+
+	tests := []struct {
+		name     string
+		affected int64
+		wantErr  error
+	}{
+		{name: "success", affected: 1},
+		{name: "unknown or deleted user", affected: 0, wantErr: domain.ErrUserNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+
+			mock.ExpectExec(regexp.QuoteMeta(`
+				UPDATE users SET plan_type=$1, plan_id=NULLIF($2, '')::uuid, premium_since=$3, plan_expiry=$4, plan_cancelled=$5, updated_at=NOW()
+				WHERE id=$6 AND deleted_at IS NULL
+			`)).
+				WithArgs("premium", "premium-id", &since, &expiry, true, "user-id").
+				WillReturnResult(sqlmock.NewResult(0, tt.affected))
+
+			repo := postgres.NewUserPostgres(db)
+			err := repo.UpdatePlan(context.Background(), user)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestUserPostgres_ListLapsedPlans(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	now := time.Now()
+	graceEnd := now.Add(-72 * time.Hour)
+	expired := now.Add(-time.Hour)
+	mock.ExpectQuery(regexp.QuoteMeta(userListSelect+`
+		WHERE deleted_at IS NULL AND plan_expiry IS NOT NULL
+		AND ((plan_cancelled AND plan_expiry < $1) OR plan_expiry < $2)
+		ORDER BY plan_expiry, id LIMIT $3
+	`)).
+		WithArgs(now, graceEnd, 100).
+		WillReturnRows(sqlmock.NewRows(userListColumns).
+			AddRow("user-1", "", "a@example.com", "A", "", "premium", "premium-id", now, expired, true, "", true, nil, now, now))
+
+	repo := postgres.NewUserPostgres(db)
+	users, err := repo.ListLapsedPlans(context.Background(), now, graceEnd, 100)
+
+	assert.NoError(t, err)
+	if assert.Len(t, users, 1) {
+		assert.True(t, users[0].PlanCancelled)
+		assert.Equal(t, "premium-id", users[0].PlanID)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserPostgres_SoftDelete(t *testing.T) {
+	deletedAt := time.Now()
+
+	t.Run("hides the user and unlinks the identities", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE users SET deleted_at=$1, updated_at=NOW() WHERE id=$2 AND deleted_at IS NULL`)).
+			WithArgs(deletedAt, "user-id").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM user_identities WHERE user_id=$1`)).
+			WithArgs("user-id").
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectCommit()
+
+		repo := postgres.NewUserPostgres(db)
+		assert.NoError(t, repo.SoftDelete(context.Background(), "user-id", deletedAt))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("rolls back on failure", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE users SET deleted_at=$1`)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM user_identities`)).
+			WillReturnError(sql.ErrConnDone)
+		mock.ExpectRollback()
+
+		repo := postgres.NewUserPostgres(db)
+		assert.ErrorIs(t, repo.SoftDelete(context.Background(), "user-id", deletedAt), sql.ErrConnDone)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestUserPostgres_PurgeDeleted(t *testing.T) {
+	deletedBefore := time.Now().Add(-30 * 24 * time.Hour)
+	purgedEmails := `SELECT lower(u.email) FROM users u WHERE u.deleted_at < $1
+		AND NOT EXISTS (SELECT 1 FROM users a WHERE lower(a.email) = lower(u.email) AND a.deleted_at IS NULL)`
+
+	t.Run("deletes the rows keyed by e-mail along with the users", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM magic_links WHERE lower(email) IN (` + purgedEmails + `)`)).
+			WithArgs(deletedBefore).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM login_attempts WHERE key LIKE 'account:%' AND EXISTS (
+			SELECT 1 FROM (` + purgedEmails + `) e(email) WHERE right(key, length(e.email) + 1) = ':' || e.email)`)).
+			WithArgs(deletedBefore).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM users WHERE deleted_at < $1`)).
+			WithArgs(deletedBefore).
+			WillReturnResult(sqlmock.NewResult(0, 3))
+		mock.ExpectCommit()
+
+		repo := postgres.NewUserPostgres(db)
+		purged, err := repo.PurgeDeleted(context.Background(), deletedBefore)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), purged)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("failure keeps the users", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM magic_links`)).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM login_attempts`)).WillReturnError(sql.ErrConnDone)
+		mock.ExpectRollback()
+
+		repo := postgres.NewUserPostgres(db)
+		_, err := repo.PurgeDeleted(context.Background(), deletedBefore)
+		assert.ErrorIs(t, err, sql.ErrConnDone)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+var userListColumns = []string{
+	"id", "firebase_uid", "email", "name", "picture_url", "plan_type", "plan_id", "premium_since", "plan_expiry", "plan_cancelled", "password_hash", "email_verified", "disabled_at", "created_at", "updated_at",
+}
+
+const userListSelect = `SELECT id, COALESCE(firebase_uid, ''), email, name, picture_url, plan_type, COALESCE(plan_id::text, ''), premium_since, plan_expiry, plan_cancelled, COALESCE(password_hash, ''), email_verified, disabled_at, created_at, updated_at FROM users`
+
+func TestUserPostgres_List(t *testing.T) {
+	createdAfter := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	first := time.Date(2026, 3, 2, 10, 0, 0, 123456000, time.UTC)
+	second := first.Add(-time.Hour)
+
+	t.Run("filters and returns the cursor of the next page", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta(userListSelect+` WHERE deleted_at IS NULL AND plan_type=$1 AND email LIKE $2 AND created_at >= $3 ORDER BY created_at DESC, id DESC LIMIT $4`)).
+			WithArgs("premium", `ann\_%`, createdAfter, 2).
+			WillReturnRows(sqlmock.NewRows(userListColumns).
+				AddRow("user-1", "", "ann_1@example.com", "Ann", "", "premium", "", nil, nil, false, "", true, nil, first, first).
+				AddRow("user-2", "", "ann_2@example.com", "Ann", "", "premium", "", nil, nil, false, "", false, nil, second, second))
+
+		repo := postgres.NewUserPostgres(db)
+		users, next, err := repo.List(context.Background(), domain.UserFilter{
+			PlanType:     "premium",
+			EmailPrefix:  "ann_",
+			CreatedAfter: &createdAfter,
+			Limit:        1,
+		})
+
+		assert.NoError(t, err)
+		assert.Len(t, users, 1)
+		assert.Equal(t, "user-1", users[0].ID)
+		assert.NotEmpty(t, next)
+		assert.NoError(t, mock.ExpectationsWereMet())
+
+		// The cursor resumes after the last user of the page
+		mock.ExpectQuery(regexp.QuoteMeta(userListSelect+` WHERE deleted_at IS NULL AND (created_at, id) < ($1, $2) ORDER BY created_at DESC, id DESC LIMIT $3`)).
+			WithArgs(first, "user-1", 2).
+			WillReturnRows(sqlmock.NewRows(userListColumns).
+				AddRow("user-2", "", "ann_2@example.com", "Ann", "", "premium", "", nil, nil, false, "", false, nil, second, second))
+
+		users, next, err = repo.List(context.Background(), domain.UserFilter{Cursor: next, Limit: 1})
+		assert.NoError(t, err)
+		assert.Len(t, users, 1)
+		assert.Equal(t, "user-2", users[0].ID)
+		assert.Empty(t, next)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("sorts by email", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta(userListSelect + ` WHERE deleted_at IS NULL ORDER BY email ASC, id ASC LIMIT $1`)).
+			WithArgs(51).
+			WillReturnRows(sqlmock.NewRows(userListColumns))
+
+		repo := postgres.NewUserPostgres(db)
+		users, next, err := repo.List(context.Background(), domain.UserFilter{Sort: domain.UserSortEmailAsc, Limit: 50})
+		assert.NoError(t, err)
+		assert.Empty(t, users)
+		assert.Empty(t, next)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("rejects a cursor of another sort", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta(userListSelect)).
+			WillReturnRows(sqlmock.NewRows(userListColumns).
+				AddRow("user-1", "", "a@example.com", "A", "", "free", "", nil, nil, false, "", false, nil, first, first).
+				AddRow("user-2", "", "b@example.com", "B", "", "free", "", nil, nil, false, "", false, nil, second, second))
+
+		repo := postgres.NewUserPostgres(db)
+		_, next, err := repo.List(context.Background(), domain.UserFilter{Limit: 1})
+		assert.NoError(t, err)
+
+		_, _, err = repo.List(context.Background(), domain.UserFilter{Sort: domain.UserSortEmailAsc, Cursor: next, Limit: 1})
+		assert.ErrorIs(t, err, domain.ErrInvalidCursor)
+
+		_, _, err = repo.List(context.Background(), domain.UserFilter{Cursor: "not-a-cursor", Limit: 1})
+		assert.ErrorIs(t, err, domain.ErrInvalidCursor)
+	})
+}
@@ -0,0 +1,31 @@
+package services
+
+import (
+	"github.com/nuhorizon/go-project-template/services/template/internal/ports/services"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+type bcryptPasswordHasher struct {
+	cost int
+}
+
+// NewBcryptPasswordHasher falls back to bcrypt.DefaultCost when cost is out of bcrypt's range
+func NewBcryptPasswordHasher(cost int) services.PasswordHasher {
+	if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		cost = bcrypt.DefaultCost
+	}
+	return &bcryptPasswordHasher{cost: cost}
+}
+
+func (b *bcryptPasswordHasher) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), b.cost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+func (b *bcryptPasswordHasher) Compare(hash, password string) error {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+}
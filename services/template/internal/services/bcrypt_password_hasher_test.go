@@ -0,0 +1,31 @@
+package services_test
+
+import (
+	"testing"
+
+	internalservices "github.com/nuhorizon/go-project-template/services/template/internal/services"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestBcryptPasswordHasher(t *testing.T) {
+	hasher := internalservices.NewBcryptPasswordHasher(bcrypt.MinCost)
+
+	t.Run("hash and compare", func(t *testing.T) {
+		hash, err := hasher.Hash("correct-password")
+		assert.NoError(t, err)
+		assert.NotEqual(t, "correct-password", hash)
+
+		assert.NoError(t, hasher.Compare(hash, "correct-password"))
+		assert.Error(t, hasher.Compare(hash, "wrong-password"))
+	})
+
+	t.Run("invalid cost falls back to default", func(t *testing.T) {
+		hash, err := internalservices.NewBcryptPasswordHasher(0).Hash("password")
+		assert.NoError(t, err)
+
+		cost, err := bcrypt.Cost([]byte(hash))
+		assert.NoError(t, err)
+		assert.Equal(t, bcrypt.DefaultCost, cost)
+	})
+}
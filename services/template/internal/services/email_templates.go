@@ -0,0 +1,93 @@
+package services
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"mime"
+	"mime/multipart"
+	"net/textproto"
+	"strings"
+	"text/template"
+	"time"
+)
+
+//go:embed templates/email/*.tmpl
+var emailTemplates embed.FS
+
+// email is a rendered template: the text file defines the "subject" block and the
+// plain-text body, the optional html file the alternative HTML body
+type email struct {
+	Subject string
+	Text    string
+	HTML    string
+}
+
+func renderEmail(name string, data any) (*email, error) {
+	textTmpl, err := template.ParseFS(emailTemplates, "templates/email/"+name+".txt.tmpl")
+	if err != nil {
+		return nil, fmt.Errorf("email template %q: %w", name, err)
+	}
+
+	var subject, text bytes.Buffer
+	if err := textTmpl.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return nil, fmt.Errorf("email template %q subject: %w", name, err)
+	}
+	if err := textTmpl.Execute(&text, data); err != nil {
+		return nil, fmt.Errorf("email template %q text: %w", name, err)
+	}
+
+	rendered := &email{Subject: strings.TrimSpace(subject.String()), Text: text.String()}
+
+	htmlTmpl, err := htmltemplate.ParseFS(emailTemplates, "templates/email/"+name+".html.tmpl")
+	if err != nil {
+		// The HTML version is optional
+		return rendered, nil
+	}
+
+	var html bytes.Buffer
+	if err := htmlTmpl.Execute(&html, data); err != nil {
+		return nil, fmt.Errorf("email template %q html: %w", name, err)
+	}
+	rendered.HTML = html.String()
+
+	return rendered, nil
+}
+
+// buildMessage encodes the e-mail as an RFC 5322 message (multipart/alternative when there is an HTML body)
+func buildMessage(from, to string, e *email) ([]byte, error) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", to)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", e.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+
+	if e.HTML == "" {
+		buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+		buf.WriteString(e.Text)
+		return buf.Bytes(), nil
+	}
+
+	writer := multipart.NewWriter(&buf)
+	fmt.Fprintf(&buf, "Content-Type: multipart/alternative; boundary=%s\r\n\r\n", writer.Boundary())
+
+	for _, part := range []struct{ contentType, body string }{
+		{"text/plain; charset=utf-8", e.Text},
+		{"text/html; charset=utf-8", e.HTML},
+	} {
+		w, err := writer.CreatePart(textproto.MIMEHeader{"Content-Type": {part.contentType}})
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write([]byte(part.body)); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,43 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/ports/services"
+
+	"github.com/google/uuid"
+)
+
+type fileMailer struct {
+	dir  string
+	from string
+}
+
+// NewFileMailer writes every e-mail as an .eml file in dir instead of sending it,
+// for local development and tests
+func NewFileMailer(dir, from string) (services.Mailer, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &fileMailer{dir: dir, from: from}, nil
+}
+
+func (f *fileMailer) Send(ctx context.Context, to string, template string, data any) error {
+	rendered, err := renderEmail(template, data)
+	if err != nil {
+		return err
+	}
+
+	msg, err := buildMessage(f.from, to, rendered)
+	if err != nil {
+		return err
+	}
+
+	// Timestamp first so the files list in sending order
+	name := fmt.Sprintf("%s-%s-%s.eml", time.Now().Format("20060102T150405.000"), template, uuid.NewString()[:8])
+	return os.WriteFile(filepath.Join(f.dir, name), msg, 0o644)
+}
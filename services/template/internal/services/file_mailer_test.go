@@ -0,0 +1,92 @@
+package services_test
+
+import (
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	portservices "github.com/nuhorizon/go-project-template/services/template/internal/ports/services"
+	internalservices "github.com/nuhorizon/go-project-template/services/template/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileMailer_Send(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mail")
+	mailer, err := internalservices.NewFileMailer(dir, "no-reply@example.com")
+	require.NoError(t, err)
+
+	err = mailer.Send(context.Background(), "user@example.com", portservices.EmailTemplatePasswordReset, portservices.PasswordResetEmail{
+		Email: "user@example.com",
+		Link:  "https://example.com/reset?code=abc&x=1",
+	})
+	require.NoError(t, err)
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	raw, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+
+	msg, err := mail.ReadMessage(strings.NewReader(string(raw)))
+	require.NoError(t, err)
+	assert.Equal(t, "user@example.com", msg.Header.Get("To"))
+	assert.Equal(t, "no-reply@example.com", msg.Header.Get("From"))
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, "Redefinição de senha", subject)
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	assert.Equal(t, "multipart/alternative", mediaType)
+
+	parts := multipart.NewReader(msg.Body, params["boundary"])
+	text, err := parts.NextPart()
+	require.NoError(t, err)
+	textBody, _ := io.ReadAll(text)
+	assert.Contains(t, string(textBody), "https://example.com/reset?code=abc&x=1")
+
+	html, err := parts.NextPart()
+	require.NoError(t, err)
+	htmlBody, _ := io.ReadAll(html)
+	assert.Contains(t, html.Header.Get("Content-Type"), "text/html")
+	assert.Contains(t, string(htmlBody), `href="https://example.com/reset?code=abc&amp;x=1"`)
+}
+
+func TestFileMailer_SendMagicLink(t *testing.T) {
+	dir := t.TempDir()
+	mailer, err := internalservices.NewFileMailer(dir, "no-reply@example.com")
+	require.NoError(t, err)
+
+	err = mailer.Send(context.Background(), "user@example.com", portservices.EmailTemplateMagicLink, portservices.MagicLinkEmail{
+		Email:            "user@example.com",
+		Link:             "https://example.com/auth/magic-link/verify?token=abc",
+		ExpiresInMinutes: 15,
+	})
+	require.NoError(t, err)
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	raw, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	assert.Contains(t, string(raw), "https://example.com/auth/magic-link/verify?token=abc")
+	assert.Contains(t, string(raw), "15 minutos")
+}
+
+func TestFileMailer_UnknownTemplate(t *testing.T) {
+	mailer, err := internalservices.NewFileMailer(t.TempDir(), "no-reply@example.com")
+	require.NoError(t, err)
+
+	err = mailer.Send(context.Background(), "user@example.com", "does_not_exist", nil)
+	assert.Error(t, err)
+}
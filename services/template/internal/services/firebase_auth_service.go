@@ -5,14 +5,20 @@ import (
 	"errors"
 
 	"github.com/nuhorizon/go-project-template/services/template/internal/ports/services"
+
+	"firebase.google.com/go/v4/auth"
 )
 
 type firebaseAuthService struct {
-	client services.FirebaseClient
+	client        services.FirebaseClient
+	mailer        services.Mailer
+	resetSettings *auth.ActionCodeSettings
 }
 
-func NewFirebaseAuthService(client services.FirebaseClient) services.FirebaseAuthService {
-	return &firebaseAuthService{client: client}
+// NewFirebaseAuthService verifies Firebase ID tokens and delivers password reset links through mailer.
+// resetSettings customizes the reset link (continue URL, mobile apps); nil keeps the Firebase defaults.
+func NewFirebaseAuthService(client services.FirebaseClient, mailer services.Mailer, resetSettings *auth.ActionCodeSettings) services.FirebaseAuthService {
+	return &firebaseAuthService{client: client, mailer: mailer, resetSettings: resetSettings}
 }
 
 func (f *firebaseAuthService) VerifyToken(ctx context.Context, firebaseToken string) (*services.FirebaseUser, error) {
@@ -27,24 +33,37 @@ func (f *firebaseAuthService) VerifyToken(ctx context.Context, firebaseToken str
 		return nil, err
 	}
 
+	// The ID token is minted after the link in the Firebase verification e-mail is followed,
+	// it may know about it before a cached user record does
+	claimVerified, _ := token.Claims["email_verified"].(bool)
+
 	return &services.FirebaseUser{
-		UID:     userRecord.UID,
-		Email:   userRecord.Email,
-		Name:    userRecord.DisplayName,
-		Picture: userRecord.PhotoURL,
+		UID:           userRecord.UID,
+		Email:         userRecord.Email,
+		EmailVerified: userRecord.EmailVerified || claimVerified,
+		Name:          userRecord.DisplayName,
+		Picture:       userRecord.PhotoURL,
 	}, nil
 }
 
 func (f *firebaseAuthService) SendPasswordReset(ctx context.Context, email string) error {
-	link, err := f.client.PasswordResetLinkWithSettings(ctx, email, nil)
+	link, err := f.client.PasswordResetLinkWithSettings(ctx, email, f.resetSettings)
 	if err != nil {
 		return err
 	}
-	// Aqui você pode enviar o link por e-mail ou logar no sistema
 	if link == "" {
 		return errors.New("failed to generate password reset link")
 	}
-	// Exemplo de log (ou enviar e-mail real)
-	// log.Println("Password reset link:", link)
+
+	return f.mailer.Send(ctx, email, services.EmailTemplatePasswordReset, services.PasswordResetEmail{
+		Email: email,
+		Link:  link,
+	})
+}
+
+func (f *firebaseAuthService) DeleteUser(ctx context.Context, uid string) error {
+	if err := f.client.DeleteUser(ctx, uid); err != nil && !auth.IsUserNotFound(err) {
+		return err
+	}
 	return nil
 }
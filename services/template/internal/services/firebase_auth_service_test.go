@@ -34,16 +34,54 @@ func (m *MockFirebaseClient) PasswordResetLinkWithSettings(ctx context.Context,
 	return args.String(0), args.Error(1)
 }
 
+func (m *MockFirebaseClient) DeleteUser(ctx context.Context, uid string) error {
+	args := m.Called(ctx, uid)
+	return args.Error(0)
+}
+
+type MockMailer struct {
+	mock.Mock
+}
+
+func (m *MockMailer) Send(ctx context.Context, to string, template string, data any) error {
+	args := m.Called(ctx, to, template, data)
+	return args.Error(0)
+}
+
 func TestFirebaseAuthService_VerifyToken(t *testing.T) {
 	tests := []struct {
 		name           string
+		claims         map[string]interface{}
+		recordVerified bool
 		mockVerifyErr  error
 		mockUserErr    error
 		expectedErr    bool
 		expectedResult *portservices.FirebaseUser
 	}{
 		{
-			name: "success",
+			name:           "success",
+			recordVerified: true,
+			expectedResult: &portservices.FirebaseUser{
+				UID:           "user123",
+				Email:         "test@example.com",
+				EmailVerified: true,
+				Name:          "Test User",
+				Picture:       "http://pic.url",
+			},
+		},
+		{
+			name:   "email verified only in the token claims",
+			claims: map[string]interface{}{"email_verified": true},
+			expectedResult: &portservices.FirebaseUser{
+				UID:           "user123",
+				Email:         "test@example.com",
+				EmailVerified: true,
+				Name:          "Test User",
+				Picture:       "http://pic.url",
+			},
+		},
+		{
+			name: "email not verified",
 			expectedResult: &portservices.FirebaseUser{
 				UID:     "user123",
 				Email:   "test@example.com",
@@ -70,7 +108,7 @@ func TestFirebaseAuthService_VerifyToken(t *testing.T) {
 
 			if tt.mockVerifyErr == nil {
 				mockClient.On("VerifyIDToken", ctx, "valid-token").
-					Return(&auth.Token{UID: "user123"}, nil)
+					Return(&auth.Token{UID: "user123", Claims: tt.claims}, nil)
 			} else {
 				mockClient.On("VerifyIDToken", ctx, "valid-token").
 					Return((*auth.Token)(nil), tt.mockVerifyErr)
@@ -85,13 +123,14 @@ func TestFirebaseAuthService_VerifyToken(t *testing.T) {
 							DisplayName: "Test User",
 							PhotoURL:    "http://pic.url",
 						},
+						EmailVerified: tt.recordVerified,
 					}, nil)
 				} else {
 					mockClient.On("GetUser", ctx, "user123").Return((*auth.UserRecord)(nil), tt.mockUserErr)
 				}
 			}
 
-			service := internalservices.NewFirebaseAuthService(mockClient)
+			service := internalservices.NewFirebaseAuthService(mockClient, new(MockMailer), nil)
 
 			user, err := service.VerifyToken(ctx, "valid-token")
 			if tt.expectedErr {
@@ -108,15 +147,20 @@ func TestFirebaseAuthService_VerifyToken(t *testing.T) {
 }
 
 func TestFirebaseAuthService_SendPasswordReset(t *testing.T) {
+	settings := &auth.ActionCodeSettings{URL: "https://app.example.com/login"}
+
 	tests := []struct {
 		name        string
 		mockLink    string
 		mockError   error
+		sendErr     error
+		expectSend  bool
 		expectedErr bool
 	}{
 		{
-			name:     "success",
-			mockLink: "https://reset.link",
+			name:       "success",
+			mockLink:   "https://reset.link",
+			expectSend: true,
 		},
 		{
 			name:        "firebase error",
@@ -128,17 +172,31 @@ func TestFirebaseAuthService_SendPasswordReset(t *testing.T) {
 			mockLink:    "",
 			expectedErr: true,
 		},
+		{
+			name:        "mailer error",
+			mockLink:    "https://reset.link",
+			sendErr:     errors.New("smtp error"),
+			expectSend:  true,
+			expectedErr: true,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockClient := new(MockFirebaseClient)
+			mockMailer := new(MockMailer)
 			ctx := context.Background()
 
-			mockClient.On("PasswordResetLinkWithSettings", ctx, "test@example.com", (*auth.ActionCodeSettings)(nil)).
+			mockClient.On("PasswordResetLinkWithSettings", ctx, "test@example.com", settings).
 				Return(tt.mockLink, tt.mockError)
+			if tt.expectSend {
+				mockMailer.On("Send", ctx, "test@example.com", portservices.EmailTemplatePasswordReset, portservices.PasswordResetEmail{
+					Email: "test@example.com",
+					Link:  tt.mockLink,
+				}).Return(tt.sendErr)
+			}
 
-			service := internalservices.NewFirebaseAuthService(mockClient)
+			service := internalservices.NewFirebaseAuthService(mockClient, mockMailer, settings)
 
 			err := service.SendPasswordReset(ctx, "test@example.com")
 			if tt.expectedErr {
@@ -147,6 +205,36 @@ func TestFirebaseAuthService_SendPasswordReset(t *testing.T) {
 				assert.NoError(t, err)
 			}
 			mockClient.AssertExpectations(t)
+			mockMailer.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFirebaseAuthService_DeleteUser(t *testing.T) {
+	tests := []struct {
+		name        string
+		mockErr     error
+		expectedErr bool
+	}{
+		{name: "success"},
+		{name: "client error", mockErr: errors.New("firebase unavailable"), expectedErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockFirebaseClient)
+			ctx := context.Background()
+			mockClient.On("DeleteUser", ctx, "user123").Return(tt.mockErr)
+
+			service := internalservices.NewFirebaseAuthService(mockClient, new(MockMailer), nil)
+
+			err := service.DeleteUser(ctx, "user123")
+			if tt.expectedErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			mockClient.AssertExpectations(t)
 		})
 	}
 }
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// KeyManifestFile lists, next to the keys, when each key becomes active. It maps the kid to an
+// RFC 3339 time, e.g. {"2026-01": "2026-01-01T00:00:00Z"}. File times change when keys are copied
+// or restored, so they are never used to order rotations.
+const KeyManifestFile = "keys.json"
+
+// SigningKey is a private key loaded from the key directory. The kid is the file name
+// without extension and CreatedAt is its activation time from the manifest, used to order rotations.
+type SigningKey struct {
+	Kid       string
+	Method    jwt.SigningMethod
+	Private   crypto.Signer
+	CreatedAt time.Time
+}
+
+// LoadSigningKeys reads every *.pem private key (PKCS#8 or PKCS#1) from dir along with the
+// KeyManifestFile, which must list every key. RSA keys sign with RS256 and Ed25519 keys with EdDSA.
+func LoadSigningKeys(dir string) ([]*SigningKey, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no *.pem keys found in %s", dir)
+	}
+
+	manifest, err := loadKeyManifest(filepath.Join(dir, KeyManifestFile))
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]*SigningKey, 0, len(paths))
+	for _, path := range paths {
+		kid := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		createdAt, ok := manifest[kid]
+		if !ok {
+			return nil, fmt.Errorf("key %q is missing from %s", kid, KeyManifestFile)
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		signer, method, err := parsePrivateKey(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+		}
+
+		keys = append(keys, &SigningKey{
+			Kid:       kid,
+			Method:    method,
+			Private:   signer,
+			CreatedAt: createdAt,
+		})
+	}
+
+	return keys, nil
+}
+
+// loadKeyManifest reads the activation time of each kid
+func loadKeyManifest(path string) (map[string]time.Time, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := map[string]time.Time{}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	return manifest, nil
+}
+
+func parsePrivateKey(data []byte) (crypto.Signer, jwt.SigningMethod, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, nil, errors.New("invalid PEM data")
+	}
+
+	var parsed any
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, nil, errors.New("unsupported private key format")
+		}
+	}
+
+	switch key := parsed.(type) {
+	case *rsa.PrivateKey:
+		return key, jwt.SigningMethodRS256, nil
+	case ed25519.PrivateKey:
+		return key, jwt.SigningMethodEdDSA, nil
+	default:
+		return nil, nil, fmt.Errorf("unsupported key type %T", parsed)
+	}
+}
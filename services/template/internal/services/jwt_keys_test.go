@@ -0,0 +1,95 @@
+package services_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	internalservices "github.com/nuhorizon/go-project-template/services/template/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writePEM(t *testing.T, path, blockType string, der []byte) {
+	data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	require.NoError(t, os.WriteFile(path, data, 0600))
+}
+
+func writeManifest(t *testing.T, dir, manifest string) {
+	require.NoError(t, os.WriteFile(filepath.Join(dir, internalservices.KeyManifestFile), []byte(manifest), 0600))
+}
+
+func TestLoadSigningKeys(t *testing.T) {
+	t.Run("loads RSA and Ed25519 keys", func(t *testing.T) {
+		dir := t.TempDir()
+
+		rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+		writePEM(t, filepath.Join(dir, "rsa-key.pem"), "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey))
+
+		_, edKey, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		der, err := x509.MarshalPKCS8PrivateKey(edKey)
+		require.NoError(t, err)
+		writePEM(t, filepath.Join(dir, "ed-key.pem"), "PRIVATE KEY", der)
+		writeManifest(t, dir, `{"rsa-key": "2026-01-01T00:00:00Z", "ed-key": "2026-02-01T00:00:00Z"}`)
+
+		keys, err := internalservices.LoadSigningKeys(dir)
+		require.NoError(t, err)
+		require.Len(t, keys, 2)
+
+		algs := map[string]string{}
+		createdAt := map[string]time.Time{}
+		for _, key := range keys {
+			algs[key.Kid] = key.Method.Alg()
+			createdAt[key.Kid] = key.CreatedAt
+		}
+		assert.Equal(t, map[string]string{"rsa-key": "RS256", "ed-key": "EdDSA"}, algs)
+		assert.Equal(t, time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC), createdAt["ed-key"].UTC())
+	})
+
+	t.Run("key missing from the manifest", func(t *testing.T) {
+		dir := t.TempDir()
+		_, edKey, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		der, err := x509.MarshalPKCS8PrivateKey(edKey)
+		require.NoError(t, err)
+		writePEM(t, filepath.Join(dir, "ed-key.pem"), "PRIVATE KEY", der)
+		writeManifest(t, dir, `{"other-key": "2026-01-01T00:00:00Z"}`)
+
+		_, err = internalservices.LoadSigningKeys(dir)
+		assert.ErrorContains(t, err, "ed-key")
+	})
+
+	t.Run("no manifest", func(t *testing.T) {
+		dir := t.TempDir()
+		_, edKey, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		der, err := x509.MarshalPKCS8PrivateKey(edKey)
+		require.NoError(t, err)
+		writePEM(t, filepath.Join(dir, "ed-key.pem"), "PRIVATE KEY", der)
+
+		_, err = internalservices.LoadSigningKeys(dir)
+		assert.Error(t, err)
+	})
+
+	t.Run("empty directory", func(t *testing.T) {
+		_, err := internalservices.LoadSigningKeys(t.TempDir())
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid PEM", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "broken.pem"), []byte("not a key"), 0600))
+		writeManifest(t, dir, `{"broken": "2026-01-01T00:00:00Z"}`)
+
+		_, err := internalservices.LoadSigningKeys(dir)
+		assert.Error(t, err)
+	})
+}
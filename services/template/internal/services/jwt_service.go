@@ -2,36 +2,255 @@ package services
 
 import (
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
 	"github.com/nuhorizon/go-project-template/services/template/internal/ports/services"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
 type jwtService struct {
 	secretKey string
+	issuer    string
+	audience  string
+
+	// Asymmetric mode (see NewKeySetJWTService), pinnedKey is set by signingKid
+	keys        map[string]*SigningKey
+	pinnedKey   *SigningKey
+	gracePeriod time.Duration
+}
+
+// NewJWTService signs and validates tokens with a shared HS256 secret. Every token carries the
+// issuer and audience as its iss and aud claims, and tokens without them are rejected.
+func NewJWTService(secretKey, issuer, audience string) services.JWTService {
+	return &jwtService{secretKey: secretKey, issuer: issuer, audience: audience}
 }
 
-func NewJWTService(secretKey string) services.JWTService {
-	return &jwtService{secretKey: secretKey}
+// NewKeySetJWTService signs with the key identified by signingKid, or else with the newest key
+// already active (CreatedAt not after now), and sets it as the kid header. Keys are published as
+// soon as they are loaded, so a key activating later is known to the verifiers before it signs.
+// Keys older than the signing key are considered rotated out and keep verifying tokens for
+// gracePeriod after the signing key became active. The issuer and audience are handled as in NewJWTService.
+func NewKeySetJWTService(keys []*SigningKey, signingKid string, gracePeriod time.Duration, issuer, audience string) (services.JWTService, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("at least one signing key is required")
+	}
+
+	s := &jwtService{
+		issuer:      issuer,
+		audience:    audience,
+		keys:        make(map[string]*SigningKey, len(keys)),
+		gracePeriod: gracePeriod,
+	}
+	for _, key := range keys {
+		if _, exists := s.keys[key.Kid]; exists {
+			return nil, fmt.Errorf("duplicate key id %q", key.Kid)
+		}
+		s.keys[key.Kid] = key
+	}
+
+	if signingKid != "" {
+		s.pinnedKey = s.keys[signingKid]
+		if s.pinnedKey == nil {
+			return nil, fmt.Errorf("signing key %q not found", signingKid)
+		}
+	} else if s.signingKey(time.Now()) == nil {
+		return nil, errors.New("no signing key is active yet")
+	}
+
+	return s, nil
 }
 
-func (j *jwtService) GenerateToken(user *domain.User) (string, error) {
+// signingKey returns the key that signs at now: the pinned key, or else the newest active key.
+// Nil in HS256 mode and before any key is active.
+func (j *jwtService) signingKey(now time.Time) *SigningKey {
+	if j.pinnedKey != nil {
+		return j.pinnedKey
+	}
+
+	var signing *SigningKey
+	for _, key := range j.keys {
+		if key.CreatedAt.After(now) {
+			continue
+		}
+		if signing == nil || key.CreatedAt.After(signing.CreatedAt) {
+			signing = key
+		}
+	}
+	return signing
+}
+
+func (j *jwtService) GenerateToken(user *domain.User, sessionID string) (string, error) {
 	claims := jwt.MapClaims{
-		"user_id":   user.ID,
-		"plan_type": user.PlanType,
-		"exp":       domain.TokenExpiry(),
+		"user_id":        user.ID,
+		"plan_type":      user.PlanType,
+		"roles":          user.Roles,
+		"permissions":    user.Permissions,
+		"jti":            uuid.NewString(),
+		"sid":            sessionID,
+		"email_verified": user.EmailVerified,
+		"iat":            time.Now().Unix(),
+		"exp":            domain.TokenExpiry(),
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(j.secretKey))
+	return j.sign(claims)
 }
 
-func (j *jwtService) ValidateToken(tokenStr string) (*domain.User, error) {
-	token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
-		return []byte(j.secretKey), nil
+func (j *jwtService) ValidateToken(tokenStr string) (*domain.TokenClaims, error) {
+	claims, err := j.parse(tokenStr)
+	if err != nil {
+		return nil, err
+	}
+
+	// Typed tokens (mfa_pending, magic_link, email_verification) never grant access to the API
+	if typ, _ := claims["typ"].(string); typ != "" {
+		return nil, errors.New("invalid token type")
+	}
+
+	// Recupera o user_id do token
+	userID, ok := claims["user_id"].(string)
+	if !ok {
+		return nil, errors.New("user_id missing in token")
+	}
+
+	result := &domain.TokenClaims{UserID: userID}
+	result.PlanType, _ = claims["plan_type"].(string)
+	result.Roles = stringSlice(claims["roles"])
+	result.Permissions = stringSlice(claims["permissions"])
+	result.TokenID, _ = claims["jti"].(string)
+	result.SessionID, _ = claims["sid"].(string)
+	result.EmailVerified, _ = claims["email_verified"].(bool)
+	if iat, err := claims.GetIssuedAt(); err == nil && iat != nil {
+		result.IssuedAt = iat.Time
+	}
+	if exp, err := claims.GetExpirationTime(); err == nil && exp != nil {
+		result.ExpiresAt = exp.Time
+	}
+
+	return result, nil
+}
+
+func (j *jwtService) GenerateMFAToken(userID string) (string, error) {
+	now := time.Now()
+	return j.sign(jwt.MapClaims{
+		"user_id": userID,
+		"typ":     domain.TokenTypeMFAPending,
+		"jti":     uuid.NewString(),
+		"iat":     now.Unix(),
+		"exp":     now.Add(domain.MFATokenTTL).Unix(),
 	})
+}
+
+func (j *jwtService) ValidateMFAToken(tokenStr string) (*domain.TokenClaims, error) {
+	claims, err := j.parse(tokenStr)
+	if err != nil {
+		return nil, err
+	}
+
+	if typ, _ := claims["typ"].(string); typ != domain.TokenTypeMFAPending {
+		return nil, errors.New("invalid token type")
+	}
+
+	result := &domain.TokenClaims{}
+	result.UserID, _ = claims["user_id"].(string)
+	result.TokenID, _ = claims["jti"].(string)
+	if result.UserID == "" || result.TokenID == "" {
+		return nil, errors.New("user_id or jti missing in token")
+	}
+	if iat, err := claims.GetIssuedAt(); err == nil && iat != nil {
+		result.IssuedAt = iat.Time
+	}
+	if exp, err := claims.GetExpirationTime(); err == nil && exp != nil {
+		result.ExpiresAt = exp.Time
+	}
+	return result, nil
+}
+
+func (j *jwtService) GenerateMagicLinkToken(linkID, email string, expiresAt time.Time) (string, error) {
+	return j.sign(jwt.MapClaims{
+		"email": email,
+		"typ":   domain.TokenTypeMagicLink,
+		"jti":   linkID,
+		"iat":   time.Now().Unix(),
+		"exp":   expiresAt.Unix(),
+	})
+}
+
+func (j *jwtService) ValidateMagicLinkToken(tokenStr string) (string, string, error) {
+	claims, err := j.parse(tokenStr)
+	if err != nil {
+		return "", "", err
+	}
+
+	if typ, _ := claims["typ"].(string); typ != domain.TokenTypeMagicLink {
+		return "", "", errors.New("invalid token type")
+	}
+
+	linkID, _ := claims["jti"].(string)
+	email, _ := claims["email"].(string)
+	if linkID == "" || email == "" {
+		return "", "", errors.New("jti or email missing in token")
+	}
+	return linkID, email, nil
+}
+
+func (j *jwtService) GenerateEmailVerificationToken(userID, email string) (string, error) {
+	now := time.Now()
+	return j.sign(jwt.MapClaims{
+		"user_id": userID,
+		"email":   email,
+		"typ":     domain.TokenTypeEmailVerification,
+		"jti":     uuid.NewString(),
+		"iat":     now.Unix(),
+		"exp":     now.Add(domain.EmailVerificationTTL).Unix(),
+	})
+}
+
+func (j *jwtService) ValidateEmailVerificationToken(tokenStr string) (string, string, error) {
+	claims, err := j.parse(tokenStr)
+	if err != nil {
+		return "", "", err
+	}
+
+	if typ, _ := claims["typ"].(string); typ != domain.TokenTypeEmailVerification {
+		return "", "", errors.New("invalid token type")
+	}
+
+	userID, _ := claims["user_id"].(string)
+	email, _ := claims["email"].(string)
+	if userID == "" || email == "" {
+		return "", "", errors.New("user_id or email missing in token")
+	}
+	return userID, email, nil
+}
+
+func (j *jwtService) sign(claims jwt.MapClaims) (string, error) {
+	claims["iss"] = j.issuer
+	claims["aud"] = j.audience
+
+	if j.keys == nil {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+		return token.SignedString([]byte(j.secretKey))
+	}
+
+	key := j.signingKey(time.Now())
+	if key == nil {
+		return "", errors.New("no signing key is active")
+	}
+	token := jwt.NewWithClaims(key.Method, claims)
+	token.Header["kid"] = key.Kid
+	return token.SignedString(key.Private)
+}
+
+func (j *jwtService) parse(tokenStr string) (jwt.MapClaims, error) {
+	token, err := jwt.Parse(tokenStr, j.keyFunc,
+		jwt.WithValidMethods(j.validMethods()),
+		jwt.WithIssuer(j.issuer),
+		jwt.WithAudience(j.audience),
+	)
 
 	if err != nil || !token.Valid {
 		return nil, errors.New("invalid token")
@@ -41,12 +260,74 @@ func (j *jwtService) ValidateToken(tokenStr string) (*domain.User, error) {
 	if !ok {
 		return nil, errors.New("invalid token claims")
 	}
+	return claims, nil
+}
 
-	// Recupera o user_id do token
-	userID, ok := claims["user_id"].(string)
+// stringSlice converts a decoded JSON array claim, ignoring non-string entries
+func stringSlice(claim interface{}) []string {
+	values, ok := claim.([]interface{})
 	if !ok {
-		return nil, errors.New("user_id missing in token")
+		return nil
+	}
+
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		if s, ok := v.(string); ok {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+func (j *jwtService) PublicKeys() []services.PublicKey {
+	if j.keys == nil {
+		return nil
+	}
+
+	now := time.Now()
+	keys := make([]services.PublicKey, 0, len(j.keys))
+	for _, key := range j.keys {
+		if j.retired(key, now) {
+			continue
+		}
+		keys = append(keys, services.PublicKey{
+			Kid:       key.Kid,
+			Algorithm: key.Method.Alg(),
+			Key:       key.Private.Public(),
+		})
+	}
+	return keys
+}
+
+func (j *jwtService) keyFunc(token *jwt.Token) (interface{}, error) {
+	if j.keys == nil {
+		return []byte(j.secretKey), nil
 	}
 
-	return &domain.User{ID: userID}, nil
+	kid, _ := token.Header["kid"].(string)
+	key, ok := j.keys[kid]
+	if !ok || j.retired(key, time.Now()) {
+		return nil, errors.New("unknown or retired key id")
+	}
+	if key.Method.Alg() != token.Method.Alg() {
+		return nil, errors.New("algorithm does not match key")
+	}
+	return key.Private.Public(), nil
+}
+
+func (j *jwtService) validMethods() []string {
+	if j.keys == nil {
+		return []string{jwt.SigningMethodHS256.Alg()}
+	}
+	return []string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg()}
+}
+
+// retired reports whether a rotated-out key is past its verification grace period at now, which
+// starts when the key signing at now became active
+func (j *jwtService) retired(key *SigningKey, now time.Time) bool {
+	signing := j.signingKey(now)
+	if signing == nil || key == signing || !key.CreatedAt.Before(signing.CreatedAt) {
+		return false
+	}
+	return now.After(signing.CreatedAt.Add(j.gracePeriod))
 }
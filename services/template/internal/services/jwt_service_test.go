@@ -1,6 +1,8 @@
 package services_test
 
 import (
+	"crypto/ed25519"
+	"crypto/rand"
 	"testing"
 	"time"
 
@@ -10,21 +12,102 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-const testSecretKey = "supersecretkey"
+const (
+	testSecretKey = "supersecretkey"
+	testIssuer    = "catwise-api"
+	testAudience  = "catwise-app"
+)
 
 func TestJWTService_GenerateAndValidateToken(t *testing.T) {
-	jwtService := internalservices.NewJWTService(testSecretKey)
+	jwtService := internalservices.NewJWTService(testSecretKey, testIssuer, testAudience)
 
 	t.Run("successfully generates and validates token", func(t *testing.T) {
 		user := &domain.User{ID: "user-123", PlanType: "premium"}
 
-		tokenStr, err := jwtService.GenerateToken(user)
+		tokenStr, err := jwtService.GenerateToken(user, "")
 		assert.NoError(t, err)
 		assert.NotEmpty(t, tokenStr)
 
-		validatedUser, err := jwtService.ValidateToken(tokenStr)
+		claims, err := jwtService.ValidateToken(tokenStr)
+		assert.NoError(t, err)
+		assert.Equal(t, user.ID, claims.UserID)
+		assert.Equal(t, "premium", claims.PlanType)
+		assert.NotEmpty(t, claims.TokenID)
+		assert.WithinDuration(t, time.Now(), claims.IssuedAt, 5*time.Second)
+		assert.True(t, claims.ExpiresAt.After(time.Now()))
+	})
+
+	t.Run("carries roles and permissions", func(t *testing.T) {
+		user := &domain.User{
+			ID:          "user-123",
+			Roles:       []string{domain.RoleAdmin},
+			Permissions: []string{domain.PermissionUsersRead, domain.PermissionSessionsRevoke},
+		}
+
+		tokenStr, err := jwtService.GenerateToken(user, "")
+		assert.NoError(t, err)
+
+		claims, err := jwtService.ValidateToken(tokenStr)
+		assert.NoError(t, err)
+		assert.Equal(t, user.Roles, claims.Roles)
+		assert.Equal(t, user.Permissions, claims.Permissions)
+	})
+
+	t.Run("carries the email verified flag", func(t *testing.T) {
+		tokenStr, err := jwtService.GenerateToken(&domain.User{ID: "user-123", EmailVerified: true}, "")
+		assert.NoError(t, err)
+
+		claims, err := jwtService.ValidateToken(tokenStr)
+		assert.NoError(t, err)
+		assert.True(t, claims.EmailVerified)
+	})
+
+	t.Run("issues a distinct jti per token", func(t *testing.T) {
+		user := &domain.User{ID: "user-123"}
+		first, _ := jwtService.GenerateToken(user, "")
+		second, _ := jwtService.GenerateToken(user, "")
+
+		firstClaims, err := jwtService.ValidateToken(first)
+		assert.NoError(t, err)
+		secondClaims, err := jwtService.ValidateToken(second)
 		assert.NoError(t, err)
-		assert.Equal(t, user.ID, validatedUser.ID)
+		assert.NotEqual(t, firstClaims.TokenID, secondClaims.TokenID)
+	})
+
+	t.Run("carries the session id", func(t *testing.T) {
+		tokenStr, err := jwtService.GenerateToken(&domain.User{ID: "user-123"}, "session-1")
+		assert.NoError(t, err)
+
+		claims, err := jwtService.ValidateToken(tokenStr)
+		assert.NoError(t, err)
+		assert.Equal(t, "session-1", claims.SessionID)
+	})
+
+	t.Run("carries the issuer and audience", func(t *testing.T) {
+		tokenStr, err := jwtService.GenerateToken(&domain.User{ID: "user-123"}, "")
+		assert.NoError(t, err)
+
+		parsed, _, err := jwt.NewParser().ParseUnverified(tokenStr, jwt.MapClaims{})
+		assert.NoError(t, err)
+		issuer, _ := parsed.Claims.GetIssuer()
+		audience, _ := parsed.Claims.GetAudience()
+		assert.Equal(t, testIssuer, issuer)
+		assert.Equal(t, jwt.ClaimStrings{testAudience}, audience)
+	})
+
+	t.Run("rejects tokens of another issuer or audience", func(t *testing.T) {
+		user := &domain.User{ID: "user-123"}
+		otherIssuer, _ := internalservices.NewJWTService(testSecretKey, "other-api", testAudience).GenerateToken(user, "")
+		otherAudience, _ := internalservices.NewJWTService(testSecretKey, testIssuer, "other-app").GenerateToken(user, "")
+		without, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"user_id": "user-123",
+			"exp":     time.Now().Add(time.Hour).Unix(),
+		}).SignedString([]byte(testSecretKey))
+
+		for _, tokenStr := range []string{otherIssuer, otherAudience, without} {
+			_, err := jwtService.ValidateToken(tokenStr)
+			assert.Error(t, err)
+		}
 	})
 
 	t.Run("fails validation with invalid token", func(t *testing.T) {
@@ -38,6 +121,8 @@ func TestJWTService_GenerateAndValidateToken(t *testing.T) {
 	t.Run("fails validation if user_id missing in token", func(t *testing.T) {
 		claims := jwt.MapClaims{
 			"plan_type": "premium",
+			"iss":       testIssuer,
+			"aud":       testAudience,
 			"exp":       time.Now().Add(1 * time.Hour).Unix(),
 		}
 		token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
@@ -48,3 +133,259 @@ func TestJWTService_GenerateAndValidateToken(t *testing.T) {
 		assert.Nil(t, user)
 	})
 }
+
+func TestJWTService_MFAToken(t *testing.T) {
+	jwtService := internalservices.NewJWTService(testSecretKey, testIssuer, testAudience)
+
+	t.Run("round trips the user id and jti", func(t *testing.T) {
+		tokenStr, err := jwtService.GenerateMFAToken("user-123")
+		assert.NoError(t, err)
+
+		claims, err := jwtService.ValidateMFAToken(tokenStr)
+		assert.NoError(t, err)
+		assert.Equal(t, "user-123", claims.UserID)
+		assert.NotEmpty(t, claims.TokenID)
+		assert.WithinDuration(t, time.Now().Add(domain.MFATokenTTL), claims.ExpiresAt, 5*time.Second)
+	})
+
+	t.Run("is not accepted as an access token", func(t *testing.T) {
+		tokenStr, _ := jwtService.GenerateMFAToken("user-123")
+
+		claims, err := jwtService.ValidateToken(tokenStr)
+		assert.Error(t, err)
+		assert.Nil(t, claims)
+	})
+
+	t.Run("an access token cannot complete the second factor", func(t *testing.T) {
+		tokenStr, _ := jwtService.GenerateToken(&domain.User{ID: "user-123"}, "")
+
+		_, err := jwtService.ValidateMFAToken(tokenStr)
+		assert.Error(t, err)
+	})
+
+	t.Run("expires", func(t *testing.T) {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"user_id": "user-123",
+			"typ":     domain.TokenTypeMFAPending,
+			"iss":     testIssuer,
+			"aud":     testAudience,
+			"exp":     time.Now().Add(-time.Minute).Unix(),
+		})
+		tokenStr, _ := token.SignedString([]byte(testSecretKey))
+
+		_, err := jwtService.ValidateMFAToken(tokenStr)
+		assert.Error(t, err)
+	})
+
+	t.Run("needs a jti to be used once", func(t *testing.T) {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"user_id": "user-123",
+			"typ":     domain.TokenTypeMFAPending,
+			"iss":     testIssuer,
+			"aud":     testAudience,
+			"exp":     time.Now().Add(time.Minute).Unix(),
+		})
+		tokenStr, _ := token.SignedString([]byte(testSecretKey))
+
+		_, err := jwtService.ValidateMFAToken(tokenStr)
+		assert.Error(t, err)
+	})
+}
+
+func TestJWTService_MagicLinkToken(t *testing.T) {
+	jwtService := internalservices.NewJWTService(testSecretKey, testIssuer, testAudience)
+
+	t.Run("round trips the link id and email", func(t *testing.T) {
+		tokenStr, err := jwtService.GenerateMagicLinkToken("link-1", "user@example.com", time.Now().Add(domain.MagicLinkTTL))
+		assert.NoError(t, err)
+
+		linkID, email, err := jwtService.ValidateMagicLinkToken(tokenStr)
+		assert.NoError(t, err)
+		assert.Equal(t, "link-1", linkID)
+		assert.Equal(t, "user@example.com", email)
+	})
+
+	t.Run("is not accepted as an access or mfa token", func(t *testing.T) {
+		tokenStr, _ := jwtService.GenerateMagicLinkToken("link-1", "user@example.com", time.Now().Add(domain.MagicLinkTTL))
+
+		claims, err := jwtService.ValidateToken(tokenStr)
+		assert.Error(t, err)
+		assert.Nil(t, claims)
+
+		_, err = jwtService.ValidateMFAToken(tokenStr)
+		assert.Error(t, err)
+	})
+
+	t.Run("an mfa token is not a magic link", func(t *testing.T) {
+		tokenStr, _ := jwtService.GenerateMFAToken("user-123")
+
+		_, _, err := jwtService.ValidateMagicLinkToken(tokenStr)
+		assert.Error(t, err)
+	})
+
+	t.Run("expires", func(t *testing.T) {
+		tokenStr, _ := jwtService.GenerateMagicLinkToken("link-1", "user@example.com", time.Now().Add(-time.Minute))
+
+		_, _, err := jwtService.ValidateMagicLinkToken(tokenStr)
+		assert.Error(t, err)
+	})
+}
+
+func TestJWTService_EmailVerificationToken(t *testing.T) {
+	jwtService := internalservices.NewJWTService(testSecretKey, testIssuer, testAudience)
+
+	t.Run("round trips the user id and email", func(t *testing.T) {
+		tokenStr, err := jwtService.GenerateEmailVerificationToken("user-123", "user@example.com")
+		assert.NoError(t, err)
+
+		userID, email, err := jwtService.ValidateEmailVerificationToken(tokenStr)
+		assert.NoError(t, err)
+		assert.Equal(t, "user-123", userID)
+		assert.Equal(t, "user@example.com", email)
+	})
+
+	t.Run("is not accepted as an access or magic link token", func(t *testing.T) {
+		tokenStr, _ := jwtService.GenerateEmailVerificationToken("user-123", "user@example.com")
+
+		claims, err := jwtService.ValidateToken(tokenStr)
+		assert.Error(t, err)
+		assert.Nil(t, claims)
+
+		_, _, err = jwtService.ValidateMagicLinkToken(tokenStr)
+		assert.Error(t, err)
+	})
+
+	t.Run("a magic link is not a verification link", func(t *testing.T) {
+		tokenStr, _ := jwtService.GenerateMagicLinkToken("link-1", "user@example.com", time.Now().Add(domain.MagicLinkTTL))
+
+		_, _, err := jwtService.ValidateEmailVerificationToken(tokenStr)
+		assert.Error(t, err)
+	})
+
+	t.Run("expires", func(t *testing.T) {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"user_id": "user-123",
+			"email":   "user@example.com",
+			"typ":     domain.TokenTypeEmailVerification,
+			"iss":     testIssuer,
+			"aud":     testAudience,
+			"exp":     time.Now().Add(-time.Minute).Unix(),
+		})
+		tokenStr, _ := token.SignedString([]byte(testSecretKey))
+
+		_, _, err := jwtService.ValidateEmailVerificationToken(tokenStr)
+		assert.Error(t, err)
+	})
+}
+
+func newTestSigningKey(t *testing.T, kid string, createdAt time.Time) *internalservices.SigningKey {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	return &internalservices.SigningKey{Kid: kid, Method: jwt.SigningMethodEdDSA, Private: private, CreatedAt: createdAt}
+}
+
+func TestJWTService_KeySet(t *testing.T) {
+	now := time.Now()
+	user := &domain.User{ID: "user-123", PlanType: "premium"}
+
+	t.Run("signs with the newest key and sets kid", func(t *testing.T) {
+		oldKey := newTestSigningKey(t, "2025-01", now.Add(-2*time.Hour))
+		newKey := newTestSigningKey(t, "2025-02", now.Add(-time.Hour))
+
+		jwtService, err := internalservices.NewKeySetJWTService([]*internalservices.SigningKey{oldKey, newKey}, "", 24*time.Hour, testIssuer, testAudience)
+		assert.NoError(t, err)
+
+		tokenStr, err := jwtService.GenerateToken(user, "")
+		assert.NoError(t, err)
+
+		parsed, _, err := jwt.NewParser().ParseUnverified(tokenStr, jwt.MapClaims{})
+		assert.NoError(t, err)
+		assert.Equal(t, "2025-02", parsed.Header["kid"])
+		assert.Equal(t, "EdDSA", parsed.Method.Alg())
+
+		claims, err := jwtService.ValidateToken(tokenStr)
+		assert.NoError(t, err)
+		assert.Equal(t, user.ID, claims.UserID)
+		assert.Len(t, jwtService.PublicKeys(), 2)
+	})
+
+	t.Run("a key published ahead signs once it becomes active", func(t *testing.T) {
+		currentKey := newTestSigningKey(t, "current", now.Add(-time.Hour))
+		nextKey := newTestSigningKey(t, "next", time.Now().Add(50*time.Millisecond))
+
+		jwtService, err := internalservices.NewKeySetJWTService([]*internalservices.SigningKey{currentKey, nextKey}, "", time.Hour, testIssuer, testAudience)
+		assert.NoError(t, err)
+		assert.Len(t, jwtService.PublicKeys(), 2)
+
+		tokenStr, err := jwtService.GenerateToken(user, "")
+		assert.NoError(t, err)
+		parsed, _, err := jwt.NewParser().ParseUnverified(tokenStr, jwt.MapClaims{})
+		assert.NoError(t, err)
+		assert.Equal(t, "current", parsed.Header["kid"])
+
+		time.Sleep(60 * time.Millisecond)
+		rotated, err := jwtService.GenerateToken(user, "")
+		assert.NoError(t, err)
+		parsed, _, err = jwt.NewParser().ParseUnverified(rotated, jwt.MapClaims{})
+		assert.NoError(t, err)
+		assert.Equal(t, "next", parsed.Header["kid"])
+
+		// The previous key is within the grace period that started with the rotation
+		_, err = jwtService.ValidateToken(tokenStr)
+		assert.NoError(t, err)
+	})
+
+	t.Run("no key active yet", func(t *testing.T) {
+		_, err := internalservices.NewKeySetJWTService([]*internalservices.SigningKey{newTestSigningKey(t, "next", now.Add(time.Hour))}, "", time.Hour, testIssuer, testAudience)
+		assert.Error(t, err)
+	})
+
+	t.Run("rotated key verifies during grace period only", func(t *testing.T) {
+		oldKey := newTestSigningKey(t, "old", now.Add(-72*time.Hour))
+		currentKey := newTestSigningKey(t, "current", now.Add(-time.Hour))
+
+		// Token signed before the rotation
+		before, err := internalservices.NewKeySetJWTService([]*internalservices.SigningKey{oldKey}, "", time.Hour, testIssuer, testAudience)
+		assert.NoError(t, err)
+		tokenStr, err := before.GenerateToken(user, "")
+		assert.NoError(t, err)
+
+		withinGrace, err := internalservices.NewKeySetJWTService([]*internalservices.SigningKey{oldKey, currentKey}, "current", 24*time.Hour, testIssuer, testAudience)
+		assert.NoError(t, err)
+		_, err = withinGrace.ValidateToken(tokenStr)
+		assert.NoError(t, err)
+
+		pastGrace, err := internalservices.NewKeySetJWTService([]*internalservices.SigningKey{oldKey, currentKey}, "current", 30*time.Minute, testIssuer, testAudience)
+		assert.NoError(t, err)
+		_, err = pastGrace.ValidateToken(tokenStr)
+		assert.Error(t, err)
+
+		keys := pastGrace.PublicKeys()
+		assert.Len(t, keys, 1)
+		assert.Equal(t, "current", keys[0].Kid)
+	})
+
+	t.Run("rejects tokens without a known kid", func(t *testing.T) {
+		key := newTestSigningKey(t, "current", now)
+		jwtService, err := internalservices.NewKeySetJWTService([]*internalservices.SigningKey{key}, "", time.Hour, testIssuer, testAudience)
+		assert.NoError(t, err)
+
+		hsToken, _ := internalservices.NewJWTService(testSecretKey, testIssuer, testAudience).GenerateToken(user, "")
+		_, err = jwtService.ValidateToken(hsToken)
+		assert.Error(t, err)
+	})
+
+	t.Run("unknown signing kid", func(t *testing.T) {
+		_, err := internalservices.NewKeySetJWTService([]*internalservices.SigningKey{newTestSigningKey(t, "a", now)}, "b", time.Hour, testIssuer, testAudience)
+		assert.Error(t, err)
+	})
+
+	t.Run("no keys", func(t *testing.T) {
+		_, err := internalservices.NewKeySetJWTService(nil, "", time.Hour, testIssuer, testAudience)
+		assert.Error(t, err)
+	})
+
+	t.Run("HS256 publishes no keys", func(t *testing.T) {
+		assert.Empty(t, internalservices.NewJWTService(testSecretKey, testIssuer, testAudience).PublicKeys())
+	})
+}
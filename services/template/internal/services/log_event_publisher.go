@@ -0,0 +1,40 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/internal/ports/services"
+)
+
+type logEventPublisher struct {
+	logger *log.Logger
+}
+
+// NewLogEventPublisher writes each subscription event as a JSON line to the given logger
+// (the standard logger when nil)
+func NewLogEventPublisher(logger *log.Logger) services.SubscriptionEventPublisher {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return &logEventPublisher{logger: logger}
+}
+
+func (l *logEventPublisher) Publish(ctx context.Context, event domain.SubscriptionEvent) error {
+	payload, err := json.Marshal(map[string]any{
+		"type":          event.Type,
+		"user_id":       event.UserID,
+		"plan":          event.Plan,
+		"previous_plan": event.PreviousPlan,
+		"plan_expiry":   event.PlanExpiry,
+		"occurred_at":   event.OccurredAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	l.logger.Printf("📣 event %s", payload)
+	return nil
+}
@@ -0,0 +1,32 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	internalservices "github.com/nuhorizon/go-project-template/services/template/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogEventPublisher_Publish(t *testing.T) {
+	var buf bytes.Buffer
+	publisher := internalservices.NewLogEventPublisher(log.New(&buf, "", 0))
+
+	err := publisher.Publish(context.Background(), domain.SubscriptionEvent{
+		Type:         domain.SubscriptionExpired,
+		UserID:       "user-id",
+		Plan:         domain.PlanFree,
+		PreviousPlan: domain.PlanPremium,
+		OccurredAt:   time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	})
+
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), `"type":"subscription.expired"`)
+	assert.Contains(t, buf.String(), `"user_id":"user-id"`)
+	assert.Contains(t, buf.String(), `"previous_plan":"premium"`)
+	assert.Contains(t, buf.String(), `"occurred_at":"2026-01-02T03:04:05Z"`)
+}
@@ -0,0 +1,30 @@
+package services
+
+import (
+	"context"
+	"log"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/ports/services"
+)
+
+type logMailer struct {
+	logger *log.Logger
+}
+
+// NewLogMailer prints e-mails to the given logger (the standard logger when nil) instead of sending them
+func NewLogMailer(logger *log.Logger) services.Mailer {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return &logMailer{logger: logger}
+}
+
+func (l *logMailer) Send(ctx context.Context, to string, template string, data any) error {
+	rendered, err := renderEmail(template, data)
+	if err != nil {
+		return err
+	}
+
+	l.logger.Printf("📧 e-mail %q to %s\nSubject: %s\n\n%s", template, to, rendered.Subject, rendered.Text)
+	return nil
+}
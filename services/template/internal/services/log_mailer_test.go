@@ -0,0 +1,27 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"testing"
+
+	portservices "github.com/nuhorizon/go-project-template/services/template/internal/ports/services"
+	internalservices "github.com/nuhorizon/go-project-template/services/template/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogMailer_Send(t *testing.T) {
+	var buf bytes.Buffer
+	mailer := internalservices.NewLogMailer(log.New(&buf, "", 0))
+
+	err := mailer.Send(context.Background(), "user@example.com", portservices.EmailTemplatePasswordReset, portservices.PasswordResetEmail{
+		Email: "user@example.com",
+		Link:  "https://example.com/reset",
+	})
+
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), "user@example.com")
+	assert.Contains(t, buf.String(), "Subject: Redefinição de senha")
+	assert.Contains(t, buf.String(), "https://example.com/reset")
+}
@@ -0,0 +1,279 @@
+package services
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/ports/services"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// jwksMinRefreshInterval limits JWKS refetches triggered by tokens with an unknown kid
+const jwksMinRefreshInterval = 30 * time.Second
+
+// OIDCConfig describes an OpenID Connect provider (Keycloak, Auth0, Google, ...)
+type OIDCConfig struct {
+	Name         string
+	IssuerURL    string
+	Audiences    []string      // Accepted aud values (the client IDs of our apps)
+	JWKSCacheTTL time.Duration // Defaults to one hour
+	HTTPClient   *http.Client  // Defaults to a client with a 10s timeout
+}
+
+type oidcDiscovery struct {
+	Issuer  string `json:"issuer"`
+	JWKSURI string `json:"jwks_uri"`
+}
+
+type oidcIdentityProvider struct {
+	config OIDCConfig
+
+	mu            sync.Mutex
+	discovery     *oidcDiscovery
+	keys          map[string]crypto.PublicKey
+	keysFetchedAt time.Time
+}
+
+// NewOIDCIdentityProvider verifies ID tokens against the issuer's discovery document and JWKS,
+// both fetched lazily on the first login and the keys cached for JWKSCacheTTL
+func NewOIDCIdentityProvider(config OIDCConfig) (services.IdentityProvider, error) {
+	if config.Name == "" || config.IssuerURL == "" {
+		return nil, errors.New("oidc provider name and issuer URL are required")
+	}
+	if len(config.Audiences) == 0 {
+		return nil, fmt.Errorf("oidc provider %q: at least one audience is required", config.Name)
+	}
+	if config.JWKSCacheTTL <= 0 {
+		config.JWKSCacheTTL = time.Hour
+	}
+	if config.HTTPClient == nil {
+		config.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	config.IssuerURL = strings.TrimSuffix(config.IssuerURL, "/")
+
+	return &oidcIdentityProvider{config: config}, nil
+}
+
+func (p *oidcIdentityProvider) Name() string {
+	return p.config.Name
+}
+
+func (p *oidcIdentityProvider) VerifyToken(ctx context.Context, tokenStr string) (*services.ExternalIdentity, error) {
+	discovery, err := p.loadDiscovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(tokenStr, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return p.key(ctx, discovery, kid)
+	},
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "PS256", "ES256", "ES384", "EdDSA"}),
+		jwt.WithIssuer(discovery.Issuer),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(30*time.Second),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s token: %w", p.config.Name, err)
+	}
+
+	audiences, _ := claims.GetAudience()
+	if !slices.ContainsFunc(audiences, func(aud string) bool { return slices.Contains(p.config.Audiences, aud) }) {
+		return nil, fmt.Errorf("invalid %s token: audience not accepted", p.config.Name)
+	}
+
+	subject, _ := claims.GetSubject()
+	if subject == "" {
+		return nil, fmt.Errorf("invalid %s token: missing sub", p.config.Name)
+	}
+
+	identity := &services.ExternalIdentity{
+		Provider:      p.config.Name,
+		Subject:       subject,
+		EmailVerified: boolClaim(claims["email_verified"]),
+	}
+	identity.Email, _ = claims["email"].(string)
+	identity.Name, _ = claims["name"].(string)
+	identity.Picture, _ = claims["picture"].(string)
+
+	return identity, nil
+}
+
+func (p *oidcIdentityProvider) loadDiscovery(ctx context.Context) (*oidcDiscovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	var discovery oidcDiscovery
+	if err := p.getJSON(ctx, p.config.IssuerURL+"/.well-known/openid-configuration", &discovery); err != nil {
+		return nil, fmt.Errorf("oidc discovery for %q: %w", p.config.Name, err)
+	}
+	if strings.TrimSuffix(discovery.Issuer, "/") != p.config.IssuerURL {
+		return nil, fmt.Errorf("oidc discovery for %q: issuer %q does not match %q", p.config.Name, discovery.Issuer, p.config.IssuerURL)
+	}
+	if discovery.JWKSURI == "" {
+		return nil, fmt.Errorf("oidc discovery for %q: missing jwks_uri", p.config.Name)
+	}
+
+	p.discovery = &discovery
+	return p.discovery, nil
+}
+
+// key returns the verification key for kid, refreshing the cached JWKS when it expired
+// or when the kid is unknown (the provider rotated its keys)
+func (p *oidcIdentityProvider) key(ctx context.Context, discovery *oidcDiscovery, kid string) (crypto.PublicKey, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	age := time.Since(p.keysFetchedAt)
+	key, known := p.keys[kid]
+	if age > p.config.JWKSCacheTTL || (!known && age > jwksMinRefreshInterval) {
+		var set jwkSet
+		if err := p.getJSON(ctx, discovery.JWKSURI, &set); err != nil {
+			if known {
+				// Keep verifying with the stale keys while the provider is unreachable
+				return key, nil
+			}
+			return nil, fmt.Errorf("fetching %s JWKS: %w", p.config.Name, err)
+		}
+		p.keys = set.publicKeys()
+		p.keysFetchedAt = time.Now()
+		key, known = p.keys[kid]
+	}
+
+	if !known {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+	return key, nil
+}
+
+func (p *oidcIdentityProvider) getJSON(ctx context.Context, url string, dst any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := p.config.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: unexpected status %d", url, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(dst)
+}
+
+type jwkSet struct {
+	Keys []jwk `json:"keys"`
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// publicKeys decodes the signing keys of the set, skipping encryption and unsupported keys
+func (s jwkSet) publicKeys() map[string]crypto.PublicKey {
+	keys := make(map[string]crypto.PublicKey, len(s.Keys))
+	for _, k := range s.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		if key, err := k.publicKey(); err == nil {
+			keys[k.Kid] = key
+		}
+	}
+	return keys
+}
+
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid Ed25519 key")
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+// boolClaim accepts both JSON booleans and the "true"/"false" strings some providers send
+func boolClaim(claim interface{}) bool {
+	switch v := claim.(type) {
+	case bool:
+		return v
+	case string:
+		return v == "true"
+	default:
+		return false
+	}
+}
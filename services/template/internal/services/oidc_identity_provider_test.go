@@ -0,0 +1,262 @@
+package services_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	internalservices "github.com/nuhorizon/go-project-template/services/template/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeOIDCIssuer serves a discovery document and a JWKS with the given keys
+type fakeOIDCIssuer struct {
+	server     *httptest.Server
+	jwks       atomic.Value // []map[string]string
+	jwksCalls  atomic.Int32
+	rsaKey     *rsa.PrivateKey
+	ecKey      *ecdsa.PrivateKey
+	edKey      ed25519.PrivateKey
+	rotatedKey *rsa.PrivateKey
+}
+
+func newFakeOIDCIssuer(t *testing.T) *fakeOIDCIssuer {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	rotatedKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	f := &fakeOIDCIssuer{rsaKey: rsaKey, ecKey: ecKey, edKey: edKey, rotatedKey: rotatedKey}
+	f.jwks.Store([]map[string]string{
+		rsaJWK("rsa-1", &rsaKey.PublicKey),
+		{"kty": "EC", "kid": "ec-1", "crv": "P-256", "x": b64(ecKey.PublicKey.X.Bytes()), "y": b64(ecKey.PublicKey.Y.Bytes())},
+		{"kty": "OKP", "kid": "ed-1", "crv": "Ed25519", "x": b64(edKey.Public().(ed25519.PublicKey))},
+		rsaJWK("enc-1", &rsaKey.PublicKey, "enc"),
+	})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":   f.server.URL,
+			"jwks_uri": f.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		f.jwksCalls.Add(1)
+		json.NewEncoder(w).Encode(map[string]any{"keys": f.jwks.Load()})
+	})
+	f.server = httptest.NewServer(mux)
+	t.Cleanup(f.server.Close)
+
+	return f
+}
+
+func (f *fakeOIDCIssuer) sign(t *testing.T, method jwt.SigningMethod, kid string, key any, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+	return signed
+}
+
+func (f *fakeOIDCIssuer) claims(overrides jwt.MapClaims) jwt.MapClaims {
+	claims := jwt.MapClaims{
+		"iss":            f.server.URL,
+		"aud":            "mobile-app",
+		"sub":            "subject-1",
+		"email":          "user@example.com",
+		"email_verified": true,
+		"name":           "Test User",
+		"exp":            time.Now().Add(time.Hour).Unix(),
+		"iat":            time.Now().Unix(),
+	}
+	for k, v := range overrides {
+		claims[k] = v
+	}
+	return claims
+}
+
+func rsaJWK(kid string, key *rsa.PublicKey, use ...string) map[string]string {
+	jwk := map[string]string{"kty": "RSA", "kid": kid, "n": b64(key.N.Bytes()), "e": b64(big.NewInt(int64(key.E)).Bytes())}
+	if len(use) > 0 {
+		jwk["use"] = use[0]
+	}
+	return jwk
+}
+
+func b64(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func TestNewOIDCIdentityProvider_Validation(t *testing.T) {
+	_, err := internalservices.NewOIDCIdentityProvider(internalservices.OIDCConfig{IssuerURL: "https://issuer"})
+	assert.Error(t, err)
+
+	_, err = internalservices.NewOIDCIdentityProvider(internalservices.OIDCConfig{Name: "keycloak", IssuerURL: "https://issuer"})
+	assert.Error(t, err, "audience is required")
+}
+
+func TestOIDCIdentityProvider_VerifyToken(t *testing.T) {
+	issuer := newFakeOIDCIssuer(t)
+
+	provider, err := internalservices.NewOIDCIdentityProvider(internalservices.OIDCConfig{
+		Name:      "keycloak",
+		IssuerURL: issuer.server.URL + "/",
+		Audiences: []string{"web-app", "mobile-app"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "keycloak", provider.Name())
+
+	tests := []struct {
+		name    string
+		token   func() string
+		wantErr bool
+	}{
+		{
+			name: "RS256",
+			token: func() string {
+				return issuer.sign(t, jwt.SigningMethodRS256, "rsa-1", issuer.rsaKey, issuer.claims(nil))
+			},
+		},
+		{
+			name: "ES256",
+			token: func() string {
+				return issuer.sign(t, jwt.SigningMethodES256, "ec-1", issuer.ecKey, issuer.claims(nil))
+			},
+		},
+		{
+			name: "EdDSA",
+			token: func() string {
+				return issuer.sign(t, jwt.SigningMethodEdDSA, "ed-1", issuer.edKey, issuer.claims(nil))
+			},
+		},
+		{
+			name: "audience in a list",
+			token: func() string {
+				return issuer.sign(t, jwt.SigningMethodRS256, "rsa-1", issuer.rsaKey, issuer.claims(jwt.MapClaims{"aud": []string{"other", "web-app"}}))
+			},
+		},
+		{
+			name: "wrong audience",
+			token: func() string {
+				return issuer.sign(t, jwt.SigningMethodRS256, "rsa-1", issuer.rsaKey, issuer.claims(jwt.MapClaims{"aud": "other-app"}))
+			},
+			wantErr: true,
+		},
+		{
+			name: "wrong issuer",
+			token: func() string {
+				return issuer.sign(t, jwt.SigningMethodRS256, "rsa-1", issuer.rsaKey, issuer.claims(jwt.MapClaims{"iss": "https://evil.example.com"}))
+			},
+			wantErr: true,
+		},
+		{
+			name: "expired",
+			token: func() string {
+				return issuer.sign(t, jwt.SigningMethodRS256, "rsa-1", issuer.rsaKey, issuer.claims(jwt.MapClaims{"exp": time.Now().Add(-time.Hour).Unix()}))
+			},
+			wantErr: true,
+		},
+		{
+			name: "signed by another key",
+			token: func() string {
+				return issuer.sign(t, jwt.SigningMethodRS256, "rsa-1", issuer.rotatedKey, issuer.claims(nil))
+			},
+			wantErr: true,
+		},
+		{
+			name: "encryption key is not used for signatures",
+			token: func() string {
+				return issuer.sign(t, jwt.SigningMethodRS256, "enc-1", issuer.rsaKey, issuer.claims(nil))
+			},
+			wantErr: true,
+		},
+		{
+			name: "missing subject",
+			token: func() string {
+				return issuer.sign(t, jwt.SigningMethodRS256, "rsa-1", issuer.rsaKey, issuer.claims(jwt.MapClaims{"sub": ""}))
+			},
+			wantErr: true,
+		},
+		{
+			name:    "malformed",
+			token:   func() string { return "not-a-jwt" },
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			identity, err := provider.VerifyToken(context.Background(), tt.token())
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Nil(t, identity)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, "keycloak", identity.Provider)
+			assert.Equal(t, "subject-1", identity.Subject)
+			assert.Equal(t, "user@example.com", identity.Email)
+			assert.True(t, identity.EmailVerified)
+			assert.Equal(t, "Test User", identity.Name)
+		})
+	}
+
+	assert.Equal(t, int32(1), issuer.jwksCalls.Load(), "JWKS should be cached between verifications")
+}
+
+func TestOIDCIdentityProvider_KeyRotation(t *testing.T) {
+	issuer := newFakeOIDCIssuer(t)
+
+	provider, err := internalservices.NewOIDCIdentityProvider(internalservices.OIDCConfig{
+		Name:         "auth0",
+		IssuerURL:    issuer.server.URL,
+		Audiences:    []string{"mobile-app"},
+		JWKSCacheTTL: time.Millisecond,
+	})
+	require.NoError(t, err)
+
+	_, err = provider.VerifyToken(context.Background(), issuer.sign(t, jwt.SigningMethodRS256, "rsa-1", issuer.rsaKey, issuer.claims(nil)))
+	require.NoError(t, err)
+
+	// The provider publishes a new key; the expired cache is refreshed on the next login
+	issuer.jwks.Store([]map[string]string{rsaJWK("rsa-2", &issuer.rotatedKey.PublicKey)})
+	time.Sleep(5 * time.Millisecond)
+
+	identity, err := provider.VerifyToken(context.Background(), issuer.sign(t, jwt.SigningMethodRS256, "rsa-2", issuer.rotatedKey, issuer.claims(jwt.MapClaims{"email_verified": "true"})))
+	require.NoError(t, err)
+	assert.True(t, identity.EmailVerified)
+	assert.Equal(t, int32(2), issuer.jwksCalls.Load())
+}
+
+func TestOIDCIdentityProvider_DiscoveryIssuerMismatch(t *testing.T) {
+	issuer := newFakeOIDCIssuer(t)
+
+	provider, err := internalservices.NewOIDCIdentityProvider(internalservices.OIDCConfig{
+		Name:      "google",
+		IssuerURL: issuer.server.URL + "/realms/other",
+		Audiences: []string{"mobile-app"},
+	})
+	require.NoError(t, err)
+
+	_, err = provider.VerifyToken(context.Background(), issuer.sign(t, jwt.SigningMethodRS256, "rsa-1", issuer.rsaKey, issuer.claims(nil)))
+	assert.Error(t, err)
+}
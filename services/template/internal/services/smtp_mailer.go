@@ -0,0 +1,47 @@
+package services
+
+import (
+	"context"
+	"net"
+	"net/smtp"
+	"strconv"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/ports/services"
+)
+
+// SMTPConfig holds the SMTP relay settings. Authentication is skipped when Username is empty.
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+type smtpMailer struct {
+	config SMTPConfig
+}
+
+func NewSMTPMailer(config SMTPConfig) services.Mailer {
+	return &smtpMailer{config: config}
+}
+
+func (s *smtpMailer) Send(ctx context.Context, to string, template string, data any) error {
+	rendered, err := renderEmail(template, data)
+	if err != nil {
+		return err
+	}
+
+	msg, err := buildMessage(s.config.From, to, rendered)
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if s.config.Username != "" {
+		auth = smtp.PlainAuth("", s.config.Username, s.config.Password, s.config.Host)
+	}
+
+	addr := net.JoinHostPort(s.config.Host, strconv.Itoa(s.config.Port))
+	return smtp.SendMail(addr, auth, s.config.From, []string{to}, msg)
+}
@@ -0,0 +1,100 @@
+package services_test
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	portservices "github.com/nuhorizon/go-project-template/services/template/internal/ports/services"
+	internalservices "github.com/nuhorizon/go-project-template/services/template/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSMTPServer accepts a single session and returns the recipients and DATA it received
+func fakeSMTPServer(t *testing.T) (int, <-chan []string, <-chan string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	rcpts := make(chan []string, 1)
+	data := make(chan string, 1)
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		r := bufio.NewReader(conn)
+		reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+		reply("220 localhost ESMTP")
+
+		var to []string
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			cmd := strings.ToUpper(strings.TrimSpace(line))
+			switch {
+			case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+				reply("250 localhost")
+			case strings.HasPrefix(cmd, "RCPT TO:"):
+				to = append(to, strings.Trim(strings.TrimSpace(line)[len("RCPT TO:"):], "<>"))
+				reply("250 OK")
+			case cmd == "DATA":
+				reply("354 End data with <CR><LF>.<CR><LF>")
+				var body strings.Builder
+				for {
+					l, err := r.ReadString('\n')
+					if err != nil || l == ".\r\n" {
+						break
+					}
+					body.WriteString(l)
+				}
+				rcpts <- to
+				data <- body.String()
+				reply("250 OK")
+			case cmd == "QUIT":
+				reply("221 Bye")
+				return
+			default:
+				reply("250 OK")
+			}
+		}
+	}()
+
+	return listener.Addr().(*net.TCPAddr).Port, rcpts, data
+}
+
+func TestSMTPMailer_Send(t *testing.T) {
+	port, rcpts, data := fakeSMTPServer(t)
+
+	mailer := internalservices.NewSMTPMailer(internalservices.SMTPConfig{
+		Host: "127.0.0.1",
+		Port: port,
+		From: "no-reply@example.com",
+	})
+
+	err := mailer.Send(context.Background(), "user@example.com", portservices.EmailTemplatePasswordReset, portservices.PasswordResetEmail{
+		Email: "user@example.com",
+		Link:  "https://example.com/reset",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"user@example.com"}, <-rcpts)
+	body := <-data
+	assert.Contains(t, body, "To: user@example.com")
+	assert.Contains(t, body, "https://example.com/reset")
+}
+
+func TestSMTPMailer_UnreachableServer(t *testing.T) {
+	mailer := internalservices.NewSMTPMailer(internalservices.SMTPConfig{Host: "127.0.0.1", Port: 1, From: "no-reply@example.com"})
+
+	err := mailer.Send(context.Background(), "user@example.com", portservices.EmailTemplatePasswordReset, portservices.PasswordResetEmail{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,163 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/internal/ports/services"
+)
+
+const (
+	// StripeProvider names the events received from Stripe
+	StripeProvider = "stripe"
+	// DefaultWebhookTolerance is how old a signed webhook may be, older ones could be replays
+	DefaultWebhookTolerance = 5 * time.Minute
+)
+
+// stripeSubscriptionStatuses maps the statuses of a Stripe subscription to ours. Incomplete
+// subscriptions (first payment pending) are left out, they change nothing yet.
+var stripeSubscriptionStatuses = map[string]string{
+	"active":             domain.PaymentSubscriptionActive,
+	"trialing":           domain.PaymentSubscriptionActive,
+	"past_due":           domain.PaymentSubscriptionPastDue,
+	"canceled":           domain.PaymentSubscriptionEnded,
+	"unpaid":             domain.PaymentSubscriptionEnded,
+	"incomplete_expired": domain.PaymentSubscriptionEnded,
+	"paused":             domain.PaymentSubscriptionEnded,
+}
+
+type stripeEvent struct {
+	ID      string `json:"id"`
+	Type    string `json:"type"`
+	Created int64  `json:"created"`
+	Data    struct {
+		Object json.RawMessage `json:"object"`
+	} `json:"data"`
+}
+
+type stripeSubscription struct {
+	Status            string            `json:"status"`
+	CurrentPeriodEnd  int64             `json:"current_period_end"`
+	CancelAtPeriodEnd bool              `json:"cancel_at_period_end"`
+	Metadata          map[string]string `json:"metadata"`
+	Items             struct {
+		Data []struct {
+			CurrentPeriodEnd int64 `json:"current_period_end"`
+		} `json:"data"`
+	} `json:"items"`
+}
+
+type stripePaymentProvider struct {
+	secret    []byte
+	tolerance time.Duration
+}
+
+// NewStripePaymentProvider verifies the webhooks with the signing secret of the Stripe endpoint
+// (whsec_...). The user and the plan of a subscription are read from its metadata (user_id, plan),
+// set when creating the checkout session.
+func NewStripePaymentProvider(secret string, tolerance time.Duration) services.PaymentProvider {
+	return &stripePaymentProvider{secret: []byte(secret), tolerance: tolerance}
+}
+
+func (p *stripePaymentProvider) SignatureHeader() string {
+	return "Stripe-Signature"
+}
+
+func (p *stripePaymentProvider) ParseWebhook(payload []byte, signature string, now time.Time) (*domain.PaymentEvent, error) {
+	if err := p.verify(payload, signature, now); err != nil {
+		return nil, err
+	}
+
+	var raw stripeEvent
+	if err := json.Unmarshal(payload, &raw); err != nil || raw.ID == "" || raw.Type == "" {
+		return nil, domain.ErrInvalidWebhookPayload
+	}
+	event := &domain.PaymentEvent{
+		ID:        raw.ID,
+		Provider:  StripeProvider,
+		Type:      raw.Type,
+		Payload:   payload,
+		CreatedAt: time.Unix(raw.Created, 0).UTC(),
+	}
+
+	switch raw.Type {
+	case "customer.subscription.created", "customer.subscription.updated", "customer.subscription.deleted":
+		var sub stripeSubscription
+		if err := json.Unmarshal(raw.Data.Object, &sub); err != nil {
+			return nil, domain.ErrInvalidWebhookPayload
+		}
+		event.Subscription = toPaymentSubscription(raw.Type, sub)
+	}
+	return event, nil
+}
+
+// verify checks the Stripe-Signature header (t=<unix time>,v1=<hex HMAC-SHA256 of "t.payload">).
+// Several v1 signatures are sent while the signing secret is rolled, any of them may match.
+func (p *stripePaymentProvider) verify(payload []byte, header string, now time.Time) error {
+	var timestamp string
+	var signatures [][]byte
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			if sig, err := hex.DecodeString(value); err == nil {
+				signatures = append(signatures, sig)
+			}
+		}
+	}
+
+	signedAt, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || len(signatures) == 0 {
+		return domain.ErrInvalidWebhookSignature
+	}
+	if age := now.Sub(time.Unix(signedAt, 0)); age > p.tolerance || age < -p.tolerance {
+		return domain.ErrInvalidWebhookSignature
+	}
+
+	mac := hmac.New(sha256.New, p.secret)
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(payload)
+	expected := mac.Sum(nil)
+	for _, sig := range signatures {
+		if hmac.Equal(sig, expected) {
+			return nil
+		}
+	}
+	return domain.ErrInvalidWebhookSignature
+}
+
+func toPaymentSubscription(eventType string, sub stripeSubscription) *domain.PaymentSubscription {
+	status, ok := stripeSubscriptionStatuses[sub.Status]
+	if eventType == "customer.subscription.deleted" {
+		status, ok = domain.PaymentSubscriptionEnded, true
+	}
+	if !ok {
+		return nil
+	}
+
+	// Newer API versions moved the period to the subscription items
+	periodEnd := sub.CurrentPeriodEnd
+	if periodEnd == 0 && len(sub.Items.Data) > 0 {
+		periodEnd = sub.Items.Data[0].CurrentPeriodEnd
+	}
+
+	result := &domain.PaymentSubscription{
+		UserID:            sub.Metadata["user_id"],
+		Plan:              sub.Metadata["plan"],
+		Status:            status,
+		CancelAtPeriodEnd: sub.CancelAtPeriodEnd,
+	}
+	if periodEnd > 0 {
+		end := time.Unix(periodEnd, 0).UTC()
+		result.PeriodEnd = &end
+	}
+	return result
+}
@@ -0,0 +1,125 @@
+package services_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	internalservices "github.com/nuhorizon/go-project-template/services/template/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const stripeTestSecret = "whsec_test_secret"
+
+// stripeFixture reads a webhook payload from testdata/stripe
+func stripeFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	payload, err := os.ReadFile(filepath.Join("testdata", "stripe", name))
+	require.NoError(t, err)
+	return payload
+}
+
+// signStripe builds the Stripe-Signature header of payload as Stripe does
+func signStripe(secret string, payload []byte, at time.Time) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.%s", at.Unix(), payload)
+	return fmt.Sprintf("t=%d,v1=%s", at.Unix(), hex.EncodeToString(mac.Sum(nil)))
+}
+
+func TestStripePaymentProvider_ParseWebhook(t *testing.T) {
+	provider := internalservices.NewStripePaymentProvider(stripeTestSecret, internalservices.DefaultWebhookTolerance)
+	now := time.Date(2026, 1, 1, 0, 0, 30, 0, time.UTC)
+	periodEnd := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("subscription updated", func(t *testing.T) {
+		payload := stripeFixture(t, "subscription_updated.json")
+
+		event, err := provider.ParseWebhook(payload, signStripe(stripeTestSecret, payload, now), now)
+
+		require.NoError(t, err)
+		assert.Equal(t, "evt_1PaymentUpdated", event.ID)
+		assert.Equal(t, internalservices.StripeProvider, event.Provider)
+		assert.Equal(t, "customer.subscription.updated", event.Type)
+		assert.Equal(t, payload, event.Payload)
+		assert.Equal(t, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), event.CreatedAt)
+		assert.Equal(t, &domain.PaymentSubscription{
+			UserID:            "5b0c7a52-3f4e-4d8a-9b1c-2e6f8a9d0c11",
+			Plan:              "premium",
+			Status:            domain.PaymentSubscriptionActive,
+			PeriodEnd:         &periodEnd,
+			CancelAtPeriodEnd: true,
+		}, event.Subscription)
+	})
+
+	t.Run("subscription deleted ends it, period read from the items", func(t *testing.T) {
+		payload := stripeFixture(t, "subscription_deleted.json")
+
+		event, err := provider.ParseWebhook(payload, signStripe(stripeTestSecret, payload, now), now)
+
+		require.NoError(t, err)
+		require.NotNil(t, event.Subscription)
+		assert.Equal(t, domain.PaymentSubscriptionEnded, event.Subscription.Status)
+		assert.Equal(t, &periodEnd, event.Subscription.PeriodEnd)
+	})
+
+	t.Run("other events carry no subscription", func(t *testing.T) {
+		payload := stripeFixture(t, "invoice_paid.json")
+
+		event, err := provider.ParseWebhook(payload, signStripe(stripeTestSecret, payload, now), now)
+
+		require.NoError(t, err)
+		assert.Equal(t, "invoice.paid", event.Type)
+		assert.Nil(t, event.Subscription)
+	})
+
+	t.Run("any of the rolled signatures may match", func(t *testing.T) {
+		payload := stripeFixture(t, "subscription_updated.json")
+		header := strings.Replace(signStripe(stripeTestSecret, payload, now), "v1=", "v1="+strings.Repeat("0", 64)+",v1=", 1)
+
+		_, err := provider.ParseWebhook(payload, header, now)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("malformed payload", func(t *testing.T) {
+		payload := []byte(`{"object":"event"}`)
+
+		_, err := provider.ParseWebhook(payload, signStripe(stripeTestSecret, payload, now), now)
+
+		assert.ErrorIs(t, err, domain.ErrInvalidWebhookPayload)
+	})
+}
+
+func TestStripePaymentProvider_ParseWebhook_Signature(t *testing.T) {
+	provider := internalservices.NewStripePaymentProvider(stripeTestSecret, internalservices.DefaultWebhookTolerance)
+	now := time.Date(2026, 1, 1, 0, 0, 30, 0, time.UTC)
+	payload := []byte(`{"id":"evt_1","type":"invoice.paid","created":1767225600,"data":{"object":{}}}`)
+
+	tests := []struct {
+		name   string
+		header string
+	}{
+		{name: "missing header", header: ""},
+		{name: "wrong secret", header: signStripe("whsec_other", payload, now)},
+		{name: "tampered payload", header: signStripe(stripeTestSecret, append([]byte(" "), payload...), now)},
+		{name: "too old", header: signStripe(stripeTestSecret, payload, now.Add(-internalservices.DefaultWebhookTolerance-time.Second))},
+		{name: "from the future", header: signStripe(stripeTestSecret, payload, now.Add(internalservices.DefaultWebhookTolerance+time.Second))},
+		{name: "no v1 signature", header: fmt.Sprintf("t=%d,v0=abcd", now.Unix())},
+		{name: "no timestamp", header: "v1=" + hex.EncodeToString(make([]byte, sha256.Size))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := provider.ParseWebhook(payload, tt.header, now)
+			assert.ErrorIs(t, err, domain.ErrInvalidWebhookSignature)
+		})
+	}
+}
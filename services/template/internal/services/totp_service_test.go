@@ -0,0 +1,72 @@
+package services_test
+
+import (
+	"encoding/base32"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	internalservices "github.com/nuhorizon/go-project-template/services/template/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rfc6238Secret is the SHA-1 seed of the RFC 6238 test vectors ("12345678901234567890")
+var rfc6238Secret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+func TestTOTPService_Validate(t *testing.T) {
+	totp := internalservices.NewTOTPService("CatWise")
+
+	// RFC 6238 appendix B vectors, truncated to 6 digits
+	tests := []struct {
+		name     string
+		at       time.Time
+		code     string
+		wantStep int64
+		valid    bool
+	}{
+		{name: "T=59", at: time.Unix(59, 0), code: "287082", wantStep: 1, valid: true},
+		{name: "T=1111111109", at: time.Unix(1111111109, 0), code: "081804", wantStep: 37037036, valid: true},
+		{name: "T=1234567890", at: time.Unix(1234567890, 0), code: "005924", wantStep: 41152263, valid: true},
+		{name: "previous step is accepted", at: time.Unix(89, 0), code: "287082", wantStep: 1, valid: true},
+		{name: "two steps late is rejected", at: time.Unix(119, 0), code: "287082"},
+		{name: "wrong code", at: time.Unix(59, 0), code: "123456"},
+		{name: "wrong length", at: time.Unix(59, 0), code: "28708"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := totp.Validate(rfc6238Secret, tt.code, tt.at)
+			assert.Equal(t, tt.valid, ok)
+			if tt.valid {
+				assert.Equal(t, tt.wantStep, step)
+			}
+		})
+	}
+}
+
+func TestTOTPService_GenerateSecret(t *testing.T) {
+	totp := internalservices.NewTOTPService("CatWise")
+
+	secret, err := totp.GenerateSecret()
+	require.NoError(t, err)
+	assert.Len(t, secret, 32) // 20 bytes in unpadded base32
+
+	other, err := totp.GenerateSecret()
+	require.NoError(t, err)
+	assert.NotEqual(t, secret, other)
+}
+
+func TestTOTPService_ProvisioningURI(t *testing.T) {
+	totp := internalservices.NewTOTPService("CatWise")
+
+	uri, err := url.Parse(totp.ProvisioningURI("JBSWY3DPEHPK3PXP", "user@example.com"))
+	require.NoError(t, err)
+	assert.Equal(t, "otpauth", uri.Scheme)
+	assert.Equal(t, "totp", uri.Host)
+	assert.Equal(t, "/CatWise:user@example.com", uri.Path)
+	assert.Equal(t, "JBSWY3DPEHPK3PXP", uri.Query().Get("secret"))
+	assert.Equal(t, "CatWise", uri.Query().Get("issuer"))
+	assert.True(t, strings.HasPrefix(uri.String(), "otpauth://totp/CatWise:user@example.com?"))
+}
@@ -0,0 +1,31 @@
+package usecases
+
+import (
+	"context"
+	"time"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/ports/repositories"
+)
+
+// DefaultAccountRetention is how long deleted accounts are kept before they are purged
+const DefaultAccountRetention = 30 * 24 * time.Hour
+
+// AccountRetentionUseCase enforces how long the data of deleted accounts is kept
+type AccountRetentionUseCase interface {
+	// PurgeExpired permanently removes the accounts deleted more than the retention period
+	// before now, returning how many were removed
+	PurgeExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+type accountRetentionUseCase struct {
+	userRepo  repositories.UserRepository
+	retention time.Duration
+}
+
+func NewAccountRetentionUseCase(userRepo repositories.UserRepository, retention time.Duration) AccountRetentionUseCase {
+	return &accountRetentionUseCase{userRepo: userRepo, retention: retention}
+}
+
+func (a *accountRetentionUseCase) PurgeExpired(ctx context.Context, now time.Time) (int64, error) {
+	return a.userRepo.PurgeDeleted(ctx, now.Add(-a.retention))
+}
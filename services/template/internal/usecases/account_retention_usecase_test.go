@@ -0,0 +1,37 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/usecases"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAccountRetentionUseCase_PurgeExpired(t *testing.T) {
+	now := time.Date(2026, 5, 31, 12, 0, 0, 0, time.UTC)
+
+	t.Run("purges accounts deleted before the retention period", func(t *testing.T) {
+		mockRepo := new(MockUserRepo)
+		uc := usecases.NewAccountRetentionUseCase(mockRepo, usecases.DefaultAccountRetention)
+
+		mockRepo.On("PurgeDeleted", mock.Anything, time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)).Return(int64(2), nil)
+
+		purged, err := uc.PurgeExpired(context.Background(), now)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), purged)
+	})
+
+	t.Run("repository failure", func(t *testing.T) {
+		mockRepo := new(MockUserRepo)
+		uc := usecases.NewAccountRetentionUseCase(mockRepo, time.Hour)
+
+		mockRepo.On("PurgeDeleted", mock.Anything, now.Add(-time.Hour)).Return(int64(0), errors.New("db error"))
+
+		_, err := uc.PurgeExpired(context.Background(), now)
+		assert.EqualError(t, err, "db error")
+	})
+}
@@ -0,0 +1,146 @@
+package usecases
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/internal/ports/repositories"
+)
+
+// AdminUseCase defines the operations available to administrators
+type AdminUseCase interface {
+	AssignRole(ctx context.Context, userID, role string) error
+	RemoveRole(ctx context.Context, userID, role string) error
+	RevokeSessions(ctx context.Context, userID string) error
+	ListUsers(ctx context.Context, filter domain.UserFilter) (*domain.UserPage, error)
+	GetUser(ctx context.Context, userID string) (*domain.User, error)
+	UpdatePlan(ctx context.Context, userID, planType string, planExpiry *time.Time) (*domain.User, error)
+	DisableUser(ctx context.Context, userID string) error
+	EnableUser(ctx context.Context, userID string) error
+}
+
+type adminUseCase struct {
+	userRepo      repositories.UserRepository
+	roleRepo      repositories.RoleRepository
+	authUseCase   AuthUseCase
+	subscriptions SubscriptionUseCase
+}
+
+func NewAdminUseCase(userRepo repositories.UserRepository, roleRepo repositories.RoleRepository, authUseCase AuthUseCase, subscriptions SubscriptionUseCase) AdminUseCase {
+	return &adminUseCase{
+		userRepo:      userRepo,
+		roleRepo:      roleRepo,
+		authUseCase:   authUseCase,
+		subscriptions: subscriptions,
+	}
+}
+
+// AssignRole grants a role to the user. Access tokens already issued keep their old
+// roles until the user exchanges the refresh token.
+func (a *adminUseCase) AssignRole(ctx context.Context, userID, role string) error {
+	if err := a.ensureUserExists(ctx, userID); err != nil {
+		return err
+	}
+	return a.roleRepo.AssignRole(ctx, userID, role)
+}
+
+// RemoveRole takes a role away from the user, effective from the next token exchange
+func (a *adminUseCase) RemoveRole(ctx context.Context, userID, role string) error {
+	if err := a.ensureUserExists(ctx, userID); err != nil {
+		return err
+	}
+	return a.roleRepo.RemoveRole(ctx, userID, role)
+}
+
+// RevokeSessions signs the user out of every device
+func (a *adminUseCase) RevokeSessions(ctx context.Context, userID string) error {
+	if err := a.ensureUserExists(ctx, userID); err != nil {
+		return err
+	}
+	return a.authUseCase.RevokeAllSessions(ctx, userID)
+}
+
+// ListUsers pages through the users matching the filter
+func (a *adminUseCase) ListUsers(ctx context.Context, filter domain.UserFilter) (*domain.UserPage, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = domain.DefaultUserPageSize
+	}
+	filter.Limit = min(filter.Limit, domain.MaxUserPageSize)
+	filter.EmailPrefix = normalizeEmail(filter.EmailPrefix)
+
+	users, next, err := a.userRepo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.UserPage{Users: users, NextCursor: next}, nil
+}
+
+// GetUser returns the user with their roles
+func (a *adminUseCase) GetUser(ctx context.Context, userID string) (*domain.User, error) {
+	user, err := a.findUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := loadRoles(ctx, a.roleRepo, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// UpdatePlan moves the user to the plan with the given code, domain.ErrPlanNotFound when there
+// is none. A nil planExpiry grants the plan without an end. Access tokens carrying the old plan
+// stop working, clients get the new one with their next refresh.
+func (a *adminUseCase) UpdatePlan(ctx context.Context, userID, planType string, planExpiry *time.Time) (*domain.User, error) {
+	if strings.EqualFold(planType, domain.PlanFree) {
+		return a.subscriptions.Downgrade(ctx, userID)
+	}
+	return a.subscriptions.Upgrade(ctx, userID, planType, planExpiry)
+}
+
+// DisableUser blocks the user from signing in and signs them out of every device
+func (a *adminUseCase) DisableUser(ctx context.Context, userID string) error {
+	user, err := a.findUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !user.Disabled() {
+		now := time.Now()
+		if err := a.userRepo.SetDisabled(ctx, userID, &now); err != nil {
+			return err
+		}
+	}
+	return a.authUseCase.RevokeAllSessions(ctx, userID)
+}
+
+// EnableUser lets a disabled user sign in again
+func (a *adminUseCase) EnableUser(ctx context.Context, userID string) error {
+	if err := a.ensureUserExists(ctx, userID); err != nil {
+		return err
+	}
+	return a.userRepo.SetDisabled(ctx, userID, nil)
+}
+
+func (a *adminUseCase) findUser(ctx context.Context, userID string) (*domain.User, error) {
+	user, err := a.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrUserNotFound
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+func (a *adminUseCase) ensureUserExists(ctx context.Context, userID string) error {
+	if _, err := a.userRepo.FindByID(ctx, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ErrUserNotFound
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,246 @@
+package usecases_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/internal/usecases"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAdminUseCase_AssignRole(t *testing.T) {
+	tests := []struct {
+		name      string
+		findErr   error
+		assignErr error
+		expectErr error
+	}{
+		{name: "success"},
+		{name: "unknown user", findErr: sql.ErrNoRows, expectErr: domain.ErrUserNotFound},
+		{name: "unknown role", assignErr: domain.ErrRoleNotFound, expectErr: domain.ErrRoleNotFound},
+		{name: "lookup fails", findErr: sql.ErrConnDone, expectErr: sql.ErrConnDone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockUserRepo)
+			mockRoles := new(MockRoleRepo)
+			adminUC := usecases.NewAdminUseCase(mockRepo, mockRoles, nil, nil)
+
+			var found *domain.User
+			if tt.findErr == nil {
+				found = &domain.User{ID: "user-id"}
+			}
+			mockRepo.On("FindByID", mock.Anything, "user-id").Return(found, tt.findErr)
+			mockRoles.On("AssignRole", mock.Anything, "user-id", domain.RoleAdmin).Return(tt.assignErr)
+
+			err := adminUC.AssignRole(context.Background(), "user-id", domain.RoleAdmin)
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+			} else {
+				assert.NoError(t, err)
+				mockRoles.AssertCalled(t, "AssignRole", mock.Anything, "user-id", domain.RoleAdmin)
+			}
+		})
+	}
+}
+
+func TestAdminUseCase_RemoveRole(t *testing.T) {
+	mockRepo := new(MockUserRepo)
+	mockRoles := new(MockRoleRepo)
+	adminUC := usecases.NewAdminUseCase(mockRepo, mockRoles, nil, nil)
+
+	mockRepo.On("FindByID", mock.Anything, "user-id").Return(&domain.User{ID: "user-id"}, nil)
+	mockRoles.On("RemoveRole", mock.Anything, "user-id", domain.RoleAdmin).Return(nil)
+
+	assert.NoError(t, adminUC.RemoveRole(context.Background(), "user-id", domain.RoleAdmin))
+	mockRoles.AssertExpectations(t)
+}
+
+func TestAdminUseCase_RevokeSessions(t *testing.T) {
+	tests := []struct {
+		name      string
+		findErr   error
+		expectErr error
+	}{
+		{name: "success"},
+		{name: "unknown user", findErr: sql.ErrNoRows, expectErr: domain.ErrUserNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockUserRepo)
+			mockRefresh := new(MockRefreshTokenRepo)
+			mockRevocations := new(MockRevocationStore)
+			authUC := usecases.NewAuthUseCase(mockRepo, noRoles(), new(MockIdentityRepo), mockRefresh, noSessions(), mockRevocations, nil, nil, noMFA(), nil, nil, new(MockJWTService), new(MockPasswordHasher))
+			adminUC := usecases.NewAdminUseCase(mockRepo, noRoles(), authUC, nil)
+
+			var found *domain.User
+			if tt.findErr == nil {
+				found = &domain.User{ID: "user-id"}
+			}
+			mockRepo.On("FindByID", mock.Anything, "user-id").Return(found, tt.findErr)
+			mockRevocations.On("RevokeUserTokens", mock.Anything, "user-id", mock.AnythingOfType("time.Time")).Return(nil)
+			mockRefresh.On("RevokeAllForUser", mock.Anything, "user-id").Return(nil)
+
+			err := adminUC.RevokeSessions(context.Background(), "user-id")
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+				mockRevocations.AssertNotCalled(t, "RevokeUserTokens", mock.Anything, mock.Anything, mock.Anything)
+			} else {
+				assert.NoError(t, err)
+				mockRefresh.AssertCalled(t, "RevokeAllForUser", mock.Anything, "user-id")
+			}
+		})
+	}
+}
+
+func TestAdminUseCase_ListUsers(t *testing.T) {
+	tests := []struct {
+		name        string
+		filter      domain.UserFilter
+		expectLimit int
+	}{
+		{name: "default page size", expectLimit: domain.DefaultUserPageSize},
+		{name: "requested page size", filter: domain.UserFilter{Limit: 10}, expectLimit: 10},
+		{name: "page size is capped", filter: domain.UserFilter{Limit: 1000}, expectLimit: domain.MaxUserPageSize},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockUserRepo)
+			adminUC := usecases.NewAdminUseCase(mockRepo, noRoles(), nil, nil)
+
+			mockRepo.On("List", mock.Anything, mock.MatchedBy(func(f domain.UserFilter) bool {
+				return f.Limit == tt.expectLimit
+			})).Return([]domain.User{{ID: "user-id"}}, "next-cursor", nil)
+
+			page, err := adminUC.ListUsers(context.Background(), tt.filter)
+			assert.NoError(t, err)
+			assert.Len(t, page.Users, 1)
+			assert.Equal(t, "next-cursor", page.NextCursor)
+		})
+	}
+
+	t.Run("email prefix is normalized", func(t *testing.T) {
+		mockRepo := new(MockUserRepo)
+		adminUC := usecases.NewAdminUseCase(mockRepo, noRoles(), nil, nil)
+
+		mockRepo.On("List", mock.Anything, mock.MatchedBy(func(f domain.UserFilter) bool {
+			return f.EmailPrefix == "ann@"
+		})).Return(nil, "", nil)
+
+		_, err := adminUC.ListUsers(context.Background(), domain.UserFilter{EmailPrefix: " Ann@ "})
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestAdminUseCase_GetUser(t *testing.T) {
+	mockRepo := new(MockUserRepo)
+	mockRoles := new(MockRoleRepo)
+	adminUC := usecases.NewAdminUseCase(mockRepo, mockRoles, nil, nil)
+
+	mockRepo.On("FindByID", mock.Anything, "user-id").Return(&domain.User{ID: "user-id"}, nil)
+	mockRepo.On("FindByID", mock.Anything, "missing-id").Return(nil, sql.ErrNoRows)
+	mockRoles.On("FindByUserID", mock.Anything, "user-id").Return([]domain.Role{
+		{Name: domain.RoleAdmin, Permissions: []string{domain.PermissionUsersRead}},
+	}, nil)
+
+	user, err := adminUC.GetUser(context.Background(), "user-id")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{domain.RoleAdmin}, user.Roles)
+
+	_, err = adminUC.GetUser(context.Background(), "missing-id")
+	assert.ErrorIs(t, err, domain.ErrUserNotFound)
+}
+
+func TestAdminUseCase_UpdatePlan(t *testing.T) {
+	expiry := time.Now().Add(30 * 24 * time.Hour)
+	upgraded := &domain.User{ID: "user-id", PlanType: domain.PlanPremium}
+	downgraded := &domain.User{ID: "user-id", PlanType: domain.PlanFree}
+
+	t.Run("paid plan upgrades", func(t *testing.T) {
+		subscriptions := new(MockSubscriptionUseCase)
+		adminUC := usecases.NewAdminUseCase(new(MockUserRepo), noRoles(), nil, subscriptions)
+		subscriptions.On("Upgrade", mock.Anything, "user-id", "premium", &expiry).Return(upgraded, nil)
+
+		user, err := adminUC.UpdatePlan(context.Background(), "user-id", "premium", &expiry)
+
+		assert.NoError(t, err)
+		assert.Equal(t, upgraded, user)
+	})
+
+	t.Run("free plan downgrades", func(t *testing.T) {
+		subscriptions := new(MockSubscriptionUseCase)
+		adminUC := usecases.NewAdminUseCase(new(MockUserRepo), noRoles(), nil, subscriptions)
+		subscriptions.On("Downgrade", mock.Anything, "user-id").Return(downgraded, nil)
+
+		user, err := adminUC.UpdatePlan(context.Background(), "user-id", "Free", &expiry)
+
+		assert.NoError(t, err)
+		assert.Equal(t, downgraded, user)
+		subscriptions.AssertNotCalled(t, "Upgrade", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestAdminUseCase_DisableUser(t *testing.T) {
+	disabledAt := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name          string
+		user          *domain.User
+		findErr       error
+		expectDisable bool
+		expectErr     error
+	}{
+		{name: "disables and signs out", user: &domain.User{ID: "user-id"}, expectDisable: true},
+		{name: "already disabled still signs out", user: &domain.User{ID: "user-id", DisabledAt: &disabledAt}},
+		{name: "unknown user", findErr: sql.ErrNoRows, expectErr: domain.ErrUserNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockUserRepo)
+			mockRefresh := new(MockRefreshTokenRepo)
+			mockRevocations := new(MockRevocationStore)
+			authUC := usecases.NewAuthUseCase(mockRepo, noRoles(), new(MockIdentityRepo), mockRefresh, noSessions(), mockRevocations, nil, nil, noMFA(), nil, nil, new(MockJWTService), new(MockPasswordHasher))
+			adminUC := usecases.NewAdminUseCase(mockRepo, noRoles(), authUC, nil)
+
+			mockRepo.On("FindByID", mock.Anything, "user-id").Return(tt.user, tt.findErr)
+			mockRepo.On("SetDisabled", mock.Anything, "user-id", mock.AnythingOfType("*time.Time")).Return(nil)
+			mockRevocations.On("RevokeUserTokens", mock.Anything, "user-id", mock.AnythingOfType("time.Time")).Return(nil)
+			mockRefresh.On("RevokeAllForUser", mock.Anything, "user-id").Return(nil)
+
+			err := adminUC.DisableUser(context.Background(), "user-id")
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+				mockRevocations.AssertNotCalled(t, "RevokeUserTokens", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			if tt.expectDisable {
+				mockRepo.AssertCalled(t, "SetDisabled", mock.Anything, "user-id", mock.AnythingOfType("*time.Time"))
+			} else {
+				mockRepo.AssertNotCalled(t, "SetDisabled", mock.Anything, mock.Anything, mock.Anything)
+			}
+			mockRefresh.AssertCalled(t, "RevokeAllForUser", mock.Anything, "user-id")
+		})
+	}
+}
+
+func TestAdminUseCase_EnableUser(t *testing.T) {
+	mockRepo := new(MockUserRepo)
+	adminUC := usecases.NewAdminUseCase(mockRepo, noRoles(), nil, nil)
+
+	mockRepo.On("FindByID", mock.Anything, "user-id").Return(&domain.User{ID: "user-id"}, nil)
+	mockRepo.On("SetDisabled", mock.Anything, "user-id", (*time.Time)(nil)).Return(nil)
+
+	assert.NoError(t, adminUC.EnableUser(context.Background(), "user-id"))
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,139 @@
+package usecases
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/internal/ports/repositories"
+	"github.com/nuhorizon/go-project-template/services/template/pkg/utils"
+
+	"github.com/google/uuid"
+)
+
+const (
+	apiKeyBytes         = 32
+	apiKeyVisiblePrefix = len(domain.APIKeyPrefix) + 8
+)
+
+// APIKeyUseCase manages personal API keys and authenticates the requests made with them
+type APIKeyUseCase interface {
+	// Create issues a key restricted to the given scopes, the plain key is only returned here
+	Create(ctx context.Context, userID, name string, scopes []string, expiresAt *time.Time) (*domain.APIKey, string, error)
+	List(ctx context.Context, userID string) ([]domain.APIKey, error)
+	Revoke(ctx context.Context, userID, keyID string) error
+	// AuthenticateAPIKey resolves the principal of a request authenticated with the key
+	AuthenticateAPIKey(ctx context.Context, key string) (*domain.Principal, error)
+}
+
+type apiKeyUseCase struct {
+	apiKeyRepo repositories.APIKeyRepository
+	userRepo   repositories.UserRepository
+	roleRepo   repositories.RoleRepository
+}
+
+func NewAPIKeyUseCase(apiKeyRepo repositories.APIKeyRepository, userRepo repositories.UserRepository, roleRepo repositories.RoleRepository) APIKeyUseCase {
+	return &apiKeyUseCase{
+		apiKeyRepo: apiKeyRepo,
+		userRepo:   userRepo,
+		roleRepo:   roleRepo,
+	}
+}
+
+// Create checks every scope is a resource scope or a permission the user currently holds
+func (a *apiKeyUseCase) Create(ctx context.Context, userID, name string, scopes []string, expiresAt *time.Time) (*domain.APIKey, string, error) {
+	user, err := a.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, "", domain.ErrUserNotFound
+		}
+		return nil, "", err
+	}
+	if err := loadRoles(ctx, a.roleRepo, user); err != nil {
+		return nil, "", err
+	}
+
+	var granted []string
+	for _, scope := range scopes {
+		permitted := slices.Contains(domain.Permissions, scope) && slices.Contains(user.Permissions, scope)
+		if !permitted && !slices.Contains(domain.ResourceScopes, scope) {
+			return nil, "", domain.ErrInvalidAPIKeyScope
+		}
+		if !slices.Contains(granted, scope) {
+			granted = append(granted, scope)
+		}
+	}
+
+	secret, err := utils.GenerateRandomToken(apiKeyBytes)
+	if err != nil {
+		return nil, "", err
+	}
+	plain := domain.APIKeyPrefix + secret
+
+	key := &domain.APIKey{
+		ID:        uuid.NewString(),
+		UserID:    userID,
+		Name:      strings.TrimSpace(name),
+		Prefix:    plain[:apiKeyVisiblePrefix],
+		KeyHash:   utils.HashToken(plain),
+		Scopes:    granted,
+		ExpiresAt: expiresAt,
+		CreatedAt: time.Now(),
+	}
+	if err := a.apiKeyRepo.Create(ctx, key); err != nil {
+		return nil, "", err
+	}
+	return key, plain, nil
+}
+
+func (a *apiKeyUseCase) List(ctx context.Context, userID string) ([]domain.APIKey, error) {
+	return a.apiKeyRepo.ListByUserID(ctx, userID)
+}
+
+func (a *apiKeyUseCase) Revoke(ctx context.Context, userID, keyID string) error {
+	return a.apiKeyRepo.Revoke(ctx, userID, keyID)
+}
+
+// AuthenticateAPIKey reloads the user on every request, so plan and role changes apply to
+// keys right away
+func (a *apiKeyUseCase) AuthenticateAPIKey(ctx context.Context, key string) (*domain.Principal, error) {
+	if !strings.HasPrefix(key, domain.APIKeyPrefix) {
+		return nil, domain.ErrInvalidAPIKey
+	}
+
+	apiKey, err := a.apiKeyRepo.FindByHash(ctx, utils.HashToken(key))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrInvalidAPIKey
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	if !apiKey.Active(now) {
+		return nil, domain.ErrInvalidAPIKey
+	}
+
+	user, err := a.userRepo.FindByID(ctx, apiKey.UserID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrInvalidAPIKey
+		}
+		return nil, err
+	}
+	if user.Disabled() {
+		return nil, domain.ErrInvalidAPIKey
+	}
+	if err := loadRoles(ctx, a.roleRepo, user); err != nil {
+		return nil, err
+	}
+
+	if err := a.apiKeyRepo.TouchLastUsed(ctx, apiKey.ID, now); err != nil {
+		return nil, err
+	}
+	return domain.NewPrincipalFromAPIKey(user, apiKey), nil
+}
@@ -0,0 +1,221 @@
+package usecases_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/internal/usecases"
+	"github.com/nuhorizon/go-project-template/services/template/pkg/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAPIKeyRepo struct{ mock.Mock }
+
+func (m *MockAPIKeyRepo) Create(ctx context.Context, key *domain.APIKey) error {
+	args := m.Called(ctx, key)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepo) FindByHash(ctx context.Context, keyHash string) (*domain.APIKey, error) {
+	args := m.Called(ctx, keyHash)
+	key, _ := args.Get(0).(*domain.APIKey)
+	return key, args.Error(1)
+}
+
+func (m *MockAPIKeyRepo) ListByUserID(ctx context.Context, userID string) ([]domain.APIKey, error) {
+	args := m.Called(ctx, userID)
+	keys, _ := args.Get(0).([]domain.APIKey)
+	return keys, args.Error(1)
+}
+
+func (m *MockAPIKeyRepo) Revoke(ctx context.Context, userID, keyID string) error {
+	args := m.Called(ctx, userID, keyID)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepo) TouchLastUsed(ctx context.Context, keyID string, at time.Time) error {
+	args := m.Called(ctx, keyID, at)
+	return args.Error(0)
+}
+
+// readerRoles grants the user the "support" role with users:read only
+func readerRoles() *MockRoleRepo {
+	m := new(MockRoleRepo)
+	m.On("FindByUserID", mock.Anything, "user-id").
+		Return([]domain.Role{{Name: "support", Permissions: []string{domain.PermissionUsersRead}}}, nil)
+	return m
+}
+
+func TestAPIKeyUseCase_Create(t *testing.T) {
+	tests := []struct {
+		name        string
+		scopes      []string
+		userErr     error
+		createErr   error
+		wantScopes  []string
+		expectedErr error
+		wantErr     bool
+	}{
+		{
+			name:       "success",
+			scopes:     []string{domain.PermissionUsersRead, domain.PermissionUsersRead},
+			wantScopes: []string{domain.PermissionUsersRead},
+		},
+		{
+			name:       "resource scopes need no permission",
+			scopes:     []string{domain.ScopeCatsRead, domain.ScopeProfileWrite},
+			wantScopes: []string{domain.ScopeCatsRead, domain.ScopeProfileWrite},
+		},
+		{
+			name:        "scope the user does not hold",
+			scopes:      []string{domain.PermissionUsersWrite},
+			expectedErr: domain.ErrInvalidAPIKeyScope,
+		},
+		{
+			name:        "unknown scope",
+			scopes:      []string{"cats:eat"},
+			expectedErr: domain.ErrInvalidAPIKeyScope,
+		},
+		{
+			name:        "unknown user",
+			scopes:      []string{domain.PermissionUsersRead},
+			userErr:     sql.ErrNoRows,
+			expectedErr: domain.ErrUserNotFound,
+		},
+		{
+			name:      "repository failure",
+			scopes:    []string{domain.PermissionUsersRead},
+			createErr: errors.New("db error"),
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := new(MockUserRepo)
+			apiKeyRepo := new(MockAPIKeyRepo)
+			if tt.userErr != nil {
+				userRepo.On("FindByID", mock.Anything, "user-id").Return(nil, tt.userErr)
+			} else {
+				userRepo.On("FindByID", mock.Anything, "user-id").Return(&domain.User{ID: "user-id"}, nil)
+			}
+			apiKeyRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.APIKey")).Return(tt.createErr).Maybe()
+
+			uc := usecases.NewAPIKeyUseCase(apiKeyRepo, userRepo, readerRoles())
+			key, plain, err := uc.Create(context.Background(), "user-id", " ci ", tt.scopes, nil)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				apiKeyRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+				return
+			}
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.True(t, strings.HasPrefix(plain, domain.APIKeyPrefix))
+			assert.True(t, strings.HasPrefix(plain, key.Prefix))
+			assert.Equal(t, utils.HashToken(plain), key.KeyHash)
+			assert.Equal(t, "ci", key.Name)
+			assert.Equal(t, tt.wantScopes, key.Scopes)
+		})
+	}
+}
+
+func TestAPIKeyUseCase_AuthenticateAPIKey(t *testing.T) {
+	const plain = "cwk_secret"
+	past := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name            string
+		key             string
+		storedKey       *domain.APIKey
+		findErr         error
+		expectedErr     error
+		wantPermissions []string
+	}{
+		{
+			name:            "valid key gets the scopes the user still holds",
+			key:             plain,
+			storedKey:       &domain.APIKey{ID: "key-id", UserID: "user-id", Scopes: []string{domain.PermissionUsersRead, domain.PermissionUsersWrite}},
+			wantPermissions: []string{domain.PermissionUsersRead},
+		},
+		{
+			name:        "missing prefix",
+			key:         "secret",
+			expectedErr: domain.ErrInvalidAPIKey,
+		},
+		{
+			name:        "unknown key",
+			key:         plain,
+			findErr:     sql.ErrNoRows,
+			expectedErr: domain.ErrInvalidAPIKey,
+		},
+		{
+			name:        "revoked key",
+			key:         plain,
+			storedKey:   &domain.APIKey{ID: "key-id", UserID: "user-id", RevokedAt: &past},
+			expectedErr: domain.ErrInvalidAPIKey,
+		},
+		{
+			name:        "expired key",
+			key:         plain,
+			storedKey:   &domain.APIKey{ID: "key-id", UserID: "user-id", ExpiresAt: &past},
+			expectedErr: domain.ErrInvalidAPIKey,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := new(MockUserRepo)
+			userRepo.On("FindByID", mock.Anything, "user-id").Return(&domain.User{ID: "user-id", PlanType: domain.PlanPremium}, nil).Maybe()
+			apiKeyRepo := new(MockAPIKeyRepo)
+			apiKeyRepo.On("FindByHash", mock.Anything, utils.HashToken(plain)).Return(tt.storedKey, tt.findErr).Maybe()
+			apiKeyRepo.On("TouchLastUsed", mock.Anything, "key-id", mock.Anything).Return(nil).Maybe()
+
+			uc := usecases.NewAPIKeyUseCase(apiKeyRepo, userRepo, readerRoles())
+			principal, err := uc.AuthenticateAPIKey(context.Background(), tt.key)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				apiKeyRepo.AssertNotCalled(t, "TouchLastUsed", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, "user-id", principal.UserID)
+			assert.Equal(t, domain.PlanPremium, principal.PlanType)
+			assert.Equal(t, domain.AuthMethodAPIKey, principal.AuthMethod)
+			assert.Equal(t, "key-id", principal.APIKeyID)
+			assert.Equal(t, tt.wantPermissions, principal.Permissions)
+			apiKeyRepo.AssertCalled(t, "TouchLastUsed", mock.Anything, "key-id", mock.Anything)
+		})
+	}
+}
+
+func TestAPIKeyUseCase_AuthenticateAPIKey_DisabledUser(t *testing.T) {
+	const plain = "cwk_secret"
+	disabledAt := time.Now().Add(-time.Hour)
+
+	userRepo := new(MockUserRepo)
+	userRepo.On("FindByID", mock.Anything, "user-id").Return(&domain.User{ID: "user-id", DisabledAt: &disabledAt}, nil)
+	apiKeyRepo := new(MockAPIKeyRepo)
+	apiKeyRepo.On("FindByHash", mock.Anything, utils.HashToken(plain)).
+		Return(&domain.APIKey{ID: "key-id", UserID: "user-id", Scopes: []string{domain.PermissionUsersRead}}, nil)
+
+	uc := usecases.NewAPIKeyUseCase(apiKeyRepo, userRepo, noRoles())
+	principal, err := uc.AuthenticateAPIKey(context.Background(), plain)
+
+	assert.ErrorIs(t, err, domain.ErrInvalidAPIKey)
+	assert.Nil(t, principal)
+	apiKeyRepo.AssertNotCalled(t, "TouchLastUsed", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -2,74 +2,739 @@ package usecases
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
 	"github.com/nuhorizon/go-project-template/services/template/internal/ports/repositories"
 	"github.com/nuhorizon/go-project-template/services/template/internal/ports/services"
+	"github.com/nuhorizon/go-project-template/services/template/pkg/utils"
 
 	"github.com/google/uuid"
 )
 
+// refreshTokenBytes is the entropy of the opaque refresh tokens handed to clients
+const refreshTokenBytes = 32
+
+// dummyPasswordHash is compared against when the e-mail is unknown, so a failed
+// direct login takes the same time whether or not the account exists
+const dummyPasswordHash = "$2a$10$/3GHQ4bZjjJEXSTmnleH7OR1nOgDtj9QK6K1Cc7.G0UBljyGruk0C"
+
 // AuthUseCase defines the behavior for authentication flows
 type AuthUseCase interface {
-	LoginOrRegister(ctx context.Context, firebaseToken string) (*domain.User, string, error)
+	LoginOrRegister(ctx context.Context, provider, idToken string) (*domain.User, *domain.TokenPair, error)
+	LinkIdentity(ctx context.Context, userID, provider, idToken string) (*domain.Identity, error)
+	ListIdentities(ctx context.Context, userID string) ([]domain.Identity, error)
+	Register(ctx context.Context, name, email, password string) (*domain.User, *domain.TokenPair, error)
+	LoginWithPassword(ctx context.Context, email, password string) (*domain.User, *domain.TokenPair, error)
+	PendingMFAUser(mfaToken string) (string, error)
+	CompleteMFALogin(ctx context.Context, mfaToken, code string) (*domain.User, *domain.TokenPair, error)
+	RequestMagicLink(ctx context.Context, email string) error
+	LoginWithMagicLink(ctx context.Context, token string) (*domain.User, *domain.TokenPair, error)
+	SendEmailVerification(ctx context.Context, userID string) error
+	VerifyEmail(ctx context.Context, token string) error
+	ExchangeToken(ctx context.Context, refreshToken string) (*domain.User, *domain.TokenPair, error)
 	ResetPassword(ctx context.Context, email string) error
+	Logout(ctx context.Context, principal *domain.Principal, refreshToken string, allSessions bool) error
+	RevokeAllSessions(ctx context.Context, userID string) error
+	ListSessions(ctx context.Context, userID string) ([]domain.Session, error)
+	RevokeSession(ctx context.Context, userID, sessionID string) error
 }
 
 type authUseCase struct {
-	userRepo     repositories.UserRepository
-	firebaseAuth services.FirebaseAuthService
-	jwtService   services.JWTService
+	userRepo          repositories.UserRepository
+	roleRepo          repositories.RoleRepository
+	identityRepo      repositories.IdentityRepository
+	refreshTokenRepo  repositories.RefreshTokenRepository
+	sessionRepo       repositories.SessionRepository
+	revocations       repositories.TokenRevocationStore
+	firebaseAuth      services.FirebaseAuthService
+	identityProviders map[string]services.IdentityProvider
+	mfa               MFAUseCase
+	magicLinks        MagicLinkUseCase
+	emailVerification EmailVerificationUseCase
+	jwtService        services.JWTService
+	passwordHasher    services.PasswordHasher
 }
 
+// NewAuthUseCase builds the auth flows. firebaseAuth may be nil on deployments
+// without Firebase, in which case only the direct e-mail/password flow and the
+// identityProviders (OpenID Connect) are available. mfa adds the optional second
+// factor of the e-mail/password flow, magicLinks the passwordless e-mail login and
+// emailVerification the verify-email links of direct registrations.
 func NewAuthUseCase(
 	userRepo repositories.UserRepository,
+	roleRepo repositories.RoleRepository,
+	identityRepo repositories.IdentityRepository,
+	refreshTokenRepo repositories.RefreshTokenRepository,
+	sessionRepo repositories.SessionRepository,
+	revocations repositories.TokenRevocationStore,
 	firebaseAuth services.FirebaseAuthService,
+	identityProviders []services.IdentityProvider,
+	mfa MFAUseCase,
+	magicLinks MagicLinkUseCase,
+	emailVerification EmailVerificationUseCase,
 	jwtService services.JWTService,
+	passwordHasher services.PasswordHasher,
 ) AuthUseCase {
+	providers := make(map[string]services.IdentityProvider, len(identityProviders))
+	for _, provider := range identityProviders {
+		providers[provider.Name()] = provider
+	}
+
 	return &authUseCase{
-		userRepo:     userRepo,
-		firebaseAuth: firebaseAuth,
-		jwtService:   jwtService,
+		userRepo:          userRepo,
+		roleRepo:          roleRepo,
+		identityRepo:      identityRepo,
+		refreshTokenRepo:  refreshTokenRepo,
+		sessionRepo:       sessionRepo,
+		revocations:       revocations,
+		firebaseAuth:      firebaseAuth,
+		identityProviders: providers,
+		mfa:               mfa,
+		magicLinks:        magicLinks,
+		emailVerification: emailVerification,
+		jwtService:        jwtService,
+		passwordHasher:    passwordHasher,
+	}
+}
+
+// LoginOrRegister validates an identity provider token (Firebase when provider is empty),
+// syncs the user, and generates app JWT + refresh token
+func (a *authUseCase) LoginOrRegister(ctx context.Context, provider, idToken string) (*domain.User, *domain.TokenPair, error) {
+	identity, err := a.verifyIdentity(ctx, provider, idToken)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	user, err := a.resolveIdentityUser(ctx, identity)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tokens, err := a.issueTokens(ctx, user)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return user, tokens, nil
+}
+
+// LinkIdentity attaches the identity asserted by the token to the signed-in user, so the
+// same account can be reached through that provider afterwards
+func (a *authUseCase) LinkIdentity(ctx context.Context, userID, provider, idToken string) (*domain.Identity, error) {
+	external, err := a.verifyIdentity(ctx, provider, idToken)
+	if err != nil {
+		return nil, err
+	}
+
+	linked, err := a.identityRepo.FindByProviderSubject(ctx, external.Provider, external.Subject)
+	if err == nil {
+		if linked.UserID != userID {
+			return nil, domain.ErrIdentityAlreadyLinked
+		}
+		return linked, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	return a.linkIdentity(ctx, userID, external)
+}
+
+// ListIdentities returns the identity providers linked to a user
+func (a *authUseCase) ListIdentities(ctx context.Context, userID string) ([]domain.Identity, error) {
+	return a.identityRepo.ListByUserID(ctx, userID)
+}
+
+// verifyIdentity validates the token with the named provider (Firebase when empty). A token the
+// provider rejects fails with domain.ErrInvalidIdentityToken.
+func (a *authUseCase) verifyIdentity(ctx context.Context, provider, idToken string) (*services.ExternalIdentity, error) {
+	if provider == "" || provider == domain.ProviderFirebase {
+		if a.firebaseAuth == nil {
+			return nil, domain.ErrFirebaseDisabled
+		}
+
+		fbUser, err := a.firebaseAuth.VerifyToken(ctx, idToken)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", domain.ErrInvalidIdentityToken, err)
+		}
+		return &services.ExternalIdentity{
+			Provider:      domain.ProviderFirebase,
+			Subject:       fbUser.UID,
+			Email:         fbUser.Email,
+			EmailVerified: fbUser.EmailVerified,
+			Name:          fbUser.Name,
+			Picture:       fbUser.Picture,
+		}, nil
+	}
+
+	identityProvider, ok := a.identityProviders[provider]
+	if !ok {
+		return nil, domain.ErrUnknownIdentityProvider
+	}
+	identity, err := identityProvider.VerifyToken(ctx, idToken)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidIdentityToken, err)
+	}
+	return identity, nil
+}
+
+// resolveIdentityUser returns the user linked to the (provider, subject) pair. On the first
+// login through a provider the identity is linked to the account owning the same e-mail when
+// both the provider and the account verified it. A new user is created when no account owns it.
+func (a *authUseCase) resolveIdentityUser(ctx context.Context, external *services.ExternalIdentity) (*domain.User, error) {
+	linked, err := a.identityRepo.FindByProviderSubject(ctx, external.Provider, external.Subject)
+	if err == nil {
+		user, err := a.userRepo.FindByID(ctx, linked.UserID)
+		if err != nil {
+			return nil, err
+		}
+		// The provider may have verified the address since the last login
+		if external.EmailVerified && normalizeEmail(external.Email) == user.Email {
+			if err := a.markEmailVerified(ctx, user); err != nil {
+				return nil, err
+			}
+		}
+		return user, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	if external.Email == "" {
+		return nil, domain.ErrIdentityEmailMissing
+	}
+	email := normalizeEmail(external.Email)
+
+	existing, err := a.userRepo.FindByEmail(ctx, email)
+	if err == nil {
+		// An unverified e-mail could be claimed by anyone at the provider, and an unverified
+		// account could have been registered by anyone with a password of their own. Both
+		// sides must have proven the mailbox, otherwise the owner has to sign in to the
+		// existing account and link the identity explicitly.
+		if !external.EmailVerified || !existing.EmailVerified {
+			return nil, domain.ErrIdentityLinkRequired
+		}
+		if _, err := a.linkIdentity(ctx, existing.ID, external); err != nil {
+			return nil, err
+		}
+		return existing, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	name := external.Name
+	if name == "" {
+		name = email
+	}
+
+	user := &domain.User{
+		ID:            uuid.NewString(),
+		Email:         email,
+		Name:          name,
+		PictureURL:    external.Picture,
+		EmailVerified: external.EmailVerified,
+	}
+	if external.Provider == domain.ProviderFirebase {
+		user.FirebaseUID = external.Subject
+	}
+	identity := &domain.Identity{
+		ID:       uuid.NewString(),
+		UserID:   user.ID,
+		Provider: external.Provider,
+		Subject:  external.Subject,
+		Email:    email,
+	}
+	if err := a.identityRepo.CreateWithUser(ctx, user, identity); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (a *authUseCase) linkIdentity(ctx context.Context, userID string, external *services.ExternalIdentity) (*domain.Identity, error) {
+	identity := &domain.Identity{
+		ID:       uuid.NewString(),
+		UserID:   userID,
+		Provider: external.Provider,
+		Subject:  external.Subject,
+		Email:    normalizeEmail(external.Email),
+	}
+	if err := a.identityRepo.Create(ctx, identity); err != nil {
+		return nil, err
 	}
+	return identity, nil
 }
 
-// LoginOrRegister validates Firebase token, syncs user, and generates app JWT
-func (a *authUseCase) LoginOrRegister(ctx context.Context, firebaseToken string) (*domain.User, string, error) {
-	// Step 1: Validate Firebase Token and extract claims
-	fbUser, err := a.firebaseAuth.VerifyToken(ctx, firebaseToken)
+// Register creates a user with e-mail/password credentials and logs them in
+func (a *authUseCase) Register(ctx context.Context, name, email, password string) (*domain.User, *domain.TokenPair, error) {
+	email = normalizeEmail(email)
+
+	_, err := a.userRepo.FindByEmail(ctx, email)
+	if err == nil {
+		return nil, nil, domain.ErrEmailAlreadyRegistered
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, err
+	}
+
+	hash, err := a.passwordHasher.Hash(password)
 	if err != nil {
-		return nil, "", err
+		return nil, nil, err
 	}
 
-	// Step 2: Upsert user based on Firebase UID
 	user := &domain.User{
-		ID:          fbUser.AppUserID, // Can be generated if empty
-		FirebaseUID: fbUser.UID,
-		Email:       fbUser.Email,
-		Name:        fbUser.Name,
-		PictureURL:  fbUser.Picture,
+		ID:           uuid.NewString(),
+		Email:        email,
+		Name:         name,
+		PasswordHash: hash,
+	}
+	if err := a.userRepo.Create(ctx, user); err != nil {
+		return nil, nil, err
+	}
+
+	tokens, err := a.issueTokens(ctx, user)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return user, tokens, nil
+}
+
+// LoginWithPassword authenticates a user registered through the direct flow
+func (a *authUseCase) LoginWithPassword(ctx context.Context, email, password string) (*domain.User, *domain.TokenPair, error) {
+	user, err := a.userRepo.FindByEmail(ctx, normalizeEmail(email))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			_ = a.passwordHasher.Compare(dummyPasswordHash, password)
+			return nil, nil, domain.ErrInvalidCredentials
+		}
+		return nil, nil, err
+	}
+
+	// Firebase-only accounts have no local password
+	if user.PasswordHash == "" {
+		_ = a.passwordHasher.Compare(dummyPasswordHash, password)
+		return nil, nil, domain.ErrInvalidCredentials
+	}
+
+	if err := a.passwordHasher.Compare(user.PasswordHash, password); err != nil {
+		return nil, nil, domain.ErrInvalidCredentials
+	}
+
+	if err := a.requireSecondFactor(ctx, user); err != nil {
+		return nil, nil, err
+	}
+
+	tokens, err := a.issueTokens(ctx, user)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return user, tokens, nil
+}
+
+// RequestMagicLink mails a passwordless login link. Unknown e-mails get one too, the
+// account is created when the link is followed.
+func (a *authUseCase) RequestMagicLink(ctx context.Context, email string) error {
+	return a.magicLinks.Send(ctx, email)
+}
+
+// LoginWithMagicLink redeems a magic link, signing in (or registering) the owner of the
+// e-mail it was sent to. Users with a second factor still have to complete it.
+func (a *authUseCase) LoginWithMagicLink(ctx context.Context, token string) (*domain.User, *domain.TokenPair, error) {
+	email, err := a.magicLinks.Consume(ctx, token)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Following the link proves the mailbox, the e-mail is verified either way and an
+	// unverified account is claimed by its owner
+	user, err := a.userRepo.FindByEmail(ctx, email)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		user = &domain.User{
+			ID:            uuid.NewString(),
+			Email:         email,
+			Name:          email,
+			EmailVerified: true,
+		}
+		if err := a.userRepo.Create(ctx, user); err != nil {
+			return nil, nil, err
+		}
+	case err != nil:
+		return nil, nil, err
+	default:
+		if !user.EmailVerified {
+			if err := a.claimUnverifiedAccount(ctx, user); err != nil {
+				return nil, nil, err
+			}
+		}
+		if err := a.requireSecondFactor(ctx, user); err != nil {
+			return nil, nil, err
+		}
+		if err := a.markEmailVerified(ctx, user); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	tokens, err := a.issueTokens(ctx, user)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return user, tokens, nil
+}
+
+// PendingMFAUser returns the user a "mfa_pending" token was issued to, so the attempts to
+// complete the login can be counted against that user
+func (a *authUseCase) PendingMFAUser(mfaToken string) (string, error) {
+	claims, err := a.jwtService.ValidateMFAToken(mfaToken)
+	if err != nil {
+		return "", domain.ErrInvalidMFAToken
+	}
+	return claims.UserID, nil
+}
+
+// CompleteMFALogin finishes a password login with the TOTP (or recovery) code of the user
+// the "mfa_pending" token was issued to. The token is revoked by its jti once it succeeds,
+// it completes a single login.
+func (a *authUseCase) CompleteMFALogin(ctx context.Context, mfaToken, code string) (*domain.User, *domain.TokenPair, error) {
+	claims, err := a.jwtService.ValidateMFAToken(mfaToken)
+	if err != nil {
+		return nil, nil, domain.ErrInvalidMFAToken
+	}
+	revoked, err := a.revocations.IsRevoked(ctx, claims)
+	if err != nil {
+		return nil, nil, err
+	}
+	if revoked {
+		return nil, nil, domain.ErrInvalidMFAToken
+	}
+
+	if err := a.mfa.Verify(ctx, claims.UserID, code); err != nil {
+		return nil, nil, err
+	}
+
+	user, err := a.userRepo.FindByID(ctx, claims.UserID)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	if user.ID == "" {
-		user.ID = uuid.NewString()
+	if err := a.revocations.RevokeToken(ctx, claims.TokenID, claims.ExpiresAt); err != nil {
+		return nil, nil, err
 	}
 
-	dbUser, err := a.userRepo.UpsertByFirebaseUID(ctx, user)
+	tokens, err := a.issueTokens(ctx, user)
 	if err != nil {
-		return nil, "", err
+		return nil, nil, err
 	}
 
-	// Step 3: Issue App JWT
-	token, err := a.jwtService.GenerateToken(dbUser)
+	return user, tokens, nil
+}
+
+// ExchangeToken rotates a refresh token, returning a new access/refresh pair.
+// Presenting an already rotated token revokes every token of its family.
+func (a *authUseCase) ExchangeToken(ctx context.Context, refreshToken string) (*domain.User, *domain.TokenPair, error) {
+	stored, err := a.refreshTokenRepo.FindByHash(ctx, utils.HashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil, domain.ErrInvalidRefreshToken
+		}
+		return nil, nil, err
+	}
+
+	if stored.RevokedAt != nil {
+		if err := a.refreshTokenRepo.RevokeFamily(ctx, stored.FamilyID); err != nil {
+			return nil, nil, err
+		}
+		return nil, nil, domain.ErrRefreshTokenReused
+	}
+
+	if time.Now().After(stored.ExpiresAt) {
+		return nil, nil, domain.ErrInvalidRefreshToken
+	}
+
+	user, err := a.userRepo.FindByID(ctx, stored.UserID)
 	if err != nil {
-		return nil, "", err
+		return nil, nil, err
+	}
+	if user.Disabled() {
+		return nil, nil, domain.ErrUserDisabled
 	}
 
-	return dbUser, token, nil
+	// Role changes are picked up here, on the next exchange
+	if err := loadRoles(ctx, a.roleRepo, user); err != nil {
+		return nil, nil, err
+	}
+
+	accessToken, err := a.jwtService.GenerateToken(user, stored.FamilyID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	plain, next, err := newRefreshToken(user.ID, stored.FamilyID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := a.refreshTokenRepo.Rotate(ctx, stored, next); err != nil {
+		if errors.Is(err, domain.ErrRefreshTokenReused) {
+			// Lost the race against another exchange of the same token: treat it as reuse
+			if revokeErr := a.refreshTokenRepo.RevokeFamily(ctx, stored.FamilyID); revokeErr != nil {
+				return nil, nil, revokeErr
+			}
+		}
+		return nil, nil, err
+	}
+
+	if err := a.recordSession(ctx, stored.FamilyID, user.ID); err != nil {
+		return nil, nil, err
+	}
+
+	return user, &domain.TokenPair{AccessToken: accessToken, RefreshToken: plain}, nil
 }
 
 // ResetPassword triggers Firebase password reset
 func (a *authUseCase) ResetPassword(ctx context.Context, email string) error {
+	if a.firebaseAuth == nil {
+		return domain.ErrFirebaseDisabled
+	}
 	return a.firebaseAuth.SendPasswordReset(ctx, email)
 }
+
+// Logout revokes the access token in use and, when given, the refresh token of the same session.
+// With allSessions every token of the user is revoked instead.
+func (a *authUseCase) Logout(ctx context.Context, principal *domain.Principal, refreshToken string, allSessions bool) error {
+	if allSessions {
+		return a.RevokeAllSessions(ctx, principal.UserID)
+	}
+
+	if err := a.revocations.RevokeToken(ctx, principal.TokenID, principal.ExpiresAt); err != nil {
+		return err
+	}
+
+	if refreshToken == "" {
+		return nil
+	}
+
+	stored, err := a.refreshTokenRepo.FindByHash(ctx, utils.HashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ErrInvalidRefreshToken
+		}
+		return err
+	}
+
+	// Never let a user revoke someone else's session
+	if stored.UserID != principal.UserID {
+		return domain.ErrInvalidRefreshToken
+	}
+
+	return a.refreshTokenRepo.RevokeFamily(ctx, stored.FamilyID)
+}
+
+// RevokeAllSessions invalidates every access and refresh token issued to the user so far
+func (a *authUseCase) RevokeAllSessions(ctx context.Context, userID string) error {
+	if err := revokeUserTokens(ctx, a.revocations, userID, time.Now()); err != nil {
+		return err
+	}
+	return a.refreshTokenRepo.RevokeAllForUser(ctx, userID)
+}
+
+// revokeUserTokens rejects every access token issued to the user up to now. iat has a one
+// second resolution, so the whole current second is revoked: a token issued earlier in that
+// second can't be told apart from one issued right after, and both are rejected.
+func revokeUserTokens(ctx context.Context, revocations repositories.TokenRevocationStore, userID string, now time.Time) error {
+	return revocations.RevokeUserTokens(ctx, userID, now.Truncate(time.Second))
+}
+
+// claimUnverifiedAccount hands an account whose e-mail was never verified over to the owner of
+// the mailbox. Whoever registered it may not be that owner, so the password they chose stops
+// working and their devices are signed out. The sessions are revoked one by one rather than
+// through RevokeAllSessions, which would also reject the tokens issued right after in the
+// same second.
+func (a *authUseCase) claimUnverifiedAccount(ctx context.Context, user *domain.User) error {
+	if user.PasswordHash != "" {
+		if err := a.userRepo.ClearPassword(ctx, user.ID); err != nil {
+			return err
+		}
+		user.PasswordHash = ""
+	}
+
+	sessions, err := a.sessionRepo.ListActiveByUserID(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	for _, session := range sessions {
+		if err := a.revocations.RevokeSession(ctx, session.ID, time.Unix(domain.TokenExpiry(), 0)); err != nil {
+			return err
+		}
+	}
+	return a.refreshTokenRepo.RevokeAllForUser(ctx, user.ID)
+}
+
+// ListSessions lists the devices the user is signed in on
+func (a *authUseCase) ListSessions(ctx context.Context, userID string) ([]domain.Session, error) {
+	return a.sessionRepo.ListActiveByUserID(ctx, userID)
+}
+
+// RevokeSession signs a single device out: its refresh tokens stop working right away and so
+// do the access tokens already issued to it
+func (a *authUseCase) RevokeSession(ctx context.Context, userID, sessionID string) error {
+	session, err := a.sessionRepo.FindByID(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ErrSessionNotFound
+		}
+		return err
+	}
+
+	// Never let a user sign out someone else's device
+	if session.UserID != userID {
+		return domain.ErrSessionNotFound
+	}
+
+	// The session's access tokens were all issued before now, none outlives a fresh one
+	if err := a.revocations.RevokeSession(ctx, sessionID, time.Unix(domain.TokenExpiry(), 0)); err != nil {
+		return err
+	}
+	return a.refreshTokenRepo.RevokeFamily(ctx, sessionID)
+}
+
+// issueTokens generates the app JWT and persists a refresh token opening a new family,
+// recorded as a new session of the requesting client
+func (a *authUseCase) issueTokens(ctx context.Context, user *domain.User) (*domain.TokenPair, error) {
+	if user.Disabled() {
+		return nil, domain.ErrUserDisabled
+	}
+	if err := loadRoles(ctx, a.roleRepo, user); err != nil {
+		return nil, err
+	}
+
+	sessionID := uuid.NewString()
+	accessToken, err := a.jwtService.GenerateToken(user, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	plain, refresh, err := newRefreshToken(user.ID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := a.refreshTokenRepo.Create(ctx, refresh); err != nil {
+		return nil, err
+	}
+
+	if err := a.recordSession(ctx, sessionID, user.ID); err != nil {
+		return nil, err
+	}
+
+	return &domain.TokenPair{AccessToken: accessToken, RefreshToken: plain}, nil
+}
+
+// SendEmailVerification mails a verify-email link to a user whose e-mail is not verified yet
+func (a *authUseCase) SendEmailVerification(ctx context.Context, userID string) error {
+	return a.emailVerification.Send(ctx, userID)
+}
+
+// VerifyEmail confirms the e-mail a verify-email link was sent to
+func (a *authUseCase) VerifyEmail(ctx context.Context, token string) error {
+	return a.emailVerification.Confirm(ctx, token)
+}
+
+// markEmailVerified records a proof of ownership of the user's e-mail, once
+func (a *authUseCase) markEmailVerified(ctx context.Context, user *domain.User) error {
+	if user.EmailVerified {
+		return nil
+	}
+	if err := a.userRepo.MarkEmailVerified(ctx, user.ID); err != nil {
+		return err
+	}
+	user.EmailVerified = true
+	return nil
+}
+
+// requireSecondFactor fails with an MFARequiredError carrying the "mfa_pending" token
+// when the user has to complete the login with their second factor
+func (a *authUseCase) requireSecondFactor(ctx context.Context, user *domain.User) error {
+	// A disabled account doesn't get as far as the second factor
+	if user.Disabled() {
+		return domain.ErrUserDisabled
+	}
+
+	required, err := a.mfa.Required(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	if !required {
+		return nil
+	}
+
+	mfaToken, err := a.jwtService.GenerateMFAToken(user.ID)
+	if err != nil {
+		return err
+	}
+	return &domain.MFARequiredError{Token: mfaToken, ExpiresAt: time.Now().Add(domain.MFATokenTTL)}
+}
+
+// recordSession stores the client of the request on the session, creating it on login and
+// updating its last-seen time on every refresh
+func (a *authUseCase) recordSession(ctx context.Context, sessionID, userID string) error {
+	client := domain.ClientInfoFromContext(ctx)
+	now := time.Now()
+	return a.sessionRepo.Upsert(ctx, &domain.Session{
+		ID:         sessionID,
+		UserID:     userID,
+		UserAgent:  client.UserAgent,
+		IP:         client.IP,
+		CreatedAt:  now,
+		LastSeenAt: now,
+	})
+}
+
+// loadRoles fills the user's roles and the union of their permissions, which end up in the JWT claims
+func loadRoles(ctx context.Context, roleRepo repositories.RoleRepository, user *domain.User) error {
+	roles, err := roleRepo.FindByUserID(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	user.Roles, user.Permissions = nil, nil
+	for _, role := range roles {
+		user.Roles = append(user.Roles, role.Name)
+		for _, permission := range role.Permissions {
+			if !slices.Contains(user.Permissions, permission) {
+				user.Permissions = append(user.Permissions, permission)
+			}
+		}
+	}
+	return nil
+}
+
+// newRefreshToken returns the opaque token for the client and the hashed record to persist
+func newRefreshToken(userID, familyID string) (string, *domain.RefreshToken, error) {
+	plain, err := utils.GenerateRandomToken(refreshTokenBytes)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return plain, &domain.RefreshToken{
+		ID:        uuid.NewString(),
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: utils.HashToken(plain),
+		ExpiresAt: domain.RefreshTokenExpiry(),
+	}, nil
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
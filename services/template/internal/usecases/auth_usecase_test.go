@@ -2,8 +2,10 @@ package usecases_test
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
 	"github.com/nuhorizon/go-project-template/services/template/internal/ports/services"
@@ -27,9 +29,46 @@ func (m *MockUserRepo) Update(ctx context.Context, user *domain.User) error {
 	return args.Error(0)
 }
 
-func (m *MockUserRepo) UpsertByFirebaseUID(ctx context.Context, user *domain.User) (*domain.User, error) {
+func (m *MockUserRepo) UpdatePlan(ctx context.Context, user *domain.User) error {
 	args := m.Called(ctx, user)
-	return args.Get(0).(*domain.User), args.Error(1)
+	return args.Error(0)
+}
+
+func (m *MockUserRepo) ListLapsedPlans(ctx context.Context, cancelledBefore, expiredBefore time.Time, limit int) ([]domain.User, error) {
+	args := m.Called(ctx, cancelledBefore, expiredBefore, limit)
+	users, _ := args.Get(0).([]domain.User)
+	return users, args.Error(1)
+}
+
+func (m *MockUserRepo) MarkEmailVerified(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockUserRepo) ClearPassword(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockUserRepo) SetDisabled(ctx context.Context, id string, disabledAt *time.Time) error {
+	args := m.Called(ctx, id, disabledAt)
+	return args.Error(0)
+}
+
+func (m *MockUserRepo) SoftDelete(ctx context.Context, id string, deletedAt time.Time) error {
+	args := m.Called(ctx, id, deletedAt)
+	return args.Error(0)
+}
+
+func (m *MockUserRepo) PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	args := m.Called(ctx, deletedBefore)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockUserRepo) List(ctx context.Context, filter domain.UserFilter) ([]domain.User, string, error) {
+	args := m.Called(ctx, filter)
+	users, _ := args.Get(0).([]domain.User)
+	return users, args.String(1), args.Error(2)
 }
 
 func (m *MockUserRepo) FindByID(ctx context.Context, id string) (*domain.User, error) {
@@ -41,8 +80,8 @@ func (m *MockUserRepo) FindByID(ctx context.Context, id string) (*domain.User, e
 	return user.(*domain.User), args.Error(1)
 }
 
-func (m *MockUserRepo) FindByFirebaseUID(ctx context.Context, firebaseUID string) (*domain.User, error) {
-	args := m.Called(ctx, firebaseUID)
+func (m *MockUserRepo) FindByEmail(ctx context.Context, email string) (*domain.User, error) {
+	args := m.Called(ctx, email)
 	user := args.Get(0)
 	if user == nil {
 		return nil, args.Error(1)
@@ -50,13 +89,140 @@ func (m *MockUserRepo) FindByFirebaseUID(ctx context.Context, firebaseUID string
 	return user.(*domain.User), args.Error(1)
 }
 
-func (m *MockUserRepo) FindByEmail(ctx context.Context, email string) (*domain.User, error) {
-	args := m.Called(ctx, email)
-	user := args.Get(0)
-	if user == nil {
+type MockRoleRepo struct{ mock.Mock }
+
+func (m *MockRoleRepo) FindByUserID(ctx context.Context, userID string) ([]domain.Role, error) {
+	args := m.Called(ctx, userID)
+	roles, _ := args.Get(0).([]domain.Role)
+	return roles, args.Error(1)
+}
+
+func (m *MockRoleRepo) AssignRole(ctx context.Context, userID, role string) error {
+	args := m.Called(ctx, userID, role)
+	return args.Error(0)
+}
+
+func (m *MockRoleRepo) RemoveRole(ctx context.Context, userID, role string) error {
+	args := m.Called(ctx, userID, role)
+	return args.Error(0)
+}
+
+// noRoles is a role repository for users without any role granted
+func noRoles() *MockRoleRepo {
+	m := new(MockRoleRepo)
+	m.On("FindByUserID", mock.Anything, mock.Anything).Return(nil, nil)
+	return m
+}
+
+type MockIdentityRepo struct{ mock.Mock }
+
+func (m *MockIdentityRepo) FindByProviderSubject(ctx context.Context, provider, subject string) (*domain.Identity, error) {
+	args := m.Called(ctx, provider, subject)
+	identity, _ := args.Get(0).(*domain.Identity)
+	return identity, args.Error(1)
+}
+
+func (m *MockIdentityRepo) ListByUserID(ctx context.Context, userID string) ([]domain.Identity, error) {
+	args := m.Called(ctx, userID)
+	identities, _ := args.Get(0).([]domain.Identity)
+	return identities, args.Error(1)
+}
+
+func (m *MockIdentityRepo) Create(ctx context.Context, identity *domain.Identity) error {
+	args := m.Called(ctx, identity)
+	return args.Error(0)
+}
+
+func (m *MockIdentityRepo) CreateWithUser(ctx context.Context, user *domain.User, identity *domain.Identity) error {
+	args := m.Called(ctx, user, identity)
+	return args.Error(0)
+}
+
+type MockRefreshTokenRepo struct{ mock.Mock }
+
+func (m *MockRefreshTokenRepo) Create(ctx context.Context, token *domain.RefreshToken) error {
+	args := m.Called(ctx, token)
+	return args.Error(0)
+}
+
+func (m *MockRefreshTokenRepo) FindByHash(ctx context.Context, tokenHash string) (*domain.RefreshToken, error) {
+	args := m.Called(ctx, tokenHash)
+	token := args.Get(0)
+	if token == nil {
 		return nil, args.Error(1)
 	}
-	return user.(*domain.User), args.Error(1)
+	return token.(*domain.RefreshToken), args.Error(1)
+}
+
+func (m *MockRefreshTokenRepo) Rotate(ctx context.Context, current *domain.RefreshToken, next *domain.RefreshToken) error {
+	args := m.Called(ctx, current, next)
+	return args.Error(0)
+}
+
+func (m *MockRefreshTokenRepo) RevokeFamily(ctx context.Context, familyID string) error {
+	args := m.Called(ctx, familyID)
+	return args.Error(0)
+}
+
+func (m *MockRefreshTokenRepo) RevokeAllForUser(ctx context.Context, userID string) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+type MockSessionRepo struct{ mock.Mock }
+
+func (m *MockSessionRepo) Upsert(ctx context.Context, session *domain.Session) error {
+	args := m.Called(ctx, session)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepo) FindByID(ctx context.Context, id string) (*domain.Session, error) {
+	args := m.Called(ctx, id)
+	session := args.Get(0)
+	if session == nil {
+		return nil, args.Error(1)
+	}
+	return session.(*domain.Session), args.Error(1)
+}
+
+func (m *MockSessionRepo) ListActiveByUserID(ctx context.Context, userID string) ([]domain.Session, error) {
+	args := m.Called(ctx, userID)
+	sessions, _ := args.Get(0).([]domain.Session)
+	return sessions, args.Error(1)
+}
+
+// noSessions accepts any session upsert
+func noSessions() *MockSessionRepo {
+	m := new(MockSessionRepo)
+	m.On("Upsert", mock.Anything, mock.Anything).Return(nil).Maybe()
+	return m
+}
+
+type MockRevocationStore struct{ mock.Mock }
+
+func (m *MockRevocationStore) RevokeToken(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	args := m.Called(ctx, tokenID, expiresAt)
+	return args.Error(0)
+}
+
+func (m *MockRevocationStore) RevokeUserTokens(ctx context.Context, userID string, before time.Time) error {
+	args := m.Called(ctx, userID, before)
+	return args.Error(0)
+}
+
+func (m *MockRevocationStore) RevokeSession(ctx context.Context, sessionID string, expiresAt time.Time) error {
+	args := m.Called(ctx, sessionID, expiresAt)
+	return args.Error(0)
+}
+
+func (m *MockRevocationStore) IsRevoked(ctx context.Context, claims *domain.TokenClaims) (bool, error) {
+	args := m.Called(ctx, claims)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRevocationStore) PruneExpired(ctx context.Context, now time.Time) (int64, error) {
+	args := m.Called(ctx, now)
+	return args.Get(0).(int64), args.Error(1)
 }
 
 type MockFirebaseAuth struct{ mock.Mock }
@@ -71,28 +237,135 @@ func (m *MockFirebaseAuth) SendPasswordReset(ctx context.Context, email string)
 	return args.Error(0)
 }
 
+func (m *MockFirebaseAuth) DeleteUser(ctx context.Context, uid string) error {
+	args := m.Called(ctx, uid)
+	return args.Error(0)
+}
+
+type MockIdentityProvider struct{ mock.Mock }
+
+func (m *MockIdentityProvider) Name() string {
+	return "acme"
+}
+
+func (m *MockIdentityProvider) VerifyToken(ctx context.Context, token string) (*services.ExternalIdentity, error) {
+	args := m.Called(ctx, token)
+	identity, _ := args.Get(0).(*services.ExternalIdentity)
+	return identity, args.Error(1)
+}
+
 type MockJWTService struct{ mock.Mock }
 
-func (m *MockJWTService) GenerateToken(user *domain.User) (string, error) {
-	args := m.Called(user)
+func (m *MockJWTService) GenerateToken(user *domain.User, sessionID string) (string, error) {
+	args := m.Called(user, sessionID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockJWTService) ValidateToken(token string) (*domain.TokenClaims, error) {
+	args := m.Called(token)
+	claims, _ := args.Get(0).(*domain.TokenClaims)
+	return claims, args.Error(1)
+}
+
+func (m *MockJWTService) PublicKeys() []services.PublicKey {
+	args := m.Called()
+	keys, _ := args.Get(0).([]services.PublicKey)
+	return keys
+}
+
+func (m *MockJWTService) GenerateMFAToken(userID string) (string, error) {
+	args := m.Called(userID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockJWTService) ValidateMFAToken(token string) (*domain.TokenClaims, error) {
+	args := m.Called(token)
+	claims, _ := args.Get(0).(*domain.TokenClaims)
+	return claims, args.Error(1)
+}
+
+func (m *MockJWTService) GenerateMagicLinkToken(linkID, email string, expiresAt time.Time) (string, error) {
+	args := m.Called(linkID, email, expiresAt)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockJWTService) ValidateMagicLinkToken(token string) (string, string, error) {
+	args := m.Called(token)
+	return args.String(0), args.String(1), args.Error(2)
+}
+
+func (m *MockJWTService) GenerateEmailVerificationToken(userID, email string) (string, error) {
+	args := m.Called(userID, email)
 	return args.String(0), args.Error(1)
 }
 
-func (m *MockJWTService) ValidateToken(token string) (*domain.User, error) {
+func (m *MockJWTService) ValidateEmailVerificationToken(token string) (string, string, error) {
 	args := m.Called(token)
-	return args.Get(0).(*domain.User), args.Error(1)
+	return args.String(0), args.String(1), args.Error(2)
+}
+
+type MockMFAUseCase struct{ mock.Mock }
+
+func (m *MockMFAUseCase) EnrollTOTP(ctx context.Context, userID string) (*domain.TOTPSetup, error) {
+	args := m.Called(ctx, userID)
+	setup, _ := args.Get(0).(*domain.TOTPSetup)
+	return setup, args.Error(1)
+}
+
+func (m *MockMFAUseCase) ConfirmTOTP(ctx context.Context, userID, code string) ([]string, error) {
+	args := m.Called(ctx, userID, code)
+	codes, _ := args.Get(0).([]string)
+	return codes, args.Error(1)
+}
+
+func (m *MockMFAUseCase) DisableTOTP(ctx context.Context, userID, code string) error {
+	args := m.Called(ctx, userID, code)
+	return args.Error(0)
+}
+
+func (m *MockMFAUseCase) Required(ctx context.Context, userID string) (bool, error) {
+	args := m.Called(ctx, userID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockMFAUseCase) Verify(ctx context.Context, userID, code string) error {
+	args := m.Called(ctx, userID, code)
+	return args.Error(0)
+}
+
+// noMFA is a second factor use case for users that did not enrol
+func noMFA() *MockMFAUseCase {
+	m := new(MockMFAUseCase)
+	m.On("Required", mock.Anything, mock.Anything).Return(false, nil)
+	return m
+}
+
+type MockPasswordHasher struct{ mock.Mock }
+
+func (m *MockPasswordHasher) Hash(password string) (string, error) {
+	args := m.Called(password)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockPasswordHasher) Compare(hash, password string) error {
+	args := m.Called(hash, password)
+	return args.Error(0)
 }
 
 func TestAuthUseCase_LoginOrRegister(t *testing.T) {
+	linked := &domain.Identity{UserID: "user-id", Provider: domain.ProviderFirebase, Subject: "firebase-uid"}
+
 	tests := []struct {
 		name           string
 		firebaseToken  string
 		firebaseUser   *services.FirebaseUser
 		verifyErr      error
-		upsertResult   *domain.User
-		upsertErr      error
+		identity       *domain.Identity
+		identityErr    error
+		user           *domain.User
 		jwtToken       string
 		jwtErr         error
+		refreshErr     error
 		expectErr      bool
 		expectedToken  string
 		expectedUserID string
@@ -105,7 +378,8 @@ func TestAuthUseCase_LoginOrRegister(t *testing.T) {
 				Name:    "Test User",
 				Picture: "http://picture.url",
 			},
-			upsertResult:   &domain.User{ID: "user-id", FirebaseUID: "firebase-uid"},
+			identity:       linked,
+			user:           &domain.User{ID: "user-id", FirebaseUID: "firebase-uid"},
 			jwtToken:       "jwt-token",
 			expectedToken:  "jwt-token",
 			expectedUserID: "user-id",
@@ -116,13 +390,13 @@ func TestAuthUseCase_LoginOrRegister(t *testing.T) {
 			expectErr: true,
 		},
 		{
-			name: "user repo fails",
+			name: "identity repo fails",
 			firebaseUser: &services.FirebaseUser{
 				UID:   "firebase-uid",
 				Email: "user@example.com",
 			},
-			upsertErr: errors.New("db error"),
-			expectErr: true,
+			identityErr: errors.New("db error"),
+			expectErr:   true,
 		},
 		{
 			name: "jwt generation fails",
@@ -130,18 +404,33 @@ func TestAuthUseCase_LoginOrRegister(t *testing.T) {
 				UID:   "firebase-uid",
 				Email: "user@example.com",
 			},
-			upsertResult: &domain.User{ID: "user-id", FirebaseUID: "firebase-uid"},
-			jwtErr:       errors.New("jwt fail"),
-			expectErr:    true,
+			identity:  linked,
+			user:      &domain.User{ID: "user-id", FirebaseUID: "firebase-uid"},
+			jwtErr:    errors.New("jwt fail"),
+			expectErr: true,
+		},
+		{
+			name: "refresh token persistence fails",
+			firebaseUser: &services.FirebaseUser{
+				UID:   "firebase-uid",
+				Email: "user@example.com",
+			},
+			identity:   linked,
+			user:       &domain.User{ID: "user-id", FirebaseUID: "firebase-uid"},
+			jwtToken:   "jwt-token",
+			refreshErr: errors.New("db error"),
+			expectErr:  true,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := new(MockUserRepo)
+			mockIdentities := new(MockIdentityRepo)
+			mockRefresh := new(MockRefreshTokenRepo)
 			mockFirebase := new(MockFirebaseAuth)
 			mockJWT := new(MockJWTService)
-			authUC := usecases.NewAuthUseCase(mockRepo, mockFirebase, mockJWT)
+			authUC := usecases.NewAuthUseCase(mockRepo, noRoles(), mockIdentities, mockRefresh, noSessions(), new(MockRevocationStore), mockFirebase, nil, noMFA(), nil, nil, mockJWT, new(MockPasswordHasher))
 
 			if tt.verifyErr != nil || tt.firebaseUser != nil {
 				mockFirebase.On("VerifyToken", mock.Anything, tt.firebaseToken).
@@ -149,28 +438,649 @@ func TestAuthUseCase_LoginOrRegister(t *testing.T) {
 			}
 
 			if tt.firebaseUser != nil {
-				mockRepo.On("UpsertByFirebaseUID", mock.Anything, mock.AnythingOfType("*domain.User")).
-					Return(tt.upsertResult, tt.upsertErr)
+				mockIdentities.On("FindByProviderSubject", mock.Anything, domain.ProviderFirebase, tt.firebaseUser.UID).
+					Return(tt.identity, tt.identityErr)
 			}
 
-			if tt.upsertResult != nil {
-				mockJWT.On("GenerateToken", tt.upsertResult).
+			if tt.user != nil {
+				mockRepo.On("FindByID", mock.Anything, tt.identity.UserID).Return(tt.user, nil)
+				mockJWT.On("GenerateToken", tt.user, mock.Anything).
 					Return(tt.jwtToken, tt.jwtErr)
 			}
 
-			user, token, err := authUC.LoginOrRegister(context.Background(), tt.firebaseToken)
+			if tt.user != nil && tt.jwtErr == nil {
+				mockRefresh.On("Create", mock.Anything, mock.MatchedBy(func(rt *domain.RefreshToken) bool {
+					return rt.UserID == tt.user.ID && rt.FamilyID != "" && rt.TokenHash != ""
+				})).Return(tt.refreshErr)
+			}
+
+			user, tokens, err := authUC.LoginOrRegister(context.Background(), "", tt.firebaseToken)
 
 			if tt.expectErr {
 				assert.Error(t, err)
 			} else {
 				assert.NoError(t, err)
-				assert.Equal(t, tt.expectedToken, token)
+				assert.Equal(t, tt.expectedToken, tokens.AccessToken)
+				assert.NotEmpty(t, tokens.RefreshToken)
 				assert.Equal(t, tt.expectedUserID, user.ID)
 			}
 		})
 	}
 }
 
+func TestAuthUseCase_LoginOrRegister_FirebaseFirstLogin(t *testing.T) {
+	mockRepo := new(MockUserRepo)
+	mockIdentities := new(MockIdentityRepo)
+	mockRefresh := new(MockRefreshTokenRepo)
+	mockFirebase := new(MockFirebaseAuth)
+	mockJWT := new(MockJWTService)
+	authUC := usecases.NewAuthUseCase(mockRepo, noRoles(), mockIdentities, mockRefresh, noSessions(), new(MockRevocationStore), mockFirebase, nil, noMFA(), nil, nil, mockJWT, new(MockPasswordHasher))
+
+	mockFirebase.On("VerifyToken", mock.Anything, "firebase-token").
+		Return(&services.FirebaseUser{UID: "firebase-uid", Email: "user@example.com", Name: "Test User"}, nil)
+	mockIdentities.On("FindByProviderSubject", mock.Anything, domain.ProviderFirebase, "firebase-uid").Return(nil, sql.ErrNoRows)
+	mockRepo.On("FindByEmail", mock.Anything, "user@example.com").Return(nil, sql.ErrNoRows)
+	mockIdentities.On("CreateWithUser", mock.Anything,
+		mock.MatchedBy(func(u *domain.User) bool { return u.FirebaseUID == "firebase-uid" }),
+		mock.MatchedBy(func(i *domain.Identity) bool {
+			return i.Provider == domain.ProviderFirebase && i.Subject == "firebase-uid"
+		}),
+	).Return(nil)
+	mockJWT.On("GenerateToken", mock.Anything, mock.Anything).Return("jwt-token", nil)
+	mockRefresh.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+	user, _, err := authUC.LoginOrRegister(context.Background(), domain.ProviderFirebase, "firebase-token")
+	assert.NoError(t, err)
+	assert.Equal(t, "Test User", user.Name)
+	mockIdentities.AssertExpectations(t)
+}
+
+func TestAuthUseCase_FirebaseDisabled(t *testing.T) {
+	authUC := usecases.NewAuthUseCase(new(MockUserRepo), noRoles(), new(MockIdentityRepo), new(MockRefreshTokenRepo), noSessions(), new(MockRevocationStore), nil, nil, noMFA(), nil, nil, new(MockJWTService), new(MockPasswordHasher))
+
+	_, _, err := authUC.LoginOrRegister(context.Background(), domain.ProviderFirebase, "firebase-token")
+	assert.ErrorIs(t, err, domain.ErrFirebaseDisabled)
+
+	err = authUC.ResetPassword(context.Background(), "user@example.com")
+	assert.ErrorIs(t, err, domain.ErrFirebaseDisabled)
+}
+
+func TestAuthUseCase_LoginOrRegister_IdentityProvider(t *testing.T) {
+	external := &services.ExternalIdentity{
+		Provider: "acme",
+		Subject:  "acme-sub",
+		Email:    "User@Example.com",
+		Name:     "Test User",
+	}
+	verified := &services.ExternalIdentity{
+		Provider:      "acme",
+		Subject:       "acme-sub",
+		Email:         "user@example.com",
+		EmailVerified: true,
+	}
+	// markEmailVerified flags the returned user, every case gets its own copy
+	existing := func() *domain.User { return &domain.User{ID: "user-id", Email: "user@example.com"} }
+
+	tests := []struct {
+		name         string
+		provider     string
+		identity     *services.ExternalIdentity
+		verifyErr    error
+		setup        func(*MockUserRepo, *MockIdentityRepo)
+		wantErr      error
+		wantNew      bool
+		wantVerified bool
+	}{
+		{
+			name:     "returning user",
+			provider: "acme",
+			identity: external,
+			setup: func(users *MockUserRepo, identities *MockIdentityRepo) {
+				identities.On("FindByProviderSubject", mock.Anything, "acme", "acme-sub").
+					Return(&domain.Identity{UserID: "user-id"}, nil)
+				users.On("FindByID", mock.Anything, "user-id").Return(existing(), nil)
+			},
+		},
+		{
+			name:     "returning user with an e-mail verified by the provider",
+			provider: "acme",
+			identity: verified,
+			setup: func(users *MockUserRepo, identities *MockIdentityRepo) {
+				identities.On("FindByProviderSubject", mock.Anything, "acme", "acme-sub").
+					Return(&domain.Identity{UserID: "user-id"}, nil)
+				users.On("FindByID", mock.Anything, "user-id").Return(existing(), nil)
+				users.On("MarkEmailVerified", mock.Anything, "user-id").Return(nil)
+			},
+			wantVerified: true,
+		},
+		{
+			name:     "first login creates the user",
+			provider: "acme",
+			identity: external,
+			setup: func(users *MockUserRepo, identities *MockIdentityRepo) {
+				identities.On("FindByProviderSubject", mock.Anything, "acme", "acme-sub").Return(nil, sql.ErrNoRows)
+				users.On("FindByEmail", mock.Anything, "user@example.com").Return(nil, sql.ErrNoRows)
+				identities.On("CreateWithUser", mock.Anything,
+					mock.MatchedBy(func(u *domain.User) bool { return u.Email == "user@example.com" && u.Name == "Test User" }),
+					mock.MatchedBy(func(i *domain.Identity) bool {
+						return i.Provider == "acme" && i.Subject == "acme-sub" && i.UserID != ""
+					}),
+				).Return(nil)
+			},
+			wantNew: true,
+		},
+		{
+			name:     "verified e-mail links the verified existing account",
+			provider: "acme",
+			identity: verified,
+			setup: func(users *MockUserRepo, identities *MockIdentityRepo) {
+				identities.On("FindByProviderSubject", mock.Anything, "acme", "acme-sub").Return(nil, sql.ErrNoRows)
+				users.On("FindByEmail", mock.Anything, "user@example.com").
+					Return(&domain.User{ID: "user-id", Email: "user@example.com", EmailVerified: true}, nil)
+				identities.On("Create", mock.Anything, mock.MatchedBy(func(i *domain.Identity) bool {
+					return i.UserID == "user-id" && i.Provider == "acme" && i.Subject == "acme-sub"
+				})).Return(nil)
+			},
+			wantVerified: true,
+		},
+		{
+			name:     "verified e-mail owned by an unverified account with a password",
+			provider: "acme",
+			identity: verified,
+			setup: func(users *MockUserRepo, identities *MockIdentityRepo) {
+				identities.On("FindByProviderSubject", mock.Anything, "acme", "acme-sub").Return(nil, sql.ErrNoRows)
+				users.On("FindByEmail", mock.Anything, "user@example.com").
+					Return(&domain.User{ID: "user-id", Email: "user@example.com", PasswordHash: "attacker-hash"}, nil)
+			},
+			wantErr: domain.ErrIdentityLinkRequired,
+		},
+		{
+			name:     "unverified e-mail owned by another account",
+			provider: "acme",
+			identity: external,
+			setup: func(users *MockUserRepo, identities *MockIdentityRepo) {
+				identities.On("FindByProviderSubject", mock.Anything, "acme", "acme-sub").Return(nil, sql.ErrNoRows)
+				users.On("FindByEmail", mock.Anything, "user@example.com").Return(existing(), nil)
+			},
+			wantErr: domain.ErrIdentityLinkRequired,
+		},
+		{
+			name:     "missing e-mail",
+			provider: "acme",
+			identity: &services.ExternalIdentity{Provider: "acme", Subject: "acme-sub"},
+			setup: func(users *MockUserRepo, identities *MockIdentityRepo) {
+				identities.On("FindByProviderSubject", mock.Anything, "acme", "acme-sub").Return(nil, sql.ErrNoRows)
+			},
+			wantErr: domain.ErrIdentityEmailMissing,
+		},
+		{
+			name:      "invalid token",
+			provider:  "acme",
+			verifyErr: errors.New("token is expired"),
+			setup:     func(*MockUserRepo, *MockIdentityRepo) {},
+			wantErr:   errors.New("invalid identity token: token is expired"),
+		},
+		{
+			name:     "unknown provider",
+			provider: "other",
+			setup:    func(*MockUserRepo, *MockIdentityRepo) {},
+			wantErr:  domain.ErrUnknownIdentityProvider,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockUserRepo)
+			mockIdentities := new(MockIdentityRepo)
+			mockProvider := new(MockIdentityProvider)
+			mockRefresh := new(MockRefreshTokenRepo)
+			mockJWT := new(MockJWTService)
+			authUC := usecases.NewAuthUseCase(mockRepo, noRoles(), mockIdentities, mockRefresh, noSessions(), new(MockRevocationStore), nil, []services.IdentityProvider{mockProvider}, noMFA(), nil, nil, mockJWT, new(MockPasswordHasher))
+
+			mockProvider.On("VerifyToken", mock.Anything, "id-token").Return(tt.identity, tt.verifyErr)
+			tt.setup(mockRepo, mockIdentities)
+			mockJWT.On("GenerateToken", mock.Anything, mock.Anything).Return("jwt-token", nil)
+			mockRefresh.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+			user, tokens, err := authUC.LoginOrRegister(context.Background(), tt.provider, "id-token")
+
+			if tt.wantErr != nil {
+				assert.EqualError(t, err, tt.wantErr.Error())
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "jwt-token", tokens.AccessToken)
+			assert.Equal(t, "user@example.com", user.Email)
+			if tt.wantNew {
+				assert.NotEqual(t, "user-id", user.ID)
+			} else {
+				assert.Equal(t, "user-id", user.ID)
+			}
+			assert.Equal(t, tt.wantVerified, user.EmailVerified)
+			mockRepo.AssertExpectations(t)
+			mockIdentities.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuthUseCase_LinkIdentity(t *testing.T) {
+	external := &services.ExternalIdentity{Provider: "acme", Subject: "acme-sub", Email: "other@example.com"}
+
+	tests := []struct {
+		name    string
+		setup   func(*MockIdentityRepo)
+		wantErr error
+	}{
+		{
+			name: "links a new identity",
+			setup: func(identities *MockIdentityRepo) {
+				identities.On("FindByProviderSubject", mock.Anything, "acme", "acme-sub").Return(nil, sql.ErrNoRows)
+				identities.On("Create", mock.Anything, mock.MatchedBy(func(i *domain.Identity) bool {
+					return i.UserID == "user-id" && i.Email == "other@example.com"
+				})).Return(nil)
+			},
+		},
+		{
+			name: "already linked to the same user",
+			setup: func(identities *MockIdentityRepo) {
+				identities.On("FindByProviderSubject", mock.Anything, "acme", "acme-sub").
+					Return(&domain.Identity{UserID: "user-id", Provider: "acme", Subject: "acme-sub"}, nil)
+			},
+		},
+		{
+			name: "linked to another user",
+			setup: func(identities *MockIdentityRepo) {
+				identities.On("FindByProviderSubject", mock.Anything, "acme", "acme-sub").
+					Return(&domain.Identity{UserID: "other-user", Provider: "acme", Subject: "acme-sub"}, nil)
+			},
+			wantErr: domain.ErrIdentityAlreadyLinked,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockIdentities := new(MockIdentityRepo)
+			mockProvider := new(MockIdentityProvider)
+			authUC := usecases.NewAuthUseCase(new(MockUserRepo), noRoles(), mockIdentities, new(MockRefreshTokenRepo), noSessions(), new(MockRevocationStore), nil, []services.IdentityProvider{mockProvider}, noMFA(), nil, nil, new(MockJWTService), new(MockPasswordHasher))
+
+			mockProvider.On("VerifyToken", mock.Anything, "id-token").Return(external, nil)
+			tt.setup(mockIdentities)
+
+			identity, err := authUC.LinkIdentity(context.Background(), "user-id", "acme", "id-token")
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "user-id", identity.UserID)
+			mockIdentities.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuthUseCase_Register(t *testing.T) {
+	tests := []struct {
+		name      string
+		findUser  *domain.User
+		findErr   error
+		hashErr   error
+		createErr error
+		expectErr error
+	}{
+		{
+			name:    "success",
+			findErr: sql.ErrNoRows,
+		},
+		{
+			name:      "email already registered",
+			findUser:  &domain.User{ID: "existing-id"},
+			expectErr: domain.ErrEmailAlreadyRegistered,
+		},
+		{
+			name:      "lookup fails",
+			findErr:   sql.ErrConnDone,
+			expectErr: sql.ErrConnDone,
+		},
+		{
+			name:      "hash fails",
+			findErr:   sql.ErrNoRows,
+			hashErr:   errors.New("hash error"),
+			expectErr: errors.New("hash error"),
+		},
+		{
+			name:      "create fails",
+			findErr:   sql.ErrNoRows,
+			createErr: sql.ErrConnDone,
+			expectErr: sql.ErrConnDone,
+		},
+		{
+			name:      "email registered by a concurrent sign-up",
+			findErr:   sql.ErrNoRows,
+			createErr: domain.ErrEmailAlreadyRegistered,
+			expectErr: domain.ErrEmailAlreadyRegistered,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockUserRepo)
+			mockRefresh := new(MockRefreshTokenRepo)
+			mockJWT := new(MockJWTService)
+			mockHasher := new(MockPasswordHasher)
+			authUC := usecases.NewAuthUseCase(mockRepo, noRoles(), new(MockIdentityRepo), mockRefresh, noSessions(), new(MockRevocationStore), nil, nil, noMFA(), nil, nil, mockJWT, mockHasher)
+
+			mockRepo.On("FindByEmail", mock.Anything, "user@example.com").Return(tt.findUser, tt.findErr)
+			mockHasher.On("Hash", "strong-password").Return("hashed", tt.hashErr)
+			mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(u *domain.User) bool {
+				return u.Email == "user@example.com" && u.PasswordHash == "hashed" && u.FirebaseUID == ""
+			})).Return(tt.createErr)
+			mockJWT.On("GenerateToken", mock.AnythingOfType("*domain.User"), mock.Anything).Return("jwt-token", nil)
+			mockRefresh.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+			user, tokens, err := authUC.Register(context.Background(), "Test User", "  User@Example.com ", "strong-password")
+
+			if tt.expectErr != nil {
+				assert.EqualError(t, err, tt.expectErr.Error())
+				mockJWT.AssertNotCalled(t, "GenerateToken", mock.Anything, mock.Anything)
+			} else {
+				assert.NoError(t, err)
+				assert.NotEmpty(t, user.ID)
+				assert.Equal(t, "Test User", user.Name)
+				assert.Equal(t, "jwt-token", tokens.AccessToken)
+			}
+		})
+	}
+}
+
+func TestAuthUseCase_LoginWithPassword(t *testing.T) {
+	disabledAt := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name       string
+		findUser   *domain.User
+		findErr    error
+		compareErr error
+		expectErr  error
+	}{
+		{
+			name:     "success",
+			findUser: &domain.User{ID: "user-id", PasswordHash: "hashed"},
+		},
+		{
+			name:      "unknown email",
+			findErr:   sql.ErrNoRows,
+			expectErr: domain.ErrInvalidCredentials,
+		},
+		{
+			name:      "firebase only account",
+			findUser:  &domain.User{ID: "user-id"},
+			expectErr: domain.ErrInvalidCredentials,
+		},
+		{
+			name:       "wrong password",
+			findUser:   &domain.User{ID: "user-id", PasswordHash: "hashed"},
+			compareErr: errors.New("mismatch"),
+			expectErr:  domain.ErrInvalidCredentials,
+		},
+		{
+			name:      "disabled account",
+			findUser:  &domain.User{ID: "user-id", PasswordHash: "hashed", DisabledAt: &disabledAt},
+			expectErr: domain.ErrUserDisabled,
+		},
+		{
+			name:      "lookup fails",
+			findErr:   sql.ErrConnDone,
+			expectErr: sql.ErrConnDone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockUserRepo)
+			mockRefresh := new(MockRefreshTokenRepo)
+			mockJWT := new(MockJWTService)
+			mockHasher := new(MockPasswordHasher)
+			authUC := usecases.NewAuthUseCase(mockRepo, noRoles(), new(MockIdentityRepo), mockRefresh, noSessions(), new(MockRevocationStore), nil, nil, noMFA(), nil, nil, mockJWT, mockHasher)
+
+			mockRepo.On("FindByEmail", mock.Anything, "user@example.com").Return(tt.findUser, tt.findErr)
+			mockHasher.On("Compare", "hashed", "password").Return(tt.compareErr)
+			mockHasher.On("Compare", mock.Anything, "password").Return(errors.New("mismatch"))
+			mockJWT.On("GenerateToken", tt.findUser, mock.Anything).Return("jwt-token", nil)
+			mockRefresh.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+			user, tokens, err := authUC.LoginWithPassword(context.Background(), "user@example.com", "password")
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+				assert.Nil(t, tokens)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "user-id", user.ID)
+				assert.Equal(t, "jwt-token", tokens.AccessToken)
+			}
+		})
+	}
+}
+
+func TestAuthUseCase_LoginWithPassword_MFA(t *testing.T) {
+	mockRepo := new(MockUserRepo)
+	mockJWT := new(MockJWTService)
+	mockHasher := new(MockPasswordHasher)
+	mockMFA := new(MockMFAUseCase)
+	authUC := usecases.NewAuthUseCase(mockRepo, noRoles(), new(MockIdentityRepo), new(MockRefreshTokenRepo), noSessions(), new(MockRevocationStore), nil, nil, mockMFA, nil, nil, mockJWT, mockHasher)
+
+	mockRepo.On("FindByEmail", mock.Anything, "user@example.com").Return(&domain.User{ID: "user-id", PasswordHash: "hashed"}, nil)
+	mockHasher.On("Compare", "hashed", "password").Return(nil)
+	mockMFA.On("Required", mock.Anything, "user-id").Return(true, nil)
+	mockJWT.On("GenerateMFAToken", "user-id").Return("mfa-token", nil)
+
+	user, tokens, err := authUC.LoginWithPassword(context.Background(), "user@example.com", "password")
+
+	var mfaErr *domain.MFARequiredError
+	if assert.ErrorAs(t, err, &mfaErr) {
+		assert.Equal(t, "mfa-token", mfaErr.Token)
+		assert.True(t, mfaErr.ExpiresAt.After(time.Now()))
+	}
+	assert.Nil(t, user)
+	assert.Nil(t, tokens)
+	mockJWT.AssertNotCalled(t, "GenerateToken", mock.Anything, mock.Anything)
+}
+
+func TestAuthUseCase_ExchangeToken_DisabledUser(t *testing.T) {
+	disabledAt := time.Now().Add(-time.Minute)
+	mockRepo := new(MockUserRepo)
+	mockRefresh := new(MockRefreshTokenRepo)
+	mockJWT := new(MockJWTService)
+	authUC := usecases.NewAuthUseCase(mockRepo, noRoles(), new(MockIdentityRepo), mockRefresh, noSessions(), new(MockRevocationStore), nil, nil, noMFA(), nil, nil, mockJWT, new(MockPasswordHasher))
+
+	mockRefresh.On("FindByHash", mock.Anything, mock.Anything).
+		Return(&domain.RefreshToken{ID: "token-id", UserID: "user-id", FamilyID: "family-id", ExpiresAt: time.Now().Add(time.Hour)}, nil)
+	mockRepo.On("FindByID", mock.Anything, "user-id").Return(&domain.User{ID: "user-id", DisabledAt: &disabledAt}, nil)
+
+	user, tokens, err := authUC.ExchangeToken(context.Background(), "refresh-token")
+
+	assert.ErrorIs(t, err, domain.ErrUserDisabled)
+	assert.Nil(t, user)
+	assert.Nil(t, tokens)
+	mockJWT.AssertNotCalled(t, "GenerateToken", mock.Anything, mock.Anything)
+}
+
+func TestAuthUseCase_CompleteMFALogin(t *testing.T) {
+	expiresAt := time.Now().Add(domain.MFATokenTTL)
+	claims := &domain.TokenClaims{UserID: "user-id", TokenID: "mfa-jti", ExpiresAt: expiresAt}
+
+	tests := []struct {
+		name        string
+		validateErr error
+		used        bool
+		verifyErr   error
+		expectErr   error
+	}{
+		{name: "success"},
+		{name: "invalid mfa token", validateErr: errors.New("invalid token"), expectErr: domain.ErrInvalidMFAToken},
+		{name: "mfa token already used", used: true, expectErr: domain.ErrInvalidMFAToken},
+		{name: "invalid code", verifyErr: domain.ErrInvalidMFACode, expectErr: domain.ErrInvalidMFACode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockUserRepo)
+			mockRefresh := new(MockRefreshTokenRepo)
+			mockJWT := new(MockJWTService)
+			mockMFA := new(MockMFAUseCase)
+			mockRevocations := new(MockRevocationStore)
+			authUC := usecases.NewAuthUseCase(mockRepo, noRoles(), new(MockIdentityRepo), mockRefresh, noSessions(), mockRevocations, nil, nil, mockMFA, nil, nil, mockJWT, new(MockPasswordHasher))
+
+			user := &domain.User{ID: "user-id"}
+			if tt.validateErr != nil {
+				mockJWT.On("ValidateMFAToken", "mfa-token").Return(nil, tt.validateErr)
+			} else {
+				mockJWT.On("ValidateMFAToken", "mfa-token").Return(claims, nil)
+			}
+			mockRevocations.On("IsRevoked", mock.Anything, claims).Return(tt.used, nil)
+			mockRevocations.On("RevokeToken", mock.Anything, "mfa-jti", expiresAt).Return(nil)
+			mockMFA.On("Verify", mock.Anything, "user-id", "123456").Return(tt.verifyErr)
+			mockRepo.On("FindByID", mock.Anything, "user-id").Return(user, nil)
+			mockJWT.On("GenerateToken", user, mock.Anything).Return("jwt-token", nil)
+			mockRefresh.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+			got, tokens, err := authUC.CompleteMFALogin(context.Background(), "mfa-token", "123456")
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+				assert.Nil(t, tokens)
+				mockJWT.AssertNotCalled(t, "GenerateToken", mock.Anything, mock.Anything)
+				// A failed attempt leaves the token usable until the login guard locks the user out
+				mockRevocations.AssertNotCalled(t, "RevokeToken", mock.Anything, mock.Anything, mock.Anything)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "user-id", got.ID)
+				assert.Equal(t, "jwt-token", tokens.AccessToken)
+				mockRevocations.AssertCalled(t, "RevokeToken", mock.Anything, "mfa-jti", expiresAt)
+			}
+		})
+	}
+}
+
+func TestAuthUseCase_PendingMFAUser(t *testing.T) {
+	mockJWT := new(MockJWTService)
+	authUC := usecases.NewAuthUseCase(new(MockUserRepo), noRoles(), new(MockIdentityRepo), new(MockRefreshTokenRepo), noSessions(), new(MockRevocationStore), nil, nil, noMFA(), nil, nil, mockJWT, new(MockPasswordHasher))
+
+	mockJWT.On("ValidateMFAToken", "mfa-token").Return(&domain.TokenClaims{UserID: "user-id", TokenID: "mfa-jti"}, nil)
+	mockJWT.On("ValidateMFAToken", "other-token").Return(nil, errors.New("invalid token"))
+
+	userID, err := authUC.PendingMFAUser("mfa-token")
+	assert.NoError(t, err)
+	assert.Equal(t, "user-id", userID)
+
+	_, err = authUC.PendingMFAUser("other-token")
+	assert.ErrorIs(t, err, domain.ErrInvalidMFAToken)
+}
+
+func TestAuthUseCase_IssuesRolesInToken(t *testing.T) {
+	mockRepo := new(MockUserRepo)
+	mockRoles := new(MockRoleRepo)
+	mockRefresh := new(MockRefreshTokenRepo)
+	mockJWT := new(MockJWTService)
+	mockHasher := new(MockPasswordHasher)
+	authUC := usecases.NewAuthUseCase(mockRepo, mockRoles, new(MockIdentityRepo), mockRefresh, noSessions(), new(MockRevocationStore), nil, nil, noMFA(), nil, nil, mockJWT, mockHasher)
+
+	mockRepo.On("FindByEmail", mock.Anything, "user@example.com").Return(&domain.User{ID: "user-id", PasswordHash: "hashed"}, nil)
+	mockHasher.On("Compare", "hashed", "password").Return(nil)
+	mockRoles.On("FindByUserID", mock.Anything, "user-id").Return([]domain.Role{
+		{Name: "admin", Permissions: []string{"users:read", "users:write"}},
+		{Name: "support", Permissions: []string{"users:read"}},
+	}, nil)
+	mockJWT.On("GenerateToken", mock.MatchedBy(func(u *domain.User) bool {
+		return assert.ObjectsAreEqual([]string{"admin", "support"}, u.Roles) &&
+			assert.ObjectsAreEqual([]string{"users:read", "users:write"}, u.Permissions)
+	}), mock.Anything).Return("jwt-token", nil)
+	mockRefresh.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+	_, tokens, err := authUC.LoginWithPassword(context.Background(), "user@example.com", "password")
+	assert.NoError(t, err)
+	assert.Equal(t, "jwt-token", tokens.AccessToken)
+	mockJWT.AssertExpectations(t)
+}
+
+func TestAuthUseCase_ExchangeToken(t *testing.T) {
+	revokedAt := time.Now().Add(-time.Minute)
+	active := &domain.RefreshToken{ID: "token-id", UserID: "user-id", FamilyID: "family-id", ExpiresAt: time.Now().Add(time.Hour)}
+	user := &domain.User{ID: "user-id"}
+
+	tests := []struct {
+		name               string
+		stored             *domain.RefreshToken
+		findErr            error
+		rotateErr          error
+		expectFamilyRevoke bool
+		expectErr          error
+	}{
+		{
+			name:   "success",
+			stored: active,
+		},
+		{
+			name:      "unknown token",
+			findErr:   sql.ErrNoRows,
+			expectErr: domain.ErrInvalidRefreshToken,
+		},
+		{
+			name:      "expired token",
+			stored:    &domain.RefreshToken{ID: "token-id", UserID: "user-id", FamilyID: "family-id", ExpiresAt: time.Now().Add(-time.Hour)},
+			expectErr: domain.ErrInvalidRefreshToken,
+		},
+		{
+			name:               "reused token revokes family",
+			stored:             &domain.RefreshToken{ID: "token-id", UserID: "user-id", FamilyID: "family-id", ExpiresAt: time.Now().Add(time.Hour), RevokedAt: &revokedAt},
+			expectFamilyRevoke: true,
+			expectErr:          domain.ErrRefreshTokenReused,
+		},
+		{
+			name:               "concurrent rotation revokes family",
+			stored:             active,
+			rotateErr:          domain.ErrRefreshTokenReused,
+			expectFamilyRevoke: true,
+			expectErr:          domain.ErrRefreshTokenReused,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockUserRepo)
+			mockRefresh := new(MockRefreshTokenRepo)
+			mockFirebase := new(MockFirebaseAuth)
+			mockJWT := new(MockJWTService)
+			authUC := usecases.NewAuthUseCase(mockRepo, noRoles(), new(MockIdentityRepo), mockRefresh, noSessions(), new(MockRevocationStore), mockFirebase, nil, noMFA(), nil, nil, mockJWT, new(MockPasswordHasher))
+
+			mockRefresh.On("FindByHash", mock.Anything, mock.Anything).Return(tt.stored, tt.findErr)
+			mockRefresh.On("RevokeFamily", mock.Anything, "family-id").Return(nil)
+			mockRepo.On("FindByID", mock.Anything, "user-id").Return(user, nil)
+			mockJWT.On("GenerateToken", user, "family-id").Return("new-jwt", nil)
+			mockRefresh.On("Rotate", mock.Anything, tt.stored, mock.MatchedBy(func(next *domain.RefreshToken) bool {
+				return next.FamilyID == "family-id" && next.UserID == "user-id"
+			})).Return(tt.rotateErr)
+
+			gotUser, tokens, err := authUC.ExchangeToken(context.Background(), "plain-refresh-token")
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "user-id", gotUser.ID)
+				assert.Equal(t, "new-jwt", tokens.AccessToken)
+				assert.NotEqual(t, "plain-refresh-token", tokens.RefreshToken)
+			}
+
+			if tt.expectFamilyRevoke {
+				mockRefresh.AssertCalled(t, "RevokeFamily", mock.Anything, "family-id")
+			} else {
+				mockRefresh.AssertNotCalled(t, "RevokeFamily", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
 func TestAuthUseCase_ResetPassword(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -193,9 +1103,10 @@ func TestAuthUseCase_ResetPassword(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := new(MockUserRepo)
+			mockRefresh := new(MockRefreshTokenRepo)
 			mockFirebase := new(MockFirebaseAuth)
 			mockJWT := new(MockJWTService)
-			authUC := usecases.NewAuthUseCase(mockRepo, mockFirebase, mockJWT)
+			authUC := usecases.NewAuthUseCase(mockRepo, noRoles(), new(MockIdentityRepo), mockRefresh, noSessions(), new(MockRevocationStore), mockFirebase, nil, noMFA(), nil, nil, mockJWT, new(MockPasswordHasher))
 
 			mockFirebase.On("SendPasswordReset", mock.Anything, tt.email).
 				Return(tt.sendErr)
@@ -210,3 +1121,223 @@ func TestAuthUseCase_ResetPassword(t *testing.T) {
 		})
 	}
 }
+
+func TestAuthUseCase_Logout(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour)
+	principal := &domain.Principal{UserID: "user-id", TokenID: "jti-1", ExpiresAt: expiresAt}
+
+	tests := []struct {
+		name          string
+		refreshToken  string
+		allSessions   bool
+		stored        *domain.RefreshToken
+		findErr       error
+		expectRevoke  bool
+		expectFamily  bool
+		expectAllUser bool
+		expectErr     error
+	}{
+		{
+			name:         "access token only",
+			expectRevoke: true,
+		},
+		{
+			name:         "with refresh token",
+			refreshToken: "refresh-token",
+			stored:       &domain.RefreshToken{UserID: "user-id", FamilyID: "family-id"},
+			expectRevoke: true,
+			expectFamily: true,
+		},
+		{
+			name:         "refresh token of another user",
+			refreshToken: "refresh-token",
+			stored:       &domain.RefreshToken{UserID: "other-user", FamilyID: "family-id"},
+			expectRevoke: true,
+			expectErr:    domain.ErrInvalidRefreshToken,
+		},
+		{
+			name:         "unknown refresh token",
+			refreshToken: "refresh-token",
+			findErr:      sql.ErrNoRows,
+			expectRevoke: true,
+			expectErr:    domain.ErrInvalidRefreshToken,
+		},
+		{
+			name:          "all sessions",
+			allSessions:   true,
+			expectAllUser: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRefresh := new(MockRefreshTokenRepo)
+			mockRevocations := new(MockRevocationStore)
+			authUC := usecases.NewAuthUseCase(new(MockUserRepo), noRoles(), new(MockIdentityRepo), mockRefresh, noSessions(), mockRevocations, nil, nil, noMFA(), nil, nil, new(MockJWTService), new(MockPasswordHasher))
+
+			mockRevocations.On("RevokeToken", mock.Anything, "jti-1", expiresAt).Return(nil)
+			mockRevocations.On("RevokeUserTokens", mock.Anything, "user-id", mock.AnythingOfType("time.Time")).Return(nil)
+			mockRefresh.On("FindByHash", mock.Anything, mock.Anything).Return(tt.stored, tt.findErr)
+			mockRefresh.On("RevokeFamily", mock.Anything, "family-id").Return(nil)
+			mockRefresh.On("RevokeAllForUser", mock.Anything, "user-id").Return(nil)
+
+			err := authUC.Logout(context.Background(), principal, tt.refreshToken, tt.allSessions)
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			if tt.expectRevoke {
+				mockRevocations.AssertCalled(t, "RevokeToken", mock.Anything, "jti-1", expiresAt)
+			}
+			if tt.expectFamily {
+				mockRefresh.AssertCalled(t, "RevokeFamily", mock.Anything, "family-id")
+			} else {
+				mockRefresh.AssertNotCalled(t, "RevokeFamily", mock.Anything, mock.Anything)
+			}
+			if tt.expectAllUser {
+				// iat is in whole seconds, the cutoff is too
+				mockRevocations.AssertCalled(t, "RevokeUserTokens", mock.Anything, "user-id", mock.MatchedBy(func(before time.Time) bool {
+					return before.Equal(before.Truncate(time.Second))
+				}))
+				mockRefresh.AssertCalled(t, "RevokeAllForUser", mock.Anything, "user-id")
+			}
+		})
+	}
+}
+
+func TestAuthUseCase_RevokeAllSessions(t *testing.T) {
+	tests := []struct {
+		name      string
+		revokeErr error
+		expectErr bool
+	}{
+		{name: "success"},
+		{name: "store fails", revokeErr: errors.New("store error"), expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRefresh := new(MockRefreshTokenRepo)
+			mockRevocations := new(MockRevocationStore)
+			authUC := usecases.NewAuthUseCase(new(MockUserRepo), noRoles(), new(MockIdentityRepo), mockRefresh, noSessions(), mockRevocations, nil, nil, noMFA(), nil, nil, new(MockJWTService), new(MockPasswordHasher))
+
+			mockRevocations.On("RevokeUserTokens", mock.Anything, "user-id", mock.AnythingOfType("time.Time")).Return(tt.revokeErr)
+			mockRefresh.On("RevokeAllForUser", mock.Anything, "user-id").Return(nil)
+
+			err := authUC.RevokeAllSessions(context.Background(), "user-id")
+			if tt.expectErr {
+				assert.Error(t, err)
+				mockRefresh.AssertNotCalled(t, "RevokeAllForUser", mock.Anything, mock.Anything)
+			} else {
+				assert.NoError(t, err)
+				mockRefresh.AssertCalled(t, "RevokeAllForUser", mock.Anything, "user-id")
+			}
+		})
+	}
+}
+
+func TestAuthUseCase_RecordsSession(t *testing.T) {
+	mockRepo := new(MockUserRepo)
+	mockRefresh := new(MockRefreshTokenRepo)
+	mockSessions := new(MockSessionRepo)
+	mockJWT := new(MockJWTService)
+	mockHasher := new(MockPasswordHasher)
+	authUC := usecases.NewAuthUseCase(mockRepo, noRoles(), new(MockIdentityRepo), mockRefresh, mockSessions, new(MockRevocationStore), nil, nil, noMFA(), nil, nil, mockJWT, mockHasher)
+
+	var familyID, sessionID string
+	mockRepo.On("FindByEmail", mock.Anything, "user@example.com").Return(&domain.User{ID: "user-id", PasswordHash: "hashed"}, nil)
+	mockHasher.On("Compare", "hashed", "password").Return(nil)
+	mockJWT.On("GenerateToken", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		sessionID = args.String(1)
+	}).Return("jwt-token", nil)
+	mockRefresh.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		familyID = args.Get(1).(*domain.RefreshToken).FamilyID
+	}).Return(nil)
+	mockSessions.On("Upsert", mock.Anything, mock.MatchedBy(func(s *domain.Session) bool {
+		return s.UserID == "user-id" && s.IP == "203.0.113.7" && s.UserAgent == "Mozilla/5.0"
+	})).Return(nil)
+
+	ctx := domain.WithClientInfo(context.Background(), domain.ClientInfo{IP: "203.0.113.7", UserAgent: "Mozilla/5.0"})
+	_, _, err := authUC.LoginWithPassword(ctx, "user@example.com", "password")
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, sessionID)
+	assert.Equal(t, familyID, sessionID)
+	mockSessions.AssertExpectations(t)
+}
+
+func TestAuthUseCase_ListSessions(t *testing.T) {
+	mockSessions := new(MockSessionRepo)
+	authUC := usecases.NewAuthUseCase(new(MockUserRepo), noRoles(), new(MockIdentityRepo), new(MockRefreshTokenRepo), mockSessions, new(MockRevocationStore), nil, nil, noMFA(), nil, nil, new(MockJWTService), new(MockPasswordHasher))
+
+	mockSessions.On("ListActiveByUserID", mock.Anything, "user-id").Return([]domain.Session{{ID: "session-1"}, {ID: "session-2"}}, nil)
+
+	sessions, err := authUC.ListSessions(context.Background(), "user-id")
+	assert.NoError(t, err)
+	assert.Len(t, sessions, 2)
+}
+
+func TestAuthUseCase_RevokeSession(t *testing.T) {
+	tests := []struct {
+		name         string
+		session      *domain.Session
+		findErr      error
+		revokeErr    error
+		expectErr    error
+		expectRevoke bool
+	}{
+		{
+			name:         "success",
+			session:      &domain.Session{ID: "session-id", UserID: "user-id"},
+			expectRevoke: true,
+		},
+		{
+			name:      "unknown session",
+			findErr:   sql.ErrNoRows,
+			expectErr: domain.ErrSessionNotFound,
+		},
+		{
+			name:      "session of another user",
+			session:   &domain.Session{ID: "session-id", UserID: "other-user"},
+			expectErr: domain.ErrSessionNotFound,
+		},
+		{
+			name:      "revocation store fails",
+			session:   &domain.Session{ID: "session-id", UserID: "user-id"},
+			revokeErr: errors.New("store error"),
+			expectErr: errors.New("store error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRefresh := new(MockRefreshTokenRepo)
+			mockSessions := new(MockSessionRepo)
+			mockRevocations := new(MockRevocationStore)
+			authUC := usecases.NewAuthUseCase(new(MockUserRepo), noRoles(), new(MockIdentityRepo), mockRefresh, mockSessions, mockRevocations, nil, nil, noMFA(), nil, nil, new(MockJWTService), new(MockPasswordHasher))
+
+			mockSessions.On("FindByID", mock.Anything, "session-id").Return(tt.session, tt.findErr)
+			mockRevocations.On("RevokeSession", mock.Anything, "session-id", mock.AnythingOfType("time.Time")).Return(tt.revokeErr)
+			mockRefresh.On("RevokeFamily", mock.Anything, "session-id").Return(nil)
+
+			err := authUC.RevokeSession(context.Background(), "user-id", "session-id")
+
+			if tt.expectErr != nil {
+				assert.Error(t, err)
+				if errors.Is(tt.expectErr, domain.ErrSessionNotFound) {
+					assert.ErrorIs(t, err, domain.ErrSessionNotFound)
+				}
+			} else {
+				assert.NoError(t, err)
+			}
+			if tt.expectRevoke {
+				mockRefresh.AssertCalled(t, "RevokeFamily", mock.Anything, "session-id")
+			} else {
+				mockRefresh.AssertNotCalled(t, "RevokeFamily", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
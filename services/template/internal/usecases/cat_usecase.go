@@ -0,0 +1,89 @@
+package usecases
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/internal/ports/repositories"
+
+	"github.com/google/uuid"
+)
+
+// CatUseCase manages the cats of a user. Every method is scoped to userID, the cats of other
+// users are reported as domain.ErrCatNotFound.
+type CatUseCase interface {
+	List(ctx context.Context, userID string) ([]domain.Cat, error)
+	Get(ctx context.Context, userID, catID string) (*domain.Cat, error)
+	// Create registers the cat for cat.UserID under a new ID. Fails with a *domain.EntitlementError
+	// when the plan of the user doesn't allow another cat.
+	Create(ctx context.Context, cat *domain.Cat) (*domain.Cat, error)
+	// Update replaces every detail of the cat identified by cat.ID and cat.UserID
+	Update(ctx context.Context, cat *domain.Cat) (*domain.Cat, error)
+	Delete(ctx context.Context, userID, catID string) error
+}
+
+type catUseCase struct {
+	catRepo repositories.CatRepository
+}
+
+func NewCatUseCase(catRepo repositories.CatRepository) CatUseCase {
+	return &catUseCase{catRepo: catRepo}
+}
+
+func (c *catUseCase) List(ctx context.Context, userID string) ([]domain.Cat, error) {
+	return c.catRepo.ListByUserID(ctx, userID)
+}
+
+func (c *catUseCase) Get(ctx context.Context, userID, catID string) (*domain.Cat, error) {
+	cat, err := c.catRepo.FindByID(ctx, userID, catID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrCatNotFound
+		}
+		return nil, err
+	}
+	return cat, nil
+}
+
+// Create leaves the cat limit to the repository, which reads the plan and checks it in the same
+// transaction as the insert
+func (c *catUseCase) Create(ctx context.Context, cat *domain.Cat) (*domain.Cat, error) {
+	cat.ID = uuid.NewString()
+	normalizeCat(cat)
+
+	if err := c.catRepo.Create(ctx, cat); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrUserNotFound
+		}
+		return nil, err
+	}
+	return cat, nil
+}
+
+func (c *catUseCase) Update(ctx context.Context, cat *domain.Cat) (*domain.Cat, error) {
+	normalizeCat(cat)
+
+	if err := c.catRepo.Update(ctx, cat); err != nil {
+		return nil, err
+	}
+	return cat, nil
+}
+
+func (c *catUseCase) Delete(ctx context.Context, userID, catID string) error {
+	return c.catRepo.Delete(ctx, userID, catID)
+}
+
+// normalizeCat trims the free text fields, birth dates are stored without a time of day
+func normalizeCat(cat *domain.Cat) {
+	cat.Name = strings.TrimSpace(cat.Name)
+	cat.Breed = strings.TrimSpace(cat.Breed)
+	cat.PictureURL = strings.TrimSpace(cat.PictureURL)
+	if cat.BirthDate != nil {
+		birthDate := cat.BirthDate.UTC().Truncate(24 * time.Hour)
+		cat.BirthDate = &birthDate
+	}
+}
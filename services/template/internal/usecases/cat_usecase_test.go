@@ -0,0 +1,166 @@
+package usecases_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/internal/usecases"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockCatRepo struct{ mock.Mock }
+
+func (m *MockCatRepo) Create(ctx context.Context, cat *domain.Cat) error {
+	args := m.Called(ctx, cat)
+	return args.Error(0)
+}
+
+func (m *MockCatRepo) FindByID(ctx context.Context, userID, id string) (*domain.Cat, error) {
+	args := m.Called(ctx, userID, id)
+	cat, _ := args.Get(0).(*domain.Cat)
+	return cat, args.Error(1)
+}
+
+func (m *MockCatRepo) ListByUserID(ctx context.Context, userID string) ([]domain.Cat, error) {
+	args := m.Called(ctx, userID)
+	cats, _ := args.Get(0).([]domain.Cat)
+	return cats, args.Error(1)
+}
+
+func (m *MockCatRepo) Update(ctx context.Context, cat *domain.Cat) error {
+	args := m.Called(ctx, cat)
+	return args.Error(0)
+}
+
+func (m *MockCatRepo) Delete(ctx context.Context, userID, id string) error {
+	args := m.Called(ctx, userID, id)
+	return args.Error(0)
+}
+
+func TestCatUseCase_Get(t *testing.T) {
+	tests := []struct {
+		name      string
+		findErr   error
+		expectErr error
+	}{
+		{name: "success"},
+		{name: "unknown or not owned", findErr: sql.ErrNoRows, expectErr: domain.ErrCatNotFound},
+		{name: "lookup fails", findErr: sql.ErrConnDone, expectErr: sql.ErrConnDone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockCatRepo)
+			uc := usecases.NewCatUseCase(mockRepo)
+
+			var found *domain.Cat
+			if tt.findErr == nil {
+				found = &domain.Cat{ID: "cat-id", UserID: "user-id", Name: "Mingau"}
+			}
+			mockRepo.On("FindByID", mock.Anything, "user-id", "cat-id").Return(found, tt.findErr)
+
+			cat, err := uc.Get(context.Background(), "user-id", "cat-id")
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+				assert.Nil(t, cat)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "Mingau", cat.Name)
+		})
+	}
+}
+
+func TestCatUseCase_List(t *testing.T) {
+	mockRepo := new(MockCatRepo)
+	uc := usecases.NewCatUseCase(mockRepo)
+
+	mockRepo.On("ListByUserID", mock.Anything, "user-id").Return([]domain.Cat{{ID: "cat-1"}, {ID: "cat-2"}}, nil)
+
+	cats, err := uc.List(context.Background(), "user-id")
+	assert.NoError(t, err)
+	assert.Len(t, cats, 2)
+}
+
+func TestCatUseCase_Create(t *testing.T) {
+	birthDate := time.Date(2021, 4, 10, 15, 30, 0, 0, time.UTC)
+	limitErr := &domain.EntitlementError{Reason: domain.EntitlementCatLimitReached, Plan: domain.PlanFree, Limit: 1}
+	tests := []struct {
+		name      string
+		createErr error
+		expectErr error
+	}{
+		{name: "success"},
+		{name: "unknown user", createErr: sql.ErrNoRows, expectErr: domain.ErrUserNotFound},
+		{name: "limit reached", createErr: limitErr, expectErr: limitErr},
+		{name: "repository failure", createErr: sql.ErrConnDone, expectErr: sql.ErrConnDone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockCatRepo)
+			uc := usecases.NewCatUseCase(mockRepo)
+
+			mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Cat")).Return(tt.createErr)
+
+			cat, err := uc.Create(context.Background(), &domain.Cat{UserID: "user-id", Name: "  Mingau ", Breed: " Siamês", BirthDate: &birthDate})
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+				assert.Nil(t, cat)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NotEmpty(t, cat.ID)
+			assert.Equal(t, "user-id", cat.UserID)
+			assert.Equal(t, "Mingau", cat.Name)
+			assert.Equal(t, "Siamês", cat.Breed)
+			assert.Equal(t, time.Date(2021, 4, 10, 0, 0, 0, 0, time.UTC), *cat.BirthDate)
+		})
+	}
+}
+
+func TestCatUseCase_Update(t *testing.T) {
+	tests := []struct {
+		name      string
+		updateErr error
+	}{
+		{name: "success"},
+		{name: "unknown or not owned", updateErr: domain.ErrCatNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockCatRepo)
+			uc := usecases.NewCatUseCase(mockRepo)
+
+			mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(cat *domain.Cat) bool {
+				return cat.ID == "cat-id" && cat.UserID == "user-id" && cat.Name == "Frajola"
+			})).Return(tt.updateErr)
+
+			cat, err := uc.Update(context.Background(), &domain.Cat{ID: "cat-id", UserID: "user-id", Name: "Frajola "})
+
+			if tt.updateErr != nil {
+				assert.ErrorIs(t, err, tt.updateErr)
+				assert.Nil(t, cat)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "Frajola", cat.Name)
+		})
+	}
+}
+
+func TestCatUseCase_Delete(t *testing.T) {
+	mockRepo := new(MockCatRepo)
+	uc := usecases.NewCatUseCase(mockRepo)
+
+	mockRepo.On("Delete", mock.Anything, "user-id", "cat-id").Return(domain.ErrCatNotFound)
+
+	assert.ErrorIs(t, uc.Delete(context.Background(), "user-id", "cat-id"), domain.ErrCatNotFound)
+}
@@ -0,0 +1,137 @@
+package usecases_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/internal/ports/services"
+	"github.com/nuhorizon/go-project-template/services/template/internal/usecases"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestEmailVerificationUseCase_Send(t *testing.T) {
+	tests := []struct {
+		name      string
+		user      *domain.User
+		findErr   error
+		expectErr error
+	}{
+		{
+			name: "mails the link",
+			user: &domain.User{ID: "user-id", Name: "Test User", Email: "user@example.com"},
+		},
+		{
+			name:      "already verified",
+			user:      &domain.User{ID: "user-id", Email: "user@example.com", EmailVerified: true},
+			expectErr: domain.ErrEmailAlreadyVerified,
+		},
+		{
+			name:      "unknown user",
+			findErr:   sql.ErrNoRows,
+			expectErr: domain.ErrUserNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockUserRepo)
+			mockJWT := new(MockJWTService)
+			mockMailer := new(MockMailer)
+			uc := usecases.NewEmailVerificationUseCase(mockRepo, mockJWT, mockMailer, "https://app.example.com/verify?source=email")
+
+			mockRepo.On("FindByID", mock.Anything, "user-id").Return(tt.user, tt.findErr)
+			mockJWT.On("GenerateEmailVerificationToken", "user-id", "user@example.com").Return("signed-token", nil)
+			mockMailer.On("Send", mock.Anything, "user@example.com", services.EmailTemplateVerifyEmail, mock.AnythingOfType("services.VerifyEmailEmail")).Return(nil)
+
+			err := uc.Send(context.Background(), "user-id")
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+				mockMailer.AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+
+			assert.NoError(t, err)
+			data := mockMailer.Calls[0].Arguments.Get(3).(services.VerifyEmailEmail)
+			link, err := url.Parse(data.Link)
+			assert.NoError(t, err)
+			assert.Equal(t, "app.example.com", link.Host)
+			assert.Equal(t, "email", link.Query().Get("source"))
+			assert.Equal(t, "signed-token", link.Query().Get("token"))
+			assert.Equal(t, "Test User", data.Name)
+			assert.Equal(t, 24, data.ExpiresInHours)
+		})
+	}
+}
+
+func TestEmailVerificationUseCase_Confirm(t *testing.T) {
+	tests := []struct {
+		name        string
+		validateErr error
+		tokenEmail  string
+		user        *domain.User
+		findErr     error
+		expectMark  bool
+		expectErr   error
+	}{
+		{
+			name:       "marks the e-mail verified",
+			tokenEmail: "user@example.com",
+			user:       &domain.User{ID: "user-id", Email: "user@example.com"},
+			expectMark: true,
+		},
+		{
+			name:       "already verified is a no-op",
+			tokenEmail: "user@example.com",
+			user:       &domain.User{ID: "user-id", Email: "user@example.com", EmailVerified: true},
+		},
+		{
+			name:        "bad signature",
+			validateErr: errors.New("invalid token"),
+			expectErr:   domain.ErrInvalidEmailVerificationLink,
+		},
+		{
+			name:       "e-mail changed since the link was sent",
+			tokenEmail: "old@example.com",
+			user:       &domain.User{ID: "user-id", Email: "user@example.com"},
+			expectErr:  domain.ErrInvalidEmailVerificationLink,
+		},
+		{
+			name:       "deleted user",
+			tokenEmail: "user@example.com",
+			findErr:    sql.ErrNoRows,
+			expectErr:  domain.ErrUserNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockUserRepo)
+			mockJWT := new(MockJWTService)
+			uc := usecases.NewEmailVerificationUseCase(mockRepo, mockJWT, new(MockMailer), "https://app.example.com/verify")
+
+			mockJWT.On("ValidateEmailVerificationToken", "signed-token").Return("user-id", tt.tokenEmail, tt.validateErr)
+			mockRepo.On("FindByID", mock.Anything, "user-id").Return(tt.user, tt.findErr)
+			mockRepo.On("MarkEmailVerified", mock.Anything, "user-id").Return(nil)
+
+			err := uc.Confirm(context.Background(), "signed-token")
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			if tt.expectMark {
+				mockRepo.AssertCalled(t, "MarkEmailVerified", mock.Anything, "user-id")
+			} else {
+				mockRepo.AssertNotCalled(t, "MarkEmailVerified", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
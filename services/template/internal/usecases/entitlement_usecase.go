@@ -0,0 +1,70 @@
+package usecases
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/internal/ports/repositories"
+)
+
+// EntitlementUseCase decides what a user may do according to the plans table. Denials are
+// reported as *domain.EntitlementError.
+type EntitlementUseCase interface {
+	// PlanOf returns the plan the user is entitled to right now, the free plan for users without
+	// one. Lapsed plans count until the subscription sweeper moves their users to free, so the
+	// entitlements always match the plan_type claim.
+	PlanOf(ctx context.Context, userID string) (*domain.Plan, error)
+	// RequireFeature fails unless the plan of the user includes the feature (domain.FeatureImageAnalysis, ...)
+	RequireFeature(ctx context.Context, userID, feature string) error
+}
+
+type entitlementUseCase struct {
+	userRepo repositories.UserRepository
+	planRepo repositories.PlanRepository
+}
+
+func NewEntitlementUseCase(userRepo repositories.UserRepository, planRepo repositories.PlanRepository) EntitlementUseCase {
+	return &entitlementUseCase{userRepo: userRepo, planRepo: planRepo}
+}
+
+func (e *entitlementUseCase) PlanOf(ctx context.Context, userID string) (*domain.Plan, error) {
+	user, err := e.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	var plan *domain.Plan
+	switch {
+	case user.PlanID != "":
+		plan, err = e.planRepo.FindByID(ctx, user.PlanID)
+	default:
+		code := user.PlanType
+		if code == "" {
+			code = domain.PlanFree
+		}
+		plan, err = e.planRepo.FindByCode(ctx, code)
+	}
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrPlanNotFound
+		}
+		return nil, err
+	}
+	return plan, nil
+}
+
+func (e *entitlementUseCase) RequireFeature(ctx context.Context, userID, feature string) error {
+	plan, err := e.PlanOf(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !plan.HasFeature(feature) {
+		return &domain.EntitlementError{Reason: domain.EntitlementFeatureNotInPlan, Plan: plan.Code, Feature: feature}
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package usecases_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/internal/usecases"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPlanRepo struct{ mock.Mock }
+
+func (m *MockPlanRepo) FindByID(ctx context.Context, id string) (*domain.Plan, error) {
+	args := m.Called(ctx, id)
+	plan, _ := args.Get(0).(*domain.Plan)
+	return plan, args.Error(1)
+}
+
+func (m *MockPlanRepo) FindByCode(ctx context.Context, code string) (*domain.Plan, error) {
+	args := m.Called(ctx, code)
+	plan, _ := args.Get(0).(*domain.Plan)
+	return plan, args.Error(1)
+}
+
+func (m *MockPlanRepo) List(ctx context.Context) ([]domain.Plan, error) {
+	args := m.Called(ctx)
+	plans, _ := args.Get(0).([]domain.Plan)
+	return plans, args.Error(1)
+}
+
+var (
+	freePlan    = &domain.Plan{ID: "free-id", Code: domain.PlanFree, MaxCats: 1}
+	premiumPlan = &domain.Plan{ID: "premium-id", Code: domain.PlanPremium, MaxCats: 10, HasImageAnalysis: true, HasConsultation: true}
+)
+
+func TestEntitlementUseCase_PlanOf(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name       string
+		user       *domain.User
+		findErr    error
+		setupPlans func(*MockPlanRepo)
+		expectPlan *domain.Plan
+		expectErr  error
+	}{
+		{
+			name:       "linked plan",
+			user:       &domain.User{ID: "user-id", PlanType: domain.PlanPremium, PlanID: "premium-id", PlanExpiry: &future},
+			setupPlans: func(m *MockPlanRepo) { m.On("FindByID", mock.Anything, "premium-id").Return(premiumPlan, nil) },
+			expectPlan: premiumPlan,
+		},
+		{
+			name:       "lapsed plan counts until the sweeper runs",
+			user:       &domain.User{ID: "user-id", PlanType: domain.PlanPremium, PlanID: "premium-id", PlanExpiry: &past},
+			setupPlans: func(m *MockPlanRepo) { m.On("FindByID", mock.Anything, "premium-id").Return(premiumPlan, nil) },
+			expectPlan: premiumPlan,
+		},
+		{
+			name:       "no plan assigned",
+			user:       &domain.User{ID: "user-id"},
+			setupPlans: func(m *MockPlanRepo) { m.On("FindByCode", mock.Anything, domain.PlanFree).Return(freePlan, nil) },
+			expectPlan: freePlan,
+		},
+		{
+			name:       "linked plan missing",
+			user:       &domain.User{ID: "user-id", PlanID: "gone-id"},
+			setupPlans: func(m *MockPlanRepo) { m.On("FindByID", mock.Anything, "gone-id").Return(nil, sql.ErrNoRows) },
+			expectErr:  domain.ErrPlanNotFound,
+		},
+		{
+			name:       "unknown user",
+			findErr:    sql.ErrNoRows,
+			setupPlans: func(m *MockPlanRepo) {},
+			expectErr:  domain.ErrUserNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := new(MockUserRepo)
+			planRepo := new(MockPlanRepo)
+			uc := usecases.NewEntitlementUseCase(userRepo, planRepo)
+
+			userRepo.On("FindByID", mock.Anything, "user-id").Return(tt.user, tt.findErr)
+			tt.setupPlans(planRepo)
+
+			plan, err := uc.PlanOf(context.Background(), "user-id")
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+				assert.Nil(t, plan)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectPlan, plan)
+		})
+	}
+}
+
+func TestEntitlementUseCase_RequireFeature(t *testing.T) {
+	tests := []struct {
+		name      string
+		plan      *domain.Plan
+		feature   string
+		expectErr bool
+	}{
+		{name: "included", plan: premiumPlan, feature: domain.FeatureImageAnalysis},
+		{name: "not in plan", plan: freePlan, feature: domain.FeatureConsultation, expectErr: true},
+		{name: "unknown feature", plan: premiumPlan, feature: "teleportation", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := new(MockUserRepo)
+			planRepo := new(MockPlanRepo)
+			uc := usecases.NewEntitlementUseCase(userRepo, planRepo)
+
+			userRepo.On("FindByID", mock.Anything, "user-id").Return(&domain.User{ID: "user-id", PlanID: tt.plan.ID}, nil)
+			planRepo.On("FindByID", mock.Anything, tt.plan.ID).Return(tt.plan, nil)
+
+			err := uc.RequireFeature(context.Background(), "user-id", tt.feature)
+
+			if !tt.expectErr {
+				assert.NoError(t, err)
+				return
+			}
+			var entitlementErr *domain.EntitlementError
+			if assert.ErrorAs(t, err, &entitlementErr) {
+				assert.Equal(t, domain.EntitlementFeatureNotInPlan, entitlementErr.Reason)
+				assert.Equal(t, tt.plan.Code, entitlementErr.Plan)
+				assert.Equal(t, tt.feature, entitlementErr.Feature)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package usecases
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/internal/ports/repositories"
+)
+
+// LockoutPolicy locks a key out for BaseDelay once it reaches FreeAttempts failures, doubling
+// the delay on every further failure up to MaxDelay. Failures are forgotten after ResetAfter
+// without a new one.
+type LockoutPolicy struct {
+	FreeAttempts int
+	BaseDelay    time.Duration
+	MaxDelay     time.Duration
+	ResetAfter   time.Duration
+}
+
+var (
+	// DefaultAccountLockout protects a single account against password guessing
+	DefaultAccountLockout = LockoutPolicy{FreeAttempts: 5, BaseDelay: 30 * time.Second, MaxDelay: time.Hour, ResetAfter: 24 * time.Hour}
+	// DefaultIPLockout is looser since several users may share an address (NAT, offices)
+	DefaultIPLockout = LockoutPolicy{FreeAttempts: 20, BaseDelay: 30 * time.Second, MaxDelay: time.Hour, ResetAfter: 24 * time.Hour}
+)
+
+// lockedUntil returns the end of the lockout caused by the given history, zero when not locked
+func (p LockoutPolicy) lockedUntil(attempts domain.LoginAttempts) time.Time {
+	if p.FreeAttempts <= 0 || attempts.Failures < p.FreeAttempts {
+		return time.Time{}
+	}
+
+	delay := p.MaxDelay
+	if exp := attempts.Failures - p.FreeAttempts; exp < 32 {
+		if d := p.BaseDelay << exp; d > 0 && d < p.MaxDelay {
+			delay = d
+		}
+	}
+	return attempts.LastFailureAt.Add(delay)
+}
+
+// LoginGuard tracks failed authentication attempts per client IP and per account
+// and rejects new attempts while either one is locked out.
+type LoginGuard interface {
+	// Check returns a *domain.LockedOutError while the IP or the account is locked out.
+	// An empty account only checks the IP.
+	Check(ctx context.Context, clientIP, account string) error
+	// Fail counts a failed attempt against the IP and, when given, the account
+	Fail(ctx context.Context, clientIP, account string) error
+	// Reset clears the failures of the account after a successful login
+	Reset(ctx context.Context, account string) error
+}
+
+type loginGuard struct {
+	store         repositories.LoginAttemptStore
+	ipPolicy      LockoutPolicy
+	accountPolicy LockoutPolicy
+}
+
+func NewLoginGuard(store repositories.LoginAttemptStore, ipPolicy, accountPolicy LockoutPolicy) LoginGuard {
+	return &loginGuard{
+		store:         store,
+		ipPolicy:      ipPolicy,
+		accountPolicy: accountPolicy,
+	}
+}
+
+func (g *loginGuard) Check(ctx context.Context, clientIP, account string) error {
+	now := time.Now()
+	var retryAfter time.Duration
+
+	for _, k := range g.keys(clientIP, account) {
+		attempts, err := g.store.Get(ctx, k.key)
+		if err != nil {
+			return err
+		}
+		if wait := k.policy.lockedUntil(attempts).Sub(now); wait > retryAfter {
+			retryAfter = wait
+		}
+	}
+
+	if retryAfter > 0 {
+		return &domain.LockedOutError{RetryAfter: retryAfter}
+	}
+	return nil
+}
+
+func (g *loginGuard) Fail(ctx context.Context, clientIP, account string) error {
+	now := time.Now()
+	for _, k := range g.keys(clientIP, account) {
+		if _, err := g.store.RecordFailure(ctx, k.key, now, k.policy.ResetAfter); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (g *loginGuard) Reset(ctx context.Context, account string) error {
+	if account = normalizeAccount(account); account == "" {
+		return nil
+	}
+	return g.store.Reset(ctx, accountKey(account))
+}
+
+type guardKey struct {
+	key    string
+	policy LockoutPolicy
+}
+
+func (g *loginGuard) keys(clientIP, account string) []guardKey {
+	var keys []guardKey
+	if clientIP != "" {
+		keys = append(keys, guardKey{key: "ip:" + clientIP, policy: g.ipPolicy})
+	}
+	if account = normalizeAccount(account); account != "" {
+		keys = append(keys, guardKey{key: accountKey(account), policy: g.accountPolicy})
+	}
+	return keys
+}
+
+func accountKey(account string) string {
+	return "account:" + account
+}
+
+// normalizeAccount makes "User@Example.com " and "user@example.com" share one counter
+func normalizeAccount(account string) string {
+	return strings.ToLower(strings.TrimSpace(account))
+}
@@ -0,0 +1,134 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/internal/usecases"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockLoginAttemptStore struct {
+	mock.Mock
+}
+
+func (m *MockLoginAttemptStore) Get(ctx context.Context, key string) (domain.LoginAttempts, error) {
+	args := m.Called(ctx, key)
+	return args.Get(0).(domain.LoginAttempts), args.Error(1)
+}
+
+func (m *MockLoginAttemptStore) RecordFailure(ctx context.Context, key string, at time.Time, resetAfter time.Duration) (domain.LoginAttempts, error) {
+	args := m.Called(ctx, key, at, resetAfter)
+	return args.Get(0).(domain.LoginAttempts), args.Error(1)
+}
+
+func (m *MockLoginAttemptStore) Reset(ctx context.Context, key string) error {
+	args := m.Called(ctx, key)
+	return args.Error(0)
+}
+
+var testLockout = usecases.LockoutPolicy{FreeAttempts: 3, BaseDelay: time.Minute, MaxDelay: 10 * time.Minute, ResetAfter: time.Hour}
+
+func TestLoginGuard_Check(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name        string
+		ipAttempts  domain.LoginAttempts
+		accAttempts domain.LoginAttempts
+		getErr      error
+		wantLocked  bool
+		minWait     time.Duration
+		maxWait     time.Duration
+		wantErr     bool
+	}{
+		{
+			name: "no failures",
+		},
+		{
+			name:        "below the free attempts",
+			accAttempts: domain.LoginAttempts{Failures: 2, LastFailureAt: now},
+		},
+		{
+			name:        "first lockout",
+			accAttempts: domain.LoginAttempts{Failures: 3, LastFailureAt: now},
+			wantLocked:  true,
+			minWait:     59 * time.Second,
+			maxWait:     time.Minute,
+		},
+		{
+			name:        "delay doubles with every failure",
+			accAttempts: domain.LoginAttempts{Failures: 5, LastFailureAt: now},
+			wantLocked:  true,
+			minWait:     4*time.Minute - time.Second,
+			maxWait:     4 * time.Minute,
+		},
+		{
+			name:       "delay is capped",
+			ipAttempts: domain.LoginAttempts{Failures: 200, LastFailureAt: now},
+			wantLocked: true,
+			minWait:    10*time.Minute - time.Second,
+			maxWait:    10 * time.Minute,
+		},
+		{
+			name:        "lockout over",
+			accAttempts: domain.LoginAttempts{Failures: 3, LastFailureAt: now.Add(-2 * time.Minute)},
+		},
+		{
+			name:    "store error",
+			getErr:  errors.New("db error"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := new(MockLoginAttemptStore)
+			store.On("Get", mock.Anything, "ip:192.0.2.1").Return(tt.ipAttempts, tt.getErr)
+			store.On("Get", mock.Anything, "account:user@example.com").Return(tt.accAttempts, nil).Maybe()
+
+			guard := usecases.NewLoginGuard(store, testLockout, testLockout)
+			err := guard.Check(context.Background(), "192.0.2.1", " User@Example.com")
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			if !tt.wantLocked {
+				assert.NoError(t, err)
+				return
+			}
+
+			var lockedErr *domain.LockedOutError
+			if assert.ErrorAs(t, err, &lockedErr) {
+				assert.GreaterOrEqual(t, lockedErr.RetryAfter, tt.minWait)
+				assert.LessOrEqual(t, lockedErr.RetryAfter, tt.maxWait)
+			}
+		})
+	}
+}
+
+func TestLoginGuard_Fail(t *testing.T) {
+	store := new(MockLoginAttemptStore)
+	store.On("RecordFailure", mock.Anything, "ip:192.0.2.1", mock.Anything, time.Hour).Return(domain.LoginAttempts{Failures: 1}, nil).Once()
+	store.On("RecordFailure", mock.Anything, "account:user@example.com", mock.Anything, time.Hour).Return(domain.LoginAttempts{Failures: 1}, nil).Once()
+
+	guard := usecases.NewLoginGuard(store, testLockout, testLockout)
+	assert.NoError(t, guard.Fail(context.Background(), "192.0.2.1", "user@example.com"))
+	assert.NoError(t, guard.Fail(context.Background(), "", ""))
+	store.AssertExpectations(t)
+}
+
+func TestLoginGuard_Reset(t *testing.T) {
+	store := new(MockLoginAttemptStore)
+	store.On("Reset", mock.Anything, "account:user@example.com").Return(nil).Once()
+
+	guard := usecases.NewLoginGuard(store, testLockout, testLockout)
+	assert.NoError(t, guard.Reset(context.Background(), "USER@example.com"))
+	assert.NoError(t, guard.Reset(context.Background(), ""))
+	store.AssertExpectations(t)
+}
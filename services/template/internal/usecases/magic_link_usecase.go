@@ -0,0 +1,88 @@
+package usecases
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/url"
+	"time"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/internal/ports/repositories"
+	"github.com/nuhorizon/go-project-template/services/template/internal/ports/services"
+
+	"github.com/google/uuid"
+)
+
+// MagicLinkUseCase mails and redeems the single-use links of the passwordless e-mail login
+type MagicLinkUseCase interface {
+	// Send mails a new magic link to email
+	Send(ctx context.Context, email string) error
+	// Consume redeems a magic-link token, returning the e-mail it was sent to
+	Consume(ctx context.Context, token string) (string, error)
+}
+
+type magicLinkUseCase struct {
+	magicLinkRepo repositories.MagicLinkRepository
+	jwtService    services.JWTService
+	mailer        services.Mailer
+	linkURL       string
+}
+
+// NewMagicLinkUseCase builds the links on linkURL, the page (or GET /auth/magic-link/verify)
+// receiving the token in its "token" query parameter
+func NewMagicLinkUseCase(magicLinkRepo repositories.MagicLinkRepository, jwtService services.JWTService, mailer services.Mailer, linkURL string) MagicLinkUseCase {
+	return &magicLinkUseCase{
+		magicLinkRepo: magicLinkRepo,
+		jwtService:    jwtService,
+		mailer:        mailer,
+		linkURL:       linkURL,
+	}
+}
+
+func (m *magicLinkUseCase) Send(ctx context.Context, email string) error {
+	link := &domain.MagicLink{
+		ID:        uuid.NewString(),
+		Email:     normalizeEmail(email),
+		ExpiresAt: time.Now().Add(domain.MagicLinkTTL),
+	}
+
+	token, err := m.jwtService.GenerateMagicLinkToken(link.ID, link.Email, link.ExpiresAt)
+	if err != nil {
+		return err
+	}
+
+	target, err := url.Parse(m.linkURL)
+	if err != nil {
+		return err
+	}
+	query := target.Query()
+	query.Set("token", token)
+	target.RawQuery = query.Encode()
+
+	if err := m.magicLinkRepo.Create(ctx, link); err != nil {
+		return err
+	}
+
+	return m.mailer.Send(ctx, link.Email, services.EmailTemplateMagicLink, services.MagicLinkEmail{
+		Email:            link.Email,
+		Link:             target.String(),
+		ExpiresInMinutes: int(domain.MagicLinkTTL / time.Minute),
+	})
+}
+
+func (m *magicLinkUseCase) Consume(ctx context.Context, token string) (string, error) {
+	linkID, _, err := m.jwtService.ValidateMagicLinkToken(token)
+	if err != nil {
+		return "", domain.ErrInvalidMagicLink
+	}
+
+	link, err := m.magicLinkRepo.Consume(ctx, linkID, time.Now())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", domain.ErrInvalidMagicLink
+		}
+		return "", err
+	}
+	return link.Email, nil
+}
@@ -0,0 +1,266 @@
+package usecases_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/internal/ports/services"
+	"github.com/nuhorizon/go-project-template/services/template/internal/usecases"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockMagicLinkRepo struct{ mock.Mock }
+
+func (m *MockMagicLinkRepo) Create(ctx context.Context, link *domain.MagicLink) error {
+	args := m.Called(ctx, link)
+	return args.Error(0)
+}
+
+func (m *MockMagicLinkRepo) Consume(ctx context.Context, id string, at time.Time) (*domain.MagicLink, error) {
+	args := m.Called(ctx, id, at)
+	link, _ := args.Get(0).(*domain.MagicLink)
+	return link, args.Error(1)
+}
+
+func (m *MockMagicLinkRepo) PruneExpired(ctx context.Context, now time.Time) (int64, error) {
+	args := m.Called(ctx, now)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+type MockMailer struct{ mock.Mock }
+
+func (m *MockMailer) Send(ctx context.Context, to string, template string, data any) error {
+	args := m.Called(ctx, to, template, data)
+	return args.Error(0)
+}
+
+type MockMagicLinkUseCase struct{ mock.Mock }
+
+func (m *MockMagicLinkUseCase) Send(ctx context.Context, email string) error {
+	args := m.Called(ctx, email)
+	return args.Error(0)
+}
+
+func (m *MockMagicLinkUseCase) Consume(ctx context.Context, token string) (string, error) {
+	args := m.Called(ctx, token)
+	return args.String(0), args.Error(1)
+}
+
+func TestMagicLinkUseCase_Send(t *testing.T) {
+	tests := []struct {
+		name       string
+		createErr  error
+		expectErr  bool
+		expectMail bool
+	}{
+		{name: "mails the link", expectMail: true},
+		{name: "repository failure", createErr: errors.New("db error"), expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockMagicLinkRepo)
+			mockJWT := new(MockJWTService)
+			mockMailer := new(MockMailer)
+			uc := usecases.NewMagicLinkUseCase(mockRepo, mockJWT, mockMailer, "https://app.example.com/login?source=email")
+
+			var linkID string
+			mockJWT.On("GenerateMagicLinkToken", mock.Anything, "user@example.com", mock.AnythingOfType("time.Time")).
+				Run(func(args mock.Arguments) { linkID = args.String(0) }).
+				Return("signed-token", nil)
+			mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(link *domain.MagicLink) bool {
+				return link.ID == linkID && link.Email == "user@example.com" && time.Until(link.ExpiresAt) <= domain.MagicLinkTTL
+			})).Return(tt.createErr)
+			mockMailer.On("Send", mock.Anything, "user@example.com", services.EmailTemplateMagicLink, mock.AnythingOfType("services.MagicLinkEmail")).Return(nil)
+
+			err := uc.Send(context.Background(), "  User@Example.com ")
+
+			if tt.expectErr {
+				assert.Error(t, err)
+				mockMailer.AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+
+			assert.NoError(t, err)
+			data := mockMailer.Calls[0].Arguments.Get(3).(services.MagicLinkEmail)
+			link, err := url.Parse(data.Link)
+			assert.NoError(t, err)
+			assert.Equal(t, "app.example.com", link.Host)
+			assert.Equal(t, "email", link.Query().Get("source"))
+			assert.Equal(t, "signed-token", link.Query().Get("token"))
+			assert.Equal(t, 15, data.ExpiresInMinutes)
+		})
+	}
+}
+
+func TestMagicLinkUseCase_Consume(t *testing.T) {
+	tests := []struct {
+		name        string
+		validateErr error
+		link        *domain.MagicLink
+		consumeErr  error
+		expectEmail string
+		expectErr   error
+	}{
+		{
+			name:        "valid link",
+			link:        &domain.MagicLink{ID: "link-id", Email: "user@example.com"},
+			expectEmail: "user@example.com",
+		},
+		{
+			name:        "bad signature",
+			validateErr: errors.New("invalid token"),
+			expectErr:   domain.ErrInvalidMagicLink,
+		},
+		{
+			name:       "already used or expired",
+			consumeErr: sql.ErrNoRows,
+			expectErr:  domain.ErrInvalidMagicLink,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockMagicLinkRepo)
+			mockJWT := new(MockJWTService)
+			uc := usecases.NewMagicLinkUseCase(mockRepo, mockJWT, new(MockMailer), "https://app.example.com/login")
+
+			mockJWT.On("ValidateMagicLinkToken", "signed-token").Return("link-id", "user@example.com", tt.validateErr)
+			mockRepo.On("Consume", mock.Anything, "link-id", mock.AnythingOfType("time.Time")).Return(tt.link, tt.consumeErr)
+
+			email, err := uc.Consume(context.Background(), "signed-token")
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+				assert.Empty(t, email)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectEmail, email)
+			}
+		})
+	}
+}
+
+func TestAuthUseCase_LoginWithMagicLink(t *testing.T) {
+	tests := []struct {
+		name         string
+		consumeErr   error
+		findUser     *domain.User
+		findErr      error
+		expectCreate bool
+		expectClaim  bool
+		expectErr    error
+	}{
+		{
+			name:     "existing account",
+			findUser: &domain.User{ID: "user-id", Email: "user@example.com", PasswordHash: "hash", EmailVerified: true},
+		},
+		{
+			name:        "unverified account is claimed by the owner of the mailbox",
+			findUser:    &domain.User{ID: "user-id", Email: "user@example.com", PasswordHash: "attacker-hash"},
+			expectClaim: true,
+		},
+		{
+			name:         "first login registers the account",
+			findErr:      sql.ErrNoRows,
+			expectCreate: true,
+		},
+		{
+			name:       "invalid link",
+			consumeErr: domain.ErrInvalidMagicLink,
+			expectErr:  domain.ErrInvalidMagicLink,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockUserRepo)
+			mockRefresh := new(MockRefreshTokenRepo)
+			mockJWT := new(MockJWTService)
+			mockLinks := new(MockMagicLinkUseCase)
+			mockSessions := noSessions()
+			mockRevocations := new(MockRevocationStore)
+			authUC := usecases.NewAuthUseCase(mockRepo, noRoles(), new(MockIdentityRepo), mockRefresh, mockSessions, mockRevocations, nil, nil, noMFA(), mockLinks, nil, mockJWT, new(MockPasswordHasher))
+
+			mockLinks.On("Consume", mock.Anything, "link-token").Return("user@example.com", tt.consumeErr)
+			mockRepo.On("FindByEmail", mock.Anything, "user@example.com").Return(tt.findUser, tt.findErr)
+			mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(u *domain.User) bool {
+				return u.Email == "user@example.com" && u.Name == "user@example.com" && u.PasswordHash == "" && u.EmailVerified
+			})).Return(nil)
+			mockRepo.On("MarkEmailVerified", mock.Anything, "user-id").Return(nil)
+			mockRepo.On("ClearPassword", mock.Anything, "user-id").Return(nil)
+			mockSessions.On("ListActiveByUserID", mock.Anything, "user-id").
+				Return([]domain.Session{{ID: "attacker-session"}}, nil)
+			mockRevocations.On("RevokeSession", mock.Anything, "attacker-session", mock.Anything).Return(nil)
+			mockRefresh.On("RevokeAllForUser", mock.Anything, "user-id").Return(nil)
+			mockJWT.On("GenerateToken", mock.Anything, mock.Anything).Return("jwt-token", nil)
+			mockRefresh.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+			user, tokens, err := authUC.LoginWithMagicLink(context.Background(), "link-token")
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+				assert.Nil(t, tokens)
+				mockJWT.AssertNotCalled(t, "GenerateToken", mock.Anything, mock.Anything)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, "user@example.com", user.Email)
+			assert.True(t, user.EmailVerified)
+			assert.Equal(t, "jwt-token", tokens.AccessToken)
+			if tt.expectCreate {
+				mockRepo.AssertCalled(t, "Create", mock.Anything, mock.Anything)
+			} else {
+				mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+			}
+			if tt.expectClaim {
+				assert.Empty(t, user.PasswordHash)
+				mockRepo.AssertCalled(t, "ClearPassword", mock.Anything, "user-id")
+				mockRevocations.AssertCalled(t, "RevokeSession", mock.Anything, "attacker-session", mock.Anything)
+				mockRefresh.AssertCalled(t, "RevokeAllForUser", mock.Anything, "user-id")
+			} else {
+				mockRepo.AssertNotCalled(t, "ClearPassword", mock.Anything, mock.Anything)
+				mockRefresh.AssertNotCalled(t, "RevokeAllForUser", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestAuthUseCase_LoginWithMagicLink_MFA(t *testing.T) {
+	mockRepo := new(MockUserRepo)
+	mockJWT := new(MockJWTService)
+	mockMFA := new(MockMFAUseCase)
+	mockLinks := new(MockMagicLinkUseCase)
+	authUC := usecases.NewAuthUseCase(mockRepo, noRoles(), new(MockIdentityRepo), new(MockRefreshTokenRepo), noSessions(), new(MockRevocationStore), nil, nil, mockMFA, mockLinks, nil, mockJWT, new(MockPasswordHasher))
+
+	mockLinks.On("Consume", mock.Anything, "link-token").Return("user@example.com", nil)
+	mockRepo.On("FindByEmail", mock.Anything, "user@example.com").Return(&domain.User{ID: "user-id", Email: "user@example.com", EmailVerified: true}, nil)
+	mockMFA.On("Required", mock.Anything, "user-id").Return(true, nil)
+	mockJWT.On("GenerateMFAToken", "user-id").Return("mfa-token", nil)
+
+	_, tokens, err := authUC.LoginWithMagicLink(context.Background(), "link-token")
+
+	var mfaErr *domain.MFARequiredError
+	assert.ErrorAs(t, err, &mfaErr)
+	assert.Equal(t, "mfa-token", mfaErr.Token)
+	assert.Nil(t, tokens)
+	mockJWT.AssertNotCalled(t, "GenerateToken", mock.Anything, mock.Anything)
+}
+
+func TestAuthUseCase_RequestMagicLink(t *testing.T) {
+	mockLinks := new(MockMagicLinkUseCase)
+	authUC := usecases.NewAuthUseCase(new(MockUserRepo), noRoles(), new(MockIdentityRepo), new(MockRefreshTokenRepo), noSessions(), new(MockRevocationStore), nil, nil, noMFA(), mockLinks, nil, new(MockJWTService), new(MockPasswordHasher))
+
+	mockLinks.On("Send", mock.Anything, "user@example.com").Return(nil)
+
+	assert.NoError(t, authUC.RequestMagicLink(context.Background(), "user@example.com"))
+	mockLinks.AssertExpectations(t)
+}
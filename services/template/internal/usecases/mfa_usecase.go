@@ -0,0 +1,165 @@
+package usecases
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base32"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/internal/ports/repositories"
+	"github.com/nuhorizon/go-project-template/services/template/internal/ports/services"
+	"github.com/nuhorizon/go-project-template/services/template/pkg/utils"
+)
+
+const recoveryCodeCount = 10
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// MFAUseCase manages the TOTP second factor of direct-login (e-mail/password) users
+type MFAUseCase interface {
+	EnrollTOTP(ctx context.Context, userID string) (*domain.TOTPSetup, error)
+	ConfirmTOTP(ctx context.Context, userID, code string) ([]string, error)
+	DisableTOTP(ctx context.Context, userID, code string) error
+	// Required reports whether logins of the user need the second factor
+	Required(ctx context.Context, userID string) (bool, error)
+	// Verify checks a TOTP or recovery code, consuming it
+	Verify(ctx context.Context, userID, code string) error
+}
+
+type mfaUseCase struct {
+	userRepo repositories.UserRepository
+	mfaRepo  repositories.MFARepository
+	totp     services.TOTP
+}
+
+func NewMFAUseCase(userRepo repositories.UserRepository, mfaRepo repositories.MFARepository, totp services.TOTP) MFAUseCase {
+	return &mfaUseCase{
+		userRepo: userRepo,
+		mfaRepo:  mfaRepo,
+		totp:     totp,
+	}
+}
+
+// EnrollTOTP generates a new secret, pending until ConfirmTOTP receives a first valid code
+func (m *mfaUseCase) EnrollTOTP(ctx context.Context, userID string) (*domain.TOTPSetup, error) {
+	user, err := m.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	// Firebase and OpenID Connect logins enforce their own second factor at the provider
+	if user.PasswordHash == "" {
+		return nil, domain.ErrMFAUnsupported
+	}
+
+	secret, err := m.totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err := m.mfaRepo.SavePending(ctx, userID, secret); err != nil {
+		return nil, err
+	}
+
+	return &domain.TOTPSetup{
+		Secret:          secret,
+		ProvisioningURI: m.totp.ProvisioningURI(secret, user.Email),
+	}, nil
+}
+
+// ConfirmTOTP enables the pending enrolment and returns the recovery codes, shown only once
+func (m *mfaUseCase) ConfirmTOTP(ctx context.Context, userID, code string) ([]string, error) {
+	enrollment, err := m.findEnrollment(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if enrollment.Enabled() {
+		return nil, domain.ErrMFAAlreadyEnabled
+	}
+
+	step, ok := m.totp.Validate(enrollment.Secret, normalizeCode(code), time.Now())
+	if !ok {
+		return nil, domain.ErrInvalidMFACode
+	}
+
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for range recoveryCodeCount {
+		code, err := newRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+		hashes = append(hashes, utils.HashToken(normalizeCode(code)))
+	}
+
+	if err := m.mfaRepo.Enable(ctx, userID, step, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// DisableTOTP removes the second factor, proven by a current TOTP or recovery code
+func (m *mfaUseCase) DisableTOTP(ctx context.Context, userID, code string) error {
+	if err := m.Verify(ctx, userID, code); err != nil {
+		return err
+	}
+	return m.mfaRepo.Delete(ctx, userID)
+}
+
+func (m *mfaUseCase) Required(ctx context.Context, userID string) (bool, error) {
+	enrollment, err := m.mfaRepo.FindByUserID(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return enrollment.Enabled(), nil
+}
+
+func (m *mfaUseCase) Verify(ctx context.Context, userID, code string) error {
+	enrollment, err := m.findEnrollment(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !enrollment.Enabled() {
+		return domain.ErrMFANotEnabled
+	}
+
+	code = normalizeCode(code)
+	if step, ok := m.totp.Validate(enrollment.Secret, code, time.Now()); ok {
+		return m.mfaRepo.RecordStep(ctx, userID, step)
+	}
+	return m.mfaRepo.UseRecoveryCode(ctx, userID, utils.HashToken(code))
+}
+
+func (m *mfaUseCase) findEnrollment(ctx context.Context, userID string) (*domain.MFAEnrollment, error) {
+	enrollment, err := m.mfaRepo.FindByUserID(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrMFANotEnabled
+	}
+	return enrollment, err
+}
+
+// newRecoveryCode returns a code formatted as "xxxxx-xxxxx" (50 bits of entropy)
+func newRecoveryCode() (string, error) {
+	b := make([]byte, 7)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	code := strings.ToLower(recoveryCodeEncoding.EncodeToString(b))[:10]
+	return code[:5] + "-" + code[5:], nil
+}
+
+// normalizeCode strips the separators users type or paste along with a code
+func normalizeCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
@@ -0,0 +1,254 @@
+package usecases_test
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/internal/usecases"
+	"github.com/nuhorizon/go-project-template/services/template/pkg/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockMFARepo struct{ mock.Mock }
+
+func (m *MockMFARepo) FindByUserID(ctx context.Context, userID string) (*domain.MFAEnrollment, error) {
+	args := m.Called(ctx, userID)
+	enrollment, _ := args.Get(0).(*domain.MFAEnrollment)
+	return enrollment, args.Error(1)
+}
+
+func (m *MockMFARepo) SavePending(ctx context.Context, userID, secret string) error {
+	args := m.Called(ctx, userID, secret)
+	return args.Error(0)
+}
+
+func (m *MockMFARepo) Enable(ctx context.Context, userID string, step int64, recoveryCodeHashes []string) error {
+	args := m.Called(ctx, userID, step, recoveryCodeHashes)
+	return args.Error(0)
+}
+
+func (m *MockMFARepo) Delete(ctx context.Context, userID string) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockMFARepo) RecordStep(ctx context.Context, userID string, step int64) error {
+	args := m.Called(ctx, userID, step)
+	return args.Error(0)
+}
+
+func (m *MockMFARepo) UseRecoveryCode(ctx context.Context, userID, codeHash string) error {
+	args := m.Called(ctx, userID, codeHash)
+	return args.Error(0)
+}
+
+type MockTOTP struct{ mock.Mock }
+
+func (m *MockTOTP) GenerateSecret() (string, error) {
+	args := m.Called()
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockTOTP) ProvisioningURI(secret, account string) string {
+	args := m.Called(secret, account)
+	return args.String(0)
+}
+
+func (m *MockTOTP) Validate(secret, code string, at time.Time) (int64, bool) {
+	args := m.Called(secret, code, at)
+	return args.Get(0).(int64), args.Bool(1)
+}
+
+func enabledEnrollment() *domain.MFAEnrollment {
+	now := time.Now()
+	return &domain.MFAEnrollment{UserID: "user-id", Secret: "SECRET", EnabledAt: &now}
+}
+
+func TestMFAUseCase_EnrollTOTP(t *testing.T) {
+	tests := []struct {
+		name      string
+		user      *domain.User
+		findErr   error
+		saveErr   error
+		expectErr error
+	}{
+		{
+			name: "success",
+			user: &domain.User{ID: "user-id", Email: "user@example.com", PasswordHash: "hashed"},
+		},
+		{
+			name:      "unknown user",
+			findErr:   sql.ErrNoRows,
+			expectErr: domain.ErrUserNotFound,
+		},
+		{
+			name:      "account without password",
+			user:      &domain.User{ID: "user-id", Email: "user@example.com"},
+			expectErr: domain.ErrMFAUnsupported,
+		},
+		{
+			name:      "already enabled",
+			user:      &domain.User{ID: "user-id", Email: "user@example.com", PasswordHash: "hashed"},
+			saveErr:   domain.ErrMFAAlreadyEnabled,
+			expectErr: domain.ErrMFAAlreadyEnabled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockUserRepo)
+			mockMFA := new(MockMFARepo)
+			mockTOTP := new(MockTOTP)
+			mfaUC := usecases.NewMFAUseCase(mockRepo, mockMFA, mockTOTP)
+
+			mockRepo.On("FindByID", mock.Anything, "user-id").Return(tt.user, tt.findErr)
+			mockTOTP.On("GenerateSecret").Return("SECRET", nil)
+			mockMFA.On("SavePending", mock.Anything, "user-id", "SECRET").Return(tt.saveErr)
+			mockTOTP.On("ProvisioningURI", "SECRET", "user@example.com").Return("otpauth://totp/x")
+
+			setup, err := mfaUC.EnrollTOTP(context.Background(), "user-id")
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "SECRET", setup.Secret)
+			assert.Equal(t, "otpauth://totp/x", setup.ProvisioningURI)
+		})
+	}
+}
+
+func TestMFAUseCase_ConfirmTOTP(t *testing.T) {
+	pending := &domain.MFAEnrollment{UserID: "user-id", Secret: "SECRET"}
+
+	tests := []struct {
+		name       string
+		enrollment *domain.MFAEnrollment
+		findErr    error
+		valid      bool
+		expectErr  error
+	}{
+		{name: "success", enrollment: pending, valid: true},
+		{name: "wrong code", enrollment: pending, expectErr: domain.ErrInvalidMFACode},
+		{name: "not enrolled", findErr: sql.ErrNoRows, expectErr: domain.ErrMFANotEnabled},
+		{name: "already enabled", enrollment: enabledEnrollment(), valid: true, expectErr: domain.ErrMFAAlreadyEnabled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockMFA := new(MockMFARepo)
+			mockTOTP := new(MockTOTP)
+			mfaUC := usecases.NewMFAUseCase(new(MockUserRepo), mockMFA, mockTOTP)
+
+			mockMFA.On("FindByUserID", mock.Anything, "user-id").Return(tt.enrollment, tt.findErr)
+			mockTOTP.On("Validate", "SECRET", "123456", mock.Anything).Return(int64(100), tt.valid)
+			mockMFA.On("Enable", mock.Anything, "user-id", int64(100), mock.MatchedBy(func(hashes []string) bool {
+				return len(hashes) == 10
+			})).Return(nil)
+
+			codes, err := mfaUC.ConfirmTOTP(context.Background(), "user-id", "123 456")
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+				mockMFA.AssertNotCalled(t, "Enable", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, codes, 10)
+			for _, code := range codes {
+				assert.Regexp(t, `^[a-z2-7]{5}-[a-z2-7]{5}$`, code)
+			}
+
+			// Only the hashes of the codes handed to the user are persisted
+			hashes := mockMFA.Calls[1].Arguments.Get(3).([]string)
+			assert.Equal(t, utils.HashToken(strings.ReplaceAll(codes[0], "-", "")), hashes[0])
+		})
+	}
+}
+
+func TestMFAUseCase_Verify(t *testing.T) {
+	tests := []struct {
+		name       string
+		enrollment *domain.MFAEnrollment
+		code       string
+		totpValid  bool
+		stepErr    error
+		recoverErr error
+		expectErr  error
+	}{
+		{name: "totp code", enrollment: enabledEnrollment(), code: "123456", totpValid: true},
+		{name: "replayed totp code", enrollment: enabledEnrollment(), code: "123456", totpValid: true, stepErr: domain.ErrInvalidMFACode, expectErr: domain.ErrInvalidMFACode},
+		{name: "recovery code", enrollment: enabledEnrollment(), code: "ABCDE-FGHIJ"},
+		{name: "unknown code", enrollment: enabledEnrollment(), code: "000000", recoverErr: domain.ErrInvalidMFACode, expectErr: domain.ErrInvalidMFACode},
+		{name: "pending enrolment", enrollment: &domain.MFAEnrollment{UserID: "user-id", Secret: "SECRET"}, code: "123456", expectErr: domain.ErrMFANotEnabled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockMFA := new(MockMFARepo)
+			mockTOTP := new(MockTOTP)
+			mfaUC := usecases.NewMFAUseCase(new(MockUserRepo), mockMFA, mockTOTP)
+
+			mockMFA.On("FindByUserID", mock.Anything, "user-id").Return(tt.enrollment, nil)
+			mockTOTP.On("Validate", "SECRET", mock.Anything, mock.Anything).Return(int64(100), tt.totpValid)
+			mockMFA.On("RecordStep", mock.Anything, "user-id", int64(100)).Return(tt.stepErr)
+			mockMFA.On("UseRecoveryCode", mock.Anything, "user-id", mock.Anything).Return(tt.recoverErr)
+
+			err := mfaUC.Verify(context.Background(), "user-id", tt.code)
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			if tt.code == "ABCDE-FGHIJ" {
+				mockMFA.AssertCalled(t, "UseRecoveryCode", mock.Anything, "user-id", utils.HashToken("abcdefghij"))
+			}
+		})
+	}
+}
+
+func TestMFAUseCase_DisableTOTP(t *testing.T) {
+	mockMFA := new(MockMFARepo)
+	mockTOTP := new(MockTOTP)
+	mfaUC := usecases.NewMFAUseCase(new(MockUserRepo), mockMFA, mockTOTP)
+
+	mockMFA.On("FindByUserID", mock.Anything, "user-id").Return(enabledEnrollment(), nil)
+	mockTOTP.On("Validate", "SECRET", "123456", mock.Anything).Return(int64(100), true)
+	mockMFA.On("RecordStep", mock.Anything, "user-id", int64(100)).Return(nil)
+	mockMFA.On("Delete", mock.Anything, "user-id").Return(nil)
+
+	assert.NoError(t, mfaUC.DisableTOTP(context.Background(), "user-id", "123456"))
+	mockMFA.AssertCalled(t, "Delete", mock.Anything, "user-id")
+}
+
+func TestMFAUseCase_Required(t *testing.T) {
+	tests := []struct {
+		name       string
+		enrollment *domain.MFAEnrollment
+		findErr    error
+		want       bool
+	}{
+		{name: "enabled", enrollment: enabledEnrollment(), want: true},
+		{name: "pending", enrollment: &domain.MFAEnrollment{UserID: "user-id"}},
+		{name: "not enrolled", findErr: sql.ErrNoRows},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockMFA := new(MockMFARepo)
+			mfaUC := usecases.NewMFAUseCase(new(MockUserRepo), mockMFA, new(MockTOTP))
+
+			mockMFA.On("FindByUserID", mock.Anything, "user-id").Return(tt.enrollment, tt.findErr)
+
+			required, err := mfaUC.Required(context.Background(), "user-id")
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, required)
+		})
+	}
+}
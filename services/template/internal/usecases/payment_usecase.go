@@ -0,0 +1,135 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/internal/ports/repositories"
+	"github.com/nuhorizon/go-project-template/services/template/internal/ports/services"
+
+	"github.com/google/uuid"
+)
+
+// PaymentUseCase keeps the plans of the users in sync with their subscriptions at the payment provider
+type PaymentUseCase interface {
+	// HandleWebhook verifies a webhook of the payment provider, records it and applies the
+	// subscription change it carries. Events already recorded are accepted and ignored, so the
+	// provider can retry freely. Events a retry can't fix (unknown plan, older than the plan of
+	// the user) are accepted and recorded with their domain.PaymentEvent.Rejection. Fails with
+	// domain.ErrInvalidWebhookSignature or domain.ErrInvalidWebhookPayload when the webhook can't be trusted.
+	HandleWebhook(ctx context.Context, payload []byte, signature string) error
+}
+
+type paymentUseCase struct {
+	provider    services.PaymentProvider
+	paymentRepo repositories.PaymentEventRepository
+	planRepo    repositories.PlanRepository
+	notifier    planChangeNotifier
+}
+
+func NewPaymentUseCase(
+	provider services.PaymentProvider,
+	paymentRepo repositories.PaymentEventRepository,
+	planRepo repositories.PlanRepository,
+	revocations repositories.TokenRevocationStore,
+	events services.SubscriptionEventPublisher,
+) PaymentUseCase {
+	return &paymentUseCase{
+		provider:    provider,
+		paymentRepo: paymentRepo,
+		planRepo:    planRepo,
+		notifier:    planChangeNotifier{revocations: revocations, events: events},
+	}
+}
+
+// planChange is the plan a payment event gives a user
+type planChange struct {
+	user         *domain.User
+	eventType    string
+	previousPlan string
+}
+
+func (p *paymentUseCase) HandleWebhook(ctx context.Context, payload []byte, signature string) error {
+	now := time.Now()
+	event, err := p.provider.ParseWebhook(payload, signature, now)
+	if err != nil {
+		return err
+	}
+
+	sub := event.Subscription
+	if sub == nil || uuid.Validate(sub.UserID) != nil {
+		_, err := p.paymentRepo.Record(ctx, event, nil)
+		return err
+	}
+	event.UserID = sub.UserID
+
+	// Failing before Record leaves the event unrecorded, so the retry of the provider applies it
+	plan, err := p.subscriptionPlan(ctx, sub)
+	if errors.Is(err, domain.ErrPlanNotFound) {
+		event.Rejection = domain.PaymentEventUnknownPlan
+	} else if err != nil {
+		return err
+	}
+
+	var change *planChange
+	var apply repositories.PaymentPlanChange
+	if plan != nil {
+		apply = func(user *domain.User) bool {
+			change = changePlan(user, sub, plan, now)
+			return change != nil
+		}
+	}
+	recorded, err := p.paymentRepo.Record(ctx, event, apply)
+	if err != nil || !recorded || change == nil {
+		return err
+	}
+	return p.notifier.notify(ctx, change.user, change.eventType, change.previousPlan, now)
+}
+
+// subscriptionPlan returns the plan the subscription entitles to, nil when the plan stays as it is
+func (p *paymentUseCase) subscriptionPlan(ctx context.Context, sub *domain.PaymentSubscription) (*domain.Plan, error) {
+	switch sub.Status {
+	case domain.PaymentSubscriptionActive:
+		return findPlanByCode(ctx, p.planRepo, strings.ToLower(sub.Plan))
+	case domain.PaymentSubscriptionEnded:
+		return findPlanByCode(ctx, p.planRepo, domain.PlanFree)
+	}
+	// Past due subscriptions keep their plan, the grace period of ExpireLapsed covers the retries
+	return nil, nil
+}
+
+// changePlan puts user on the plan of the subscription and returns the change, nil when the
+// plan stays as it is
+func changePlan(user *domain.User, sub *domain.PaymentSubscription, plan *domain.Plan, now time.Time) *planChange {
+	change := &planChange{user: user, previousPlan: user.PlanType}
+	if plan.Code == domain.PlanFree {
+		if onFreePlan(user) {
+			return nil
+		}
+		resetToFree(user, plan)
+		change.eventType = domain.SubscriptionDowngraded
+		return change
+	}
+
+	before := *user
+	change.eventType = upgradeTo(user, plan, sub.PeriodEnd, now)
+	user.PlanCancelled = sub.CancelAtPeriodEnd && sub.PeriodEnd != nil
+	if samePlan(&before, user) {
+		*user = before
+		return nil
+	}
+	if user.PlanCancelled && !before.PlanCancelled && change.eventType == domain.SubscriptionRenewed {
+		change.eventType = domain.SubscriptionCancelled
+	}
+	return change
+}
+
+// samePlan reports whether a and b are on the same plan, period and cancellation
+func samePlan(a, b *domain.User) bool {
+	sameExpiry := a.PlanExpiry == nil && b.PlanExpiry == nil ||
+		a.PlanExpiry != nil && b.PlanExpiry != nil && a.PlanExpiry.Equal(*b.PlanExpiry)
+	return a.PlanType == b.PlanType && a.PlanID == b.PlanID && a.PlanCancelled == b.PlanCancelled && sameExpiry
+}
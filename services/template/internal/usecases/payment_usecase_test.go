@@ -0,0 +1,243 @@
+package usecases_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/internal/ports/repositories"
+	"github.com/nuhorizon/go-project-template/services/template/internal/usecases"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const paymentUserID = "5b0c7a52-3f4e-4d8a-9b1c-2e6f8a9d0c11"
+
+type MockPaymentProvider struct{ mock.Mock }
+
+func (m *MockPaymentProvider) SignatureHeader() string {
+	return m.Called().String(0)
+}
+
+func (m *MockPaymentProvider) ParseWebhook(payload []byte, signature string, now time.Time) (*domain.PaymentEvent, error) {
+	args := m.Called(payload, signature, now)
+	event, _ := args.Get(0).(*domain.PaymentEvent)
+	return event, args.Error(1)
+}
+
+// MockPaymentEventRepo applies the plan change to user, the user locked by the real repository.
+// A nil user stands for an event of an unknown user.
+type MockPaymentEventRepo struct {
+	mock.Mock
+	user    *domain.User
+	changed bool
+}
+
+func (m *MockPaymentEventRepo) Record(ctx context.Context, event *domain.PaymentEvent, apply repositories.PaymentPlanChange) (bool, error) {
+	args := m.Called(ctx, event)
+	if apply != nil && m.user != nil {
+		m.changed = apply(m.user)
+	}
+	return args.Bool(0), args.Error(1)
+}
+
+func subscriptionEvent(status, plan string, periodEnd *time.Time, cancel bool) *domain.PaymentEvent {
+	return &domain.PaymentEvent{
+		ID:       "evt_1",
+		Provider: "stripe",
+		Type:     "customer.subscription.updated",
+		Subscription: &domain.PaymentSubscription{
+			UserID:            paymentUserID,
+			Plan:              plan,
+			Status:            status,
+			PeriodEnd:         periodEnd,
+			CancelAtPeriodEnd: cancel,
+		},
+	}
+}
+
+func TestPaymentUseCase_HandleWebhook(t *testing.T) {
+	since := time.Now().Add(-30 * 24 * time.Hour)
+	expiry := time.Now().Add(24 * time.Hour)
+	periodEnd := time.Now().Add(31 * 24 * time.Hour)
+
+	tests := []struct {
+		name          string
+		event         *domain.PaymentEvent
+		user          *domain.User
+		expectChange  bool
+		expectEvent   string
+		expectPlan    string
+		expectExpiry  *time.Time
+		expectCancel  bool
+		expectRevoke  bool
+		expectUserID  string
+		expectReject  string
+		expectErr     error
+		expectNoStore bool
+	}{
+		{
+			name:         "active subscription upgrades",
+			event:        subscriptionEvent(domain.PaymentSubscriptionActive, "Premium", &periodEnd, false),
+			user:         &domain.User{ID: paymentUserID, PlanType: domain.PlanFree},
+			expectChange: true,
+			expectEvent:  domain.SubscriptionUpgraded,
+			expectPlan:   domain.PlanPremium,
+			expectExpiry: &periodEnd,
+			expectRevoke: true,
+			expectUserID: paymentUserID,
+		},
+		{
+			name:         "new period renews",
+			event:        subscriptionEvent(domain.PaymentSubscriptionActive, domain.PlanPremium, &periodEnd, false),
+			user:         &domain.User{ID: paymentUserID, PlanType: domain.PlanPremium, PlanID: "premium-id", PremiumSince: &since, PlanExpiry: &expiry},
+			expectChange: true,
+			expectEvent:  domain.SubscriptionRenewed,
+			expectPlan:   domain.PlanPremium,
+			expectExpiry: &periodEnd,
+			expectUserID: paymentUserID,
+		},
+		{
+			name:         "cancellation at period end",
+			event:        subscriptionEvent(domain.PaymentSubscriptionActive, domain.PlanPremium, &expiry, true),
+			user:         &domain.User{ID: paymentUserID, PlanType: domain.PlanPremium, PlanID: "premium-id", PremiumSince: &since, PlanExpiry: &expiry},
+			expectChange: true,
+			expectEvent:  domain.SubscriptionCancelled,
+			expectPlan:   domain.PlanPremium,
+			expectExpiry: &expiry,
+			expectCancel: true,
+			expectUserID: paymentUserID,
+		},
+		{
+			name:         "unchanged subscription is only recorded",
+			event:        subscriptionEvent(domain.PaymentSubscriptionActive, domain.PlanPremium, &expiry, false),
+			user:         &domain.User{ID: paymentUserID, PlanType: domain.PlanPremium, PlanID: "premium-id", PremiumSince: &since, PlanExpiry: &expiry},
+			expectUserID: paymentUserID,
+		},
+		{
+			name:         "ended subscription downgrades",
+			event:        subscriptionEvent(domain.PaymentSubscriptionEnded, domain.PlanPremium, &expiry, false),
+			user:         &domain.User{ID: paymentUserID, PlanType: domain.PlanPremium, PlanID: "premium-id", PremiumSince: &since, PlanExpiry: &expiry},
+			expectChange: true,
+			expectEvent:  domain.SubscriptionDowngraded,
+			expectPlan:   domain.PlanFree,
+			expectRevoke: true,
+			expectUserID: paymentUserID,
+		},
+		{
+			name:         "past due keeps the plan",
+			event:        subscriptionEvent(domain.PaymentSubscriptionPastDue, domain.PlanPremium, &expiry, false),
+			user:         &domain.User{ID: paymentUserID, PlanType: domain.PlanPremium, PlanExpiry: &expiry},
+			expectUserID: paymentUserID,
+		},
+		{
+			name:         "unknown user is only recorded",
+			event:        subscriptionEvent(domain.PaymentSubscriptionActive, domain.PlanPremium, &periodEnd, false),
+			expectUserID: paymentUserID,
+		},
+		{
+			name:  "event without a subscription is only recorded",
+			event: &domain.PaymentEvent{ID: "evt_1", Provider: "stripe", Type: "invoice.paid"},
+		},
+		{
+			name:         "unknown plan is recorded as rejected",
+			event:        subscriptionEvent(domain.PaymentSubscriptionActive, "platinum", &periodEnd, false),
+			user:         &domain.User{ID: paymentUserID, PlanType: domain.PlanFree},
+			expectUserID: paymentUserID,
+			expectReject: domain.PaymentEventUnknownPlan,
+		},
+		{
+			name:          "plan lookup failure is left for a retry",
+			event:         subscriptionEvent(domain.PaymentSubscriptionActive, "gold", &periodEnd, false),
+			user:          &domain.User{ID: paymentUserID, PlanType: domain.PlanFree},
+			expectErr:     sql.ErrConnDone,
+			expectNoStore: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := new(MockPaymentProvider)
+			paymentRepo := &MockPaymentEventRepo{user: tt.user}
+			planRepo := new(MockPlanRepo)
+			revocations := new(MockRevocationStore)
+			events := publishedEvents()
+			uc := usecases.NewPaymentUseCase(provider, paymentRepo, planRepo, revocations, events)
+
+			provider.On("ParseWebhook", []byte("payload"), "signature", mock.Anything).Return(tt.event, nil)
+			planRepo.On("FindByCode", mock.Anything, domain.PlanFree).Return(freePlan, nil)
+			planRepo.On("FindByCode", mock.Anything, domain.PlanPremium).Return(premiumPlan, nil)
+			planRepo.On("FindByCode", mock.Anything, "platinum").Return(nil, sql.ErrNoRows)
+			planRepo.On("FindByCode", mock.Anything, "gold").Return(nil, sql.ErrConnDone)
+			paymentRepo.On("Record", mock.Anything, tt.event).Return(true, nil)
+			revocations.On("RevokeUserTokens", mock.Anything, paymentUserID, mock.Anything).Return(nil)
+
+			err := uc.HandleWebhook(context.Background(), []byte("payload"), "signature")
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			if tt.expectNoStore {
+				paymentRepo.AssertNotCalled(t, "Record", mock.Anything, mock.Anything)
+				return
+			}
+			assert.Equal(t, tt.expectUserID, tt.event.UserID)
+			assert.Equal(t, tt.expectReject, tt.event.Rejection)
+			assert.Equal(t, tt.expectChange, paymentRepo.changed)
+
+			stored := paymentRepo.user
+			if !tt.expectChange {
+				events.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything)
+				revocations.AssertNotCalled(t, "RevokeUserTokens", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			assert.Equal(t, tt.expectPlan, stored.PlanType)
+			assert.Equal(t, tt.expectExpiry, stored.PlanExpiry)
+			assert.Equal(t, tt.expectCancel, stored.PlanCancelled)
+			assert.Equal(t, tt.expectEvent, lastEvent(events).Type)
+			if tt.expectRevoke {
+				revocations.AssertCalled(t, "RevokeUserTokens", mock.Anything, paymentUserID, mock.Anything)
+			} else {
+				revocations.AssertNotCalled(t, "RevokeUserTokens", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestPaymentUseCase_HandleWebhook_Duplicate(t *testing.T) {
+	provider := new(MockPaymentProvider)
+	paymentRepo := &MockPaymentEventRepo{user: &domain.User{ID: paymentUserID, PlanType: domain.PlanFree}}
+	planRepo := new(MockPlanRepo)
+	revocations := new(MockRevocationStore)
+	events := new(MockEventPublisher)
+	uc := usecases.NewPaymentUseCase(provider, paymentRepo, planRepo, revocations, events)
+
+	periodEnd := time.Now().Add(31 * 24 * time.Hour)
+	event := subscriptionEvent(domain.PaymentSubscriptionActive, domain.PlanPremium, &periodEnd, false)
+	provider.On("ParseWebhook", mock.Anything, mock.Anything, mock.Anything).Return(event, nil)
+	planRepo.On("FindByCode", mock.Anything, domain.PlanPremium).Return(premiumPlan, nil)
+	paymentRepo.On("Record", mock.Anything, event).Return(false, nil)
+
+	err := uc.HandleWebhook(context.Background(), []byte("payload"), "signature")
+
+	assert.NoError(t, err)
+	events.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything)
+	revocations.AssertNotCalled(t, "RevokeUserTokens", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPaymentUseCase_HandleWebhook_InvalidSignature(t *testing.T) {
+	provider := new(MockPaymentProvider)
+	paymentRepo := new(MockPaymentEventRepo)
+	uc := usecases.NewPaymentUseCase(provider, paymentRepo, new(MockPlanRepo), new(MockRevocationStore), new(MockEventPublisher))
+
+	provider.On("ParseWebhook", mock.Anything, mock.Anything, mock.Anything).Return(nil, domain.ErrInvalidWebhookSignature)
+
+	err := uc.HandleWebhook(context.Background(), []byte("payload"), "t=1,v1=00")
+
+	assert.ErrorIs(t, err, domain.ErrInvalidWebhookSignature)
+	paymentRepo.AssertNotCalled(t, "Record", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,249 @@
+package usecases
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/internal/ports/repositories"
+	"github.com/nuhorizon/go-project-template/services/template/internal/ports/services"
+)
+
+// DefaultSubscriptionGracePeriod is how long a paid plan is kept after its period ended without
+// a renewal, so a late payment doesn't cut the user off
+const DefaultSubscriptionGracePeriod = 3 * 24 * time.Hour
+
+// lapsedPlansBatch is how many lapsed plans ExpireLapsed loads at a time
+const lapsedPlansBatch = 100
+
+// SubscriptionUseCase moves users between plans. Every change is published to the
+// SubscriptionEventPublisher; publishing failures are returned but the change is kept.
+type SubscriptionUseCase interface {
+	// Upgrade moves the user to the plan with the given code until periodEnd (nil for a plan
+	// without an end), or extends the paid period of their current plan. A pending cancellation
+	// is dropped. Returns domain.ErrPlanNotFound when there is no plan with that code.
+	Upgrade(ctx context.Context, userID, planCode string, periodEnd *time.Time) (*domain.User, error)
+	// Downgrade moves the user to the free plan right away
+	Downgrade(ctx context.Context, userID string) (*domain.User, error)
+	// Cancel keeps the paid plan until the end of the period already paid for, without a grace
+	// period. Plans granted without an end are downgraded right away.
+	Cancel(ctx context.Context, userID string) (*domain.User, error)
+	// ExpireLapsed moves to the free plan every user whose paid period ended before now (after the
+	// grace period, unless they cancelled) and returns how many were moved
+	ExpireLapsed(ctx context.Context, now time.Time) (int, error)
+}
+
+type subscriptionUseCase struct {
+	userRepo repositories.UserRepository
+	planRepo repositories.PlanRepository
+	notifier planChangeNotifier
+	grace    time.Duration
+}
+
+func NewSubscriptionUseCase(
+	userRepo repositories.UserRepository,
+	planRepo repositories.PlanRepository,
+	revocations repositories.TokenRevocationStore,
+	events services.SubscriptionEventPublisher,
+	grace time.Duration,
+) SubscriptionUseCase {
+	return &subscriptionUseCase{
+		userRepo: userRepo,
+		planRepo: planRepo,
+		notifier: planChangeNotifier{revocations: revocations, events: events},
+		grace:    grace,
+	}
+}
+
+func (s *subscriptionUseCase) Upgrade(ctx context.Context, userID, planCode string, periodEnd *time.Time) (*domain.User, error) {
+	user, err := s.findUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	plan, err := s.findPlan(ctx, strings.ToLower(planCode))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if plan.Code == domain.PlanFree {
+		return s.result(user, s.moveToFree(ctx, user, domain.SubscriptionDowngraded, now))
+	}
+
+	previous := user.PlanType
+	eventType := upgradeTo(user, plan, periodEnd, now)
+	return s.result(user, s.apply(ctx, user, eventType, previous, now))
+}
+
+func (s *subscriptionUseCase) Downgrade(ctx context.Context, userID string) (*domain.User, error) {
+	user, err := s.findUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if onFreePlan(user) {
+		return user, nil
+	}
+	return s.result(user, s.moveToFree(ctx, user, domain.SubscriptionDowngraded, time.Now()))
+}
+
+func (s *subscriptionUseCase) Cancel(ctx context.Context, userID string) (*domain.User, error) {
+	user, err := s.findUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	switch {
+	case onFreePlan(user):
+		return nil, domain.ErrNoActiveSubscription
+	case user.PlanExpiry == nil:
+		return s.result(user, s.moveToFree(ctx, user, domain.SubscriptionDowngraded, now))
+	case user.PlanCancelled:
+		return user, nil
+	}
+
+	user.PlanCancelled = true
+	return s.result(user, s.apply(ctx, user, domain.SubscriptionCancelled, user.PlanType, now))
+}
+
+// ExpireLapsed keeps going when a user fails, the failures are joined into the returned error
+// and the users are picked up again on the next run
+func (s *subscriptionUseCase) ExpireLapsed(ctx context.Context, now time.Time) (int, error) {
+	expired := 0
+	var errs []error
+	for {
+		users, err := s.userRepo.ListLapsedPlans(ctx, now, now.Add(-s.grace), lapsedPlansBatch)
+		if err != nil {
+			return expired, err
+		}
+		for i := range users {
+			if err := s.moveToFree(ctx, &users[i], domain.SubscriptionExpired, now); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			expired++
+		}
+		// Users that failed are still lapsed, listing again would return them forever
+		if len(users) < lapsedPlansBatch || len(errs) > 0 {
+			return expired, errors.Join(errs...)
+		}
+	}
+}
+
+func (s *subscriptionUseCase) moveToFree(ctx context.Context, user *domain.User, eventType string, now time.Time) error {
+	plan, err := s.findPlan(ctx, domain.PlanFree)
+	if err != nil {
+		return err
+	}
+
+	previous := user.PlanType
+	resetToFree(user, plan)
+	return s.apply(ctx, user, eventType, previous, now)
+}
+
+// apply stores the plan of the user and notifies the change
+func (s *subscriptionUseCase) apply(ctx context.Context, user *domain.User, eventType, previousPlan string, now time.Time) error {
+	if err := s.userRepo.UpdatePlan(ctx, user); err != nil {
+		return err
+	}
+	return s.notifier.notify(ctx, user, eventType, previousPlan, now)
+}
+
+func (s *subscriptionUseCase) result(user *domain.User, err error) (*domain.User, error) {
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (s *subscriptionUseCase) findUser(ctx context.Context, userID string) (*domain.User, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrUserNotFound
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+func (s *subscriptionUseCase) findPlan(ctx context.Context, code string) (*domain.Plan, error) {
+	return findPlanByCode(ctx, s.planRepo, code)
+}
+
+func findPlanByCode(ctx context.Context, planRepo repositories.PlanRepository, code string) (*domain.Plan, error) {
+	plan, err := planRepo.FindByCode(ctx, code)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrPlanNotFound
+		}
+		return nil, err
+	}
+	return plan, nil
+}
+
+// onFreePlan reports whether the user has nothing left to downgrade or cancel
+func onFreePlan(user *domain.User) bool {
+	return (user.PlanType == "" || user.PlanType == domain.PlanFree) && user.PlanExpiry == nil
+}
+
+// upgradeTo puts the user on a paid plan until periodEnd (nil for no end), dropping a pending
+// cancellation, and returns the type of the change
+func upgradeTo(user *domain.User, plan *domain.Plan, periodEnd *time.Time, now time.Time) string {
+	eventType := domain.SubscriptionRenewed
+	if user.PlanType != plan.Code || user.PremiumSince == nil {
+		eventType = domain.SubscriptionUpgraded
+		user.PremiumSince = &now
+	}
+	user.PlanType = plan.Code
+	user.PlanID = plan.ID
+	user.PlanExpiry = periodEnd
+	user.PlanCancelled = false
+	return eventType
+}
+
+// resetToFree puts the user on the free plan
+func resetToFree(user *domain.User, free *domain.Plan) {
+	user.PlanType = free.Code
+	user.PlanID = free.ID
+	user.PremiumSince = nil
+	user.PlanExpiry = nil
+	user.PlanCancelled = false
+}
+
+// planChangeNotifier tells the rest of the system about a plan change already stored
+type planChangeNotifier struct {
+	revocations repositories.TokenRevocationStore
+	events      services.SubscriptionEventPublisher
+}
+
+// notify publishes the change. When the plan itself changed, the access tokens issued before it
+// are rejected (see revokeTokensBefore) so clients pick up the new plan with their next refresh.
+func (n planChangeNotifier) notify(ctx context.Context, user *domain.User, eventType, previousPlan string, now time.Time) error {
+	if user.PlanType != previousPlan {
+		if err := revokeTokensBefore(ctx, n.revocations, user.ID, now); err != nil {
+			return err
+		}
+	}
+
+	return n.events.Publish(ctx, domain.SubscriptionEvent{
+		Type:         eventType,
+		UserID:       user.ID,
+		Plan:         user.PlanType,
+		PreviousPlan: previousPlan,
+		PlanExpiry:   user.PlanExpiry,
+		OccurredAt:   now,
+	})
+}
+
+// revokeTokensBefore rejects the access tokens of the user issued before the second of now.
+// Unlike revokeUserTokens the current second is spared, the refresh the client makes to pick up
+// the new plan can land in it. A token issued earlier in that same second keeps its old plan_type
+// claim until it expires. The cutoff is a microsecond short of the second, the precision Postgres
+// keeps for timestamps.
+func revokeTokensBefore(ctx context.Context, revocations repositories.TokenRevocationStore, userID string, now time.Time) error {
+	return revocations.RevokeUserTokens(ctx, userID, now.Truncate(time.Second).Add(-time.Microsecond))
+}
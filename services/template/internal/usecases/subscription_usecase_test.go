@@ -0,0 +1,300 @@
+package usecases_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/internal/usecases"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockSubscriptionUseCase struct{ mock.Mock }
+
+func (m *MockSubscriptionUseCase) Upgrade(ctx context.Context, userID, planCode string, periodEnd *time.Time) (*domain.User, error) {
+	args := m.Called(ctx, userID, planCode, periodEnd)
+	user, _ := args.Get(0).(*domain.User)
+	return user, args.Error(1)
+}
+
+func (m *MockSubscriptionUseCase) Downgrade(ctx context.Context, userID string) (*domain.User, error) {
+	args := m.Called(ctx, userID)
+	user, _ := args.Get(0).(*domain.User)
+	return user, args.Error(1)
+}
+
+func (m *MockSubscriptionUseCase) Cancel(ctx context.Context, userID string) (*domain.User, error) {
+	args := m.Called(ctx, userID)
+	user, _ := args.Get(0).(*domain.User)
+	return user, args.Error(1)
+}
+
+func (m *MockSubscriptionUseCase) ExpireLapsed(ctx context.Context, now time.Time) (int, error) {
+	args := m.Called(ctx, now)
+	return args.Int(0), args.Error(1)
+}
+
+type MockEventPublisher struct{ mock.Mock }
+
+func (m *MockEventPublisher) Publish(ctx context.Context, event domain.SubscriptionEvent) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
+// publishedEvents lets every event through
+func publishedEvents() *MockEventPublisher {
+	events := new(MockEventPublisher)
+	events.On("Publish", mock.Anything, mock.Anything).Return(nil)
+	return events
+}
+
+func lastEvent(events *MockEventPublisher) domain.SubscriptionEvent {
+	return events.Calls[len(events.Calls)-1].Arguments.Get(1).(domain.SubscriptionEvent)
+}
+
+func TestSubscriptionUseCase_Upgrade(t *testing.T) {
+	since := time.Now().Add(-30 * 24 * time.Hour)
+	periodEnd := time.Now().Add(30 * 24 * time.Hour)
+
+	tests := []struct {
+		name         string
+		user         *domain.User
+		findErr      error
+		planCode     string
+		expectEvent  string
+		expectSince  func(*testing.T, *time.Time)
+		expectRevoke bool
+		expectErr    error
+	}{
+		{
+			name:         "upgrade starts the premium period",
+			user:         &domain.User{ID: "user-id", PlanType: domain.PlanFree},
+			planCode:     "Premium",
+			expectEvent:  domain.SubscriptionUpgraded,
+			expectSince:  func(t *testing.T, got *time.Time) { assert.WithinDuration(t, time.Now(), *got, 5*time.Second) },
+			expectRevoke: true,
+		},
+		{
+			name:        "renewal keeps the start and drops the cancellation",
+			user:        &domain.User{ID: "user-id", PlanType: domain.PlanPremium, PremiumSince: &since, PlanCancelled: true},
+			planCode:    domain.PlanPremium,
+			expectEvent: domain.SubscriptionRenewed,
+			expectSince: func(t *testing.T, got *time.Time) { assert.Equal(t, since, *got) },
+		},
+		{
+			name:         "free plan downgrades",
+			user:         &domain.User{ID: "user-id", PlanType: domain.PlanPremium, PremiumSince: &since},
+			planCode:     domain.PlanFree,
+			expectEvent:  domain.SubscriptionDowngraded,
+			expectSince:  func(t *testing.T, got *time.Time) { assert.Nil(t, got) },
+			expectRevoke: true,
+		},
+		{name: "unknown plan", user: &domain.User{ID: "user-id"}, planCode: "platinum", expectErr: domain.ErrPlanNotFound},
+		{name: "unknown user", findErr: sql.ErrNoRows, planCode: domain.PlanPremium, expectErr: domain.ErrUserNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := new(MockUserRepo)
+			planRepo := new(MockPlanRepo)
+			revocations := new(MockRevocationStore)
+			events := publishedEvents()
+			uc := usecases.NewSubscriptionUseCase(userRepo, planRepo, revocations, events, usecases.DefaultSubscriptionGracePeriod)
+
+			userRepo.On("FindByID", mock.Anything, "user-id").Return(tt.user, tt.findErr)
+			userRepo.On("UpdatePlan", mock.Anything, mock.AnythingOfType("*domain.User")).Return(nil)
+			planRepo.On("FindByCode", mock.Anything, domain.PlanFree).Return(freePlan, nil)
+			planRepo.On("FindByCode", mock.Anything, domain.PlanPremium).Return(premiumPlan, nil)
+			planRepo.On("FindByCode", mock.Anything, "platinum").Return(nil, sql.ErrNoRows)
+			revocations.On("RevokeUserTokens", mock.Anything, "user-id", mock.Anything).Return(nil)
+
+			user, err := uc.Upgrade(context.Background(), "user-id", tt.planCode, &periodEnd)
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+				assert.Nil(t, user)
+				userRepo.AssertNotCalled(t, "UpdatePlan", mock.Anything, mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			tt.expectSince(t, user.PremiumSince)
+			assert.False(t, user.PlanCancelled)
+			userRepo.AssertCalled(t, "UpdatePlan", mock.Anything, user)
+			assert.Equal(t, tt.expectEvent, lastEvent(events).Type)
+			if tt.expectRevoke {
+				revocations.AssertCalled(t, "RevokeUserTokens", mock.Anything, "user-id", mock.Anything)
+			} else {
+				revocations.AssertNotCalled(t, "RevokeUserTokens", mock.Anything, mock.Anything, mock.Anything)
+			}
+			if tt.expectEvent == domain.SubscriptionDowngraded {
+				assert.Equal(t, "free-id", user.PlanID)
+				assert.Nil(t, user.PlanExpiry)
+			} else {
+				assert.Equal(t, "premium-id", user.PlanID)
+				assert.Equal(t, &periodEnd, user.PlanExpiry)
+			}
+		})
+	}
+}
+
+func TestSubscriptionUseCase_Downgrade(t *testing.T) {
+	expiry := time.Now().Add(24 * time.Hour)
+
+	t.Run("paid plan", func(t *testing.T) {
+		userRepo := new(MockUserRepo)
+		planRepo := new(MockPlanRepo)
+		revocations := new(MockRevocationStore)
+		events := publishedEvents()
+		uc := usecases.NewSubscriptionUseCase(userRepo, planRepo, revocations, events, usecases.DefaultSubscriptionGracePeriod)
+
+		userRepo.On("FindByID", mock.Anything, "user-id").Return(&domain.User{ID: "user-id", PlanType: domain.PlanPremium, PlanExpiry: &expiry}, nil)
+		userRepo.On("UpdatePlan", mock.Anything, mock.AnythingOfType("*domain.User")).Return(nil)
+		planRepo.On("FindByCode", mock.Anything, domain.PlanFree).Return(freePlan, nil)
+		revocations.On("RevokeUserTokens", mock.Anything, "user-id", mock.Anything).Return(nil)
+
+		user, err := uc.Downgrade(context.Background(), "user-id")
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.PlanFree, user.PlanType)
+		assert.Nil(t, user.PlanExpiry)
+		event := lastEvent(events)
+		assert.Equal(t, domain.SubscriptionDowngraded, event.Type)
+		assert.Equal(t, domain.PlanPremium, event.PreviousPlan)
+	})
+
+	t.Run("already free", func(t *testing.T) {
+		userRepo := new(MockUserRepo)
+		events := new(MockEventPublisher)
+		uc := usecases.NewSubscriptionUseCase(userRepo, new(MockPlanRepo), new(MockRevocationStore), events, usecases.DefaultSubscriptionGracePeriod)
+
+		userRepo.On("FindByID", mock.Anything, "user-id").Return(&domain.User{ID: "user-id"}, nil)
+
+		_, err := uc.Downgrade(context.Background(), "user-id")
+
+		assert.NoError(t, err)
+		userRepo.AssertNotCalled(t, "UpdatePlan", mock.Anything, mock.Anything)
+		events.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything)
+	})
+}
+
+func TestSubscriptionUseCase_Cancel(t *testing.T) {
+	expiry := time.Now().Add(24 * time.Hour)
+
+	tests := []struct {
+		name        string
+		user        *domain.User
+		expectEvent string
+		expectErr   error
+	}{
+		{
+			name:        "keeps the plan until the period ends",
+			user:        &domain.User{ID: "user-id", PlanType: domain.PlanPremium, PlanExpiry: &expiry},
+			expectEvent: domain.SubscriptionCancelled,
+		},
+		{
+			name:        "plan without an end is downgraded",
+			user:        &domain.User{ID: "user-id", PlanType: domain.PlanPremium},
+			expectEvent: domain.SubscriptionDowngraded,
+		},
+		{
+			name: "already cancelled",
+			user: &domain.User{ID: "user-id", PlanType: domain.PlanPremium, PlanExpiry: &expiry, PlanCancelled: true},
+		},
+		{
+			name:      "nothing to cancel",
+			user:      &domain.User{ID: "user-id", PlanType: domain.PlanFree},
+			expectErr: domain.ErrNoActiveSubscription,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := new(MockUserRepo)
+			planRepo := new(MockPlanRepo)
+			revocations := new(MockRevocationStore)
+			events := publishedEvents()
+			uc := usecases.NewSubscriptionUseCase(userRepo, planRepo, revocations, events, usecases.DefaultSubscriptionGracePeriod)
+
+			userRepo.On("FindByID", mock.Anything, "user-id").Return(tt.user, nil)
+			userRepo.On("UpdatePlan", mock.Anything, mock.AnythingOfType("*domain.User")).Return(nil)
+			planRepo.On("FindByCode", mock.Anything, domain.PlanFree).Return(freePlan, nil)
+			revocations.On("RevokeUserTokens", mock.Anything, "user-id", mock.Anything).Return(nil)
+
+			user, err := uc.Cancel(context.Background(), "user-id")
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+				return
+			}
+			assert.NoError(t, err)
+			if tt.expectEvent == "" {
+				userRepo.AssertNotCalled(t, "UpdatePlan", mock.Anything, mock.Anything)
+				return
+			}
+			assert.Equal(t, tt.expectEvent, lastEvent(events).Type)
+			if tt.expectEvent == domain.SubscriptionCancelled {
+				assert.True(t, user.PlanCancelled)
+				assert.Equal(t, domain.PlanPremium, user.PlanType)
+				// The plan_type claim is still right, tokens are kept
+				revocations.AssertNotCalled(t, "RevokeUserTokens", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestSubscriptionUseCase_ExpireLapsed(t *testing.T) {
+	now := time.Now()
+	expired := now.Add(-time.Hour)
+	grace := 72 * time.Hour
+
+	t.Run("moves the lapsed users to free", func(t *testing.T) {
+		userRepo := new(MockUserRepo)
+		planRepo := new(MockPlanRepo)
+		revocations := new(MockRevocationStore)
+		events := publishedEvents()
+		uc := usecases.NewSubscriptionUseCase(userRepo, planRepo, revocations, events, grace)
+
+		userRepo.On("ListLapsedPlans", mock.Anything, now, now.Add(-grace), 100).Return([]domain.User{
+			{ID: "user-1", PlanType: domain.PlanPremium, PlanExpiry: &expired, PlanCancelled: true},
+			{ID: "user-2", PlanType: domain.PlanPremium, PlanExpiry: &expired},
+		}, nil)
+		userRepo.On("UpdatePlan", mock.Anything, mock.AnythingOfType("*domain.User")).Return(nil)
+		planRepo.On("FindByCode", mock.Anything, domain.PlanFree).Return(freePlan, nil)
+		// Tokens refreshed in the second of the change already carry the free plan and are kept
+		revocations.On("RevokeUserTokens", mock.Anything, mock.Anything, now.Truncate(time.Second).Add(-time.Microsecond)).Return(nil)
+
+		count, err := uc.ExpireLapsed(context.Background(), now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, count)
+		revocations.AssertNumberOfCalls(t, "RevokeUserTokens", 2)
+		event := lastEvent(events)
+		assert.Equal(t, domain.SubscriptionExpired, event.Type)
+		assert.Equal(t, "user-2", event.UserID)
+		assert.Equal(t, domain.PlanFree, event.Plan)
+		assert.Equal(t, domain.PlanPremium, event.PreviousPlan)
+	})
+
+	t.Run("failures are reported and retried on the next run", func(t *testing.T) {
+		userRepo := new(MockUserRepo)
+		planRepo := new(MockPlanRepo)
+		revocations := new(MockRevocationStore)
+		uc := usecases.NewSubscriptionUseCase(userRepo, planRepo, revocations, publishedEvents(), grace)
+
+		userRepo.On("ListLapsedPlans", mock.Anything, now, now.Add(-grace), 100).Return([]domain.User{
+			{ID: "user-1", PlanType: domain.PlanPremium, PlanExpiry: &expired},
+		}, nil)
+		userRepo.On("UpdatePlan", mock.Anything, mock.AnythingOfType("*domain.User")).Return(errors.New("db down"))
+		planRepo.On("FindByCode", mock.Anything, domain.PlanFree).Return(freePlan, nil)
+
+		count, err := uc.ExpireLapsed(context.Background(), now)
+
+		assert.EqualError(t, err, "db down")
+		assert.Zero(t, count)
+		userRepo.AssertNumberOfCalls(t, "ListLapsedPlans", 1)
+	})
+}
@@ -0,0 +1,108 @@
+package usecases
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/internal/ports/repositories"
+	"github.com/nuhorizon/go-project-template/services/template/internal/ports/services"
+)
+
+// UserUseCase lets users manage their own profile and account
+type UserUseCase interface {
+	GetProfile(ctx context.Context, userID string) (*domain.User, error)
+	// UpdateProfile changes the fields that are not nil, an empty picture URL removes the picture
+	UpdateProfile(ctx context.Context, userID string, name, pictureURL *string) (*domain.User, error)
+	// DeleteAccount soft deletes the user and signs them out everywhere, the data is kept for the
+	// retention period (see AccountRetentionUseCase)
+	DeleteAccount(ctx context.Context, userID string) error
+	// ExportData gathers everything stored about the user
+	ExportData(ctx context.Context, userID string) (*domain.UserDataExport, error)
+}
+
+type userUseCase struct {
+	userRepo     repositories.UserRepository
+	userDataRepo repositories.UserDataRepository
+	authUseCase  AuthUseCase
+	firebaseAuth services.FirebaseAuthService
+}
+
+// NewUserUseCase builds the self-service account flows. firebaseAuth may be nil on deployments
+// without Firebase, the Firebase account of deleted users is then left alone.
+func NewUserUseCase(
+	userRepo repositories.UserRepository,
+	userDataRepo repositories.UserDataRepository,
+	authUseCase AuthUseCase,
+	firebaseAuth services.FirebaseAuthService,
+) UserUseCase {
+	return &userUseCase{
+		userRepo:     userRepo,
+		userDataRepo: userDataRepo,
+		authUseCase:  authUseCase,
+		firebaseAuth: firebaseAuth,
+	}
+}
+
+func (u *userUseCase) GetProfile(ctx context.Context, userID string) (*domain.User, error) {
+	user, err := u.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrUserNotFound
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+func (u *userUseCase) UpdateProfile(ctx context.Context, userID string, name, pictureURL *string) (*domain.User, error) {
+	user, err := u.GetProfile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if name == nil && pictureURL == nil {
+		return user, nil
+	}
+	if name != nil {
+		user.Name = strings.TrimSpace(*name)
+	}
+	if pictureURL != nil {
+		user.PictureURL = strings.TrimSpace(*pictureURL)
+	}
+
+	if err := u.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (u *userUseCase) DeleteAccount(ctx context.Context, userID string) error {
+	user, err := u.GetProfile(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	// The Firebase account goes first: were it left behind, the next Firebase login would sign
+	// up a new user. Deleting it again on a retry is a no-op.
+	if user.FirebaseUID != "" && u.firebaseAuth != nil {
+		if err := u.firebaseAuth.DeleteUser(ctx, user.FirebaseUID); err != nil {
+			return err
+		}
+	}
+
+	if err := u.userRepo.SoftDelete(ctx, userID, time.Now()); err != nil {
+		return err
+	}
+	return u.authUseCase.RevokeAllSessions(ctx, userID)
+}
+
+func (u *userUseCase) ExportData(ctx context.Context, userID string) (*domain.UserDataExport, error) {
+	if _, err := u.GetProfile(ctx, userID); err != nil {
+		return nil, err
+	}
+	return u.userDataRepo.Export(ctx, userID)
+}
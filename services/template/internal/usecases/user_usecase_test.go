@@ -0,0 +1,256 @@
+package usecases_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/internal/usecases"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockUserDataRepo struct{ mock.Mock }
+
+func (m *MockUserDataRepo) Export(ctx context.Context, userID string) (*domain.UserDataExport, error) {
+	args := m.Called(ctx, userID)
+	export, _ := args.Get(0).(*domain.UserDataExport)
+	return export, args.Error(1)
+}
+
+func TestUserUseCase_GetProfile(t *testing.T) {
+	tests := []struct {
+		name      string
+		findErr   error
+		expectErr error
+	}{
+		{name: "success"},
+		{name: "unknown user", findErr: sql.ErrNoRows, expectErr: domain.ErrUserNotFound},
+		{name: "lookup fails", findErr: sql.ErrConnDone, expectErr: sql.ErrConnDone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockUserRepo)
+			uc := usecases.NewUserUseCase(mockRepo, nil, nil, nil)
+
+			var found *domain.User
+			if tt.findErr == nil {
+				found = &domain.User{ID: "user-id", Email: "user@example.com"}
+			}
+			mockRepo.On("FindByID", mock.Anything, "user-id").Return(found, tt.findErr)
+
+			user, err := uc.GetProfile(context.Background(), "user-id")
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+				assert.Nil(t, user)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "user@example.com", user.Email)
+		})
+	}
+}
+
+func TestUserUseCase_UpdateProfile(t *testing.T) {
+	name := "  New Name "
+	picture := "https://cdn.example.com/me.png"
+	empty := ""
+
+	tests := []struct {
+		name          string
+		newName       *string
+		newPicture    *string
+		findErr       error
+		updateErr     error
+		expectUpdate  bool
+		expectName    string
+		expectPicture string
+		expectErr     error
+	}{
+		{
+			name:          "name only keeps the picture",
+			newName:       &name,
+			expectUpdate:  true,
+			expectName:    "New Name",
+			expectPicture: "https://cdn.example.com/old.png",
+		},
+		{
+			name:          "picture only keeps the name",
+			newPicture:    &picture,
+			expectUpdate:  true,
+			expectName:    "Old Name",
+			expectPicture: "https://cdn.example.com/me.png",
+		},
+		{
+			name:         "empty picture removes it",
+			newPicture:   &empty,
+			expectUpdate: true,
+			expectName:   "Old Name",
+		},
+		{
+			name:          "nothing to change",
+			expectName:    "Old Name",
+			expectPicture: "https://cdn.example.com/old.png",
+		},
+		{
+			name:      "unknown user",
+			newName:   &name,
+			findErr:   sql.ErrNoRows,
+			expectErr: domain.ErrUserNotFound,
+		},
+		{
+			name:         "update fails",
+			newName:      &name,
+			updateErr:    errors.New("db error"),
+			expectUpdate: true,
+			expectErr:    errors.New("db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockUserRepo)
+			uc := usecases.NewUserUseCase(mockRepo, nil, nil, nil)
+
+			var found *domain.User
+			if tt.findErr == nil {
+				found = &domain.User{ID: "user-id", Name: "Old Name", PictureURL: "https://cdn.example.com/old.png"}
+			}
+			mockRepo.On("FindByID", mock.Anything, "user-id").Return(found, tt.findErr)
+			mockRepo.On("Update", mock.Anything, mock.AnythingOfType("*domain.User")).Return(tt.updateErr)
+
+			user, err := uc.UpdateProfile(context.Background(), "user-id", tt.newName, tt.newPicture)
+
+			if tt.expectUpdate {
+				mockRepo.AssertCalled(t, "Update", mock.Anything, mock.Anything)
+			} else {
+				mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+			}
+			if tt.expectErr != nil {
+				assert.EqualError(t, err, tt.expectErr.Error())
+				assert.Nil(t, user)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectName, user.Name)
+			assert.Equal(t, tt.expectPicture, user.PictureURL)
+		})
+	}
+}
+
+func TestUserUseCase_DeleteAccount(t *testing.T) {
+	tests := []struct {
+		name              string
+		user              *domain.User
+		findErr           error
+		withoutFirebase   bool
+		firebaseErr       error
+		expectFirebaseDel bool
+		expectSoftDelete  bool
+		expectErr         error
+	}{
+		{
+			name:              "firebase user",
+			user:              &domain.User{ID: "user-id", FirebaseUID: "firebase-uid"},
+			expectFirebaseDel: true,
+			expectSoftDelete:  true,
+		},
+		{
+			name:             "direct login user",
+			user:             &domain.User{ID: "user-id"},
+			expectSoftDelete: true,
+		},
+		{
+			name:             "deployment without firebase",
+			user:             &domain.User{ID: "user-id", FirebaseUID: "firebase-uid"},
+			withoutFirebase:  true,
+			expectSoftDelete: true,
+		},
+		{
+			name:              "firebase failure keeps the account",
+			user:              &domain.User{ID: "user-id", FirebaseUID: "firebase-uid"},
+			firebaseErr:       errors.New("firebase unavailable"),
+			expectFirebaseDel: true,
+			expectErr:         errors.New("firebase unavailable"),
+		},
+		{
+			name:      "unknown user",
+			findErr:   sql.ErrNoRows,
+			expectErr: domain.ErrUserNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockUserRepo)
+			mockRefresh := new(MockRefreshTokenRepo)
+			mockRevocations := new(MockRevocationStore)
+			mockFirebase := new(MockFirebaseAuth)
+			authUC := usecases.NewAuthUseCase(mockRepo, noRoles(), new(MockIdentityRepo), mockRefresh, noSessions(), mockRevocations, nil, nil, noMFA(), nil, nil, new(MockJWTService), new(MockPasswordHasher))
+			uc := usecases.NewUserUseCase(mockRepo, nil, authUC, mockFirebase)
+			if tt.withoutFirebase {
+				uc = usecases.NewUserUseCase(mockRepo, nil, authUC, nil)
+			}
+
+			mockRepo.On("FindByID", mock.Anything, "user-id").Return(tt.user, tt.findErr)
+			mockRepo.On("SoftDelete", mock.Anything, "user-id", mock.AnythingOfType("time.Time")).Return(nil)
+			mockFirebase.On("DeleteUser", mock.Anything, "firebase-uid").Return(tt.firebaseErr)
+			mockRevocations.On("RevokeUserTokens", mock.Anything, "user-id", mock.AnythingOfType("time.Time")).Return(nil)
+			mockRefresh.On("RevokeAllForUser", mock.Anything, "user-id").Return(nil)
+
+			err := uc.DeleteAccount(context.Background(), "user-id")
+
+			if tt.expectFirebaseDel {
+				mockFirebase.AssertCalled(t, "DeleteUser", mock.Anything, "firebase-uid")
+			} else {
+				mockFirebase.AssertNotCalled(t, "DeleteUser", mock.Anything, mock.Anything)
+			}
+			if tt.expectErr != nil {
+				if errors.Is(tt.expectErr, domain.ErrUserNotFound) {
+					assert.ErrorIs(t, err, tt.expectErr)
+				} else {
+					assert.EqualError(t, err, tt.expectErr.Error())
+				}
+				mockRepo.AssertNotCalled(t, "SoftDelete", mock.Anything, mock.Anything, mock.Anything)
+				mockRefresh.AssertNotCalled(t, "RevokeAllForUser", mock.Anything, mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			mockRepo.AssertCalled(t, "SoftDelete", mock.Anything, "user-id", mock.AnythingOfType("time.Time"))
+			mockRefresh.AssertCalled(t, "RevokeAllForUser", mock.Anything, "user-id")
+		})
+	}
+}
+
+func TestUserUseCase_ExportData(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockRepo := new(MockUserRepo)
+		mockData := new(MockUserDataRepo)
+		uc := usecases.NewUserUseCase(mockRepo, mockData, nil, nil)
+
+		export := &domain.UserDataExport{UserID: "user-id", Sections: map[string]json.RawMessage{"cats": json.RawMessage(`[]`)}}
+		mockRepo.On("FindByID", mock.Anything, "user-id").Return(&domain.User{ID: "user-id"}, nil)
+		mockData.On("Export", mock.Anything, "user-id").Return(export, nil)
+
+		got, err := uc.ExportData(context.Background(), "user-id")
+		assert.NoError(t, err)
+		assert.Equal(t, export, got)
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		mockRepo := new(MockUserRepo)
+		mockData := new(MockUserDataRepo)
+		uc := usecases.NewUserUseCase(mockRepo, mockData, nil, nil)
+
+		mockRepo.On("FindByID", mock.Anything, "user-id").Return(nil, sql.ErrNoRows)
+
+		_, err := uc.ExportData(context.Background(), "user-id")
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+		mockData.AssertNotCalled(t, "Export", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,42 @@
+package middlewares
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+)
+
+// APIKeyAuthenticator resolves the principal of a request authenticated with a personal API key
+type APIKeyAuthenticator interface {
+	AuthenticateAPIKey(ctx context.Context, key string) (*domain.Principal, error)
+}
+
+// APIKeyMiddleware authenticates requests sent with "Authorization: ApiKey <key>" and hands
+// every other request to tokenAuth (usually AuthMiddleware), so a route group accepts both.
+func APIKeyMiddleware(apiKeys APIKeyAuthenticator, tokenAuth func(http.Handler) http.Handler) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		withToken := tokenAuth(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authHeader := r.Header.Get("Authorization")
+			if !strings.HasPrefix(authHeader, "ApiKey ") {
+				withToken.ServeHTTP(w, r)
+				return
+			}
+
+			principal, err := apiKeys.AuthenticateAPIKey(r.Context(), strings.TrimPrefix(authHeader, "ApiKey "))
+			if err != nil {
+				if errors.Is(err, domain.ErrInvalidAPIKey) {
+					http.Error(w, "Unauthorized - invalid api key", http.StatusUnauthorized)
+					return
+				}
+				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(WithPrincipal(r.Context(), principal)))
+		})
+	}
+}
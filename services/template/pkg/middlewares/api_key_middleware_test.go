@@ -0,0 +1,95 @@
+package middlewares_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/pkg/middlewares"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAPIKeyAuthenticator struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyAuthenticator) AuthenticateAPIKey(ctx context.Context, key string) (*domain.Principal, error) {
+	args := m.Called(ctx, key)
+	principal, _ := args.Get(0).(*domain.Principal)
+	return principal, args.Error(1)
+}
+
+func TestAPIKeyMiddleware(t *testing.T) {
+	tests := []struct {
+		name           string
+		authHeader     string
+		mockPrincipal  *domain.Principal
+		mockErr        error
+		expectedStatus int
+		expectedUserID string
+		viaTokenAuth   bool
+	}{
+		{
+			name:           "valid api key",
+			authHeader:     "ApiKey cwk_valid",
+			mockPrincipal:  &domain.Principal{UserID: "user-id", AuthMethod: domain.AuthMethodAPIKey},
+			expectedStatus: http.StatusOK,
+			expectedUserID: "user-id",
+		},
+		{
+			name:           "invalid api key",
+			authHeader:     "ApiKey cwk_valid",
+			mockErr:        domain.ErrInvalidAPIKey,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "store failure",
+			authHeader:     "ApiKey cwk_valid",
+			mockErr:        errors.New("db error"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "bearer token goes to the token middleware",
+			authHeader:     "Bearer jwt",
+			expectedStatus: http.StatusOK,
+			viaTokenAuth:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apiKeys := new(MockAPIKeyAuthenticator)
+			if !tt.viaTokenAuth {
+				apiKeys.On("AuthenticateAPIKey", mock.Anything, "cwk_valid").Return(tt.mockPrincipal, tt.mockErr)
+			}
+
+			tokenAuth := func(next http.Handler) http.Handler {
+				return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.Header().Set("X-Token-Auth", "true")
+					next.ServeHTTP(w, r)
+				})
+			}
+
+			var userID string
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				userID, _ = middlewares.UserIDFromContext(r.Context())
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+			req.Header.Set("Authorization", tt.authHeader)
+			rec := httptest.NewRecorder()
+
+			middlewares.APIKeyMiddleware(apiKeys, tokenAuth)(next).ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.Equal(t, tt.expectedUserID, userID)
+			assert.Equal(t, tt.viaTokenAuth, rec.Header().Get("X-Token-Auth") == "true")
+			apiKeys.AssertExpectations(t)
+		})
+	}
+}
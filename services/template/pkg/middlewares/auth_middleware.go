@@ -1,10 +1,11 @@
 package middlewares
 
 import (
-	"context"
 	"net/http"
 	"strings"
 
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/internal/ports/repositories"
 	"github.com/nuhorizon/go-project-template/services/template/internal/ports/services"
 )
 
@@ -12,7 +13,7 @@ type contextKey string
 
 const UserIDKey contextKey = "userID"
 
-func AuthMiddleware(jwtService services.JWTService) func(http.Handler) http.Handler {
+func AuthMiddleware(jwtService services.JWTService, revocations repositories.TokenRevocationStore) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			authHeader := r.Header.Get("Authorization")
@@ -22,14 +23,24 @@ func AuthMiddleware(jwtService services.JWTService) func(http.Handler) http.Hand
 			}
 
 			tokenString := strings.TrimPrefix(authHeader, "Bearer ")
-			user, err := jwtService.ValidateToken(tokenString)
+			claims, err := jwtService.ValidateToken(tokenString)
 			if err != nil {
 				http.Error(w, "Unauthorized - invalid token", http.StatusUnauthorized)
 				return
 			}
 
-			// Inject userID into context for handlers
-			ctx := context.WithValue(r.Context(), UserIDKey, user.ID)
+			revoked, err := revocations.IsRevoked(r.Context(), claims)
+			if err != nil {
+				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+				return
+			}
+			if revoked {
+				http.Error(w, "Unauthorized - revoked token", http.StatusUnauthorized)
+				return
+			}
+
+			// Inject the principal (and userID) into context for handlers
+			ctx := WithPrincipal(r.Context(), domain.NewPrincipalFromClaims(claims))
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
@@ -1,12 +1,15 @@
 package middlewares_test
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/internal/ports/services"
 	"github.com/nuhorizon/go-project-template/services/template/pkg/middlewares"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -16,22 +19,91 @@ type MockJWTService struct {
 	mock.Mock
 }
 
-func (m *MockJWTService) GenerateToken(user *domain.User) (string, error) {
-	args := m.Called(user)
+func (m *MockJWTService) GenerateToken(user *domain.User, sessionID string) (string, error) {
+	args := m.Called(user, sessionID)
 	return args.String(0), args.Error(1)
 }
 
-func (m *MockJWTService) ValidateToken(token string) (*domain.User, error) {
+func (m *MockJWTService) ValidateToken(token string) (*domain.TokenClaims, error) {
 	args := m.Called(token)
-	return args.Get(0).(*domain.User), args.Error(1)
+	claims, _ := args.Get(0).(*domain.TokenClaims)
+	return claims, args.Error(1)
+}
+
+func (m *MockJWTService) GenerateMFAToken(userID string) (string, error) {
+	args := m.Called(userID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockJWTService) ValidateMFAToken(token string) (*domain.TokenClaims, error) {
+	args := m.Called(token)
+	claims, _ := args.Get(0).(*domain.TokenClaims)
+	return claims, args.Error(1)
+}
+
+func (m *MockJWTService) GenerateMagicLinkToken(linkID, email string, expiresAt time.Time) (string, error) {
+	args := m.Called(linkID, email, expiresAt)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockJWTService) ValidateMagicLinkToken(token string) (string, string, error) {
+	args := m.Called(token)
+	return args.String(0), args.String(1), args.Error(2)
+}
+
+func (m *MockJWTService) GenerateEmailVerificationToken(userID, email string) (string, error) {
+	args := m.Called(userID, email)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockJWTService) ValidateEmailVerificationToken(token string) (string, string, error) {
+	args := m.Called(token)
+	return args.String(0), args.String(1), args.Error(2)
+}
+
+func (m *MockJWTService) PublicKeys() []services.PublicKey {
+	args := m.Called()
+	keys, _ := args.Get(0).([]services.PublicKey)
+	return keys
+}
+
+type MockRevocationStore struct {
+	mock.Mock
+}
+
+func (m *MockRevocationStore) RevokeToken(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	args := m.Called(ctx, tokenID, expiresAt)
+	return args.Error(0)
+}
+
+func (m *MockRevocationStore) RevokeUserTokens(ctx context.Context, userID string, before time.Time) error {
+	args := m.Called(ctx, userID, before)
+	return args.Error(0)
+}
+
+func (m *MockRevocationStore) RevokeSession(ctx context.Context, sessionID string, expiresAt time.Time) error {
+	args := m.Called(ctx, sessionID, expiresAt)
+	return args.Error(0)
+}
+
+func (m *MockRevocationStore) IsRevoked(ctx context.Context, claims *domain.TokenClaims) (bool, error) {
+	args := m.Called(ctx, claims)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRevocationStore) PruneExpired(ctx context.Context, now time.Time) (int64, error) {
+	args := m.Called(ctx, now)
+	return args.Get(0).(int64), args.Error(1)
 }
 
 func TestAuthMiddleware(t *testing.T) {
 	tests := []struct {
 		name             string
 		authHeader       string
-		mockUser         *domain.User
+		mockClaims       *domain.TokenClaims
 		mockError        error
+		revoked          bool
+		revokedErr       error
 		expectedStatus   int
 		expectedUserIDIn bool
 	}{
@@ -43,26 +115,45 @@ func TestAuthMiddleware(t *testing.T) {
 		{
 			name:           "invalid token",
 			authHeader:     "Bearer invalid-token",
-			mockUser:       nil,
+			mockClaims:     nil,
 			mockError:      assert.AnError,
 			expectedStatus: http.StatusUnauthorized,
 		},
 		{
 			name:             "valid token",
 			authHeader:       "Bearer valid-token",
-			mockUser:         &domain.User{ID: "user-123"},
+			mockClaims:       &domain.TokenClaims{UserID: "user-123", TokenID: "jti-1"},
 			mockError:        nil,
 			expectedStatus:   http.StatusOK,
 			expectedUserIDIn: true,
 		},
+		{
+			name:           "revoked token",
+			authHeader:     "Bearer revoked-token",
+			mockClaims:     &domain.TokenClaims{UserID: "user-123", TokenID: "jti-2"},
+			revoked:        true,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "revocation store failure",
+			authHeader:     "Bearer valid-token",
+			mockClaims:     &domain.TokenClaims{UserID: "user-123", TokenID: "jti-1"},
+			revokedErr:     assert.AnError,
+			expectedStatus: http.StatusInternalServerError,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockJWT := new(MockJWTService)
-			if tt.authHeader != "" && tt.mockUser != nil || tt.mockError != nil {
+			if tt.authHeader != "" && tt.mockClaims != nil || tt.mockError != nil {
 				mockJWT.On("ValidateToken", strings.TrimPrefix(tt.authHeader, "Bearer ")).
-					Return(tt.mockUser, tt.mockError)
+					Return(tt.mockClaims, tt.mockError)
+			}
+
+			mockRevocations := new(MockRevocationStore)
+			if tt.mockClaims != nil {
+				mockRevocations.On("IsRevoked", mock.Anything, tt.mockClaims).Return(tt.revoked, tt.revokedErr)
 			}
 
 			// Target handler to check context propagation
@@ -71,6 +162,11 @@ func TestAuthMiddleware(t *testing.T) {
 				if tt.expectedUserIDIn {
 					assert.True(t, ok)
 					assert.Equal(t, "user-123", userID)
+
+					principal, ok := middlewares.PrincipalFromContext(r.Context())
+					assert.True(t, ok)
+					assert.Equal(t, "user-123", principal.UserID)
+					assert.Equal(t, "jti-1", principal.TokenID)
 				}
 				w.WriteHeader(http.StatusOK)
 			})
@@ -79,11 +175,12 @@ func TestAuthMiddleware(t *testing.T) {
 			req.Header.Set("Authorization", tt.authHeader)
 			rec := httptest.NewRecorder()
 
-			handler := middlewares.AuthMiddleware(mockJWT)(finalHandler)
+			handler := middlewares.AuthMiddleware(mockJWT, mockRevocations)(finalHandler)
 			handler.ServeHTTP(rec, req)
 
 			assert.Equal(t, tt.expectedStatus, rec.Code)
 			mockJWT.AssertExpectations(t)
+			mockRevocations.AssertExpectations(t)
 		})
 	}
 }
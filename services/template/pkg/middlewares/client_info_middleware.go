@@ -0,0 +1,30 @@
+package middlewares
+
+import (
+	"net"
+	"net/http"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+)
+
+// maxUserAgentLength caps the user agent kept on sessions
+const maxUserAgentLength = 512
+
+// ClientInfoMiddleware stores the client IP and user agent of the request in its context so
+// they can be recorded on the session. It must run after chi's RealIP middleware.
+func ClientInfoMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ip := r.RemoteAddr
+		if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+			ip = host
+		}
+
+		userAgent := r.UserAgent()
+		if len(userAgent) > maxUserAgentLength {
+			userAgent = userAgent[:maxUserAgentLength]
+		}
+
+		ctx := domain.WithClientInfo(r.Context(), domain.ClientInfo{IP: ip, UserAgent: userAgent})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
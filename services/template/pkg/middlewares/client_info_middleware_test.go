@@ -0,0 +1,55 @@
+package middlewares_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/pkg/middlewares"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientInfoMiddleware(t *testing.T) {
+	tests := []struct {
+		name       string
+		remoteAddr string
+		userAgent  string
+		expected   domain.ClientInfo
+	}{
+		{
+			name:       "Strips the port",
+			remoteAddr: "203.0.113.7:51234",
+			userAgent:  "Mozilla/5.0",
+			expected:   domain.ClientInfo{IP: "203.0.113.7", UserAgent: "Mozilla/5.0"},
+		},
+		{
+			name:       "Address without port",
+			remoteAddr: "203.0.113.7",
+			expected:   domain.ClientInfo{IP: "203.0.113.7"},
+		},
+		{
+			name:       "Truncates long user agents",
+			remoteAddr: "[2001:db8::1]:443",
+			userAgent:  strings.Repeat("a", 600),
+			expected:   domain.ClientInfo{IP: "2001:db8::1", UserAgent: strings.Repeat("a", 512)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got domain.ClientInfo
+			handler := middlewares.ClientInfoMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = domain.ClientInfoFromContext(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			req.Header.Set("User-Agent", tt.userAgent)
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
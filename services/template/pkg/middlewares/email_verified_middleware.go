@@ -0,0 +1,26 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+)
+
+// RequireVerifiedEmail rejects with 403 the requests of users who haven't verified their
+// e-mail yet. The flag comes from the access token, so a user who just verified it has to
+// refresh the token first. Must run after AuthMiddleware.
+func RequireVerifiedEmail(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal, ok := PrincipalFromContext(r.Context())
+		if !ok {
+			http.Error(w, "Unauthorized - missing token", http.StatusUnauthorized)
+			return
+		}
+
+		if !principal.EmailVerified {
+			http.Error(w, "Forbidden - "+domain.ErrEmailNotVerified.Error(), http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
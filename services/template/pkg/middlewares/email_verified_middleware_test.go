@@ -0,0 +1,36 @@
+package middlewares_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/pkg/middlewares"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequireVerifiedEmail(t *testing.T) {
+	tests := []struct {
+		name           string
+		principal      *domain.Principal
+		expectedStatus int
+	}{
+		{"no principal in context", nil, http.StatusUnauthorized},
+		{"unverified email", &domain.Principal{UserID: "user-id"}, http.StatusForbidden},
+		{"verified email", &domain.Principal{UserID: "user-id", EmailVerified: true}, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := middlewares.RequireVerifiedEmail(okHandler())
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, requestWithPrincipal(tt.principal))
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedStatus == http.StatusForbidden {
+				assert.Contains(t, rec.Body.String(), "email address not verified")
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package middlewares
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/internal/models"
+)
+
+// FeatureGate checks the plan of a user, failing with a *domain.EntitlementError when the
+// feature isn't included
+type FeatureGate interface {
+	RequireFeature(ctx context.Context, userID, feature string) error
+}
+
+// RequireFeature lets the request through only when the plan of the principal includes the feature.
+// Must run after AuthMiddleware.
+func RequireFeature(gate FeatureGate, feature string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, ok := PrincipalFromContext(r.Context())
+			if !ok {
+				http.Error(w, "Unauthorized - missing token", http.StatusUnauthorized)
+				return
+			}
+
+			if err := gate.RequireFeature(r.Context(), principal.UserID, feature); err != nil {
+				var entitlementErr *domain.EntitlementError
+				if errors.As(err, &entitlementErr) {
+					WriteEntitlementError(w, entitlementErr)
+					return
+				}
+				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// WriteEntitlementError answers with a models.EntitlementErrorResponse: 402 Payment Required when
+// a limit of the plan was reached, 403 Forbidden when the feature isn't part of the plan
+func WriteEntitlementError(w http.ResponseWriter, err *domain.EntitlementError) {
+	status := http.StatusForbidden
+	body := models.EntitlementErrorResponse{
+		Error:   err.Error(),
+		Reason:  err.Reason,
+		Plan:    err.Plan,
+		Feature: err.Feature,
+	}
+	if err.Reason == domain.EntitlementCatLimitReached {
+		status = http.StatusPaymentRequired
+		body.Limit = &err.Limit
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
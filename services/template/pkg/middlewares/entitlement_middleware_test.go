@@ -0,0 +1,66 @@
+package middlewares_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/internal/models"
+	"github.com/nuhorizon/go-project-template/services/template/pkg/middlewares"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubFeatureGate struct {
+	err error
+}
+
+func (s stubFeatureGate) RequireFeature(ctx context.Context, userID, feature string) error {
+	return s.err
+}
+
+func TestRequireFeature(t *testing.T) {
+	notInPlan := &domain.EntitlementError{Reason: domain.EntitlementFeatureNotInPlan, Plan: domain.PlanFree, Feature: domain.FeatureImageAnalysis}
+
+	tests := []struct {
+		name           string
+		principal      *domain.Principal
+		gateErr        error
+		expectedStatus int
+	}{
+		{"no principal in context", nil, nil, http.StatusUnauthorized},
+		{"feature in plan", &domain.Principal{UserID: "user-id"}, nil, http.StatusOK},
+		{"feature not in plan", &domain.Principal{UserID: "user-id"}, notInPlan, http.StatusForbidden},
+		{"gate fails", &domain.Principal{UserID: "user-id"}, errors.New("db down"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := middlewares.RequireFeature(stubFeatureGate{err: tt.gateErr}, domain.FeatureImageAnalysis)(okHandler())
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, requestWithPrincipal(tt.principal))
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedStatus == http.StatusForbidden {
+				var body models.EntitlementErrorResponse
+				assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+				assert.Equal(t, domain.EntitlementFeatureNotInPlan, body.Reason)
+				assert.Equal(t, domain.PlanFree, body.Plan)
+				assert.Equal(t, domain.FeatureImageAnalysis, body.Feature)
+				assert.Nil(t, body.Limit)
+			}
+		})
+	}
+}
+
+func TestWriteEntitlementError_LimitReached(t *testing.T) {
+	rec := httptest.NewRecorder()
+	middlewares.WriteEntitlementError(rec, &domain.EntitlementError{Reason: domain.EntitlementCatLimitReached, Plan: domain.PlanFree, Limit: 1})
+
+	assert.Equal(t, http.StatusPaymentRequired, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"error":"plan \"free\" allows at most 1 cats","reason":"cat_limit_reached","plan":"free","limit":1}`, rec.Body.String())
+}
@@ -0,0 +1,27 @@
+package middlewares
+
+import (
+	"context"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+)
+
+const principalKey contextKey = "principal"
+
+// WithPrincipal returns a copy of ctx carrying the authenticated principal (and its user ID under UserIDKey)
+func WithPrincipal(ctx context.Context, principal *domain.Principal) context.Context {
+	ctx = context.WithValue(ctx, UserIDKey, principal.UserID)
+	return context.WithValue(ctx, principalKey, principal)
+}
+
+// PrincipalFromContext returns the principal set by the auth middleware
+func PrincipalFromContext(ctx context.Context) (*domain.Principal, bool) {
+	principal, ok := ctx.Value(principalKey).(*domain.Principal)
+	return principal, ok && principal != nil
+}
+
+// UserIDFromContext returns the ID of the authenticated user
+func UserIDFromContext(ctx context.Context) (string, bool) {
+	userID, ok := ctx.Value(UserIDKey).(string)
+	return userID, ok && userID != ""
+}
@@ -0,0 +1,44 @@
+package middlewares_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/pkg/middlewares"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrincipalContext(t *testing.T) {
+	t.Run("empty context", func(t *testing.T) {
+		_, ok := middlewares.PrincipalFromContext(context.Background())
+		assert.False(t, ok)
+
+		_, ok = middlewares.UserIDFromContext(context.Background())
+		assert.False(t, ok)
+	})
+
+	t.Run("with principal", func(t *testing.T) {
+		principal := &domain.Principal{UserID: "user-id", PlanType: "premium"}
+		ctx := middlewares.WithPrincipal(context.Background(), principal)
+
+		got, ok := middlewares.PrincipalFromContext(ctx)
+		assert.True(t, ok)
+		assert.Equal(t, principal, got)
+
+		userID, ok := middlewares.UserIDFromContext(ctx)
+		assert.True(t, ok)
+		assert.Equal(t, "user-id", userID)
+	})
+
+	t.Run("user id only", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), middlewares.UserIDKey, "user-id")
+
+		userID, ok := middlewares.UserIDFromContext(ctx)
+		assert.True(t, ok)
+		assert.Equal(t, "user-id", userID)
+
+		_, ok = middlewares.PrincipalFromContext(ctx)
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,91 @@
+package middlewares
+
+import (
+	"log"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/internal/ports/repositories"
+)
+
+// RateLimitPolicy is the quota of a route group. Authenticated callers get the quota of their
+// plan (Default when the plan has none), anonymous callers get Default per client IP.
+type RateLimitPolicy struct {
+	Group   string // Part of the counter key, so every route group has its own quota
+	Default domain.RateLimit
+	Plans   map[string]domain.RateLimit
+}
+
+func (p RateLimitPolicy) limitFor(principal *domain.Principal) domain.RateLimit {
+	if principal == nil {
+		return p.Default
+	}
+	plan := principal.PlanType
+	if plan == "" {
+		plan = domain.PlanFree
+	}
+	if limit, ok := p.Plans[plan]; ok {
+		return limit
+	}
+	return p.Default
+}
+
+// RateLimitMiddleware enforces the policy with a sliding window: the hits of the previous fixed
+// window are weighted by how much of it still overlaps the last Window. Responses carry the
+// RateLimit-Limit, RateLimit-Remaining and RateLimit-Reset headers, rejected ones a 429 with
+// Retry-After. Keyed by principal when it runs after AuthMiddleware, by client IP (as resolved
+// by chi's RealIP) otherwise. Store failures let the request through.
+func RateLimitMiddleware(store repositories.RateLimitStore, policy RateLimitPolicy) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, _ := PrincipalFromContext(r.Context())
+			limit := policy.limitFor(principal)
+			if limit.Requests <= 0 || limit.Window <= 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			now := time.Now()
+			windowStart := now.Truncate(limit.Window)
+			counts, err := store.Hit(r.Context(), rateLimitKey(policy.Group, principal, r), windowStart, limit.Window)
+			if err != nil {
+				log.Printf("rate limit store: %v", err)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			elapsed := now.Sub(windowStart)
+			weight := 1 - float64(elapsed)/float64(limit.Window)
+			used := int(math.Ceil(float64(counts.Previous)*weight)) + counts.Current
+			reset := int(math.Ceil((limit.Window - elapsed).Seconds()))
+
+			w.Header().Set("RateLimit-Limit", strconv.Itoa(limit.Requests))
+			w.Header().Set("RateLimit-Remaining", strconv.Itoa(max(limit.Requests-used, 0)))
+			w.Header().Set("RateLimit-Reset", strconv.Itoa(reset))
+			w.Header().Set("RateLimit-Policy", strconv.Itoa(limit.Requests)+";w="+strconv.Itoa(int(limit.Window.Seconds())))
+
+			if used > limit.Requests {
+				w.Header().Set("Retry-After", strconv.Itoa(reset))
+				http.Error(w, "Too Many Requests", http.StatusTooManyRequests)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func rateLimitKey(group string, principal *domain.Principal, r *http.Request) string {
+	if principal != nil {
+		return group + ":user:" + principal.UserID
+	}
+
+	ip := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		ip = host
+	}
+	return group + ":ip:" + ip
+}
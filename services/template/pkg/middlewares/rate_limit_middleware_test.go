@@ -0,0 +1,128 @@
+package middlewares_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/pkg/middlewares"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockRateLimitStore struct {
+	mock.Mock
+}
+
+func (m *MockRateLimitStore) Hit(ctx context.Context, key string, windowStart time.Time, window time.Duration) (domain.RateLimitCounts, error) {
+	args := m.Called(ctx, key, windowStart, window)
+	return args.Get(0).(domain.RateLimitCounts), args.Error(1)
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	policy := middlewares.RateLimitPolicy{
+		Group:   "api",
+		Default: domain.RateLimit{Requests: 10, Window: time.Hour},
+		Plans: map[string]domain.RateLimit{
+			domain.PlanFree:    {Requests: 10, Window: time.Hour},
+			domain.PlanPremium: {Requests: 100, Window: time.Hour},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		principal      *domain.Principal
+		expectedKey    string
+		expectedWindow time.Duration
+		counts         domain.RateLimitCounts
+		storeErr       error
+		expectedStatus int
+		expectedLimit  string
+	}{
+		{
+			name:           "anonymous caller keyed by ip",
+			expectedKey:    "api:ip:192.0.2.1",
+			counts:         domain.RateLimitCounts{Current: 3},
+			expectedStatus: http.StatusOK,
+			expectedLimit:  "10",
+		},
+		{
+			name:           "free plan over quota",
+			principal:      &domain.Principal{UserID: "user-1", PlanType: domain.PlanFree},
+			expectedKey:    "api:user:user-1",
+			counts:         domain.RateLimitCounts{Current: 11},
+			expectedStatus: http.StatusTooManyRequests,
+			expectedLimit:  "10",
+		},
+		{
+			name:           "premium plan has a larger quota",
+			principal:      &domain.Principal{UserID: "user-2", PlanType: domain.PlanPremium},
+			expectedKey:    "api:user:user-2",
+			counts:         domain.RateLimitCounts{Current: 11},
+			expectedStatus: http.StatusOK,
+			expectedLimit:  "100",
+		},
+		{
+			name:           "users without plan get the free quota",
+			principal:      &domain.Principal{UserID: "user-3"},
+			expectedKey:    "api:user:user-3",
+			counts:         domain.RateLimitCounts{Current: 11},
+			expectedStatus: http.StatusTooManyRequests,
+			expectedLimit:  "10",
+		},
+		{
+			name:           "store failure lets the request through",
+			expectedKey:    "api:ip:192.0.2.1",
+			storeErr:       errors.New("db error"),
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := new(MockRateLimitStore)
+			store.On("Hit", mock.Anything, tt.expectedKey, mock.Anything, time.Hour).Return(tt.counts, tt.storeErr)
+
+			req := requestWithPrincipal(tt.principal)
+			req.RemoteAddr = "192.0.2.1:4321"
+			rec := httptest.NewRecorder()
+
+			middlewares.RateLimitMiddleware(store, policy)(okHandler()).ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.Equal(t, tt.expectedLimit, rec.Header().Get("RateLimit-Limit"))
+			if tt.expectedStatus == http.StatusTooManyRequests {
+				assert.Equal(t, "0", rec.Header().Get("RateLimit-Remaining"))
+				assert.NotEmpty(t, rec.Header().Get("Retry-After"))
+			}
+			store.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRateLimitMiddleware_SlidingWindow(t *testing.T) {
+	policy := middlewares.RateLimitPolicy{Group: "auth", Default: domain.RateLimit{Requests: 10, Window: time.Hour}}
+
+	// Part of the previous window always overlaps the last hour, so at least one of its hits counts
+	store := new(MockRateLimitStore)
+	store.On("Hit", mock.Anything, "auth:ip:192.0.2.1", mock.Anything, time.Hour).
+		Return(domain.RateLimitCounts{Current: 2, Previous: 8}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "192.0.2.1:4321"
+	rec := httptest.NewRecorder()
+
+	middlewares.RateLimitMiddleware(store, policy)(okHandler()).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	remaining, err := strconv.Atoi(rec.Header().Get("RateLimit-Remaining"))
+	assert.NoError(t, err)
+	assert.LessOrEqual(t, remaining, 7)
+	assert.GreaterOrEqual(t, remaining, 0)
+	assert.Equal(t, "10;w=3600", rec.Header().Get("RateLimit-Policy"))
+}
@@ -0,0 +1,64 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+)
+
+// RequireRole lets the request through when the principal has any of the given roles.
+// Must run after AuthMiddleware.
+func RequireRole(roles ...string) func(http.Handler) http.Handler {
+	return requirePrincipal(func(principal *domain.Principal) bool {
+		for _, role := range roles {
+			if principal.HasRole(role) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// RequirePermission lets the request through only when the principal has every given permission.
+// Must run after AuthMiddleware.
+func RequirePermission(permissions ...string) func(http.Handler) http.Handler {
+	return requirePrincipal(func(principal *domain.Principal) bool {
+		for _, permission := range permissions {
+			if !principal.HasPermission(permission) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// RequireScope lets API keys through only when they carry every given scope, access tokens always
+// pass. Must run after AuthMiddleware.
+func RequireScope(scopes ...string) func(http.Handler) http.Handler {
+	return requirePrincipal(func(principal *domain.Principal) bool {
+		for _, scope := range scopes {
+			if !principal.HasScope(scope) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+func requirePrincipal(allowed func(principal *domain.Principal) bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, ok := PrincipalFromContext(r.Context())
+			if !ok {
+				http.Error(w, "Unauthorized - missing token", http.StatusUnauthorized)
+				return
+			}
+
+			if !allowed(principal) {
+				http.Error(w, "Forbidden", http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package middlewares_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nuhorizon/go-project-template/services/template/internal/domain"
+	"github.com/nuhorizon/go-project-template/services/template/pkg/middlewares"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequireRole(t *testing.T) {
+	tests := []struct {
+		name           string
+		principal      *domain.Principal
+		expectedStatus int
+	}{
+		{"no principal in context", nil, http.StatusUnauthorized},
+		{"missing role", &domain.Principal{UserID: "user-id"}, http.StatusForbidden},
+		{"one of the roles", &domain.Principal{UserID: "user-id", Roles: []string{"support"}}, http.StatusOK},
+		{"admin", &domain.Principal{UserID: "user-id", Roles: []string{domain.RoleAdmin}}, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := middlewares.RequireRole(domain.RoleAdmin, "support")(okHandler())
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, requestWithPrincipal(tt.principal))
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestRequirePermission(t *testing.T) {
+	tests := []struct {
+		name           string
+		permissions    []string
+		expectedStatus int
+	}{
+		{"all permissions", []string{domain.PermissionUsersRead, domain.PermissionUsersWrite}, http.StatusOK},
+		{"missing one permission", []string{domain.PermissionUsersRead}, http.StatusForbidden},
+		{"no permissions", nil, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := middlewares.RequirePermission(domain.PermissionUsersRead, domain.PermissionUsersWrite)(okHandler())
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, requestWithPrincipal(&domain.Principal{UserID: "user-id", Permissions: tt.permissions}))
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestRequireScope(t *testing.T) {
+	tests := []struct {
+		name           string
+		principal      *domain.Principal
+		expectedStatus int
+	}{
+		{"no principal in context", nil, http.StatusUnauthorized},
+		{"access token", &domain.Principal{UserID: "user-id", AuthMethod: domain.AuthMethodToken}, http.StatusOK},
+		{"api key with every scope", &domain.Principal{UserID: "user-id", AuthMethod: domain.AuthMethodAPIKey, Scopes: []string{domain.ScopeCatsRead, domain.ScopeCatsWrite}}, http.StatusOK},
+		{"api key missing one scope", &domain.Principal{UserID: "user-id", AuthMethod: domain.AuthMethodAPIKey, Scopes: []string{domain.ScopeCatsRead}}, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := middlewares.RequireScope(domain.ScopeCatsRead, domain.ScopeCatsWrite)(okHandler())
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, requestWithPrincipal(tt.principal))
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func okHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+func requestWithPrincipal(principal *domain.Principal) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+	if principal != nil {
+		req = req.WithContext(middlewares.WithPrincipal(req.Context(), principal))
+	}
+	return req
+}
@@ -0,0 +1,23 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateRandomToken(t *testing.T) {
+	first, err := GenerateRandomToken(32)
+	assert.NoError(t, err)
+	assert.Len(t, first, 43) // 32 bytes, base64url without padding
+
+	second, err := GenerateRandomToken(32)
+	assert.NoError(t, err)
+	assert.NotEqual(t, first, second)
+}
+
+func TestHashToken(t *testing.T) {
+	assert.Equal(t, HashToken("token"), HashToken("token"))
+	assert.NotEqual(t, HashToken("token"), HashToken("other"))
+	assert.Len(t, HashToken("token"), 64)
+}